@@ -1,17 +1,28 @@
 package cmd
 
 import (
+	"bytes"
 	"context"
+	"encoding/json"
 	"errors"
 	"fmt"
+	"io"
+	"math/rand"
+	"net/http"
 	"os"
 	"os/signal"
+	"path/filepath"
+	"runtime/debug"
+	"strings"
 	"sync"
 	"syscall"
 	"time"
 
 	"github.com/kindship-ai/kindship-cli/internal/api"
+	"github.com/kindship-ai/kindship-cli/internal/auth"
+	"github.com/kindship-ai/kindship-cli/internal/config"
 	"github.com/kindship-ai/kindship-cli/internal/logging"
+	"github.com/kindship-ai/kindship-cli/internal/sysinfo"
 	"github.com/spf13/cobra"
 )
 
@@ -24,7 +35,8 @@ var agentCmd = &cobra.Command{
 	Long: `Commands for agent containers running on infrastructure.
 
 Subcommands:
-  loop     Run autonomous execution loop`,
+  loop       Run autonomous execution loop
+  register   Register this machine as an agent container`,
 }
 
 var loopCmd = &cobra.Command{
@@ -37,65 +49,503 @@ Runs inside agent containers. Automatically:
 - Polls for next task at configurable interval
 - Dispatches execution by mode (LLM, Bash, Python, etc.)
 - Sleeps when no tasks are available
+- Reports heartbeats (version, uptime, free disk and memory, runtimes,
+  current task)
+- Stops claiming tasks while free disk under the workspace or available
+  memory is below --min-free-disk / --min-free-memory; heartbeats report
+  the agent as degraded until resources recover
+- Reports lifecycle events per agent: started, idle (on becoming idle),
+  task_claimed, draining (on SIGTERM/SIGINT) and stopped
+- Sends heartbeats, lifecycle events and transcript artifacts only to
+  servers that advertise them (negotiated on startup, cached for an hour
+  per API URL), so older self-hosted servers keep working
+- Force-kills a task that runs longer than --watchdog (e.g. a hung LLM
+  process) and completes it as FAILED
+- With --ask-user-remind-after, keeps track of the ASK_USER tasks it
+  started and, each time one has waited that many more minutes for an
+  answer, logs a reminder, posts it to --ask-user-webhook (a JSON payload
+  whose "text" field makes it usable as a Slack incoming webhook) and, with
+  --ask-user-escalate, asks the API to raise the question's priority
+- On SIGTERM/SIGINT, stops the running task (SIGTERM to its process group,
+  SIGKILL after 10s), completes it as ABANDONED and exits; a second signal
+  exits immediately
+
+Several agents can be served from one process by repeating --agent-id or by
+listing them in an agents file. Agents are polled round-robin: after each
+poll (or executed task) the loop moves on to the next agent, and it only
+sleeps once every agent has come back idle.
+
+Idle sleeps are randomized by --poll-jitter percent around --poll-interval
+so agents restarted together do not keep polling in lockstep. With
+--idle-exit N the loop exits with status 0 after N consecutive polling
+rounds in which no agent had work (failed polls reset the count), for
+deployments that scale to zero and start agents again on demand.
+
+With no agent configured, the agent saved by 'kindship agent register' is used.
+
+The agents file is a JSON array of objects with an "agent_id" and an optional
+"service_key" (defaults to --service-key / KINDSHIP_SERVICE_KEY):
+  [{"agent_id": "a1"}, {"agent_id": "a2", "service_key": "..."}]
 
 Configuration:
   --poll-interval  Seconds between idle polls (default: 30)
+  --poll-jitter    Randomize idle sleeps by up to this percent (default: 20)
+  --idle-exit      Exit after this many consecutive empty polls, 0 never (default: 0)
+  --min-free-disk  Free MB under the workspace required to claim a task, 0 disables (default: 1024)
+  --min-free-memory  Available MB of memory required to claim a task, 0 disables (default: 256)
+  --watchdog       Seconds a single task may run before it is force-killed, 0 disables (default: 7200)
+  --heartbeat-interval  Seconds between heartbeats, 0 disables (default: 60)
   --api-url        API base URL (env: KINDSHIP_API_URL)
   --service-key    Service key (env: KINDSHIP_SERVICE_KEY)
-  --agent-id       Agent ID (env: AGENT_ID)`,
+  --agent-id       Agent ID, repeatable (env: AGENT_ID)
+  --agents-file    JSON file listing agents to poll
+  --timings        Print per-phase timings accumulated over all tasks on shutdown
+  --ask-user-remind-after  Minutes an ASK_USER task may wait for an answer before a reminder, 0 disables (default: 0)
+  --ask-user-webhook       URL to post reminders to (env: KINDSHIP_ASK_USER_WEBHOOK)
+  --ask-user-escalate      Also raise the priority of waiting questions via the API
+
+` + llmFlagsHelp + `
+
+` + secretsFlagHelp + `
+
+` + eventsFlagHelp + `
+
+Examples:
+  kindship agent loop
+  kindship agent loop --permission-mode acceptEdits --max-turns 40
+  kindship agent loop --agent-id a1 --agent-id a2
+  kindship agent loop --agents-file /etc/kindship/agents.json`,
 	RunE: runLoop,
 }
 
-var pollInterval int
+var (
+	pollInterval      int
+	pollJitter        int
+	loopIdleExit      int
+	loopWatchdog      int
+	loopMinFreeDisk   int
+	loopMinFreeMemory int
+	heartbeatInterval int
+	loopAgentIDs      []string
+	loopAgentsFile    string
+)
+
+// shutdownEventTimeout bounds each draining and stopped event sent while
+// the loop shuts down
+const shutdownEventTimeout = 5 * time.Second
+
+// loopAgent is a single agent identity served by the loop.
+type loopAgent struct {
+	AgentID    string `json:"agent_id"`
+	ServiceKey string `json:"service_key,omitempty"`
+
+	log *logging.Logger
+
+	// currentTaskID is the task being executed, reported in heartbeats
+	mu            sync.Mutex
+	currentTaskID string
+
+	// idle is set once an idle event was reported, until the agent claims
+	// a task. Only used by the main loop.
+	idle bool
+
+	// sendEvents is set when the server supports lifecycle events
+	sendEvents bool
+}
+
+// setCurrentTask records the task the agent is executing ("" when idle)
+func (a *loopAgent) setCurrentTask(taskID string) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	a.currentTaskID = taskID
+}
+
+// currentTask returns the task the agent is executing, if any
+func (a *loopAgent) currentTask() string {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	return a.currentTaskID
+}
+
+// emitEvent reports a lifecycle event for the agent. Failures are logged
+// and never affect the loop.
+func (a *loopAgent) emitEvent(client *api.Client, event api.AgentEvent, taskID, reason string) {
+	if !a.sendEvents {
+		return
+	}
+	_, err := client.SendAgentEvent(api.AgentEventRequest{
+		AgentID:    a.AgentID,
+		Event:      event,
+		OccurredAt: time.Now().UTC(),
+		TaskID:     taskID,
+		Reason:     reason,
+	}, a.ServiceKey)
+	if err != nil {
+		a.log.Warn("Failed to send agent event", map[string]interface{}{
+			"event": string(event),
+			"error": err.Error(),
+		})
+	}
+}
 
 func init() {
 	loopCmd.Flags().IntVar(&pollInterval, "poll-interval", 30, "Seconds between idle polls")
-	loopCmd.Flags().StringVar(&agentID, "agent-id", "", "Agent ID")
-	loopCmd.Flags().StringVar(&serviceKey, "service-key", "", "Service key")
-	loopCmd.Flags().StringVar(&apiURL, "api-url", "", "API base URL")
-	loopCmd.Flags().BoolVarP(&verbose, "verbose", "v", false, "Verbose logging")
+	loopCmd.Flags().IntVar(&pollJitter, "poll-jitter", 20, "Randomize idle sleeps by up to this percent of --poll-interval")
+	loopCmd.Flags().IntVar(&loopIdleExit, "idle-exit", 0, "Exit after this many consecutive empty polls (0 never exits)")
+	loopCmd.Flags().IntVar(&loopMinFreeDisk, "min-free-disk", 1024, "Free MB under the workspace required to claim a task (0 disables)")
+	loopCmd.Flags().IntVar(&loopMinFreeMemory, "min-free-memory", 256, "Available MB of memory required to claim a task (0 disables)")
+	loopCmd.Flags().IntVar(&loopWatchdog, "watchdog", 7200, "Seconds a single task may run before it is force-killed (0 disables)")
+	loopCmd.Flags().IntVar(&heartbeatInterval, "heartbeat-interval", 60, "Seconds between heartbeats (0 disables)")
+	loopCmd.Flags().StringArrayVar(&loopAgentIDs, "agent-id", nil, "Agent ID (repeatable to serve several agents)")
+	loopCmd.Flags().StringVar(&loopAgentsFile, "agents-file", "", "JSON file listing agents to poll")
+	loopCmd.Flags().StringVar(&loopOpts.ServiceKey, "service-key", "", "Service key")
+	loopCmd.Flags().StringVar(&loopOpts.APIURL, "api-url", "", "API base URL")
+	loopCmd.Flags().BoolVarP(&loopOpts.Verbose, "verbose", "v", false, "Verbose logging")
+	loopCmd.Flags().BoolVar(&showTimings, "timings", false, timingsUsage)
+	loopCmd.Flags().IntVar(&askUserRemindAfter, "ask-user-remind-after", 0, "Minutes an ASK_USER task may wait for an answer before a reminder (0 disables)")
+	loopCmd.Flags().StringVar(&askUserWebhook, "ask-user-webhook", "", "URL to post ASK_USER reminders to (e.g. a Slack incoming webhook)")
+	loopCmd.Flags().BoolVar(&askUserEscalate, "ask-user-escalate", false, "Raise the priority of waiting ASK_USER questions via the API")
+	addLLMFlags(loopCmd)
+	addSecretsFlag(loopCmd)
+	addEventsFlag(loopCmd)
+
+	registerCmd.Flags().StringVar(&registerTitle, "title", "", "Agent title (defaults to hostname)")
+	registerCmd.Flags().StringSliceVar(&registerLabels, "labels", nil, "Comma-separated labels (e.g. gpu,linux)")
+	registerCmd.Flags().StringVar(&registerAgentID, "agent", "", "Claim an existing agent by ID or slug instead of creating one")
+	registerCmd.Flags().BoolVar(&registerJSON, "json", false, "Output in JSON format")
 
 	agentCmd.AddCommand(loopCmd)
+	agentCmd.AddCommand(registerCmd)
 	rootCmd.AddCommand(agentCmd)
 }
 
+var registerCmd = &cobra.Command{
+	Use:   "register",
+	Short: "Register this machine as an agent container",
+	Long: `Create (or claim) an agent container record via the API so new agents can
+be provisioned without the web UI.
+
+The agent ID is saved to agent.json in the state dir and picked up by
+'kindship agent loop' when no AGENT_ID is provided. The service key is
+only printed once and is never written to disk.
+
+Requires local authentication ('kindship login').
+
+Examples:
+  kindship agent register --title "build-box-3" --labels gpu,linux
+  kindship agent register --agent my-agent-slug
+  kindship agent register --title ci-runner --json`,
+	Args: cobra.NoArgs,
+	RunE: runRegister,
+}
+
+var (
+	registerTitle   string
+	registerLabels  []string
+	registerAgentID string
+	registerJSON    bool
+)
+
+// AgentRegisterRequest is the request body for /api/cli/agents/register
+type AgentRegisterRequest struct {
+	Title      string   `json:"title"`
+	Labels     []string `json:"labels,omitempty"`
+	Hostname   string   `json:"hostname,omitempty"`
+	AgentID    string   `json:"agent_id,omitempty"`
+	CLIVersion string   `json:"cli_version"`
+}
+
+// AgentRegisterResponse is the response from /api/cli/agents/register
+type AgentRegisterResponse struct {
+	AgentID    string   `json:"agent_id"`
+	AgentSlug  string   `json:"agent_slug,omitempty"`
+	AccountID  string   `json:"account_id,omitempty"`
+	Title      string   `json:"title"`
+	Labels     []string `json:"labels,omitempty"`
+	ServiceKey string   `json:"service_key"`
+	APIBaseURL string   `json:"api_base_url,omitempty"`
+	Claimed    bool     `json:"claimed,omitempty"`
+	Error      string   `json:"error,omitempty"`
+}
+
+func runRegister(cmd *cobra.Command, args []string) error {
+	ctx, err := auth.GetAuthContext()
+	if err != nil {
+		return err
+	}
+
+	if !ctx.IsLocalMode() {
+		return fmt.Errorf("agent register requires 'kindship login' (service key auth cannot create agents)")
+	}
+
+	hostname, _ := os.Hostname()
+	title := registerTitle
+	if title == "" {
+		title = hostname
+	}
+	if title == "" && registerAgentID == "" {
+		return fmt.Errorf("--title is required (hostname could not be determined)")
+	}
+
+	labels := make([]string, 0, len(registerLabels))
+	for _, label := range registerLabels {
+		if label = strings.TrimSpace(label); label != "" {
+			labels = append(labels, label)
+		}
+	}
+
+	reqBody := AgentRegisterRequest{
+		Title:      title,
+		Labels:     labels,
+		Hostname:   hostname,
+		AgentID:    registerAgentID,
+		CLIVersion: Version,
+	}
+
+	jsonData, err := json.Marshal(reqBody)
+	if err != nil {
+		return fmt.Errorf("failed to marshal request: %w", err)
+	}
+
+	endpoint := fmt.Sprintf("%s/api/cli/agents/register", ctx.APIBaseURL)
+	req, err := http.NewRequest(http.MethodPost, endpoint, bytes.NewBuffer(jsonData))
+	if err != nil {
+		return fmt.Errorf("failed to create request: %w", err)
+	}
+
+	ctx.SetAuthHeaders(req)
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("X-Kindship-CLI-Version", Version)
+
+	httpClient := api.NewHTTPClient(30 * time.Second)
+	resp, err := httpClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to register agent: %w", err)
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return fmt.Errorf("failed to read response: %w", err)
+	}
+
+	if resp.StatusCode != http.StatusOK && resp.StatusCode != http.StatusCreated {
+		var errResp AgentRegisterResponse
+		if json.Unmarshal(body, &errResp) == nil && errResp.Error != "" {
+			return fmt.Errorf("registration failed: %s", errResp.Error)
+		}
+		return fmt.Errorf("registration failed (%d): %s", resp.StatusCode, string(body))
+	}
+
+	var regResp AgentRegisterResponse
+	if err := json.Unmarshal(body, &regResp); err != nil {
+		return fmt.Errorf("failed to parse response: %w", err)
+	}
+	if regResp.AgentID == "" {
+		return fmt.Errorf("registration failed: API returned no agent ID")
+	}
+
+	state := &config.AgentState{
+		AgentID:      regResp.AgentID,
+		AgentSlug:    regResp.AgentSlug,
+		AccountID:    regResp.AccountID,
+		Title:        regResp.Title,
+		Labels:       regResp.Labels,
+		RegisteredAt: time.Now(),
+	}
+	if err := config.SaveAgentState(state); err != nil {
+		return fmt.Errorf("failed to save agent state: %w", err)
+	}
+
+	if registerJSON {
+		return printJSON(regResp)
+	}
+
+	apiBase := regResp.APIBaseURL
+	if apiBase == "" {
+		apiBase = ctx.APIBaseURL
+	}
+
+	if regResp.Claimed {
+		fmt.Printf("✓ Claimed agent '%s' (%s)\n", regResp.Title, regResp.AgentID)
+	} else {
+		fmt.Printf("✓ Registered agent '%s' (%s)\n", regResp.Title, regResp.AgentID)
+	}
+	if len(regResp.Labels) > 0 {
+		fmt.Printf("  Labels: %s\n", strings.Join(regResp.Labels, ", "))
+	}
+	if stateDir, err := config.GetStateDir(); err == nil {
+		fmt.Printf("  Agent state saved to %s\n", filepath.Join(stateDir, config.AgentStateFile))
+	}
+	fmt.Println()
+	fmt.Println("To bootstrap the agent container, set these environment variables:")
+	fmt.Println()
+	fmt.Printf("  export AGENT_ID=%s\n", regResp.AgentID)
+	if regResp.ServiceKey != "" {
+		fmt.Printf("  export KINDSHIP_SERVICE_KEY=%s\n", regResp.ServiceKey)
+	}
+	fmt.Printf("  export KINDSHIP_API_URL=%s\n", apiBase)
+	fmt.Println()
+	fmt.Println("Then start the loop with:")
+	fmt.Println("  kindship agent loop")
+	if regResp.ServiceKey != "" {
+		fmt.Println()
+		fmt.Println("The service key is shown only once. Store it securely.")
+	}
+
+	return nil
+}
+
+// resolveLoopAgents builds the list of agents to poll from --agent-id flags,
+// the --agents-file, the AGENT_ID environment variable and finally the agent
+// registered on this machine. Duplicate agent IDs are dropped and agents
+// without their own key inherit the default.
+func resolveLoopAgents(defaultServiceKey string) ([]*loopAgent, error) {
+	var agents []*loopAgent
+	seen := make(map[string]bool)
+	add := func(a *loopAgent) {
+		if a.AgentID == "" || seen[a.AgentID] {
+			return
+		}
+		seen[a.AgentID] = true
+		if a.ServiceKey == "" {
+			a.ServiceKey = defaultServiceKey
+		}
+		agents = append(agents, a)
+	}
+
+	for _, id := range loopAgentIDs {
+		add(&loopAgent{AgentID: strings.TrimSpace(id)})
+	}
+
+	if loopAgentsFile != "" {
+		data, err := os.ReadFile(loopAgentsFile)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read agents file: %w", err)
+		}
+		var fileAgents []*loopAgent
+		if err := json.Unmarshal(data, &fileAgents); err != nil {
+			return nil, fmt.Errorf("failed to parse agents file: %w", err)
+		}
+		for _, a := range fileAgents {
+			a.AgentID = strings.TrimSpace(a.AgentID)
+			add(a)
+		}
+	}
+
+	if len(agents) == 0 {
+		add(&loopAgent{AgentID: os.Getenv("AGENT_ID")})
+	}
+
+	// Fall back to the agent registered on this machine
+	if len(agents) == 0 {
+		if state, err := config.LoadAgentState(); err == nil && state != nil {
+			add(&loopAgent{AgentID: state.AgentID})
+		}
+	}
+
+	return agents, nil
+}
+
 func runLoop(cmd *cobra.Command, args []string) error {
-	// Read from flags first, fall back to environment variables
-	if agentID == "" {
-		agentID = os.Getenv("AGENT_ID")
+	execOpts, err := executionOptions()
+	if err != nil {
+		return err
 	}
-	if serviceKey == "" {
-		serviceKey = os.Getenv("KINDSHIP_SERVICE_KEY")
+	secrets, err := secretsFlag()
+	if err != nil {
+		return err
 	}
-	if apiURL == "" {
-		apiURL = os.Getenv("KINDSHIP_API_URL")
+	events, err := eventsFlag()
+	if err != nil {
+		return err
+	}
+	if pollJitter < 0 || pollJitter > 100 {
+		return withExitCode(ExitUsage, fmt.Errorf("--poll-jitter must be between 0 and 100"))
+	}
+	if loopIdleExit < 0 {
+		return withExitCode(ExitUsage, fmt.Errorf("--idle-exit must not be negative"))
+	}
+	if loopWatchdog < 0 {
+		return withExitCode(ExitUsage, fmt.Errorf("--watchdog must not be negative"))
 	}
-	if apiURL == "" {
-		apiURL = "https://kindship.ai"
+	if loopMinFreeDisk < 0 || loopMinFreeMemory < 0 {
+		return withExitCode(ExitUsage, fmt.Errorf("--min-free-disk and --min-free-memory must not be negative"))
+	}
+	settings := executionSettings{
+		Exec:     execOpts,
+		Secrets:  secrets,
+		Watchdog: time.Duration(loopWatchdog) * time.Second,
+		Events:   events,
+	}
+	gate := &resourceGate{
+		workDir:        execOpts.Dir(),
+		minDiskBytes:   uint64(loopMinFreeDisk) << 20,
+		minMemoryBytes: uint64(loopMinFreeMemory) << 20,
 	}
 
-	// Initialize logging with agent-loop component
-	log := logging.Init(agentID, "agent-loop", verbose)
+	// Read from flags first, fall back to environment variables
+	opts := loopOpts.resolved()
+
+	agents, agentsErr := resolveLoopAgents(opts.ServiceKey)
+
+	// Initialize logging with agent-loop component. With a single agent the
+	// root logger carries its ID; with several, each agent gets its own tag.
+	rootAgentID := ""
+	if len(agents) == 1 {
+		rootAgentID = agents[0].AgentID
+	}
+	log := logging.Init(rootAgentID, "agent-loop", opts.Verbose)
 	log.SetComponent("agent-loop")
 	defer log.FlushSync()
+	if showTimings {
+		log.EnableTimings()
+		defer printTimings(log, time.Now())
+	}
 
 	// Validate required parameters
-	if agentID == "" {
+	if agentsErr != nil {
+		log.Error("Failed to resolve agents", agentsErr)
+		return agentsErr
+	}
+	if len(agents) == 0 {
 		log.Error("AGENT_ID not provided", nil)
-		return fmt.Errorf("AGENT_ID is required (use --agent-id flag or AGENT_ID environment variable)")
+		return fmt.Errorf("AGENT_ID is required (use --agent-id flag, --agents-file or AGENT_ID environment variable)")
 	}
-	if serviceKey == "" {
-		log.Error("KINDSHIP_SERVICE_KEY not provided", nil)
-		return fmt.Errorf("KINDSHIP_SERVICE_KEY is required (use --service-key flag or KINDSHIP_SERVICE_KEY environment variable)")
+	for _, a := range agents {
+		if a.ServiceKey == "" {
+			log.Error("KINDSHIP_SERVICE_KEY not provided", nil, map[string]interface{}{
+				"agent_id": a.AgentID,
+			})
+			return fmt.Errorf("KINDSHIP_SERVICE_KEY is required for agent %s (use --service-key flag or KINDSHIP_SERVICE_KEY environment variable)", a.AgentID)
+		}
+		a.log = log.WithAgent(a.AgentID)
 	}
 
-	// Create API client
-	client := api.NewClient(apiURL, verbose)
+	// Create API client and learn which optional features its server has
+	client := opts.client()
+	askUser, err := newAskUserWatch(client, log)
+	if err != nil {
+		return err
+	}
+	caps := serverCapabilities(client, agents[0].ServiceKey, log)
+	for _, a := range agents {
+		a.sendEvents = caps.Supports(api.FeatureAgentEvents)
+	}
+	if askUser != nil && askUser.escalate && !caps.Supports(api.FeatureEscalation) {
+		log.Info("Server does not support escalation; ASK_USER reminders will not raise priority")
+		askUser.escalate = false
+	}
 
 	// Set up graceful shutdown
 	ctx, cancel := context.WithCancel(context.Background())
 	defer cancel()
 
+	// Shutdown events are sent with a short timeout so an unreachable API
+	// never holds up stopping
+	shutdownClient := client.WithTimeout(shutdownEventTimeout)
+	// drained is closed once draining has been reported after a signal
+	drained := make(chan struct{})
 	sigCh := make(chan os.Signal, 1)
 	signal.Notify(sigCh, syscall.SIGTERM, syscall.SIGINT)
 	go func() {
@@ -104,53 +554,67 @@ func runLoop(cmd *cobra.Command, args []string) error {
 			"signal": sig.String(),
 		})
 		cancel()
+		for _, a := range agents {
+			a.emitEvent(shutdownClient, api.AgentEventDraining, a.currentTask(), sig.String())
+		}
+		close(drained)
 	}()
 
+	loopStart := time.Now()
+	// heartbeatNow asks the heartbeat goroutine to report right away
+	heartbeatNow := make(chan struct{}, 1)
+
 	// Step 1: Recover runs from previous loop instance
-	log.Info("Recovering runs from previous loop instance")
-	recoverResp, err := client.RecoverRuns(agentID, serviceKey)
-	if err != nil {
-		log.Error("Failed to recover runs", err)
-		// Non-fatal — continue loop startup
-	} else {
-		log.Info("Run recovery complete", map[string]interface{}{
-			"resumed_count":    len(recoverResp.ResumedRuns),
-			"failed_count":     recoverResp.FailedCount,
-			"skipped_ask_user": recoverResp.SkippedAskUser,
-		})
+	for _, a := range agents {
+		recoverAgentRuns(a, client, settings)
+	}
 
-		// Resume ORCHESTRATE runs in background goroutines
-		for _, resumed := range recoverResp.ResumedRuns {
-			if resumed.ExecutionMode == string(api.ExecutionModeOrchestrate) {
-				runID := resumed.RunID
-				if _, loaded := activeResumes.LoadOrStore(runID, true); loaded {
-					log.Info("Resume already active, skipping", map[string]interface{}{
-						"run_id": runID,
-					})
-					continue
-				}
-				go func(entityID, runID string) {
-					defer activeResumes.Delete(runID)
-					if resumeErr := resumeOrchestration(entityID, runID, client, log); resumeErr != nil {
-						log.Error("Failed to resume ORCHESTRATE run", resumeErr, map[string]interface{}{
-							"entity_id": entityID,
-							"run_id":    runID,
-						})
-					}
-				}(resumed.EntityID, runID)
-			}
-		}
+	// Report agent health in the background
+	if heartbeatInterval > 0 && !caps.Supports(api.FeatureHeartbeats) {
+		log.Info("Server does not support heartbeats; not sending them")
+	} else if heartbeatInterval > 0 {
+		go runHeartbeats(ctx, agents, client, time.Duration(heartbeatInterval)*time.Second, loopStart, gate, heartbeatNow)
+	}
+	if askUser != nil {
+		go askUser.run(ctx)
 	}
 
+	agentIDs := make([]string, 0, len(agents))
+	for _, a := range agents {
+		agentIDs = append(agentIDs, a.AgentID)
+	}
+	for _, a := range agents {
+		a.emitEvent(client, api.AgentEventStarted, "", "")
+	}
+	stopReason := "signal"
+	defer func() {
+		// Only a signal cancels ctx here; let draining precede stopped
+		if ctx.Err() != nil {
+			<-drained
+		}
+		for _, a := range agents {
+			a.emitEvent(shutdownClient, api.AgentEventStopped, "", stopReason)
+		}
+	}()
+
 	log.Info("Loop started", map[string]interface{}{
-		"agent_id":      agentID,
+		"agent_ids":     agentIDs,
 		"poll_interval": pollInterval,
-		"api_url":       apiURL,
+		"poll_jitter":   pollJitter,
+		"idle_exit":     loopIdleExit,
+		"watchdog":      loopWatchdog,
+		"api_url":       opts.APIURL,
 	})
 	log.Flush()
 
 	pollDuration := time.Duration(pollInterval) * time.Second
 	iterationCount := 0
+	next := 0
+	idleStreak := 0
+	// idleRounds counts consecutive rounds where every poll came back empty;
+	// roundFailed marks a round with a failed poll, which doesn't count
+	idleRounds := 0
+	roundFailed := false
 
 	// Main loop
 	for {
@@ -163,63 +627,127 @@ func runLoop(cmd *cobra.Command, args []string) error {
 		default:
 		}
 
+		// Only sleep once every agent has been polled without finding work
+		if idleStreak >= len(agents) {
+			idleStreak = 0
+			if roundFailed {
+				idleRounds = 0
+			} else {
+				idleRounds++
+			}
+			roundFailed = false
+			if loopIdleExit > 0 && idleRounds >= loopIdleExit {
+				stopReason = "idle_exit"
+				log.Info("Idle limit reached, exiting", map[string]interface{}{
+					"idle_polls": idleRounds,
+					"iterations": iterationCount,
+				})
+				return nil
+			}
+			if sleepWithContext(ctx, jitter(pollDuration, pollJitter)) {
+				return nil
+			}
+			continue
+		}
+
+		// Don't claim work the machine cannot finish; wait for resources
+		reason, changed := gate.check()
+		if changed {
+			if reason != "" {
+				log.Warn("Low resources, not claiming tasks", map[string]interface{}{
+					"reason": reason,
+				})
+			} else {
+				log.Info("Resources recovered, claiming tasks again")
+			}
+			select {
+			case heartbeatNow <- struct{}{}:
+			default:
+			}
+		}
+		if reason != "" {
+			idleRounds = 0
+			if sleepWithContext(ctx, jitter(pollDuration, pollJitter)) {
+				return nil
+			}
+			continue
+		}
+
+		// Round-robin: each pass serves the next agent in turn
+		agent := agents[next]
+		next = (next + 1) % len(agents)
+		alog := agent.log
+
 		iterationCount++
 
 		// Fetch next task
-		nextResp, err := client.FetchNextTask(agentID, serviceKey)
+		nextResp, err := client.FetchNextTask(agent.AgentID, agent.ServiceKey)
 		if err != nil {
-			log.Error("Failed to fetch next task", err, map[string]interface{}{
+			alog.Error("Failed to fetch next task", err, map[string]interface{}{
 				"iteration": iterationCount,
 			})
-			if sleepWithContext(ctx, pollDuration) {
-				return nil
-			}
+			idleStreak++
+			roundFailed = true
 			continue
 		}
 
-		// No task available — sleep
+		// No task available for this agent — move on to the next one
 		if nextResp.Task == nil {
-			log.Debug("No runnable tasks, sleeping", map[string]interface{}{
+			alog.Debug("No runnable tasks", map[string]interface{}{
 				"poll_interval_s": pollInterval,
 				"pending_count":   nextResp.PendingCount,
 				"iteration":       iterationCount,
 			})
-			if sleepWithContext(ctx, pollDuration) {
-				return nil
+			if !agent.idle {
+				agent.idle = true
+				agent.emitEvent(client, api.AgentEventIdle, "", "")
 			}
+			idleStreak++
 			continue
 		}
+		idleStreak = 0
+		idleRounds = 0
+		roundFailed = false
 
 		// Execute task
 		task := nextResp.Task
-		log.Info("Executing task", map[string]interface{}{
+		alog.Info("Executing task", map[string]interface{}{
 			"task_id":        task.ID,
 			"task_title":     task.Title,
 			"execution_mode": task.ExecutionMode,
 			"iteration":      iterationCount,
 		})
 
-		success, err := executeEntity(EntityExecutionParams{
-			EntityID:   task.ID,
-			AgentID:    agentID,
-			ServiceKey: serviceKey,
-			Client:     client,
-			Log:        log,
+		agent.idle = false
+		agent.emitEvent(client, api.AgentEventTaskClaimed, task.ID, "")
+		agent.setCurrentTask(task.ID)
+		success, err := executeEntitySafely(EntityExecutionParams{
+			EntityID:    task.ID,
+			AgentID:     agent.AgentID,
+			ServiceKey:  agent.ServiceKey,
+			Client:      client,
+			Log:         alog,
+			TriggeredBy: "agent-loop",
+			Settings:    settings,
 		})
+		agent.setCurrentTask("")
 
 		if err != nil {
 			if errors.Is(err, ErrAskUserSkipped) {
-				log.Info("ASK_USER task started, continuing to next task", map[string]interface{}{
+				alog.Info("ASK_USER task started, continuing to next task", map[string]interface{}{
 					"task_id": task.ID,
 				})
+				if askUser != nil {
+					askUser.track(agent, task)
+				}
 			} else {
-				log.Error("Task execution error", err, map[string]interface{}{
+				alog.Error("Task execution error", err, map[string]interface{}{
 					"task_id": task.ID,
 				})
 			}
 			// Don't exit — continue loop
 		} else {
-			log.Info("Task completed", map[string]interface{}{
+			alog.Info("Task completed", map[string]interface{}{
 				"task_id": task.ID,
 				"success": success,
 			})
@@ -232,6 +760,137 @@ func runLoop(cmd *cobra.Command, args []string) error {
 	}
 }
 
+// recoverAgentRuns recovers RUNNING runs left behind by a previous loop
+// instance for one agent and resumes its ORCHESTRATE runs in the background
+// under settings. Failures are logged and otherwise ignored so loop startup
+// can continue.
+func recoverAgentRuns(agent *loopAgent, client *api.Client, settings executionSettings) {
+	log := agent.log
+
+	log.Info("Recovering runs from previous loop instance")
+	recoverResp, err := client.RecoverRuns(agent.AgentID, agent.ServiceKey)
+	if err != nil {
+		log.Error("Failed to recover runs", err)
+		// Non-fatal — continue loop startup
+		return
+	}
+
+	log.Info("Run recovery complete", map[string]interface{}{
+		"resumed_count":    len(recoverResp.ResumedRuns),
+		"failed_count":     recoverResp.FailedCount,
+		"skipped_ask_user": recoverResp.SkippedAskUser,
+	})
+
+	// Resume ORCHESTRATE runs in background goroutines
+	for _, resumed := range recoverResp.ResumedRuns {
+		if resumed.ExecutionMode != string(api.ExecutionModeOrchestrate) {
+			continue
+		}
+		runID := resumed.RunID
+		if _, loaded := activeResumes.LoadOrStore(runID, true); loaded {
+			log.Info("Resume already active, skipping", map[string]interface{}{
+				"run_id": runID,
+			})
+			continue
+		}
+		go func(entityID, runID string) {
+			defer activeResumes.Delete(runID)
+			defer func() {
+				// A panic here would bypass Execute's handler and kill the loop
+				if r := recover(); r != nil {
+					handlePanic(r, debug.Stack(), log, entityID)
+				}
+			}()
+			parent := EntityExecutionParams{
+				EntityID:   entityID,
+				AgentID:    agent.AgentID,
+				ServiceKey: agent.ServiceKey,
+				Client:     client,
+				Log:        log,
+				Settings:   settings,
+			}
+			if resumeErr := resumeOrchestration(parent, runID); resumeErr != nil {
+				log.Error("Failed to resume ORCHESTRATE run", resumeErr, map[string]interface{}{
+					"entity_id": entityID,
+					"run_id":    runID,
+				})
+			}
+		}(resumed.EntityID, runID)
+	}
+}
+
+// runHeartbeats periodically reports status for every agent until ctx is
+// cancelled, and right away when woken through now. Idle agents are
+// reported as degraded while gate holds them back. Runtimes are probed once
+// since PATH does not change while the loop is running. Heartbeat failures
+// are logged and never stop the loop.
+func runHeartbeats(ctx context.Context, agents []*loopAgent, client *api.Client, interval time.Duration, loopStart time.Time, gate *resourceGate, now <-chan struct{}) {
+	hostname, _ := os.Hostname()
+	runtimes := sysinfo.AvailableRuntimes()
+
+	send := func() {
+		var freeDisk *uint64
+		if free, err := sysinfo.FreeDiskBytes(gate.workDir); err == nil {
+			freeDisk = &free
+		}
+		var freeMemory *uint64
+		if available, err := sysinfo.AvailableMemoryBytes(); err == nil {
+			freeMemory = &available
+		}
+		degraded := gate.degradedReason()
+		for _, a := range agents {
+			status, reason := "idle", ""
+			taskID := a.currentTask()
+			if taskID != "" {
+				status = "busy"
+			} else if degraded != "" {
+				status, reason = "degraded", degraded
+			}
+			_, err := client.SendHeartbeat(api.HeartbeatRequest{
+				AgentID:              a.AgentID,
+				CLIVersion:           Version,
+				Platform:             sysinfo.Platform(),
+				Hostname:             hostname,
+				UptimeSeconds:        int64(time.Since(loopStart).Seconds()),
+				FreeDiskBytes:        freeDisk,
+				Runtimes:             runtimes,
+				CurrentTaskID:        taskID,
+				Status:               status,
+				AvailableMemoryBytes: freeMemory,
+				DegradedReason:       reason,
+			}, a.ServiceKey)
+			if err != nil {
+				a.log.Warn("Failed to send heartbeat", map[string]interface{}{
+					"error": err.Error(),
+				})
+			}
+		}
+	}
+
+	send()
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			send()
+		case <-now:
+			send()
+		}
+	}
+}
+
+// jitter returns d randomized by up to percent of d in either direction
+func jitter(d time.Duration, percent int) time.Duration {
+	spread := int64(d) * int64(percent) / 100
+	if spread <= 0 {
+		return d
+	}
+	return d - time.Duration(spread) + time.Duration(rand.Int63n(2*spread+1))
+}
+
 // sleepWithContext sleeps for the given duration but returns early if the
 // context is cancelled. Returns true if context was cancelled.
 func sleepWithContext(ctx context.Context, d time.Duration) bool {
@@ -244,4 +903,3 @@ func sleepWithContext(ctx context.Context, d time.Duration) bool {
 		return false
 	}
 }
-
@@ -0,0 +1,93 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/kindship-ai/kindship-cli/internal/api"
+	"github.com/kindship-ai/kindship-cli/internal/config"
+
+	"github.com/spf13/cobra"
+)
+
+// apiOptions holds the agent ID, service key, API URL and verbosity a
+// command talks to the API with. Each command family binds its flags to its
+// own apiOptions and passes it down explicitly, so flags given to one command
+// never leak into another and concurrent executions share no flag state.
+type apiOptions struct {
+	AgentID    string
+	ServiceKey string
+	APIURL     string
+	Verbose    bool
+}
+
+// Flags of each command family that uses a service key. Commands never
+// modify these; they resolve a copy per invocation.
+var (
+	runOpts       apiOptions // kindship run
+	runTaskOpts   apiOptions // kindship run next/start/complete/fail
+	loopOpts      apiOptions // kindship agent loop
+	entityOpts    apiOptions // kindship entity ...
+	envOpts       apiOptions // kindship env
+	workspaceOpts apiOptions // kindship workspace pull/push
+)
+
+// addServiceKeyFlags registers --service-key, --api-url and --verbose on
+// cmd, bound to opts
+func addServiceKeyFlags(cmd *cobra.Command, opts *apiOptions) {
+	cmd.Flags().StringVar(&opts.ServiceKey, "service-key", "", "Service key for authentication (defaults to KINDSHIP_SERVICE_KEY env var)")
+	cmd.Flags().StringVar(&opts.APIURL, "api-url", "", "API base URL (defaults to KINDSHIP_API_URL env var or https://kindship.ai)")
+	cmd.Flags().BoolVarP(&opts.Verbose, "verbose", "v", false, "Enable verbose logging")
+}
+
+// resolved returns a copy of o with the agent ID, service key and API URL
+// filled from the environment when they were not given as flags
+func (o apiOptions) resolved() *apiOptions {
+	if o.AgentID == "" {
+		o.AgentID = os.Getenv("AGENT_ID")
+	}
+	if o.ServiceKey == "" {
+		o.ServiceKey = os.Getenv("KINDSHIP_SERVICE_KEY")
+	}
+	if o.APIURL == "" {
+		o.APIURL = os.Getenv("KINDSHIP_API_URL")
+	}
+	if o.APIURL == "" {
+		o.APIURL = config.DefaultAPIBaseURL()
+	}
+	return &o
+}
+
+// requireServiceKey checks that a service key is set
+func (o *apiOptions) requireServiceKey() error {
+	if o.ServiceKey == "" {
+		return withExitCode(ExitAuth, fmt.Errorf("KINDSHIP_SERVICE_KEY is required (use --service-key flag or KINDSHIP_SERVICE_KEY environment variable)"))
+	}
+	return nil
+}
+
+// requireCredentials checks that an agent ID and service key are set
+func (o *apiOptions) requireCredentials() error {
+	if o.AgentID == "" {
+		return withExitCode(ExitUsage, fmt.Errorf("AGENT_ID is required (use --agent-id flag or AGENT_ID environment variable)"))
+	}
+	return o.requireServiceKey()
+}
+
+// client returns an API client for the resolved API URL
+func (o *apiOptions) client() *api.Client {
+	return api.NewClient(o.APIURL, o.Verbose)
+}
+
+// defaultAPIBaseURL is the API base URL of commands without --api-url:
+// KINDSHIP_API_URL, else the URL saved at login, else production
+func defaultAPIBaseURL() string {
+	if url := os.Getenv("KINDSHIP_API_URL"); url != "" {
+		return url
+	}
+	cfg, _ := config.LoadGlobalConfig()
+	if cfg == nil {
+		cfg = &config.GlobalConfig{}
+	}
+	return cfg.GetAPIBaseURL()
+}
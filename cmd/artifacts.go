@@ -0,0 +1,341 @@
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"io/fs"
+	"mime"
+	"net/http"
+	"os"
+	"path/filepath"
+	"sort"
+
+	"github.com/kindship-ai/kindship-cli/internal/api"
+	"github.com/kindship-ai/kindship-cli/internal/boundaries"
+	"github.com/kindship-ai/kindship-cli/internal/checksum"
+	"github.com/kindship-ai/kindship-cli/internal/executor"
+)
+
+// artifactChunkThreshold is the size above which an artifact is uploaded
+// in chunks, when the server supports it
+const artifactChunkThreshold = 8 << 20
+
+// artifactPartAttempts is how many times a chunk is sent before the upload
+// is given up
+const artifactPartAttempts = 3
+
+// artifactFile is a workspace file declared by boundaries.artifacts
+type artifactFile struct {
+	// name is the path relative to the workspace, with forward slashes
+	name string
+	path string
+	size int64
+}
+
+// artifactUploader uploads the artifacts of one attempt and keeps their
+// total under the run's cap
+type artifactUploader struct {
+	params      EntityExecutionParams
+	executionID string
+	maxTotal    int64
+	uploaded    int64
+}
+
+func newArtifactUploader(params EntityExecutionParams, executionID string, policy *boundaries.ArtifactPolicy) *artifactUploader {
+	return &artifactUploader{
+		params:      params,
+		executionID: executionID,
+		maxTotal:    policy.MaxTotalBytes(),
+	}
+}
+
+// uploadTranscript attaches an LLM transcript to the run as an artifact.
+// Upload failures are logged but never fail the execution itself.
+func (u *artifactUploader) uploadTranscript(transcript []byte, outputs *api.ExecutionOutputs) {
+	log := u.params.Log
+	if !serverCapabilities(u.params.Client, u.params.ServiceKey, log).Supports(api.FeatureArtifacts) {
+		log.Info("Server does not support artifacts; LLM transcript not uploaded")
+		return
+	}
+	size := int64(len(transcript))
+	if u.uploaded+size > u.maxTotal {
+		log.Warn("LLM transcript not uploaded: artifact size cap reached", map[string]interface{}{
+			"bytes":     size,
+			"cap_bytes": u.maxTotal,
+		})
+		return
+	}
+	uploadResp, err := u.params.Client.UploadArtifact(u.executionID, executor.TranscriptArtifactName, executor.TranscriptContentType, transcript, u.params.ServiceKey)
+	if err != nil {
+		log.Warn("Failed to upload LLM transcript", map[string]interface{}{
+			"error": err.Error(),
+			"bytes": len(transcript),
+		})
+		return
+	}
+	u.record(outputs, uploadResp, executor.TranscriptArtifactName, executor.TranscriptContentType, size, checksum.SHA256(transcript))
+	log.Info("Uploaded LLM transcript", map[string]interface{}{
+		"artifact_id": uploadResp.ID,
+		"bytes":       len(transcript),
+	})
+}
+
+// uploadFiles uploads the files matched by boundaries.artifacts.paths.
+// Unlike the transcript, declared artifacts are part of the task's result:
+// exceeding the cap or failing an upload fails the attempt. The total is
+// checked before anything is sent.
+func (u *artifactUploader) uploadFiles(patterns []string, outputs *api.ExecutionOutputs) error {
+	files, err := collectArtifactFiles(u.params.Settings.Exec.Dir(), patterns)
+	if err != nil {
+		return err
+	}
+	if len(files) == 0 {
+		u.params.Log.Warn("No files matched boundaries.artifacts.paths", map[string]interface{}{
+			"paths": patterns,
+		})
+		return nil
+	}
+	if !serverCapabilities(u.params.Client, u.params.ServiceKey, u.params.Log).Supports(api.FeatureArtifacts) {
+		return fmt.Errorf("server does not support artifacts; cannot upload boundaries.artifacts")
+	}
+
+	var total int64
+	for _, f := range files {
+		total += f.size
+	}
+	if u.uploaded+total > u.maxTotal {
+		return fmt.Errorf("artifacts total %d bytes (%d files) exceeds the run's cap of %s; raise boundaries.artifacts.max_total_mb or narrow boundaries.artifacts.paths",
+			u.uploaded+total, len(files), formatMB(uint64(u.maxTotal)))
+	}
+
+	chunked := serverCapabilities(u.params.Client, u.params.ServiceKey, u.params.Log).Supports(api.FeatureArtifactChunks)
+	for _, f := range files {
+		if err := u.uploadFile(f, chunked, outputs); err != nil {
+			return fmt.Errorf("failed to upload artifact %s: %w", f.name, err)
+		}
+	}
+	return nil
+}
+
+// uploadFile uploads one file, in chunks when it is large and the server
+// supports it
+func (u *artifactUploader) uploadFile(f artifactFile, chunked bool, outputs *api.ExecutionOutputs) error {
+	contentType, err := detectContentType(f.path)
+	if err != nil {
+		return err
+	}
+
+	var (
+		uploadResp *api.ArtifactUploadResponse
+		digest     string
+	)
+	if chunked && f.size > artifactChunkThreshold {
+		digest, err = checksum.SHA256File(f.path)
+		if err != nil {
+			return err
+		}
+		uploadResp, err = u.uploadChunked(f, contentType, digest)
+	} else {
+		var data []byte
+		data, err = os.ReadFile(f.path)
+		if err != nil {
+			return err
+		}
+		digest = checksum.SHA256(data)
+		uploadResp, err = u.params.Client.UploadArtifact(u.executionID, f.name, contentType, data, u.params.ServiceKey)
+	}
+	if err != nil {
+		return err
+	}
+
+	u.record(outputs, uploadResp, f.name, contentType, f.size, digest)
+	u.params.Log.Info("Uploaded artifact", map[string]interface{}{
+		"name":        f.name,
+		"artifact_id": uploadResp.ID,
+		"bytes":       f.size,
+	})
+	return nil
+}
+
+// uploadChunked streams a file to the server in the parts it asks for,
+// retrying each part a few times
+func (u *artifactUploader) uploadChunked(f artifactFile, contentType, checksum string) (*api.ArtifactUploadResponse, error) {
+	client, key := u.params.Client, u.params.ServiceKey
+	session, err := client.StartArtifactUpload(u.executionID, api.ArtifactUploadStartRequest{
+		Name:        f.name,
+		ContentType: contentType,
+		Size:        f.size,
+		SHA256:      checksum,
+	}, key)
+	if err != nil {
+		return nil, err
+	}
+
+	file, err := os.Open(f.path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	buf := make([]byte, session.ChunkSize)
+	parts := 0
+	for {
+		n, readErr := io.ReadFull(file, buf)
+		if n > 0 {
+			parts++
+			for attempt := 1; ; attempt++ {
+				err = client.UploadArtifactPart(u.executionID, session.UploadID, parts, buf[:n], key)
+				if err == nil {
+					break
+				}
+				if attempt == artifactPartAttempts {
+					return nil, fmt.Errorf("part %d: %w", parts, err)
+				}
+				u.params.Log.Warn("Retrying artifact part", map[string]interface{}{
+					"name":  f.name,
+					"part":  parts,
+					"error": err.Error(),
+				})
+			}
+		}
+		if readErr == io.EOF || readErr == io.ErrUnexpectedEOF {
+			break
+		}
+		if readErr != nil {
+			return nil, readErr
+		}
+	}
+
+	return client.CompleteArtifactUpload(u.executionID, session.UploadID, api.ArtifactUploadCompleteRequest{
+		Parts:  parts,
+		SHA256: checksum,
+	}, key)
+}
+
+// record adds an uploaded artifact to the outputs and counts it against
+// the cap
+func (u *artifactUploader) record(outputs *api.ExecutionOutputs, resp *api.ArtifactUploadResponse, name, contentType string, size int64, checksum string) {
+	u.uploaded += size
+	ref := resp.URL
+	if ref == "" {
+		ref = resp.ID
+	}
+	outputs.Artifacts = append(outputs.Artifacts, ref)
+	outputs.Files = append(outputs.Files, api.ArtifactFile{
+		Name:        name,
+		ID:          resp.ID,
+		URL:         resp.URL,
+		ContentType: contentType,
+		Size:        size,
+		SHA256:      checksum,
+	})
+}
+
+// collectArtifactFiles expands the artifact patterns against workspace
+// into the regular files they name, walking matched directories. A match
+// that leaves the workspace through a symlink is an error.
+func collectArtifactFiles(workspace string, patterns []string) ([]artifactFile, error) {
+	seen := map[string]bool{}
+	var files []artifactFile
+	add := func(path string, info fs.FileInfo) error {
+		rel, err := filepath.Rel(workspace, path)
+		if err != nil {
+			return err
+		}
+		name := filepath.ToSlash(rel)
+		if seen[name] {
+			return nil
+		}
+		inside, err := boundaries.ResolvesInside(workspace, path)
+		if err != nil {
+			return err
+		}
+		if !inside {
+			return fmt.Errorf("artifact %s resolves outside the workspace", name)
+		}
+		seen[name] = true
+		files = append(files, artifactFile{name: name, path: path, size: info.Size()})
+		return nil
+	}
+
+	for _, pattern := range patterns {
+		matches, err := filepath.Glob(filepath.Join(workspace, filepath.FromSlash(pattern)))
+		if err != nil {
+			return nil, fmt.Errorf("invalid artifact pattern %q: %w", pattern, err)
+		}
+		for _, match := range matches {
+			info, err := os.Stat(match)
+			if err != nil {
+				return nil, err
+			}
+			if !info.IsDir() {
+				if info.Mode().IsRegular() {
+					if err := add(match, info); err != nil {
+						return nil, err
+					}
+				}
+				continue
+			}
+			err = filepath.WalkDir(match, func(path string, d fs.DirEntry, err error) error {
+				if err != nil {
+					return err
+				}
+				if !d.Type().IsRegular() {
+					return nil
+				}
+				info, err := d.Info()
+				if err != nil {
+					return err
+				}
+				return add(path, info)
+			})
+			if err != nil {
+				return nil, err
+			}
+		}
+	}
+	sort.Slice(files, func(i, j int) bool { return files[i].name < files[j].name })
+	return files, nil
+}
+
+// detectContentType guesses a file's content type from its extension, or
+// from its first bytes when the extension is unknown
+func detectContentType(path string) (string, error) {
+	if contentType := mime.TypeByExtension(filepath.Ext(path)); contentType != "" {
+		return contentType, nil
+	}
+	file, err := os.Open(path)
+	if err != nil {
+		return "", err
+	}
+	defer file.Close()
+	head := make([]byte, 512)
+	n, err := io.ReadFull(file, head)
+	if err != nil && err != io.EOF && err != io.ErrUnexpectedEOF {
+		return "", err
+	}
+	return http.DetectContentType(head[:n]), nil
+}
+
+// failArtifacts records a failed artifact upload on the completion
+// request. A successful attempt becomes FAILED, since the artifacts it
+// declared are part of its result.
+func failArtifacts(completeReq *api.ExecutionCompleteRequest, policy *boundaries.ArtifactPolicy, err error) {
+	reason := err.Error()
+	if completeReq.Status == api.ExecutionAttemptStatusSuccess {
+		completeReq.Status = api.ExecutionAttemptStatusFailed
+		failureMsg := fmt.Sprintf("Artifact upload failed: %s", reason)
+		completeReq.FailureReason = &failureMsg
+	}
+	completeReq.ValidationRecords = append(completeReq.ValidationRecords, api.ValidationRecord{
+		ValidationType: "ARTIFACTS",
+		Outcome:        api.ValidationOutcomeFail,
+		Severity:       api.ValidationSeverityCritical,
+		Target:         "boundaries.artifacts",
+		Actual: map[string]interface{}{
+			"paths":     policy.Paths,
+			"cap_bytes": policy.MaxTotalBytes(),
+		},
+		FailureReason: &reason,
+	})
+}
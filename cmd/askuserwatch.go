@@ -0,0 +1,244 @@
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/kindship-ai/kindship-cli/internal/api"
+	"github.com/kindship-ai/kindship-cli/internal/logging"
+)
+
+// ASK_USER reminders of the agent loop, set by --ask-user-remind-after,
+// --ask-user-webhook and --ask-user-escalate
+var (
+	askUserRemindAfter int
+	askUserWebhook     string
+	askUserEscalate    bool
+)
+
+// askUserCheckInterval is how often the loop checks the ASK_USER questions
+// it started
+var askUserCheckInterval = time.Minute
+
+// AskUserReminder is posted to --ask-user-webhook for a question that has
+// waited too long. Text makes it usable as a Slack incoming webhook
+// payload as is.
+type AskUserReminder struct {
+	Text           string    `json:"text"`
+	Event          string    `json:"event"`
+	AgentID        string    `json:"agent_id"`
+	EntityID       string    `json:"entity_id"`
+	ExecutionID    string    `json:"execution_id"`
+	Title          string    `json:"title"`
+	StartedAt      time.Time `json:"started_at"`
+	WaitingSeconds int64     `json:"waiting_seconds"`
+	Reminder       int       `json:"reminder"`
+}
+
+// askUserQuestion is an ASK_USER execution started by the loop
+type askUserQuestion struct {
+	agent       *loopAgent
+	entityID    string
+	title       string
+	executionID string
+	startedAt   time.Time
+	// reminders counts the reminders sent so far
+	reminders int
+}
+
+// askUserWatch tracks the ASK_USER executions the loop started and, each
+// time one has waited another remindAfter without an answer, logs a
+// reminder, posts it to the webhook and asks the API to raise the
+// question's priority, so human-gated Processes do not stall unnoticed.
+type askUserWatch struct {
+	remindAfter time.Duration
+	webhookURL  string
+	escalate    bool
+	client      *api.Client
+	log         *logging.Logger
+
+	mu      sync.Mutex
+	waiting map[string]*askUserQuestion
+}
+
+// newAskUserWatch validates the ASK_USER reminder flags and returns the
+// watch they configure, or nil when reminders are off
+func newAskUserWatch(client *api.Client, log *logging.Logger) (*askUserWatch, error) {
+	webhookURL := askUserWebhook
+	if webhookURL == "" {
+		webhookURL = os.Getenv("KINDSHIP_ASK_USER_WEBHOOK")
+	}
+	if askUserRemindAfter < 0 {
+		return nil, withExitCode(ExitUsage, fmt.Errorf("--ask-user-remind-after must not be negative"))
+	}
+	if askUserRemindAfter == 0 {
+		if askUserWebhook != "" || askUserEscalate {
+			return nil, withExitCode(ExitUsage, fmt.Errorf("--ask-user-webhook and --ask-user-escalate require --ask-user-remind-after"))
+		}
+		return nil, nil
+	}
+	return &askUserWatch{
+		remindAfter: time.Duration(askUserRemindAfter) * time.Minute,
+		webhookURL:  webhookURL,
+		escalate:    askUserEscalate,
+		client:      client,
+		log:         log,
+		waiting:     map[string]*askUserQuestion{},
+	}, nil
+}
+
+// track starts watching an ASK_USER task the agent just started
+func (w *askUserWatch) track(agent *loopAgent, task *api.TaskInfo) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	w.waiting[task.ID] = &askUserQuestion{
+		agent:     agent,
+		entityID:  task.ID,
+		title:     task.Title,
+		startedAt: time.Now(),
+	}
+}
+
+// run checks the tracked questions every askUserCheckInterval until ctx
+// is cancelled
+func (w *askUserWatch) run(ctx context.Context) {
+	ticker := time.NewTicker(askUserCheckInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			w.check(time.Now())
+		}
+	}
+}
+
+// check stops tracking answered questions and reminds about those due.
+// Failures are logged and never stop the loop.
+func (w *askUserWatch) check(now time.Time) {
+	w.mu.Lock()
+	questions := make([]*askUserQuestion, 0, len(w.waiting))
+	for _, q := range w.waiting {
+		questions = append(questions, q)
+	}
+	w.mu.Unlock()
+
+	for _, q := range questions {
+		resp, err := w.client.ListAttempts(q.entityID, q.agent.ServiceKey)
+		if err != nil {
+			q.agent.log.Warn("Failed to check ASK_USER task", map[string]interface{}{
+				"task_id": q.entityID,
+				"error":   err.Error(),
+			})
+			continue
+		}
+		attempt := latestAskUserAttempt(resp.Attempts)
+		if attempt == nil || attempt.Status != api.ExecutionAttemptStatusRunning {
+			w.mu.Lock()
+			delete(w.waiting, q.entityID)
+			w.mu.Unlock()
+			continue
+		}
+		q.executionID = attempt.ID
+		q.startedAt = attempt.StartedAt
+
+		waiting := now.Sub(q.startedAt)
+		if waiting < w.remindAfter*time.Duration(q.reminders+1) {
+			continue
+		}
+		q.reminders++
+		w.remind(q, waiting)
+	}
+}
+
+// remind sends one reminder about a question that has waited too long
+func (w *askUserWatch) remind(q *askUserQuestion, waiting time.Duration) {
+	log := q.agent.log
+	log.Warn("ASK_USER task is still waiting for an answer", map[string]interface{}{
+		"task_id":      q.entityID,
+		"execution_id": q.executionID,
+		"waiting":      formatWaiting(waiting),
+		"reminder":     q.reminders,
+	})
+
+	if w.webhookURL != "" {
+		reminder := AskUserReminder{
+			Text: fmt.Sprintf("Kindship question %q has been waiting for an answer for %s (reminder %d)",
+				q.title, formatWaiting(waiting), q.reminders),
+			Event:          "ask_user_stale",
+			AgentID:        q.agent.AgentID,
+			EntityID:       q.entityID,
+			ExecutionID:    q.executionID,
+			Title:          q.title,
+			StartedAt:      q.startedAt.UTC(),
+			WaitingSeconds: int64(waiting.Seconds()),
+			Reminder:       q.reminders,
+		}
+		data, err := json.Marshal(reminder)
+		if err == nil {
+			err = postWebhook(w.webhookURL, data)
+		}
+		if err != nil {
+			log.Warn("Failed to send ASK_USER reminder", map[string]interface{}{
+				"task_id": q.entityID,
+				"error":   err.Error(),
+			})
+		}
+	}
+
+	if w.escalate {
+		resp, err := w.client.EscalateExecution(q.executionID, api.EscalationRequest{
+			AgentID:        q.agent.AgentID,
+			EntityID:       q.entityID,
+			WaitingSeconds: int64(waiting.Seconds()),
+			Reminder:       q.reminders,
+			Reason:         fmt.Sprintf("unanswered for %s", formatWaiting(waiting)),
+		}, q.agent.ServiceKey)
+		if err != nil {
+			log.Warn("Failed to escalate ASK_USER task", map[string]interface{}{
+				"task_id": q.entityID,
+				"error":   err.Error(),
+			})
+			return
+		}
+		log.Info("Escalated ASK_USER task", map[string]interface{}{
+			"task_id":  q.entityID,
+			"priority": resp.Priority,
+		})
+	}
+}
+
+// latestAskUserAttempt returns the ASK_USER attempt with the highest
+// number, or nil
+func latestAskUserAttempt(attempts []api.ExecutionAttempt) *api.ExecutionAttempt {
+	var latest *api.ExecutionAttempt
+	for i := range attempts {
+		if attempts[i].ExecutionMode == api.ExecutionModeAskUser &&
+			(latest == nil || attempts[i].AttemptNumber > latest.AttemptNumber) {
+			latest = &attempts[i]
+		}
+	}
+	return latest
+}
+
+// formatWaiting renders how long a question has waited in days, hours
+// and minutes
+func formatWaiting(d time.Duration) string {
+	d = d.Round(time.Minute)
+	days := int(d / (24 * time.Hour))
+	hours := int(d % (24 * time.Hour) / time.Hour)
+	minutes := int(d % time.Hour / time.Minute)
+	switch {
+	case days > 0:
+		return fmt.Sprintf("%dd %dh", days, hours)
+	case hours > 0:
+		return fmt.Sprintf("%dh %dm", hours, minutes)
+	default:
+		return fmt.Sprintf("%dm", minutes)
+	}
+}
@@ -0,0 +1,74 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/kindship-ai/kindship-cli/internal/audit"
+
+	"github.com/spf13/cobra"
+)
+
+var auditCmd = &cobra.Command{
+	Use:   "audit",
+	Short: "Local execution audit log commands",
+	Long: `Commands for the local execution audit log.
+
+Every execution attempt is appended to audit.jsonl in the state dir (override with
+KINDSHIP_AUDIT_LOG, or set it to "off" to disable). When KINDSHIP_AUDIT_HMAC_KEY
+is set, entries are HMAC-chained so tampering can be detected.
+
+Subcommands:
+  verify   Verify the HMAC chain of the audit log`,
+}
+
+var auditVerifyCmd = &cobra.Command{
+	Use:   "verify",
+	Short: "Verify the audit log HMAC chain",
+	Long: `Verify that no entry in the audit log has been modified, removed or
+reordered. Requires KINDSHIP_AUDIT_HMAC_KEY to be set to the signing key.
+
+Examples:
+  kindship audit verify
+  kindship audit verify --file /var/log/kindship/audit.jsonl`,
+	Args: cobra.NoArgs,
+	RunE: runAuditVerify,
+}
+
+var (
+	auditFile string
+)
+
+func init() {
+	auditVerifyCmd.Flags().StringVar(&auditFile, "file", "", "Audit log path (defaults to KINDSHIP_AUDIT_LOG or audit.jsonl in the state dir)")
+
+	auditCmd.AddCommand(auditVerifyCmd)
+	rootCmd.AddCommand(auditCmd)
+}
+
+func runAuditVerify(cmd *cobra.Command, args []string) error {
+	key := os.Getenv("KINDSHIP_AUDIT_HMAC_KEY")
+	if key == "" {
+		return fmt.Errorf("KINDSHIP_AUDIT_HMAC_KEY is required to verify the audit log")
+	}
+
+	path := auditFile
+	if path == "" {
+		var err error
+		path, err = audit.Path()
+		if err != nil {
+			return err
+		}
+		if path == "" {
+			return fmt.Errorf("audit log is disabled (KINDSHIP_AUDIT_LOG=off)")
+		}
+	}
+
+	count, err := audit.Verify(path, key)
+	if err != nil {
+		return fmt.Errorf("audit log verification failed after %d valid entries: %w", count, err)
+	}
+
+	fmt.Printf("✓ Verified %d audit entries in %s\n", count, path)
+	return nil
+}
@@ -4,15 +4,18 @@ import (
 	"fmt"
 	"os"
 	"os/exec"
-	"syscall"
 	"time"
 
 	"github.com/kindship-ai/kindship-cli/internal/api"
+	"github.com/kindship-ai/kindship-cli/internal/config"
 	"github.com/kindship-ai/kindship-cli/internal/logging"
 	"github.com/spf13/cobra"
 )
 
-var verbose bool
+var (
+	authVerbose    bool
+	authSubprocess bool
+)
 
 var authCmd = &cobra.Command{
 	Use:   "auth <command> [args...]",
@@ -23,11 +26,26 @@ with those secrets injected as environment variables.
 The command reads AGENT_ID and KINDSHIP_SERVICE_KEY from environment variables
 to authenticate with the Kindship API.
 
+By default the CLI process is replaced by the command. With --subprocess the
+command runs as a child instead: signals are forwarded to it and its exit
+code is propagated exactly (128+n if killed by signal n). Subprocess mode is
+used automatically on Windows and when running as PID 1 in a container.
+
+With --refresh-retries N the command is supervised: when it fails with an
+authentication error (an exit code listed in --auth-exit-codes, or output
+matching an --auth-failure-pattern), the secrets are fetched again and the
+command is re-run, up to N times. This recovers from credentials rotated
+mid-session. The command's output then goes through a pipe rather than
+straight to the terminal. Default patterns include "unauthorized",
+"invalid api key" and "token expired" (case-insensitive).
+
 Example:
   kindship auth claude -p "what is 2+2"     # Claude headless mode
   kindship auth codex "fix this bug"
   kindship auth gemini "explain this code"
-  kindship auth -v claude -p "debug mode"   # verbose logging`,
+  kindship auth -v claude -p "debug mode"   # verbose logging
+  kindship auth --subprocess npm publish    # supervise instead of exec
+  kindship auth --refresh-retries 2 gh pr list  # re-fetch secrets on auth errors`,
 	Args: cobra.MinimumNArgs(1),
 	RunE: runAuth,
 }
@@ -45,11 +63,15 @@ func runAuth(cmd *cobra.Command, args []string) error {
 	command := args[0]
 	commandArgs := args[1:]
 
+	if authRefreshRetries < 0 {
+		return withExitCode(ExitUsage, fmt.Errorf("--refresh-retries must not be negative"))
+	}
+
 	// Read agent ID early so we can initialize logging
 	agentID := os.Getenv("AGENT_ID")
 
 	// Initialize Axiom logging
-	log := logging.Init(agentID, command, verbose)
+	log := logging.Init(agentID, command, authVerbose)
 	defer log.FlushSync() // Ensure logs are sent before exit
 
 	log.Info("Starting auth", map[string]interface{}{
@@ -73,14 +95,14 @@ func runAuth(cmd *cobra.Command, args []string) error {
 
 	apiURL := os.Getenv("KINDSHIP_API_URL")
 	if apiURL == "" {
-		apiURL = "https://kindship.ai"
+		apiURL = config.DefaultAPIBaseURL()
 	}
 	log.Debug("Using API URL", map[string]interface{}{"api_url": apiURL})
 
 	// Fetch secrets from API
 	log.Info("Fetching secrets from API")
 	fetchStart := time.Now()
-	client := api.NewClient(apiURL, verbose)
+	client := api.NewClient(apiURL, authVerbose)
 	secrets, err := client.FetchSecrets(agentID, command, serviceKey)
 	fetchDuration := time.Since(fetchStart)
 
@@ -102,10 +124,7 @@ func runAuth(cmd *cobra.Command, args []string) error {
 	})
 
 	// Build environment with injected secrets
-	env := os.Environ()
-	for key, value := range secrets {
-		env = append(env, fmt.Sprintf("%s=%s", key, value))
-	}
+	env := authEnv(secrets)
 
 	// Find the command executable
 	executable, err := exec.LookPath(command)
@@ -118,26 +137,40 @@ func runAuth(cmd *cobra.Command, args []string) error {
 	}
 	log.Debug("Found executable", map[string]interface{}{"executable": executable})
 
+	subprocess := authSubprocess || authRefreshRetries > 0 || !execAvailable()
+	mode := "exec"
+	if authRefreshRetries > 0 {
+		mode = "supervised"
+	} else if subprocess {
+		mode = "subprocess"
+	}
+
 	// Log final setup
 	setupDuration := time.Since(startTime)
 	log.WithDuration("Setup complete, executing command", setupDuration, map[string]interface{}{
 		"executable": executable,
 		"args":       commandArgs,
+		"mode":       mode,
 	})
 
 	// Flush logs before exec (exec replaces the process)
 	log.FlushSync()
 
-	// Exec the command (replaces the current process)
+	// Exec the command, replacing the current process, or run it as a child
+	// and exit with its exit code. If either returns, an error occurred
 	execArgs := append([]string{command}, commandArgs...)
-
-	// syscall.Exec replaces the current process entirely
-	// If it returns, an error occurred
-	execErr := syscall.Exec(executable, execArgs, env)
+	var execErr error
+	if authRefreshRetries > 0 {
+		execErr = superviseAuth(client, agentID, serviceKey, executable, execArgs, env, log)
+	} else if subprocess {
+		execErr = runSubprocess(executable, execArgs, env)
+	} else {
+		execErr = execCommand(executable, execArgs, env)
+	}
 
 	// If we get here, exec failed - reinitialize logger for error reporting
-	errLog := logging.Init(agentID, command, verbose)
-	errLog.Error("syscall.Exec failed", execErr, map[string]interface{}{
+	errLog := logging.Init(agentID, command, authVerbose)
+	errLog.Error("Exec failed", execErr, map[string]interface{}{
 		"executable": executable,
 		"args":       execArgs,
 	})
@@ -158,7 +191,11 @@ func runAuth(cmd *cobra.Command, args []string) error {
 }
 
 func init() {
-	authCmd.Flags().BoolVarP(&verbose, "verbose", "v", false, "Enable verbose logging for debugging")
+	authCmd.Flags().BoolVarP(&authVerbose, "verbose", "v", false, "Enable verbose logging for debugging")
+	authCmd.Flags().BoolVar(&authSubprocess, "subprocess", false, "Run the command as a child process instead of replacing the CLI")
+	authCmd.Flags().IntVar(&authRefreshRetries, "refresh-retries", 0, "Re-fetch secrets and re-run the command this many times on authentication errors")
+	authCmd.Flags().IntSliceVar(&authFailureExitCodes, "auth-exit-codes", nil, "Exit codes that mean the command's credentials were rejected")
+	authCmd.Flags().StringSliceVar(&authFailurePatterns, "auth-failure-pattern", nil, "Output substrings that mean the command's credentials were rejected (replaces the defaults)")
 	// Stop parsing flags after the first positional argument (the command name)
 	// This allows flags like -p to be passed through to the underlying command
 	authCmd.Flags().SetInterspersed(false)
@@ -0,0 +1,60 @@
+package cmd
+
+import (
+	"io"
+	"os"
+	"os/exec"
+	"os/signal"
+)
+
+// runSubprocess runs executable as a child process with the CLI's stdio,
+// forwards signals to it and exits with its exit code. It only returns if
+// the command could not be started.
+func runSubprocess(executable string, args, env []string) error {
+	state, err := runChild(executable, args, env, nil)
+	if err != nil {
+		return err
+	}
+	os.Exit(exitCodeOf(state))
+	return nil
+}
+
+// runChild runs executable as a child process with the CLI's stdio,
+// forwarding signals to it while it runs (see forwardSignal). The child
+// stays in the CLI's process group so it can use the terminal. When tail is set, stdout and
+// stderr are also copied to it, so the child writes to pipes rather than
+// the terminal.
+func runChild(executable string, args, env []string, tail io.Writer) (*os.ProcessState, error) {
+	cmd := exec.Command(executable, args[1:]...)
+	cmd.Env = env
+	cmd.Stdin = os.Stdin
+	cmd.Stdout = os.Stdout
+	cmd.Stderr = os.Stderr
+	if tail != nil {
+		cmd.Stdout = io.MultiWriter(os.Stdout, tail)
+		cmd.Stderr = io.MultiWriter(os.Stderr, tail)
+	}
+
+	sigCh := make(chan os.Signal, 4)
+	ignoreForwardedSignals(sigCh)
+	defer signal.Stop(sigCh)
+
+	if err := cmd.Start(); err != nil {
+		return nil, err
+	}
+	done := make(chan struct{})
+	defer close(done)
+	go func() {
+		for {
+			select {
+			case sig := <-sigCh:
+				forwardSignal(cmd.Process, sig)
+			case <-done:
+				return
+			}
+		}
+	}()
+
+	cmd.Wait()
+	return cmd.ProcessState, nil
+}
@@ -0,0 +1,66 @@
+//go:build !windows
+
+package cmd
+
+import (
+	"os"
+	"os/signal"
+	"syscall"
+	"unsafe"
+)
+
+// execAvailable reports whether the target command can replace the CLI
+// process. As PID 1 in a container it should not: the kernel ignores
+// signals PID 1 has no handler for, so most tools would not stop on
+// SIGTERM.
+func execAvailable() bool {
+	return os.Getpid() != 1
+}
+
+// execCommand replaces the current process with executable. It only
+// returns on failure.
+func execCommand(executable string, args, env []string) error {
+	return syscall.Exec(executable, args, env)
+}
+
+// ignoreForwardedSignals delivers the signals a supervised child should
+// receive to sigCh instead of acting on them
+func ignoreForwardedSignals(sigCh chan os.Signal) {
+	signal.Notify(sigCh, syscall.SIGINT, syscall.SIGTERM, syscall.SIGHUP, syscall.SIGQUIT, syscall.SIGUSR1, syscall.SIGUSR2)
+}
+
+// forwardSignal passes sig on to the child, unless the terminal already
+// delivered it: a second SIGINT makes tools like claude exit at once
+func forwardSignal(process *os.Process, sig os.Signal) {
+	if (sig == syscall.SIGINT || sig == syscall.SIGQUIT) && inForegroundGroup() {
+		return
+	}
+	process.Signal(sig)
+}
+
+// inForegroundGroup reports whether the CLI, and so the child sharing its
+// process group, is the foreground process group of the controlling
+// terminal. Ctrl-C and Ctrl-\ are then sent to the whole group. A
+// SIGINT sent with kill to the CLI alone while it is in the foreground is
+// not forwarded either; stop the child with SIGTERM instead.
+func inForegroundGroup() bool {
+	tty, err := os.Open("/dev/tty")
+	if err != nil {
+		return false
+	}
+	defer tty.Close()
+	var pgrp int32
+	if _, _, errno := syscall.Syscall(syscall.SYS_IOCTL, tty.Fd(), uintptr(syscall.TIOCGPGRP), uintptr(unsafe.Pointer(&pgrp))); errno != 0 {
+		return false
+	}
+	return int(pgrp) == syscall.Getpgrp()
+}
+
+// exitCodeOf returns the exit code to propagate for a finished child,
+// using the shell convention of 128+n for a child killed by signal n
+func exitCodeOf(state *os.ProcessState) int {
+	if status, ok := state.Sys().(syscall.WaitStatus); ok && status.Signaled() {
+		return 128 + int(status.Signal())
+	}
+	return state.ExitCode()
+}
@@ -0,0 +1,36 @@
+//go:build windows
+
+package cmd
+
+import (
+	"os"
+	"os/signal"
+)
+
+// execAvailable reports whether the target command can replace the CLI
+// process, which Windows does not support
+func execAvailable() bool {
+	return false
+}
+
+// execCommand runs executable as a child process, since Windows cannot
+// replace the current process. It only returns if the command could not
+// be started.
+func execCommand(executable string, args, env []string) error {
+	return runSubprocess(executable, args, env)
+}
+
+// ignoreForwardedSignals keeps Ctrl+C from exiting the CLI underneath the
+// child. Windows delivers it to every process on the console, so the
+// child receives it without forwarding.
+func ignoreForwardedSignals(sigCh chan os.Signal) {
+	signal.Notify(sigCh, os.Interrupt)
+}
+
+// forwardSignal does nothing: the child already received the console event
+func forwardSignal(process *os.Process, sig os.Signal) {}
+
+// exitCodeOf returns the exit code to propagate for a finished child
+func exitCodeOf(state *os.ProcessState) int {
+	return state.ExitCode()
+}
@@ -0,0 +1,118 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/kindship-ai/kindship-cli/internal/api"
+	"github.com/kindship-ai/kindship-cli/internal/logging"
+)
+
+// authTailBytes is how much of the child's latest output is searched for
+// authentication failure patterns
+const authTailBytes = 64 * 1024
+
+// defaultAuthFailurePatterns identify output of a command that failed
+// because its injected credential was rejected (e.g. rotated mid-session)
+var defaultAuthFailurePatterns = []string{
+	"unauthorized",
+	"authentication failed",
+	"invalid api key",
+	"api key not valid",
+	"invalid_api_key",
+	"invalid token",
+	"token expired",
+	"expired token",
+	"bad credentials",
+}
+
+// Supervised refresh settings of 'kindship auth'
+var (
+	authRefreshRetries   int
+	authFailureExitCodes []int
+	authFailurePatterns  []string
+)
+
+// tailBuffer keeps the last limit bytes written to it
+type tailBuffer struct {
+	data  []byte
+	limit int
+}
+
+func (t *tailBuffer) Write(p []byte) (int, error) {
+	t.data = append(t.data, p...)
+	if len(t.data) > t.limit {
+		t.data = t.data[len(t.data)-t.limit:]
+	}
+	return len(p), nil
+}
+
+// isAuthFailure reports whether a failed command's exit code or output
+// marks it as rejected credentials
+func isAuthFailure(exitCode int, output string) bool {
+	for _, code := range authFailureExitCodes {
+		if exitCode == code {
+			return true
+		}
+	}
+	patterns := authFailurePatterns
+	if len(patterns) == 0 {
+		patterns = defaultAuthFailurePatterns
+	}
+	output = strings.ToLower(output)
+	for _, pattern := range patterns {
+		if strings.Contains(output, strings.ToLower(pattern)) {
+			return true
+		}
+	}
+	return false
+}
+
+// authEnv returns the CLI's environment with secrets added
+func authEnv(secrets map[string]string) []string {
+	env := os.Environ()
+	for key, value := range secrets {
+		env = append(env, fmt.Sprintf("%s=%s", key, value))
+	}
+	return env
+}
+
+// superviseAuth runs the command as a child and, when it fails with an
+// authentication error, fetches the secrets again and re-runs it, up to
+// --refresh-retries times. Exits with the last run's exit code; only
+// returns if the command could not be started.
+func superviseAuth(client *api.Client, agentID, serviceKey, executable string, args, env []string, log *logging.Logger) error {
+	command := args[0]
+	for refresh := 0; ; refresh++ {
+		tail := &tailBuffer{limit: authTailBytes}
+		state, err := runChild(executable, args, env, tail)
+		if err != nil {
+			return err
+		}
+		exitCode := exitCodeOf(state)
+		if exitCode == 0 || refresh >= authRefreshRetries || !isAuthFailure(exitCode, string(tail.data)) {
+			log.Info("Command exited", map[string]interface{}{
+				"exit_code": exitCode,
+				"refreshes": refresh,
+			})
+			log.FlushSync()
+			os.Exit(exitCode)
+		}
+
+		log.Warn("Command failed with an authentication error, refreshing secrets", map[string]interface{}{
+			"exit_code": exitCode,
+			"refresh":   refresh + 1,
+		})
+		secrets, err := client.FetchSecrets(agentID, command, serviceKey)
+		if err != nil {
+			log.Error("Failed to refresh secrets", err)
+			log.FlushSync()
+			fmt.Fprintf(os.Stderr, "[kindship] Failed to refresh secrets: %v\n", err)
+			os.Exit(exitCode)
+		}
+		env = authEnv(secrets)
+		fmt.Fprintf(os.Stderr, "[kindship] %s failed with an authentication error; retrying with refreshed secrets (%d/%d)\n",
+			command, refresh+1, authRefreshRetries)
+	}
+}
@@ -0,0 +1,135 @@
+package cmd
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/kindship-ai/kindship-cli/internal/api"
+	"github.com/kindship-ai/kindship-cli/internal/config"
+	"github.com/kindship-ai/kindship-cli/internal/logging"
+)
+
+const (
+	// capabilitiesFile caches negotiated capabilities per API URL in the
+	// state dir
+	capabilitiesFile = "capabilities.json"
+	// capabilitiesTTL is how long negotiated capabilities are reused, so
+	// a server upgrade is picked up within the hour
+	capabilitiesTTL = time.Hour
+	// legacyCapabilitiesTTL is how long the answer of a server predating
+	// negotiation is reused, so the features of an upgrade are not held
+	// back for long
+	legacyCapabilitiesTTL = 5 * time.Minute
+	// capabilitiesRetryInterval is how long a failed negotiation is
+	// remembered before it is tried again
+	capabilitiesRetryInterval = time.Minute
+)
+
+// cachedCapabilities is a negotiation result saved in capabilitiesFile
+type cachedCapabilities struct {
+	NegotiatedAt time.Time `json:"negotiated_at"`
+	APIVersion   string    `json:"api_version,omitempty"`
+	Features     []string  `json:"features"`
+	Legacy       bool      `json:"legacy,omitempty"`
+
+	// unknown marks a failed negotiation, only remembered in-process
+	unknown bool
+}
+
+// fresh reports whether the cached result may still be used
+func (c cachedCapabilities) fresh() bool {
+	ttl := capabilitiesTTL
+	switch {
+	case c.unknown:
+		ttl = capabilitiesRetryInterval
+	case c.Legacy:
+		ttl = legacyCapabilitiesTTL
+	}
+	return time.Since(c.NegotiatedAt) < ttl
+}
+
+// capabilities returns the cached result as the server's capabilities
+func (c cachedCapabilities) capabilities() *api.Capabilities {
+	return &api.Capabilities{APIVersion: c.APIVersion, Features: c.Features, Legacy: c.Legacy, Unknown: c.unknown}
+}
+
+var (
+	capabilitiesMu    sync.Mutex
+	capabilitiesByURL = map[string]cachedCapabilities{}
+)
+
+// serverCapabilities returns the features the client's server supports,
+// negotiating them at most once per capabilitiesTTL for each API URL
+// (legacyCapabilitiesTTL for servers that predate negotiation). When
+// negotiation fails, every feature is assumed to be supported so a flaky
+// request never turns behaviors off; it is retried after
+// capabilitiesRetryInterval.
+func serverCapabilities(client *api.Client, serviceKey string, log *logging.Logger) *api.Capabilities {
+	apiURL := client.BaseURL()
+
+	capabilitiesMu.Lock()
+	defer capabilitiesMu.Unlock()
+	if cached, ok := capabilitiesByURL[apiURL]; ok && cached.fresh() {
+		return cached.capabilities()
+	}
+
+	cache := loadCapabilitiesCache()
+	if cached, ok := cache[apiURL]; ok && cached.fresh() {
+		capabilitiesByURL[apiURL] = cached
+		return cached.capabilities()
+	}
+
+	caps, err := client.NegotiateCapabilities(Version, serviceKey)
+	if err != nil {
+		log.Warn("Capability negotiation failed; assuming all features are supported", map[string]interface{}{
+			"error": err.Error(),
+		})
+		failed := cachedCapabilities{NegotiatedAt: time.Now(), unknown: true}
+		capabilitiesByURL[apiURL] = failed
+		return failed.capabilities()
+	}
+	log.Debug("Negotiated server capabilities", map[string]interface{}{
+		"api_version": caps.APIVersion,
+		"features":    caps.Features,
+		"legacy":      caps.Legacy,
+	})
+	negotiated := cachedCapabilities{NegotiatedAt: time.Now(), APIVersion: caps.APIVersion, Features: caps.Features, Legacy: caps.Legacy}
+	capabilitiesByURL[apiURL] = negotiated
+	cache[apiURL] = negotiated
+	saveCapabilitiesCache(cache)
+	return caps
+}
+
+// loadCapabilitiesCache reads the cache; a missing or unreadable cache is
+// empty
+func loadCapabilitiesCache() map[string]cachedCapabilities {
+	cache := map[string]cachedCapabilities{}
+	dir, err := config.GetStateDir()
+	if err != nil {
+		return cache
+	}
+	if data, err := os.ReadFile(filepath.Join(dir, capabilitiesFile)); err == nil {
+		_ = json.Unmarshal(data, &cache)
+	}
+	return cache
+}
+
+// saveCapabilitiesCache writes the cache, ignoring failures: without it the
+// next process negotiates again
+func saveCapabilitiesCache(cache map[string]cachedCapabilities) {
+	dir, err := config.GetStateDir()
+	if err != nil {
+		return
+	}
+	if err := os.MkdirAll(dir, config.ConfigDirMode); err != nil {
+		return
+	}
+	data, err := json.MarshalIndent(cache, "", "  ")
+	if err != nil {
+		return
+	}
+	_ = os.WriteFile(filepath.Join(dir, capabilitiesFile), data, config.ConfigFileMode)
+}
@@ -0,0 +1,293 @@
+package cmd
+
+import (
+	"fmt"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/kindship-ai/kindship-cli/internal/boundaries"
+	"github.com/kindship-ai/kindship-cli/internal/config"
+	"github.com/kindship-ai/kindship-cli/internal/logging"
+	"github.com/kindship-ai/kindship-cli/internal/output"
+
+	"github.com/spf13/cobra"
+)
+
+var configCmd = &cobra.Command{
+	Use:   "config",
+	Short: "Manage CLI settings",
+	Long: `Commands for reading and changing CLI settings.
+
+Per-repository settings (--repo) are stored in .kindship/config.json of the
+repository bound with 'kindship setup' and act as defaults for commands run
+inside it. Flags and environment variables take precedence.
+
+Repository keys:
+  output_format   Default --format (table, json, yaml)
+  workspace       Directory executions run in (default /workspace)
+  poll_interval   Default 'agent loop' --poll-interval in seconds
+  llm_backend     LLM CLI tried first (claude, codex, gemini)
+  environment     Deployment name attached to log entries (e.g. staging)
+  shell           Shell for BASH/TEST code with its arguments (e.g. "bash -e");
+                  boundaries.shell overrides it per entity
+  sync_dirs       Workspace directories 'kindship workspace' syncs,
+                  comma-separated (e.g. "data,cache/models")
+
+Subcommands:
+  set          Set a setting (an empty value removes it)
+  get          Print a setting
+  self-hosted  Point the CLI at an on-prem deployment`,
+}
+
+var configSetCmd = &cobra.Command{
+	Use:   "set --repo <key> <value>",
+	Short: "Set a setting",
+	Long: `Set a repository setting. An empty value removes it.
+
+Examples:
+  kindship config set --repo output_format json
+  kindship config set --repo poll_interval 10
+  kindship config set --repo shell "bash -e -o pipefail"
+  kindship config set --repo llm_backend ""`,
+	Args:         usageArgs(cobra.ExactArgs(2)),
+	SilenceUsage: true,
+	RunE:         runConfigSet,
+}
+
+var configGetCmd = &cobra.Command{
+	Use:   "get --repo <key>",
+	Short: "Print a setting",
+	Long: `Print a repository setting. Unset settings print an empty line.
+
+Examples:
+  kindship config get --repo workspace`,
+	Args:         usageArgs(cobra.ExactArgs(1)),
+	SilenceUsage: true,
+	RunE:         runConfigGet,
+}
+
+var configRepo bool
+
+// repoSetting is a key of RepoSettings as managed by 'kindship config'
+type repoSetting struct {
+	get func(s *config.RepoSettings) string
+	// set validates value and stores it; an empty value clears the setting
+	set func(s *config.RepoSettings, value string) error
+}
+
+// repoSettings are the keys accepted by 'kindship config set --repo'
+var repoSettings = map[string]repoSetting{
+	"output_format": {
+		get: func(s *config.RepoSettings) string { return s.OutputFormat },
+		set: func(s *config.RepoSettings, value string) error {
+			if value != "" {
+				format, err := output.ParseFormat(value)
+				if err != nil {
+					return err
+				}
+				value = string(format)
+			}
+			s.OutputFormat = value
+			return nil
+		},
+	},
+	"workspace": {
+		get: func(s *config.RepoSettings) string { return s.Workspace },
+		set: func(s *config.RepoSettings, value string) error {
+			if value != "" && !filepath.IsAbs(value) {
+				return fmt.Errorf("workspace must be an absolute path")
+			}
+			s.Workspace = value
+			return nil
+		},
+	},
+	"poll_interval": {
+		get: func(s *config.RepoSettings) string {
+			if s.PollInterval == 0 {
+				return ""
+			}
+			return strconv.Itoa(s.PollInterval)
+		},
+		set: func(s *config.RepoSettings, value string) error {
+			if value == "" {
+				s.PollInterval = 0
+				return nil
+			}
+			seconds, err := strconv.Atoi(value)
+			if err != nil || seconds <= 0 {
+				return fmt.Errorf("poll_interval must be a positive number of seconds")
+			}
+			s.PollInterval = seconds
+			return nil
+		},
+	},
+	"llm_backend": {
+		get: func(s *config.RepoSettings) string { return s.LLMBackend },
+		set: func(s *config.RepoSettings, value string) error {
+			if value != "" {
+				if err := (&boundaries.LLMPolicy{Backends: []string{value}}).Validate(); err != nil {
+					return err
+				}
+			}
+			s.LLMBackend = value
+			return nil
+		},
+	},
+	"environment": {
+		get: func(s *config.RepoSettings) string { return s.Environment },
+		set: func(s *config.RepoSettings, value string) error {
+			s.Environment = value
+			return nil
+		},
+	},
+	"shell": {
+		get: func(s *config.RepoSettings) string { return s.Shell },
+		set: func(s *config.RepoSettings, value string) error {
+			if err := parseShell(value).Validate(); err != nil {
+				return err
+			}
+			s.Shell = strings.Join(strings.Fields(value), " ")
+			return nil
+		},
+	},
+	"sync_dirs": {
+		get: func(s *config.RepoSettings) string { return strings.Join(s.SyncDirs, ",") },
+		set: func(s *config.RepoSettings, value string) error {
+			var dirs []string
+			for _, dir := range strings.Split(value, ",") {
+				if strings.TrimSpace(dir) == "" {
+					continue
+				}
+				clean, err := cleanSyncDir(dir)
+				if err != nil {
+					return err
+				}
+				dirs = append(dirs, clean)
+			}
+			s.SyncDirs = dirs
+			return nil
+		},
+	},
+}
+
+func init() {
+	for _, c := range []*cobra.Command{configSetCmd, configGetCmd} {
+		c.Flags().BoolVar(&configRepo, "repo", false, "Use the settings of the current repository")
+	}
+
+	configCmd.AddCommand(configSetCmd)
+	configCmd.AddCommand(configGetCmd)
+	rootCmd.AddCommand(configCmd)
+}
+
+// lookupRepoSetting returns the setting for key, requiring --repo
+func lookupRepoSetting(key string) (repoSetting, error) {
+	if !configRepo {
+		return repoSetting{}, withExitCode(ExitUsage, fmt.Errorf("only repository settings are supported; pass --repo"))
+	}
+	setting, ok := repoSettings[key]
+	if !ok {
+		keys := make([]string, 0, len(repoSettings))
+		for k := range repoSettings {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+		return repoSetting{}, withExitCode(ExitUsage, fmt.Errorf("unknown setting %q (expected one of %s)", key, strings.Join(keys, ", ")))
+	}
+	return setting, nil
+}
+
+func runConfigSet(cmd *cobra.Command, args []string) error {
+	key, value := args[0], args[1]
+	setting, err := lookupRepoSetting(key)
+	if err != nil {
+		return err
+	}
+
+	configDir, err := config.GetRepoConfigDir()
+	if err != nil {
+		return withExitCode(ExitUsage, fmt.Errorf("%w (run 'kindship setup' first)", err))
+	}
+	repoConfig, err := config.LoadRepoConfig()
+	if err != nil {
+		return err
+	}
+
+	if err := setting.set(&repoConfig.RepoSettings, value); err != nil {
+		return withExitCode(ExitValidation, fmt.Errorf("invalid %s: %w", key, err))
+	}
+	if err := config.SaveRepoConfig(repoConfig, filepath.Dir(configDir)); err != nil {
+		return err
+	}
+
+	if value == "" {
+		fmt.Println(output.OK(fmt.Sprintf("Removed %s", key)))
+	} else {
+		fmt.Println(output.OK(fmt.Sprintf("Set %s = %s", key, setting.get(&repoConfig.RepoSettings))))
+	}
+	return nil
+}
+
+func runConfigGet(cmd *cobra.Command, args []string) error {
+	setting, err := lookupRepoSetting(args[0])
+	if err != nil {
+		return err
+	}
+
+	repoConfig, err := config.LoadRepoConfig()
+	if err != nil {
+		return withExitCode(ExitUsage, fmt.Errorf("%w (run 'kindship setup' first)", err))
+	}
+
+	fmt.Println(setting.get(&repoConfig.RepoSettings))
+	return nil
+}
+
+// applyRepoSettings installs the current repository's settings as defaults
+// for cmd: flags the user did not set are filled in. The workspace and
+// shell settings are read per execution by repoExecutionOptions. Outside a
+// configured repository it does nothing.
+func applyRepoSettings(cmd *cobra.Command) {
+	repoConfig, err := config.LoadRepoConfig()
+	if err != nil {
+		return
+	}
+	settings := repoConfig.RepoSettings
+
+	setDefault := func(name, value string) {
+		flag := cmd.Flags().Lookup(name)
+		if flag == nil || flag.Changed || value == "" {
+			return
+		}
+		cmd.Flags().Set(name, value)
+	}
+
+	// Only --format flags that take table/json/yaml (not e.g. plan graph's)
+	if flag := cmd.Flags().Lookup("format"); flag != nil && strings.HasPrefix(flag.Usage, output.FormatUsage) {
+		setDefault("format", settings.OutputFormat)
+	}
+	if settings.PollInterval > 0 {
+		setDefault("poll-interval", strconv.Itoa(settings.PollInterval))
+	}
+	if settings.LLMBackend != "" {
+		chain := []string{settings.LLMBackend}
+		if settings.LLMBackend != boundaries.LLMBackendClaude {
+			chain = append(chain, boundaries.LLMBackendClaude)
+		}
+		setDefault("llm-backends", strings.Join(chain, ","))
+	}
+	if settings.Environment != "" {
+		logging.SetEnvironment(settings.Environment)
+	}
+}
+
+// parseShell splits a shell setting into interpreter and arguments
+func parseShell(value string) *boundaries.ShellPolicy {
+	fields := strings.Fields(value)
+	if len(fields) == 0 {
+		return &boundaries.ShellPolicy{}
+	}
+	return &boundaries.ShellPolicy{Interpreter: fields[0], Args: fields[1:]}
+}
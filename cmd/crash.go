@@ -0,0 +1,136 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"runtime/debug"
+	"sync"
+	"time"
+
+	"github.com/kindship-ai/kindship-cli/internal/api"
+	"github.com/kindship-ai/kindship-cli/internal/config"
+	"github.com/kindship-ai/kindship-cli/internal/logging"
+)
+
+// crashDir holds crash reports, relative to the state dir
+const crashDir = "logs/crash"
+
+// inflight tracks executions this process started and has not completed,
+// so a crash can fail them instead of leaving them RUNNING.
+// Keyed by execution ID.
+var inflight sync.Map
+
+// inflightExecution is an execution that is still running
+type inflightExecution struct {
+	EntityID   string
+	ServiceKey string
+	Client     *api.Client
+}
+
+// trackExecution records executionID as in flight and returns a function
+// that forgets it again
+func trackExecution(executionID string, params EntityExecutionParams) func() {
+	inflight.Store(executionID, inflightExecution{
+		EntityID:   params.EntityID,
+		ServiceKey: params.ServiceKey,
+		Client:     params.Client,
+	})
+	return func() { inflight.Delete(executionID) }
+}
+
+// handlePanic reports a recovered panic: the stack trace goes to stderr, a
+// crash report file and Axiom, and in-flight executions are completed as
+// FAILED. When entityID is set, only that entity's executions are failed
+// (a single loop worker crashed); otherwise all are. Returns the error to
+// exit with.
+func handlePanic(recovered interface{}, stack []byte, log *logging.Logger, entityID string) error {
+	command := crashCommand()
+	reason := fmt.Sprintf("kindship CLI crashed: %v", recovered)
+
+	fmt.Fprintf(os.Stderr, "\n%s\n\n%s\n", reason, stack)
+
+	reportPath, reportErr := writeCrashReport(command, recovered, stack)
+	if reportErr == nil {
+		fmt.Fprintf(os.Stderr, "Crash report written to %s\n", reportPath)
+	}
+
+	log.Error("Panic", fmt.Errorf("%v", recovered), map[string]interface{}{
+		"version":   Version,
+		"command":   command,
+		"entity_id": entityID,
+		"stack":     string(stack),
+	})
+
+	inflight.Range(func(key, value interface{}) bool {
+		executionID := key.(string)
+		exec := value.(inflightExecution)
+		if entityID != "" && exec.EntityID != entityID {
+			return true
+		}
+		failureMsg := reason
+		completeReq := api.ExecutionCompleteRequest{
+			Status:        api.ExecutionAttemptStatusFailed,
+			FailureReason: &failureMsg,
+		}
+		if _, err := exec.Client.CompleteExecution(executionID, completeReq, exec.ServiceKey); err != nil {
+			log.Error("Failed to fail in-flight execution after panic", err, map[string]interface{}{
+				"execution_id": executionID,
+			})
+		} else {
+			log.Info("Failed in-flight execution after panic", map[string]interface{}{
+				"execution_id": executionID,
+				"entity_id":    exec.EntityID,
+			})
+		}
+		inflight.Delete(executionID)
+		return true
+	})
+
+	log.FlushSync()
+	return withExitCode(ExitPanic, fmt.Errorf("%s", reason))
+}
+
+// crashCommand names the command that crashed by its path alone (e.g.
+// "kindship agent loop"): its arguments may carry credentials such as
+// --service-key, and crash details go to stderr, a file and Axiom
+func crashCommand() string {
+	if len(os.Args) > 1 {
+		if cmd, _, err := rootCmd.Find(os.Args[1:]); err == nil {
+			return cmd.CommandPath()
+		}
+	}
+	return rootCmd.CommandPath()
+}
+
+// writeCrashReport saves a panic's details to the crash report directory
+func writeCrashReport(command string, recovered interface{}, stack []byte) (string, error) {
+	dir, err := config.GetStateDir()
+	if err != nil {
+		return "", err
+	}
+	dir = filepath.Join(dir, filepath.FromSlash(crashDir))
+	if err := os.MkdirAll(dir, config.ConfigDirMode); err != nil {
+		return "", err
+	}
+
+	now := time.Now().UTC()
+	path := filepath.Join(dir, fmt.Sprintf("crash-%s.log", now.Format("20060102T150405Z")))
+	report := fmt.Sprintf("time: %s\nversion: %s\ncommand: %s\npanic: %v\n\n%s", now.Format(time.RFC3339), Version, command, recovered, stack)
+	if err := os.WriteFile(path, []byte(report), config.ConfigFileMode); err != nil {
+		return "", err
+	}
+	return path, nil
+}
+
+// executeEntitySafely runs executeEntity for a loop worker, turning a panic
+// into a failed execution and an error instead of crashing the process
+func executeEntitySafely(params EntityExecutionParams) (success bool, err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			success = false
+			err = handlePanic(r, debug.Stack(), params.Log, params.EntityID)
+		}
+	}()
+	return executeEntity(params)
+}
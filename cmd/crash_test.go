@@ -0,0 +1,21 @@
+package cmd
+
+import (
+	"os"
+	"strings"
+	"testing"
+)
+
+func TestCrashCommandOmitsArguments(t *testing.T) {
+	prevArgs := os.Args
+	t.Cleanup(func() { os.Args = prevArgs })
+	os.Args = []string{"kindship", "agent", "loop", "--service-key", "sk-secret", "--agent-id", "a1"}
+
+	command := crashCommand()
+	if command != "kindship agent loop" {
+		t.Errorf("crashCommand() = %q, want %q", command, "kindship agent loop")
+	}
+	if strings.Contains(command, "sk-secret") {
+		t.Errorf("crashCommand() leaks the service key: %q", command)
+	}
+}
@@ -0,0 +1,96 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/kindship-ai/kindship-cli/internal/api"
+	"github.com/kindship-ai/kindship-cli/internal/boundaries"
+	"github.com/kindship-ai/kindship-cli/internal/executor"
+	"github.com/kindship-ai/kindship-cli/internal/logging"
+)
+
+// runDryRun is the --dry-run flag of run
+var runDryRun bool
+
+// dryRunModes are the execution modes whose executors support dry runs
+var dryRunModes = []api.ExecutionMode{
+	api.ExecutionModeBash,
+	api.ExecutionModeTest,
+	api.ExecutionModePython,
+	api.ExecutionModePythonSandbox,
+}
+
+// supportsDryRun reports whether mode's executor can describe an execution
+// instead of running it
+func supportsDryRun(mode api.ExecutionMode) bool {
+	for _, m := range dryRunModes {
+		if m == mode {
+			return true
+		}
+	}
+	return false
+}
+
+// dryRunEntity prepares a fetched entity the way an execution would
+// (boundaries, input mapping, code references, templates, command rules)
+// and prints what its executor would run, without starting an execution
+// or fetching secrets. Requested secrets are listed by name only.
+func dryRunEntity(entityResp *api.EntityExecuteResponse, settings executionSettings, log *logging.Logger) error {
+	entity := entityResp.Entity
+	if !supportsDryRun(entity.ExecutionMode) {
+		modes := make([]string, len(dryRunModes))
+		for i, m := range dryRunModes {
+			modes[i] = string(m)
+		}
+		return withExitCode(ExitUsage, fmt.Errorf("--dry-run does not support %s tasks (supported: %s)", entity.ExecutionMode, strings.Join(modes, ", ")))
+	}
+	if !entityResp.DependenciesStatus.AllMet {
+		log.Warn("Dependencies not met; inputs from pending dependencies are missing", map[string]interface{}{
+			"pending": entityResp.DependenciesStatus.Pending,
+		})
+	}
+
+	policy, err := boundaries.Parse(entity.Boundaries)
+	if err != nil {
+		return withExitCode(ExitValidation, err)
+	}
+	inputs, err := policy.Inputs.MapInputs(entityResp.Inputs)
+	if err != nil {
+		return withExitCode(ExitValidation, fmt.Errorf("input mapping failed: %w", err))
+	}
+	if entity.Code != nil {
+		code, err := executor.ResolveCode(settings.Exec.Dir(), *entity.Code)
+		if err != nil {
+			return withExitCode(ExitValidation, err)
+		}
+		if policy.Template {
+			if code, err = executor.RenderCode(code, inputs); err != nil {
+				return withExitCode(ExitValidation, err)
+			}
+		}
+		entity.Code = &code
+
+		if entity.ExecutionMode == api.ExecutionModeBash || entity.ExecutionMode == api.ExecutionModeTest {
+			if violations := policy.Commands.CheckScript(code, settings.Exec.Dir()); len(violations) > 0 {
+				return withExitCode(ExitValidation, fmt.Errorf("boundary violation: %s", violations[0].Detail))
+			}
+		}
+	}
+	if err := executor.Preflight(entity.ExecutionMode, policy, settings.Exec); err != nil {
+		return withExitCode(ExitValidation, err)
+	}
+
+	secrets := map[string]string{}
+	for _, name := range requestedSecrets(settings.Secrets, policy.Secrets) {
+		secrets[name] = ""
+	}
+	ctx := executor.WithDryRun(executor.WithSecrets(executor.WithOptions(context.Background(), settings.Exec), secrets))
+	result := dispatchExecution(ctx, &entity, inputs, log)
+	fmt.Print(result.Stdout)
+	if !result.Success {
+		return withExitCode(ExitValidation, result.Error)
+	}
+	return nil
+}
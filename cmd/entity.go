@@ -3,58 +3,123 @@ package cmd
 import (
 	"fmt"
 	"os"
+	"strconv"
+	"time"
 
 	"github.com/kindship-ai/kindship-cli/internal/api"
+	"github.com/kindship-ai/kindship-cli/internal/output"
 	"github.com/spf13/cobra"
 )
 
 var entityCmd = &cobra.Command{
 	Use:   "entity",
 	Short: "Planning entity commands",
-	Long:  `Commands for managing planning entities.`,
+	Long: `Commands for managing planning entities.
+
+Subcommands:
+  activate     Activate DRAFT entities
+  cancel       Cancel entities
+  deactivate   Move ACTIVE entities back to DRAFT
+  create       Create a single task from a task file
+  update       Update fields of a task
+  attempts     List execution attempts of an entity
+  outputs      Print the structured outputs of an entity
+  validations  List validation records of an entity
+  wait         Wait for an entity to finish`,
 }
 
 // recursiveFlag controls whether entity activation cascades to descendants
 var recursiveFlag bool
 
 var activateCmd = &cobra.Command{
-	Use:   "activate <entity-id>",
+	Use:   "activate [entity-id]",
 	Short: "Activate a planning entity",
 	Long: `Activate a DRAFT planning entity, transitioning it to ACTIVE status.
 
 With --recursive, all descendant entities in DRAFT status are also activated.
 
+With --from-file, activates every entity listed in the file: one ID per line,
+blank lines and lines starting with # are ignored, '-' reads from stdin.
+Up to --concurrency entities are processed at once, and a summary of
+successes and failures is printed at the end.
+
 Examples:
   # Activate a single entity
   kindship entity activate 550e8400-e29b-41d4-a716-446655440000
 
   # Activate entity and all descendants
-  kindship entity activate 550e8400-e29b-41d4-a716-446655440000 --recursive`,
-	Args: cobra.ExactArgs(1),
-	RunE: runActivate,
+  kindship entity activate 550e8400-e29b-41d4-a716-446655440000 --recursive
+
+  # Activate every entity listed in a file
+  kindship entity activate --from-file ids.txt --concurrency 8`,
+	Args:         usageArgs(cobra.MaximumNArgs(1)),
+	SilenceUsage: true,
+	RunE:         runActivate,
 }
 
-func runActivate(cmd *cobra.Command, args []string) error {
-	entityID := args[0]
+var entityAttemptsCmd = &cobra.Command{
+	Use:   "attempts <entity-id>",
+	Short: "List execution attempts of a planning entity",
+	Long: `List all execution attempts of a planning entity with their status,
+duration and failure reason.
 
-	// Read from flags first, fall back to environment variables
-	if serviceKey == "" {
-		serviceKey = os.Getenv("KINDSHIP_SERVICE_KEY")
-	}
-	if apiURL == "" {
-		apiURL = os.Getenv("KINDSHIP_API_URL")
+Examples:
+  kindship entity attempts 550e8400-e29b-41d4-a716-446655440000
+  kindship entity attempts 550e8400-e29b-41d4-a716-446655440000 --format json`,
+	Args:         usageArgs(cobra.ExactArgs(1)),
+	SilenceUsage: true,
+	RunE:         runEntityAttempts,
+}
+
+var entityValidationsCmd = &cobra.Command{
+	Use:   "validations <entity-id>",
+	Short: "List validation records of a planning entity",
+	Long: `List the validation records produced by all execution attempts of a
+planning entity.
+
+Examples:
+  kindship entity validations 550e8400-e29b-41d4-a716-446655440000
+  kindship entity validations 550e8400-e29b-41d4-a716-446655440000 --format yaml`,
+	Args:         usageArgs(cobra.ExactArgs(1)),
+	SilenceUsage: true,
+	RunE:         runEntityValidations,
+}
+
+// entityFormat is the --format value for entity list commands
+var entityFormat string
+
+// entityClient returns an API client for the entity commands, which need a
+// service key
+func entityClient(opts *apiOptions) (*api.Client, error) {
+	if err := opts.requireServiceKey(); err != nil {
+		return nil, err
 	}
-	if apiURL == "" {
-		apiURL = "https://kindship.ai"
+	return opts.client(), nil
+}
+
+func runActivate(cmd *cobra.Command, args []string) error {
+	ids, err := entityTargets(args)
+	if err != nil {
+		return err
 	}
 
-	if serviceKey == "" {
-		return fmt.Errorf("KINDSHIP_SERVICE_KEY is required (use --service-key flag or KINDSHIP_SERVICE_KEY environment variable)")
+	opts := entityOpts.resolved()
+	client, err := entityClient(opts)
+	if err != nil {
+		return err
 	}
 
-	client := api.NewClient(apiURL, verbose)
+	if bulkFromFile != "" {
+		return runBulk("activate", ids, func(id string) (string, error) {
+			resp, err := client.ActivateEntity(id, opts.ServiceKey, recursiveFlag)
+			if err != nil {
+				return "", err
+			}
+			return fmt.Sprintf("%d activated", resp.ActivatedCount), nil
+		})
+	}
 
-	resp, err := client.ActivateEntity(entityID, serviceKey, recursiveFlag)
+	resp, err := client.ActivateEntity(ids[0], opts.ServiceKey, recursiveFlag)
 	if err != nil {
 		return fmt.Errorf("failed to activate entity: %w", err)
 	}
@@ -67,12 +132,121 @@ func runActivate(cmd *cobra.Command, args []string) error {
 	return nil
 }
 
+func runEntityAttempts(cmd *cobra.Command, args []string) error {
+	format, err := output.ParseFormat(entityFormat)
+	if err != nil {
+		return withExitCode(ExitUsage, err)
+	}
+
+	opts := entityOpts.resolved()
+	client, err := entityClient(opts)
+	if err != nil {
+		return err
+	}
+
+	resp, err := client.ListAttempts(args[0], opts.ServiceKey)
+	if err != nil {
+		return fmt.Errorf("failed to list attempts: %w", err)
+	}
+
+	return output.Render(os.Stdout, format, resp.Attempts, func() error {
+		if len(resp.Attempts) == 0 {
+			fmt.Println("No execution attempts found.")
+			return nil
+		}
+		table := output.NewTable("#", "Status", "Mode", "Started", "Duration", "Failure reason")
+		for _, a := range resp.Attempts {
+			duration := "-"
+			if a.CompletedAt != nil {
+				duration = a.Duration().Round(time.Millisecond).String()
+			}
+			reason := ""
+			if a.FailureReason != nil {
+				reason = *a.FailureReason
+			}
+			table.Row(strconv.Itoa(a.AttemptNumber), colorAttemptStatus(a.Status), string(a.ExecutionMode),
+				a.StartedAt.Local().Format("2006-01-02 15:04:05"), duration, reason)
+		}
+		return table.Render(os.Stdout)
+	})
+}
+
+func runEntityValidations(cmd *cobra.Command, args []string) error {
+	format, err := output.ParseFormat(entityFormat)
+	if err != nil {
+		return withExitCode(ExitUsage, err)
+	}
+
+	opts := entityOpts.resolved()
+	client, err := entityClient(opts)
+	if err != nil {
+		return err
+	}
+
+	resp, err := client.ListValidations(args[0], opts.ServiceKey)
+	if err != nil {
+		return fmt.Errorf("failed to list validation records: %w", err)
+	}
+
+	return output.Render(os.Stdout, format, resp.Validations, func() error {
+		if len(resp.Validations) == 0 {
+			fmt.Println("No validation records found.")
+			return nil
+		}
+		table := output.NewTable("Execution", "Type", "Outcome", "Severity", "Target", "Failure reason")
+		for _, v := range resp.Validations {
+			reason := ""
+			if v.FailureReason != nil {
+				reason = *v.FailureReason
+			}
+			table.Row(v.ExecutionID, v.ValidationType, colorOutcome(v.Outcome), string(v.Severity), v.Target, reason)
+		}
+		return table.Render(os.Stdout)
+	})
+}
+
+// colorAttemptStatus renders an attempt status in the color of its outcome
+func colorAttemptStatus(status api.ExecutionAttemptStatus) string {
+	switch status {
+	case api.ExecutionAttemptStatusSuccess:
+		return output.Green(string(status))
+	case api.ExecutionAttemptStatusFailed:
+		return output.Red(string(status))
+	case api.ExecutionAttemptStatusAbandoned:
+		return output.Yellow(string(status))
+	default:
+		return string(status)
+	}
+}
+
+// colorOutcome renders a validation outcome in the color of its result
+func colorOutcome(outcome api.ValidationOutcome) string {
+	switch outcome {
+	case api.ValidationOutcomePass:
+		return output.Green(string(outcome))
+	case api.ValidationOutcomeFail:
+		return output.Red(string(outcome))
+	case api.ValidationOutcomeWarn, api.ValidationOutcomePartial:
+		return output.Yellow(string(outcome))
+	default:
+		return string(outcome)
+	}
+}
+
 func init() {
 	activateCmd.Flags().BoolVar(&recursiveFlag, "recursive", false, "Activate all descendant entities")
-	activateCmd.Flags().StringVar(&serviceKey, "service-key", "", "Service key for authentication (defaults to KINDSHIP_SERVICE_KEY env var)")
-	activateCmd.Flags().StringVar(&apiURL, "api-url", "", "API base URL (defaults to KINDSHIP_API_URL env var or https://kindship.ai)")
-	activateCmd.Flags().BoolVarP(&verbose, "verbose", "v", false, "Enable verbose logging")
+	activateCmd.Flags().StringVar(&entityFormat, "format", "table", output.FormatUsage+" for --from-file results")
+	addBulkFlags(activateCmd)
+
+	for _, c := range []*cobra.Command{activateCmd, entityAttemptsCmd, entityValidationsCmd} {
+		addServiceKeyFlags(c, &entityOpts)
+	}
+	for _, c := range []*cobra.Command{entityAttemptsCmd, entityValidationsCmd} {
+		c.Flags().StringVar(&entityFormat, "format", "table", output.FormatUsage)
+	}
 
 	entityCmd.AddCommand(activateCmd)
+	entityCmd.AddCommand(entityAttemptsCmd)
+	entityCmd.AddCommand(entityValidationsCmd)
 	rootCmd.AddCommand(entityCmd)
 }
@@ -0,0 +1,245 @@
+package cmd
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"sync"
+
+	"github.com/kindship-ai/kindship-cli/internal/api"
+	"github.com/kindship-ai/kindship-cli/internal/output"
+	"github.com/spf13/cobra"
+)
+
+var entityCancelCmd = &cobra.Command{
+	Use:   "cancel [entity-id]",
+	Short: "Cancel planning entities",
+	Long: `Cancel a planning entity so it is never executed.
+
+With --from-file, cancels every entity listed in the file (see 'entity
+activate --help' for the file format).
+
+Examples:
+  kindship entity cancel 550e8400-e29b-41d4-a716-446655440000
+  kindship entity cancel --from-file ids.txt --concurrency 8`,
+	Args:         usageArgs(cobra.MaximumNArgs(1)),
+	SilenceUsage: true,
+	RunE:         runEntityCancel,
+}
+
+var entityDeactivateCmd = &cobra.Command{
+	Use:   "deactivate [entity-id]",
+	Short: "Move planning entities back to DRAFT",
+	Long: `Deactivate an ACTIVE planning entity, moving it back to DRAFT status.
+
+With --from-file, deactivates every entity listed in the file (see 'entity
+activate --help' for the file format).
+
+Examples:
+  kindship entity deactivate 550e8400-e29b-41d4-a716-446655440000
+  kindship entity deactivate --from-file ids.txt`,
+	Args:         usageArgs(cobra.MaximumNArgs(1)),
+	SilenceUsage: true,
+	RunE:         runEntityDeactivate,
+}
+
+// defaultBulkConcurrency is how many entities bulk operations process at once
+const defaultBulkConcurrency = 4
+
+var (
+	bulkFromFile    string
+	bulkConcurrency int
+)
+
+// bulkResult is the outcome of a bulk operation on one entity
+type bulkResult struct {
+	EntityID string `json:"entity_id"`
+	OK       bool   `json:"ok"`
+	Detail   string `json:"detail,omitempty"`
+	Error    string `json:"error,omitempty"`
+}
+
+// bulkSummary is the result of a bulk operation
+type bulkSummary struct {
+	Action    string       `json:"action"`
+	Succeeded int          `json:"succeeded"`
+	Failed    int          `json:"failed"`
+	Results   []bulkResult `json:"results"`
+}
+
+// entityTargets returns the entity IDs a command operates on: the single
+// argument, or the IDs listed in --from-file
+func entityTargets(args []string) ([]string, error) {
+	switch {
+	case len(args) == 1 && bulkFromFile != "":
+		return nil, withExitCode(ExitUsage, fmt.Errorf("pass either an entity ID or --from-file, not both"))
+	case len(args) == 1:
+		return args, nil
+	case bulkFromFile != "":
+		ids, err := readEntityIDs(bulkFromFile)
+		if err != nil {
+			return nil, err
+		}
+		if len(ids) == 0 {
+			return nil, withExitCode(ExitUsage, fmt.Errorf("no entity IDs in %s", bulkFromFile))
+		}
+		return ids, nil
+	default:
+		return nil, withExitCode(ExitUsage, fmt.Errorf("an entity ID or --from-file is required"))
+	}
+}
+
+// readEntityIDs reads one entity ID per line from path ("-" for stdin).
+// Blank lines and lines starting with # are skipped, as are duplicates.
+func readEntityIDs(path string) ([]string, error) {
+	var r io.Reader = os.Stdin
+	if path != "-" {
+		f, err := os.Open(path)
+		if err != nil {
+			return nil, fmt.Errorf("failed to open ID file: %w", err)
+		}
+		defer f.Close()
+		r = f
+	}
+
+	var ids []string
+	seen := map[string]bool{}
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		id := strings.TrimSpace(scanner.Text())
+		if id == "" || strings.HasPrefix(id, "#") || seen[id] {
+			continue
+		}
+		seen[id] = true
+		ids = append(ids, id)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read ID file: %w", err)
+	}
+	return ids, nil
+}
+
+// runBulk applies op to every ID with up to bulkConcurrency requests in
+// flight, prints a per-entity result and summary, and fails with ExitAPI if
+// any entity failed. op returns a short detail shown on success.
+func runBulk(action string, ids []string, op func(id string) (string, error)) error {
+	format, err := output.ParseFormat(entityFormat)
+	if err != nil {
+		return withExitCode(ExitUsage, err)
+	}
+	concurrency := bulkConcurrency
+	if concurrency < 1 {
+		concurrency = 1
+	}
+
+	summary := bulkSummary{Action: action, Results: make([]bulkResult, len(ids))}
+	sem := make(chan struct{}, concurrency)
+	var wg sync.WaitGroup
+	for i, id := range ids {
+		wg.Add(1)
+		sem <- struct{}{}
+		go func(i int, id string) {
+			defer wg.Done()
+			defer func() { <-sem }()
+			result := bulkResult{EntityID: id}
+			if detail, err := op(id); err != nil {
+				result.Error = err.Error()
+			} else {
+				result.OK = true
+				result.Detail = detail
+			}
+			summary.Results[i] = result
+		}(i, id)
+	}
+	wg.Wait()
+
+	for _, r := range summary.Results {
+		if r.OK {
+			summary.Succeeded++
+		} else {
+			summary.Failed++
+		}
+	}
+
+	if err := output.Render(os.Stdout, format, summary, func() error {
+		for _, r := range summary.Results {
+			if r.OK {
+				line := r.EntityID
+				if r.Detail != "" {
+					line += " " + output.Dim("("+r.Detail+")")
+				}
+				fmt.Println(output.OK(line))
+			} else {
+				fmt.Println(output.Fail(fmt.Sprintf("%s: %s", r.EntityID, r.Error)))
+			}
+		}
+		if len(ids) > 1 {
+			fmt.Printf("\n%s: %d succeeded, %d failed\n", action, summary.Succeeded, summary.Failed)
+		}
+		return nil
+	}); err != nil {
+		return err
+	}
+
+	if summary.Failed > 0 {
+		return withExitCode(ExitAPI, fmt.Errorf("%s failed for %d of %d entities", action, summary.Failed, len(ids)))
+	}
+	return nil
+}
+
+func runEntityCancel(cmd *cobra.Command, args []string) error {
+	ids, err := entityTargets(args)
+	if err != nil {
+		return err
+	}
+	opts := entityOpts.resolved()
+	client, err := entityClient(opts)
+	if err != nil {
+		return err
+	}
+	return runBulk("cancel", ids, func(id string) (string, error) {
+		return entityStatusDetail(client.CancelEntity(id, opts.ServiceKey))
+	})
+}
+
+func runEntityDeactivate(cmd *cobra.Command, args []string) error {
+	ids, err := entityTargets(args)
+	if err != nil {
+		return err
+	}
+	opts := entityOpts.resolved()
+	client, err := entityClient(opts)
+	if err != nil {
+		return err
+	}
+	return runBulk("deactivate", ids, func(id string) (string, error) {
+		return entityStatusDetail(client.DeactivateEntity(id, opts.ServiceKey))
+	})
+}
+
+// entityStatusDetail turns a status change response into a bulk detail
+func entityStatusDetail(resp *api.EntityStatusResponse, err error) (string, error) {
+	if err != nil {
+		return "", err
+	}
+	return resp.Status, nil
+}
+
+// addBulkFlags adds --from-file and --concurrency to cmd
+func addBulkFlags(cmd *cobra.Command) {
+	cmd.Flags().StringVar(&bulkFromFile, "from-file", "", "File with one entity ID per line ('-' for stdin)")
+	cmd.Flags().IntVar(&bulkConcurrency, "concurrency", defaultBulkConcurrency, "Entities to process in parallel with --from-file")
+}
+
+func init() {
+	for _, c := range []*cobra.Command{entityCancelCmd, entityDeactivateCmd} {
+		addServiceKeyFlags(c, &entityOpts)
+		c.Flags().StringVar(&entityFormat, "format", "table", output.FormatUsage)
+		addBulkFlags(c)
+	}
+
+	entityCmd.AddCommand(entityCancelCmd)
+	entityCmd.AddCommand(entityDeactivateCmd)
+}
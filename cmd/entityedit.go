@@ -0,0 +1,228 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/kindship-ai/kindship-cli/internal/api"
+	"github.com/kindship-ai/kindship-cli/internal/output"
+	"github.com/spf13/cobra"
+)
+
+var entityCreateCmd = &cobra.Command{
+	Use:   "create <task-file>",
+	Short: "Create a single task",
+	Long: `Create one task from a YAML or JSON task file, using the same format as a
+task file in a plan directory (see 'kindship plan submit --help'):
+
+  title: Load orders
+  execution_mode: PYTHON
+  code_file: etl.py
+  dependencies_labeled:
+    raw: <entity-id>
+
+The task is created under --parent (a project or objective), or under the
+parent_id set in the file.
+
+Examples:
+  kindship entity create task.yaml --parent 550e8400-e29b-41d4-a716-446655440000
+  kindship entity create task.json --format json`,
+	Args:         usageArgs(cobra.ExactArgs(1)),
+	SilenceUsage: true,
+	RunE:         runEntityCreate,
+}
+
+var entityUpdateCmd = &cobra.Command{
+	Use:   "update <entity-id>",
+	Short: "Update a task",
+	Long: `Update fields of a task in place, without resubmitting the whole plan.
+
+Fields are taken from --file (a task file, as for 'entity create'), then
+overridden by the individual flags. Only the fields given are changed.
+
+Examples:
+  kindship entity update 550e8400-e29b-41d4-a716-446655440000 --code-file script.py
+  kindship entity update 550e8400-e29b-41d4-a716-446655440000 --title "Load orders v2"
+  kindship entity update 550e8400-e29b-41d4-a716-446655440000 --file task.yaml`,
+	Args:         usageArgs(cobra.ExactArgs(1)),
+	SilenceUsage: true,
+	RunE:         runEntityUpdate,
+}
+
+var (
+	entityParentID      string
+	entityFile          string
+	entityTitle         string
+	entityDescription   string
+	entityExecutionMode string
+	entityCodeFile      string
+)
+
+// entityCreateFile is a task file for 'entity create'
+type entityCreateFile struct {
+	planDirTask
+	ParentID string `json:"parent_id,omitempty"`
+}
+
+// entityUpdateFile is a task file for 'entity update'
+type entityUpdateFile struct {
+	api.EntityUpdateRequest
+	CodeFile string `json:"code_file,omitempty"`
+}
+
+func runEntityCreate(cmd *cobra.Command, args []string) error {
+	format, err := output.ParseFormat(entityFormat)
+	if err != nil {
+		return withExitCode(ExitUsage, err)
+	}
+
+	path := args[0]
+	var task entityCreateFile
+	if err := decodePlanDirFile(path, &task); err != nil {
+		return withExitCode(ExitValidation, err)
+	}
+	if task.Title == "" {
+		return withExitCode(ExitValidation, fmt.Errorf("%s: task title is required", path))
+	}
+	if task.CodeFile != "" {
+		if task.Code != "" {
+			return withExitCode(ExitValidation, fmt.Errorf("%s: set either code or code_file, not both", path))
+		}
+		if task.Code, err = readCodeFile(path, task.CodeFile); err != nil {
+			return err
+		}
+	}
+	if entityParentID != "" {
+		task.ParentID = entityParentID
+	}
+	if task.ParentID == "" {
+		return withExitCode(ExitUsage, fmt.Errorf("a parent entity is required (use --parent or set parent_id in %s)", path))
+	}
+
+	opts := entityOpts.resolved()
+	client, err := entityClient(opts)
+	if err != nil {
+		return err
+	}
+
+	resp, err := client.CreateEntity(api.EntityCreateRequest{
+		ParentID:            task.ParentID,
+		Key:                 task.Key,
+		Title:               task.Title,
+		Description:         task.Description,
+		SequenceOrder:       task.SequenceOrder,
+		ExecutionMode:       task.ExecutionMode,
+		Code:                task.Code,
+		DependenciesLabeled: task.DependenciesLabeled,
+		InputSchema:         task.InputSchema,
+		OutputSchema:        task.OutputSchema,
+		SuccessCriteria:     task.SuccessCriteria,
+		Boundaries:          task.Boundaries,
+	}, opts.ServiceKey)
+	if err != nil {
+		return withExitCode(ExitAPI, fmt.Errorf("failed to create entity: %w", err))
+	}
+
+	return renderEntity(format, "Created", resp)
+}
+
+func runEntityUpdate(cmd *cobra.Command, args []string) error {
+	format, err := output.ParseFormat(entityFormat)
+	if err != nil {
+		return withExitCode(ExitUsage, err)
+	}
+
+	var update entityUpdateFile
+	if entityFile != "" {
+		if err := decodePlanDirFile(entityFile, &update); err != nil {
+			return withExitCode(ExitValidation, err)
+		}
+		if update.CodeFile != "" {
+			if update.Code != nil {
+				return withExitCode(ExitValidation, fmt.Errorf("%s: set either code or code_file, not both", entityFile))
+			}
+			code, err := readCodeFile(entityFile, update.CodeFile)
+			if err != nil {
+				return err
+			}
+			update.Code = &code
+		}
+	}
+
+	flags := cmd.Flags()
+	if flags.Changed("title") {
+		update.Title = &entityTitle
+	}
+	if flags.Changed("description") {
+		update.Description = &entityDescription
+	}
+	if flags.Changed("execution-mode") {
+		update.ExecutionMode = &entityExecutionMode
+	}
+	if entityCodeFile != "" {
+		data, err := os.ReadFile(entityCodeFile)
+		if err != nil {
+			return fmt.Errorf("failed to read code file: %w", err)
+		}
+		code := string(data)
+		update.Code = &code
+	}
+
+	req := update.EntityUpdateRequest
+	if req.Key == nil && req.Title == nil && req.Description == nil && req.SequenceOrder == nil &&
+		req.ExecutionMode == nil && req.Code == nil && req.DependenciesLabeled == nil &&
+		req.InputSchema == nil && req.OutputSchema == nil && req.SuccessCriteria == nil && req.Boundaries == nil {
+		return withExitCode(ExitUsage, fmt.Errorf("nothing to update (use --file, --title, --description, --execution-mode or --code-file)"))
+	}
+
+	opts := entityOpts.resolved()
+	client, err := entityClient(opts)
+	if err != nil {
+		return err
+	}
+
+	resp, err := client.UpdateEntity(args[0], req, opts.ServiceKey)
+	if err != nil {
+		return withExitCode(ExitAPI, fmt.Errorf("failed to update entity: %w", err))
+	}
+
+	return renderEntity(format, "Updated", resp)
+}
+
+// renderEntity prints a created or updated entity
+func renderEntity(format output.Format, verb string, resp *api.EntityResponse) error {
+	e := resp.Entity
+	return output.Render(os.Stdout, format, resp, func() error {
+		fmt.Println(output.OK(fmt.Sprintf("%s '%s'", verb, e.Title)))
+		table := output.NewTable()
+		table.Indent = "  "
+		table.Row("ID", e.ID)
+		table.Row("Execution mode", string(e.ExecutionMode))
+		table.Row("Status", e.Status)
+		if e.ParentID != nil {
+			table.Row("Parent", *e.ParentID)
+		}
+		if len(e.DependenciesLabeled) > 0 {
+			table.Row("Dependencies", fmt.Sprintf("%d", len(e.DependenciesLabeled)))
+		}
+		return table.Render(os.Stdout)
+	})
+}
+
+func init() {
+	entityCreateCmd.Flags().StringVar(&entityParentID, "parent", "", "Parent entity (project or objective) to create the task under")
+
+	entityUpdateCmd.Flags().StringVar(&entityFile, "file", "", "Task file (YAML or JSON) with the fields to change")
+	entityUpdateCmd.Flags().StringVar(&entityTitle, "title", "", "New title")
+	entityUpdateCmd.Flags().StringVar(&entityDescription, "description", "", "New description")
+	entityUpdateCmd.Flags().StringVar(&entityExecutionMode, "execution-mode", "", "New execution mode (e.g. BASH, PYTHON, LLM_REASONING)")
+	entityUpdateCmd.Flags().StringVar(&entityCodeFile, "code-file", "", "File whose contents replace the task's code")
+
+	for _, c := range []*cobra.Command{entityCreateCmd, entityUpdateCmd} {
+		addServiceKeyFlags(c, &entityOpts)
+		c.Flags().StringVar(&entityFormat, "format", "table", output.FormatUsage)
+	}
+
+	entityCmd.AddCommand(entityCreateCmd)
+	entityCmd.AddCommand(entityUpdateCmd)
+}
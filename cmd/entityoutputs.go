@@ -0,0 +1,185 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"sort"
+
+	"github.com/kindship-ai/kindship-cli/internal/api"
+	"github.com/kindship-ai/kindship-cli/internal/output"
+	"github.com/kindship-ai/kindship-cli/internal/validator"
+
+	"github.com/spf13/cobra"
+)
+
+var entityOutputsCmd = &cobra.Command{
+	Use:   "outputs <entity-id>",
+	Short: "Print the structured outputs of a planning entity",
+	Long: `Print the structured output of the latest successful execution attempt of a
+planning entity, or of every attempt with --all.
+
+--path selects part of the output with a jq-style path:
+  .key, ["key"]  Object key (missing keys yield null)
+  [N]            Array element; negative N counts from the end
+  []             Every element of an array or object
+
+Values are printed as JSON, one per line when a path selects several;
+--raw prints strings without quotes. Which attempt was printed goes to
+stderr, so the output can be piped.
+
+Examples:
+  kindship entity outputs 550e8400-e29b-41d4-a716-446655440000
+  kindship entity outputs 550e8400-e29b-41d4-a716-446655440000 --path '.items[].name' --raw
+  kindship entity outputs 550e8400-e29b-41d4-a716-446655440000 --all --format json`,
+	Args:         usageArgs(cobra.ExactArgs(1)),
+	SilenceUsage: true,
+	RunE:         runEntityOutputs,
+}
+
+var (
+	entityOutputsAll  bool
+	entityOutputsPath string
+	entityOutputsRaw  bool
+)
+
+// attemptOutput is the structured output of one attempt, as selected by
+// --path
+type attemptOutput struct {
+	AttemptNumber int                        `json:"attempt_number"`
+	ExecutionID   string                     `json:"execution_id"`
+	Status        api.ExecutionAttemptStatus `json:"status"`
+	// Values holds what --path selected; empty without a structured output
+	Values []interface{} `json:"-"`
+	// Value is Values as printed: one value, or all of them as an array
+	Value interface{} `json:"value"`
+}
+
+func init() {
+	entityOutputsCmd.Flags().BoolVar(&entityOutputsAll, "all", false, "Print the outputs of every attempt, not only the latest successful one")
+	entityOutputsCmd.Flags().StringVar(&entityOutputsPath, "path", ".", "jq-style path selecting part of the output (e.g. .items[0].name)")
+	entityOutputsCmd.Flags().BoolVarP(&entityOutputsRaw, "raw", "r", false, "Print string values without JSON quotes")
+	entityOutputsCmd.Flags().StringVar(&entityFormat, "format", "table", output.FormatUsage)
+	addServiceKeyFlags(entityOutputsCmd, &entityOpts)
+	entityCmd.AddCommand(entityOutputsCmd)
+}
+
+func runEntityOutputs(cmd *cobra.Command, args []string) error {
+	format, err := output.ParseFormat(entityFormat)
+	if err != nil {
+		return withExitCode(ExitUsage, err)
+	}
+	if err := validator.ParsePath(entityOutputsPath); err != nil {
+		return withExitCode(ExitUsage, fmt.Errorf("invalid --path: %w", err))
+	}
+
+	opts := entityOpts.resolved()
+	client, err := entityClient(opts)
+	if err != nil {
+		return err
+	}
+
+	resp, err := client.ListAttemptsWithOutputs(args[0], opts.ServiceKey)
+	if err != nil {
+		return fmt.Errorf("failed to list attempts: %w", err)
+	}
+	attempts := resp.Attempts
+	sort.Slice(attempts, func(i, j int) bool { return attempts[i].AttemptNumber > attempts[j].AttemptNumber })
+
+	if !entityOutputsAll {
+		latest := latestSuccessfulAttempt(attempts)
+		if latest == nil {
+			return fmt.Errorf("entity %s has no successful attempt (see 'kindship entity attempts %s')", args[0], args[0])
+		}
+		out, err := selectAttemptOutput(latest)
+		if err != nil {
+			return withExitCode(ExitValidation, err)
+		}
+		if out.Values == nil {
+			return fmt.Errorf("attempt #%d of entity %s has no structured output", out.AttemptNumber, args[0])
+		}
+		fmt.Fprintln(os.Stderr, output.Dim(fmt.Sprintf("Attempt #%d (%s, execution %s)", out.AttemptNumber, out.Status, out.ExecutionID)))
+		return output.Render(os.Stdout, format, out.Value, func() error {
+			return printOutputValues(out.Values, "")
+		})
+	}
+
+	outs := make([]*attemptOutput, 0, len(attempts))
+	for i := range attempts {
+		out, err := selectAttemptOutput(&attempts[i])
+		if err != nil {
+			return withExitCode(ExitValidation, err)
+		}
+		outs = append(outs, out)
+	}
+	return output.Render(os.Stdout, format, outs, func() error {
+		if len(outs) == 0 {
+			fmt.Println("No execution attempts found.")
+			return nil
+		}
+		for i, out := range outs {
+			if i > 0 {
+				fmt.Println()
+			}
+			fmt.Printf("%s  %s  %s\n", output.Bold(fmt.Sprintf("Attempt #%d", out.AttemptNumber)),
+				colorAttemptStatus(out.Status), output.Dim(out.ExecutionID))
+			if out.Values == nil {
+				fmt.Println(output.Dim("  (no structured output)"))
+				continue
+			}
+			if err := printOutputValues(out.Values, "  "); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// latestSuccessfulAttempt returns the successful attempt with the highest
+// number, or nil
+func latestSuccessfulAttempt(attempts []api.ExecutionAttempt) *api.ExecutionAttempt {
+	var latest *api.ExecutionAttempt
+	for i := range attempts {
+		if attempts[i].Status == api.ExecutionAttemptStatusSuccess &&
+			(latest == nil || attempts[i].AttemptNumber > latest.AttemptNumber) {
+			latest = &attempts[i]
+		}
+	}
+	return latest
+}
+
+// selectAttemptOutput applies --path to an attempt's structured output
+func selectAttemptOutput(a *api.ExecutionAttempt) (*attemptOutput, error) {
+	out := &attemptOutput{AttemptNumber: a.AttemptNumber, ExecutionID: a.ID, Status: a.Status}
+	if a.Outputs == nil || a.Outputs.Structured == nil {
+		return out, nil
+	}
+	values, err := validator.ExtractPath(a.Outputs.Structured, entityOutputsPath)
+	if err != nil {
+		return nil, fmt.Errorf("attempt #%d: %w", a.AttemptNumber, err)
+	}
+	out.Values = values
+	if len(values) == 1 {
+		out.Value = values[0]
+	} else {
+		out.Value = values
+	}
+	return out, nil
+}
+
+// printOutputValues prints values as indented JSON, one after the other,
+// and strings without quotes with --raw
+func printOutputValues(values []interface{}, indent string) error {
+	for _, v := range values {
+		if s, ok := v.(string); ok && entityOutputsRaw {
+			fmt.Println(indent + s)
+			continue
+		}
+		data, err := json.MarshalIndent(v, indent, "  ")
+		if err != nil {
+			return err
+		}
+		fmt.Println(indent + string(data))
+	}
+	return nil
+}
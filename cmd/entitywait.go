@@ -0,0 +1,180 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/kindship-ai/kindship-cli/internal/api"
+	"github.com/kindship-ai/kindship-cli/internal/output"
+
+	"github.com/spf13/cobra"
+)
+
+var entityWaitCmd = &cobra.Command{
+	Use:   "wait <entity-id>",
+	Short: "Wait for a planning entity to finish",
+	Long: `Polls a planning entity until it reaches a terminal status (COMPLETED,
+FAILED or CANCELLED), so CI pipelines can block on a remote agent finishing
+a task.
+
+--for lists the statuses that count as success (default COMPLETED). The
+command exits 0 when the entity reaches one of them, 5 when it ends in any
+other terminal status, and 9 when --timeout passes first. Status changes
+are reported on stderr while waiting.
+
+Examples:
+  kindship entity wait 550e8400-e29b-41d4-a716-446655440000
+  kindship entity wait 550e8400-e29b-41d4-a716-446655440000 --for COMPLETED --timeout 1h
+  kindship entity wait 550e8400-e29b-41d4-a716-446655440000 --for COMPLETED,FAILED --format json
+
+` + exitCodeHelp,
+	Args:         usageArgs(cobra.ExactArgs(1)),
+	SilenceUsage: true,
+	RunE:         runEntityWait,
+}
+
+var (
+	entityWaitFor      []string
+	entityWaitTimeout  time.Duration
+	entityWaitInterval time.Duration
+)
+
+// entityTerminalStatuses are the entity statuses a wait can end on
+var entityTerminalStatuses = []string{"COMPLETED", "FAILED", "CANCELLED"}
+
+// EntityWaitResult is printed by 'kindship entity wait'
+type EntityWaitResult struct {
+	EntityID      string `json:"entity_id"`
+	Title         string `json:"title"`
+	Status        string `json:"status"`
+	Reached       bool   `json:"reached"`
+	TimedOut      bool   `json:"timed_out"`
+	FailureReason string `json:"failure_reason,omitempty"`
+	WaitedMS      int64  `json:"waited_ms"`
+}
+
+func init() {
+	entityWaitCmd.Flags().StringSliceVar(&entityWaitFor, "for", []string{"COMPLETED"}, "Terminal statuses that count as success (COMPLETED, FAILED, CANCELLED)")
+	entityWaitCmd.Flags().DurationVar(&entityWaitTimeout, "timeout", 0, "Give up after this long, e.g. 30m or 1h (default no limit)")
+	entityWaitCmd.Flags().DurationVar(&entityWaitInterval, "interval", 10*time.Second, "Time between status checks")
+	addServiceKeyFlags(entityWaitCmd, &entityOpts)
+	entityWaitCmd.Flags().StringVar(&entityFormat, "format", "table", output.FormatUsage)
+
+	entityCmd.AddCommand(entityWaitCmd)
+}
+
+func runEntityWait(cmd *cobra.Command, args []string) error {
+	format, err := output.ParseFormat(entityFormat)
+	if err != nil {
+		return withExitCode(ExitUsage, err)
+	}
+	want := map[string]bool{}
+	var wanted []string
+	for _, status := range entityWaitFor {
+		status = strings.ToUpper(strings.TrimSpace(status))
+		if !isTerminalEntityStatus(status) {
+			return withExitCode(ExitUsage, fmt.Errorf("invalid --for status %q (valid: %s)", status, strings.Join(entityTerminalStatuses, ", ")))
+		}
+		if !want[status] {
+			want[status] = true
+			wanted = append(wanted, status)
+		}
+	}
+	if entityWaitInterval <= 0 {
+		return withExitCode(ExitUsage, fmt.Errorf("--interval must be positive"))
+	}
+
+	opts := entityOpts.resolved()
+	client, err := entityClient(opts)
+	if err != nil {
+		return err
+	}
+
+	entityID := args[0]
+	result := EntityWaitResult{EntityID: entityID}
+	start := time.Now()
+	var deadline time.Time
+	if entityWaitTimeout > 0 {
+		deadline = start.Add(entityWaitTimeout)
+	}
+
+	for {
+		resp, err := client.FetchEntityForExecution(entityID, opts.ServiceKey)
+		if err != nil {
+			// Missing entities and rejected credentials will not fix themselves
+			var statusErr *api.StatusError
+			if errors.As(err, &statusErr) && statusErr.StatusCode < http.StatusInternalServerError {
+				return fmt.Errorf("failed to fetch entity: %w", err)
+			}
+			fmt.Fprintf(os.Stderr, "%s failed to fetch entity, retrying: %v\n", time.Now().Format("15:04:05"), err)
+		} else {
+			status := strings.ToUpper(resp.Entity.Status)
+			if status != result.Status {
+				fmt.Fprintf(os.Stderr, "%s %s: %s\n", time.Now().Format("15:04:05"), resp.Entity.Title, status)
+			}
+			result.Title, result.Status = resp.Entity.Title, status
+			if isTerminalEntityStatus(status) {
+				break
+			}
+		}
+
+		interval := entityWaitInterval
+		if !deadline.IsZero() {
+			remaining := time.Until(deadline)
+			if remaining <= 0 {
+				result.TimedOut = true
+				break
+			}
+			if interval > remaining {
+				interval = remaining
+			}
+		}
+		time.Sleep(interval)
+	}
+
+	result.WaitedMS = time.Since(start).Milliseconds()
+	result.Reached = !result.TimedOut && want[result.Status]
+	if result.Status == "FAILED" {
+		result.FailureReason = lastFailureReason(client, entityID, opts.ServiceKey)
+	}
+
+	waited := time.Duration(result.WaitedMS * int64(time.Millisecond)).Round(time.Second)
+	if err := output.Render(os.Stdout, format, result, func() error {
+		switch {
+		case result.Reached:
+			fmt.Println(output.OK(fmt.Sprintf("'%s' is %s (waited %s)", result.Title, result.Status, waited)))
+		case result.TimedOut:
+			fmt.Println(output.Fail(fmt.Sprintf("'%s' is still %s after %s", result.Title, result.Status, waited)))
+		default:
+			fmt.Println(output.Fail(fmt.Sprintf("'%s' ended %s (waited %s)", result.Title, result.Status, waited)))
+		}
+		if result.FailureReason != "" {
+			fmt.Printf("  Failure reason: %s\n", result.FailureReason)
+		}
+		return nil
+	}); err != nil {
+		return err
+	}
+
+	switch {
+	case result.TimedOut:
+		return withExitCode(ExitTimeout, fmt.Errorf("timed out after %s waiting for %s", entityWaitTimeout, strings.Join(wanted, " or ")))
+	case !result.Reached:
+		return withExitCode(ExitExecutionFailed, fmt.Errorf("entity ended %s, not %s", result.Status, strings.Join(wanted, " or ")))
+	}
+	return nil
+}
+
+// isTerminalEntityStatus reports whether an entity status is final
+func isTerminalEntityStatus(status string) bool {
+	for _, s := range entityTerminalStatuses {
+		if s == status {
+			return true
+		}
+	}
+	return false
+}
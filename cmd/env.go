@@ -0,0 +1,273 @@
+package cmd
+
+import (
+	"fmt"
+	"net"
+	"net/url"
+	"os"
+	"os/exec"
+	"time"
+
+	"github.com/kindship-ai/kindship-cli/internal/api"
+	"github.com/kindship-ai/kindship-cli/internal/config"
+	"github.com/kindship-ai/kindship-cli/internal/executor"
+	"github.com/kindship-ai/kindship-cli/internal/output"
+
+	"github.com/spf13/cobra"
+)
+
+var envCmd = &cobra.Command{
+	Use:   "env",
+	Short: "Show effective configuration and inspect the environment",
+	Long: `Without a subcommand, prints every setting the CLI consumes with its
+resolved value and where it came from: a flag, an environment variable, the
+repo config (.kindship/config.json), the global config, or the built-in
+default. Secrets are masked. Pass the same --agent-id, --service-key and
+--api-url flags as to 'kindship run' to see how they take precedence.
+
+Subcommands:
+  check    Verify runtimes, workspace, env vars and API connectivity
+
+Examples:
+  kindship env
+  kindship env --api-url http://localhost:3000
+  kindship env --format json`,
+	Args:         usageArgs(cobra.NoArgs),
+	SilenceUsage: true,
+	RunE:         runEnvShow,
+}
+
+var envCheckCmd = &cobra.Command{
+	Use:   "check",
+	Short: "Check the environment for everything the CLI needs",
+	Long: `Inspect the current machine or container for everything the CLI might need:
+
+- Runtimes used by executors (sh and python3, or powershell/cmd and python on
+  Windows; claude, kindship) and helpers (bash, node, git)
+- Workspace directory exists and is writable
+- Required environment variables (AGENT_ID, KINDSHIP_SERVICE_KEY)
+- DNS resolution and HTTP reachability of the Kindship API
+
+Exits non-zero if any check fails, so it can be used as a container
+healthcheck or init step.
+
+Examples:
+  kindship env check
+  kindship env check --json`,
+	Args:         cobra.NoArgs,
+	SilenceUsage: true,
+	RunE:         runEnvCheck,
+}
+
+var (
+	envCheckJSON bool
+)
+
+func init() {
+	envCheckCmd.Flags().BoolVar(&envCheckJSON, "json", false, "Output in JSON format")
+
+	envCmd.Flags().StringVar(&envOpts.AgentID, "agent-id", "", "Agent ID, as passed to other commands")
+	envCmd.Flags().StringVar(&envOpts.ServiceKey, "service-key", "", "Service key, as passed to other commands")
+	envCmd.Flags().StringVar(&envOpts.APIURL, "api-url", "", "API base URL, as passed to other commands")
+	envCmd.Flags().StringVar(&envFormat, "format", "table", output.FormatUsage)
+
+	envCmd.AddCommand(envCheckCmd)
+	rootCmd.AddCommand(envCmd)
+}
+
+// Check statuses reported by env check
+const (
+	checkOK   = "ok"
+	checkWarn = "warn"
+	checkFail = "fail"
+)
+
+// EnvCheckResult is the outcome of a single environment check
+type EnvCheckResult struct {
+	Name   string `json:"name"`
+	Status string `json:"status"`
+	Detail string `json:"detail,omitempty"`
+}
+
+// EnvCheckOutput is the JSON output for env check
+type EnvCheckOutput struct {
+	Healthy bool             `json:"healthy"`
+	Checks  []EnvCheckResult `json:"checks"`
+	Failed  int              `json:"failed"`
+	Warned  int              `json:"warned"`
+}
+
+func runEnvCheck(cmd *cobra.Command, args []string) error {
+	var checks []EnvCheckResult
+
+	checks = append(checks, checkRuntimes()...)
+	checks = append(checks, checkWorkspace())
+	checks = append(checks, checkEnvVars()...)
+
+	apiBase := os.Getenv("KINDSHIP_API_URL")
+	if apiBase == "" {
+		apiBase = config.DefaultAPIBaseURL()
+	}
+	checks = append(checks, checkAPI(apiBase)...)
+
+	output := EnvCheckOutput{Checks: checks}
+	for _, c := range checks {
+		switch c.Status {
+		case checkFail:
+			output.Failed++
+		case checkWarn:
+			output.Warned++
+		}
+	}
+	output.Healthy = output.Failed == 0
+
+	if envCheckJSON {
+		if err := printJSON(output); err != nil {
+			return err
+		}
+	} else {
+		fmt.Println("Kindship Environment Check")
+		fmt.Println("==========================")
+		fmt.Println()
+		for _, c := range checks {
+			icon := "✓"
+			switch c.Status {
+			case checkWarn:
+				icon = "!"
+			case checkFail:
+				icon = "✗"
+			}
+			if c.Detail != "" {
+				fmt.Printf("  %s %s: %s\n", icon, c.Name, c.Detail)
+			} else {
+				fmt.Printf("  %s %s\n", icon, c.Name)
+			}
+		}
+		fmt.Println()
+		fmt.Printf("%d checks, %d failed, %d warnings\n", len(checks), output.Failed, output.Warned)
+	}
+
+	if !output.Healthy {
+		return fmt.Errorf("environment check failed: %d check(s) failed", output.Failed)
+	}
+	return nil
+}
+
+// checkRuntimes verifies executor runtimes (required) and helper tools (optional)
+func checkRuntimes() []EnvCheckResult {
+	required := map[string]bool{}
+	opts := repoExecutionOptions()
+	for _, mode := range []api.ExecutionMode{api.ExecutionModeBash, api.ExecutionModePython, api.ExecutionModeLLMReasoning} {
+		for _, name := range executor.RequiredRuntimes(mode, nil, opts) {
+			required[name] = true
+		}
+	}
+
+	var results []EnvCheckResult
+	for _, name := range []string{executor.ShellRuntime(), executor.PythonRuntime, "claude", "kindship", "bash", "node", "git"} {
+		result := EnvCheckResult{Name: "runtime " + name}
+		if path, err := exec.LookPath(name); err == nil {
+			result.Status = checkOK
+			result.Detail = path
+		} else if required[name] {
+			result.Status = checkFail
+			result.Detail = "not found in PATH"
+		} else {
+			result.Status = checkWarn
+			result.Detail = "not found in PATH (optional)"
+		}
+		results = append(results, result)
+	}
+	return results
+}
+
+// checkWorkspace verifies the workspace directory exists and is writable
+func checkWorkspace() EnvCheckResult {
+	workDir := executionWorkDir()
+	result := EnvCheckResult{Name: "workspace " + workDir}
+
+	info, err := os.Stat(workDir)
+	if err != nil {
+		result.Status = checkFail
+		result.Detail = err.Error()
+		return result
+	}
+	if !info.IsDir() {
+		result.Status = checkFail
+		result.Detail = "not a directory"
+		return result
+	}
+
+	probe, err := os.CreateTemp(workDir, ".kindship-envcheck-*")
+	if err != nil {
+		result.Status = checkFail
+		result.Detail = fmt.Sprintf("not writable: %v", err)
+		return result
+	}
+	probe.Close()
+	os.Remove(probe.Name())
+
+	result.Status = checkOK
+	result.Detail = "writable"
+	return result
+}
+
+// checkEnvVars verifies container environment variables are set
+func checkEnvVars() []EnvCheckResult {
+	var results []EnvCheckResult
+	for _, name := range []string{"AGENT_ID", "KINDSHIP_SERVICE_KEY"} {
+		result := EnvCheckResult{Name: "env " + name, Status: checkOK, Detail: "set"}
+		if os.Getenv(name) == "" {
+			result.Status = checkFail
+			result.Detail = "not set"
+		}
+		results = append(results, result)
+	}
+	for _, name := range []string{"KINDSHIP_API_URL", "AXIOM_TOKEN"} {
+		result := EnvCheckResult{Name: "env " + name, Status: checkOK, Detail: "set"}
+		if os.Getenv(name) == "" {
+			result.Status = checkWarn
+			result.Detail = "not set (optional)"
+		}
+		results = append(results, result)
+	}
+	return results
+}
+
+// checkAPI verifies DNS resolution and HTTP reachability of the API
+func checkAPI(apiBase string) []EnvCheckResult {
+	u, err := url.Parse(apiBase)
+	if err != nil || u.Hostname() == "" {
+		return []EnvCheckResult{{Name: "api url", Status: checkFail, Detail: fmt.Sprintf("invalid URL: %s", apiBase)}}
+	}
+
+	dnsResult := EnvCheckResult{Name: "dns " + u.Hostname()}
+	addrs, err := net.LookupHost(u.Hostname())
+	if err != nil {
+		dnsResult.Status = checkFail
+		dnsResult.Detail = err.Error()
+		return []EnvCheckResult{dnsResult, {Name: "api " + apiBase, Status: checkFail, Detail: "skipped: DNS resolution failed"}}
+	}
+	dnsResult.Status = checkOK
+	dnsResult.Detail = fmt.Sprintf("resolved %d address(es)", len(addrs))
+
+	apiResult := EnvCheckResult{Name: "api " + apiBase}
+	client := api.NewHTTPClient(10 * time.Second)
+	start := time.Now()
+	resp, err := client.Get(apiBase)
+	if err != nil {
+		apiResult.Status = checkFail
+		apiResult.Detail = err.Error()
+		return []EnvCheckResult{dnsResult, apiResult}
+	}
+	resp.Body.Close()
+
+	if resp.StatusCode >= 500 {
+		apiResult.Status = checkFail
+	} else {
+		apiResult.Status = checkOK
+	}
+	apiResult.Detail = fmt.Sprintf("HTTP %d in %v", resp.StatusCode, time.Since(start).Round(time.Millisecond))
+
+	return []EnvCheckResult{dnsResult, apiResult}
+}
@@ -0,0 +1,198 @@
+package cmd
+
+import (
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/kindship-ai/kindship-cli/internal/audit"
+	"github.com/kindship-ai/kindship-cli/internal/config"
+	"github.com/kindship-ai/kindship-cli/internal/executor"
+	"github.com/kindship-ai/kindship-cli/internal/output"
+
+	"github.com/spf13/cobra"
+)
+
+// Sources of an effective setting, in the order they take precedence
+const (
+	sourceFlag    = "flag"
+	sourceEnv     = "env"
+	sourceRepo    = "repo config"
+	sourceGlobal  = "global config"
+	sourceDefault = "default"
+	sourceUnset   = "unset"
+)
+
+// EnvSetting is one setting the CLI consumes with its resolved value
+type EnvSetting struct {
+	Name   string `json:"name"`
+	Value  string `json:"value"`
+	Source string `json:"source"`
+	// Via is the flag, variable or config key the value came from
+	Via string `json:"via,omitempty"`
+}
+
+var envFormat string
+
+// resolver collects settings in display order
+type resolver struct {
+	settings []EnvSetting
+}
+
+// add records a setting resolved from the first non-empty candidate
+func (r *resolver) add(name string, secret bool, candidates ...EnvSetting) {
+	setting := EnvSetting{Name: name, Source: sourceUnset}
+	for _, c := range candidates {
+		if c.Value != "" {
+			setting = c
+			setting.Name = name
+			break
+		}
+	}
+	if secret && setting.Source != sourceDefault && setting.Value != "" {
+		setting.Value = maskSecret(setting.Value)
+	}
+	r.settings = append(r.settings, setting)
+}
+
+// fromFlag is a candidate from a flag of cmd, if the user set it
+func fromFlag(cmd *cobra.Command, name, value string) EnvSetting {
+	if !cmd.Flags().Changed(name) {
+		return EnvSetting{}
+	}
+	return EnvSetting{Value: value, Source: sourceFlag, Via: "--" + name}
+}
+
+// fromEnv is a candidate from an environment variable
+func fromEnv(name string) EnvSetting {
+	return EnvSetting{Value: os.Getenv(name), Source: sourceEnv, Via: name}
+}
+
+// fromConfig is a candidate from a repo or global config key
+func fromConfig(source, key, value string) EnvSetting {
+	return EnvSetting{Value: value, Source: source, Via: key}
+}
+
+// fromDefault is the built-in default
+func fromDefault(value string) EnvSetting {
+	return EnvSetting{Value: value, Source: sourceDefault}
+}
+
+// dirSetting attributes a resolved config or state directory to the
+// variable that chose it (see config.GetGlobalConfigDir)
+func dirSetting(dir, xdgVar string) EnvSetting {
+	switch {
+	case dir == "":
+		return EnvSetting{}
+	case os.Getenv("KINDSHIP_CONFIG_DIR") != "":
+		return EnvSetting{Value: dir, Source: sourceEnv, Via: "KINDSHIP_CONFIG_DIR"}
+	case os.Getenv(xdgVar) != "" && strings.HasPrefix(dir, os.Getenv(xdgVar)):
+		return EnvSetting{Value: dir, Source: sourceEnv, Via: xdgVar}
+	}
+	return fromDefault(dir)
+}
+
+func runEnvShow(cmd *cobra.Command, args []string) error {
+	format, err := output.ParseFormat(envFormat)
+	if err != nil {
+		return withExitCode(ExitUsage, err)
+	}
+
+	global, _ := config.LoadGlobalConfig()
+	if global == nil {
+		global = &config.GlobalConfig{}
+	}
+	repo, _ := config.LoadRepoConfig()
+	if repo == nil {
+		repo = &config.RepoConfig{}
+	}
+
+	var r resolver
+	r.add("agent_id", false,
+		fromFlag(cmd, "agent-id", envOpts.AgentID),
+		fromEnv("AGENT_ID"),
+		fromConfig(sourceRepo, "agent_id", repo.AgentID),
+		fromConfig(sourceGlobal, "default_agent_id", global.DefaultAgentID))
+	r.add("service_key", true,
+		fromFlag(cmd, "service-key", envOpts.ServiceKey),
+		fromEnv("KINDSHIP_SERVICE_KEY"))
+	r.add("login_token", true,
+		fromConfig(sourceGlobal, "token", global.Token))
+	r.add("api_url", false,
+		fromFlag(cmd, "api-url", envOpts.APIURL),
+		fromEnv("KINDSHIP_API_URL"),
+		fromConfig(sourceGlobal, "api_base_url", global.APIBaseURL),
+		fromEnv("KINDSHIP_SELF_HOSTED_URL"),
+		fromConfig(sourceGlobal, "self_hosted.api_base_url", selfHostedURL(global)),
+		fromDefault(config.ProductionAPIBaseURL))
+
+	configDir, _ := config.GetGlobalConfigDir()
+	stateDir, _ := config.GetStateDir()
+	r.add("config_dir", false, dirSetting(configDir, "XDG_CONFIG_HOME"))
+	r.add("state_dir", false, dirSetting(stateDir, "XDG_STATE_HOME"))
+	repoConfigPath := ""
+	if dir, err := config.GetRepoConfigDir(); err == nil {
+		repoConfigPath = filepath.Join(dir, config.RepoConfigFile)
+	}
+	r.add("repo_config", false, fromDefault(repoConfigPath))
+
+	r.add("workspace", false,
+		fromConfig(sourceRepo, "workspace", repo.Workspace),
+		fromDefault(executor.DefaultWorkDir))
+	r.add("output_format", false,
+		fromConfig(sourceRepo, "output_format", repo.OutputFormat),
+		fromDefault(string(output.FormatTable)))
+	pollInterval := ""
+	if repo.PollInterval > 0 {
+		pollInterval = strconv.Itoa(repo.PollInterval)
+	}
+	r.add("poll_interval", false,
+		fromConfig(sourceRepo, "poll_interval", pollInterval),
+		fromDefault("30"))
+	r.add("llm_backend", false,
+		fromConfig(sourceRepo, "llm_backend", repo.LLMBackend),
+		fromDefault("claude"))
+	r.add("environment", false,
+		fromConfig(sourceRepo, "environment", repo.Environment))
+	r.add("shell", false,
+		fromConfig(sourceRepo, "shell", repo.Shell),
+		fromDefault(executor.ShellRuntime()))
+
+	r.add("log_level", false,
+		fromFlag(cmd, "log-level", logLevel),
+		fromEnv("KINDSHIP_LOG_LEVEL"),
+		fromDefault("info"))
+	r.add("axiom_token", true, fromEnv("AXIOM_TOKEN"))
+	r.add("axiom_dataset", false, fromEnv("AXIOM_DATASET"), fromDefault("kindship-logs"))
+
+	auditPath := ""
+	if stateDir != "" {
+		auditPath = filepath.Join(stateDir, audit.AuditFile)
+	}
+	r.add("audit_log", false, fromEnv("KINDSHIP_AUDIT_LOG"), fromDefault(auditPath))
+	r.add("audit_hmac_key", true, fromEnv("KINDSHIP_AUDIT_HMAC_KEY"))
+	mcpPath := ""
+	if configDir != "" {
+		mcpPath = filepath.Join(configDir, executor.MCPCatalogFile)
+	}
+	r.add("mcp_config", false, fromEnv("KINDSHIP_MCP_CONFIG"), fromDefault(mcpPath))
+	r.add("no_color", false, fromEnv("NO_COLOR"))
+
+	return output.Render(os.Stdout, format, r.settings, func() error {
+		table := output.NewTable("Setting", "Value", "Source")
+		for _, s := range r.settings {
+			source := s.Source
+			if s.Via != "" {
+				source += " (" + s.Via + ")"
+			}
+			value := s.Value
+			if s.Source == sourceUnset {
+				value = output.Dim("-")
+				source = output.Dim(source)
+			}
+			table.Row(s.Name, value, source)
+		}
+		return table.Render(os.Stdout)
+	})
+}
@@ -0,0 +1,107 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/spf13/cobra"
+)
+
+// Lifecycle events emitted with --events
+const (
+	eventFetching   = "fetching"
+	eventStarted    = "started"
+	eventExecuting  = "executing"
+	eventValidating = "validating"
+	eventCompleted  = "completed"
+)
+
+// eventsFormat is the --events value; empty disables events
+var eventsFormat string
+
+// eventsFlagHelp documents --events in command help
+const eventsFlagHelp = `Machine events:
+  --events ndjson  Write one JSON object per line to stdout as each task
+                   moves through fetching, started, executing, validating
+                   (tasks with an output_schema) and completed. Logs stay on
+                   stderr, so stdout carries only events; --follow,
+                   --local, --dry-run and --explain-inputs, which print to
+                   stdout, cannot be combined with it.`
+
+// LifecycleEvent is one line written to stdout with --events ndjson
+type LifecycleEvent struct {
+	Time          time.Time `json:"time"`
+	Event         string    `json:"event"`
+	AgentID       string    `json:"agent_id,omitempty"`
+	EntityID      string    `json:"entity_id,omitempty"`
+	ExecutionID   string    `json:"execution_id,omitempty"`
+	Attempt       int       `json:"attempt,omitempty"`
+	Title         string    `json:"title,omitempty"`
+	ExecutionMode string    `json:"execution_mode,omitempty"`
+	Status        string    `json:"status,omitempty"`
+	DurationMS    int64     `json:"duration_ms,omitempty"`
+}
+
+// eventWriter writes lifecycle events for one invocation. A nil writer
+// discards them.
+type eventWriter struct {
+	mu  sync.Mutex
+	out io.Writer
+}
+
+// addEventsFlag registers --events on cmd
+func addEventsFlag(cmd *cobra.Command) {
+	cmd.Flags().StringVar(&eventsFormat, "events", "", "Emit lifecycle events on stdout in this format (ndjson)")
+}
+
+// eventsFlag validates --events and returns the writer for its events, nil
+// when it is not set. stdout must be free for events, so modes that print
+// to it are rejected.
+func eventsFlag() (*eventWriter, error) {
+	switch eventsFormat {
+	case "":
+		return nil, nil
+	case "ndjson":
+	default:
+		return nil, withExitCode(ExitUsage, fmt.Errorf("unsupported --events format %q (expected ndjson)", eventsFormat))
+	}
+	var printing []string
+	for _, flag := range []struct {
+		name string
+		set  bool
+	}{
+		{"--follow", runFollow},
+		{"--local", runLocal},
+		{"--dry-run", runDryRun},
+		{"--explain-inputs", runExplainInputs},
+	} {
+		if flag.set {
+			printing = append(printing, flag.name)
+		}
+	}
+	if len(printing) > 0 {
+		return nil, withExitCode(ExitUsage, fmt.Errorf("--events cannot be combined with %s, which print to stdout", strings.Join(printing, ", ")))
+	}
+	return &eventWriter{out: os.Stdout}, nil
+}
+
+// emit writes a lifecycle event. Events from concurrent executions are
+// written whole, one per line.
+func (w *eventWriter) emit(e LifecycleEvent) {
+	if w == nil {
+		return
+	}
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	e.Time = time.Now().UTC()
+	data, err := json.Marshal(e)
+	if err != nil {
+		return
+	}
+	w.out.Write(append(data, '\n'))
+}
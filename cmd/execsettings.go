@@ -0,0 +1,53 @@
+package cmd
+
+import (
+	"time"
+
+	"github.com/kindship-ai/kindship-cli/internal/config"
+	"github.com/kindship-ai/kindship-cli/internal/executor"
+)
+
+// executionSettings are what every execution of one invocation runs under.
+// Commands build them once from their flags and the repo config and pass
+// them down in EntityExecutionParams, so nothing about an execution lives
+// in package state.
+type executionSettings struct {
+	// Exec configures the executors: work directory, shell, LLM policy,
+	// repository context and --follow
+	Exec executor.Options
+
+	// Secrets are the --secrets names injected into every execution that
+	// runs task code, on top of the entity's boundaries.secrets
+	Secrets []string
+
+	// Watchdog is the wall-clock ceiling for a single execution attempt,
+	// on top of the executors' own timeouts. 0 disables it.
+	Watchdog time.Duration
+
+	// Events receives lifecycle events; nil when --events is not set
+	Events *eventWriter
+}
+
+// repoExecutionOptions returns the default executor options with the
+// current repository's workspace and shell settings applied
+func repoExecutionOptions() executor.Options {
+	opts := executor.DefaultOptions()
+	repoConfig, err := config.LoadRepoConfig()
+	if err != nil {
+		return opts
+	}
+	settings := repoConfig.RepoSettings
+	if settings.Workspace != "" {
+		opts.WorkDir = settings.Workspace
+	}
+	if settings.Shell != "" {
+		opts.Shell = parseShell(settings.Shell)
+	}
+	return opts
+}
+
+// executionWorkDir is the directory tasks run in: the repository's
+// workspace setting, else executor.DefaultWorkDir
+func executionWorkDir() string {
+	return repoExecutionOptions().Dir()
+}
@@ -0,0 +1,100 @@
+package cmd
+
+import (
+	"errors"
+	"net/url"
+	"strings"
+
+	"github.com/kindship-ai/kindship-cli/internal/api"
+
+	"github.com/spf13/cobra"
+)
+
+// Process exit codes. These are stable so scripts can tell failures apart:
+// anything not classified below exits with ExitFailure.
+const (
+	ExitOK              = 0
+	ExitFailure         = 1 // unclassified error
+	ExitUsage           = 2 // invalid arguments, flags or missing configuration
+	ExitAuth            = 3 // missing or rejected credentials
+	ExitDependency      = 4 // dependencies of the entity are not met
+	ExitExecutionFailed = 5 // the entity ran and failed
+	ExitAPI             = 6 // the API was unreachable or returned an error
+	ExitValidation      = 7 // inputs did not match the entity's input_schema
+	ExitPanic           = 8 // the CLI crashed; in-flight executions were failed
+	ExitTimeout         = 9 // a wait ended before the expected state was reached
+)
+
+// exitCodeHelp documents the exit codes in command help
+const exitCodeHelp = `Exit codes:
+  0  Success
+  1  Unclassified error
+  2  Usage error (invalid arguments, flags or missing configuration)
+  3  Authentication error (missing or rejected credentials)
+  4  Dependencies not met
+  5  Execution failed
+  6  API error (unreachable or non-2xx response)
+  7  Input validation failed
+  8  Internal error (the CLI crashed)
+  9  Timed out waiting`
+
+// ExitError carries the exit code for an error returned from a command
+type ExitError struct {
+	Code int
+	Err  error
+}
+
+func (e *ExitError) Error() string {
+	return e.Err.Error()
+}
+
+func (e *ExitError) Unwrap() error {
+	return e.Err
+}
+
+// withExitCode attaches an exit code to err
+func withExitCode(code int, err error) error {
+	if err == nil {
+		return nil
+	}
+	return &ExitError{Code: code, Err: err}
+}
+
+// usageArgs wraps a cobra argument validator so its errors exit with ExitUsage
+func usageArgs(validate cobra.PositionalArgs) cobra.PositionalArgs {
+	return func(cmd *cobra.Command, args []string) error {
+		return withExitCode(ExitUsage, validate(cmd, args))
+	}
+}
+
+// ExitCode maps an error returned by Execute to a process exit code
+func ExitCode(err error) int {
+	if err == nil {
+		return ExitOK
+	}
+
+	var exitErr *ExitError
+	if errors.As(err, &exitErr) {
+		return exitErr.Code
+	}
+
+	var statusErr *api.StatusError
+	if errors.As(err, &statusErr) {
+		if statusErr.IsAuthError() {
+			return ExitAuth
+		}
+		return ExitAPI
+	}
+
+	var urlErr *url.Error
+	if errors.As(err, &urlErr) {
+		return ExitAPI
+	}
+
+	// Cobra reports unknown commands as plain errors
+	if strings.HasPrefix(err.Error(), "unknown command") {
+		return ExitUsage
+	}
+
+	return ExitFailure
+}
@@ -0,0 +1,120 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/kindship-ai/kindship-cli/internal/api"
+	"github.com/kindship-ai/kindship-cli/internal/logging"
+	"github.com/kindship-ai/kindship-cli/internal/output"
+)
+
+// runExplainInputs is the --explain-inputs flag of run
+var runExplainInputs bool
+
+// maxInputPreview caps the preview of an input in the provenance report
+const maxInputPreview = 60
+
+// explainInputs prints, for each input label of a fetched entity, the
+// dependency entity and attempt that produced it, its size and a preview.
+// Labeled dependencies that produced no input are listed too. The
+// producing attempt is the dependency's latest successful one; a newer
+// attempt that did not succeed is flagged, since it often explains stale
+// inputs.
+func explainInputs(entityResp *api.EntityExecuteResponse, client *api.Client, serviceKey string, log *logging.Logger) error {
+	pending := map[string]bool{}
+	for _, dep := range entityResp.DependenciesStatus.Pending {
+		pending[dep.Label] = true
+	}
+	labels := make([]string, 0, len(entityResp.Inputs))
+	for label := range entityResp.Inputs {
+		labels = append(labels, label)
+	}
+	for label := range entityResp.Entity.DependenciesLabeled {
+		if _, ok := entityResp.Inputs[label]; !ok {
+			labels = append(labels, label)
+		}
+	}
+	sort.Strings(labels)
+
+	fmt.Println(output.Bold(fmt.Sprintf("Inputs of %s", entityResp.Entity.ID)))
+	if len(labels) == 0 {
+		fmt.Println("  No inputs.")
+		fmt.Println()
+		return nil
+	}
+
+	table := output.NewTable("Label", "Source", "Attempt", "Size", "Preview")
+	table.Indent = "  "
+	for _, label := range labels {
+		depID, isDep := entityResp.Entity.DependenciesLabeled[label]
+		value, hasValue := entityResp.Inputs[label]
+
+		source := output.Dim("entity inputs")
+		attempt := "-"
+		if isDep {
+			source = depID
+			attempt = producingAttempt(client, depID, serviceKey, log)
+		}
+		if !hasValue {
+			reason := "no output"
+			if pending[label] {
+				reason = "dependency pending"
+			}
+			table.Row(label, source, attempt, "-", output.Yellow(reason))
+			continue
+		}
+
+		data, err := json.Marshal(value)
+		if err != nil {
+			return fmt.Errorf("input %s: %w", label, err)
+		}
+		table.Row(label, source, attempt, fmt.Sprintf("%d B", len(data)), inputPreview(data))
+	}
+	if err := table.Render(os.Stdout); err != nil {
+		return err
+	}
+	fmt.Println()
+	return nil
+}
+
+// producingAttempt describes the attempt of a dependency whose output is
+// passed as input: its latest successful one
+func producingAttempt(client *api.Client, depID, serviceKey string, log *logging.Logger) string {
+	resp, err := client.ListAttempts(depID, serviceKey)
+	if err != nil {
+		log.Warn("Failed to list attempts of dependency", map[string]interface{}{
+			"entity_id": depID,
+			"error":     err.Error(),
+		})
+		return output.Dim("unknown")
+	}
+	latest := latestSuccessfulAttempt(resp.Attempts)
+	if latest == nil {
+		return output.Yellow("none succeeded")
+	}
+	desc := "#" + strconv.Itoa(latest.AttemptNumber)
+	if latest.CompletedAt != nil {
+		desc += " " + output.Dim(latest.CompletedAt.Local().Format("2006-01-02 15:04"))
+	}
+	for _, a := range resp.Attempts {
+		if a.AttemptNumber > latest.AttemptNumber && a.Status != api.ExecutionAttemptStatusSuccess {
+			desc += " " + output.Yellow(fmt.Sprintf("(newer #%d %s)", a.AttemptNumber, a.Status))
+			break
+		}
+	}
+	return desc
+}
+
+// inputPreview renders an input's JSON on one line, truncated
+func inputPreview(data []byte) string {
+	s := strings.Join(strings.Fields(string(data)), " ")
+	if len(s) > maxInputPreview {
+		s = s[:maxInputPreview] + "..."
+	}
+	return s
+}
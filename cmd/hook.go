@@ -6,6 +6,8 @@ import (
 	"io"
 	"net/http"
 	"os"
+	"os/exec"
+	"strings"
 	"time"
 
 	"github.com/kindship-ai/kindship-cli/internal/api"
@@ -25,8 +27,13 @@ var hookCmd = &cobra.Command{
 var hookStartCmd = &cobra.Command{
 	Use:   "start",
 	Short: "Session start hook handler",
-	Long:  `Called by Claude Code at session start. Returns context about the current agent and any pending tasks.`,
-	RunE:  runHookStart,
+	Long: `Called by Claude Code at session start. Returns context about the current agent and any pending tasks.
+
+The next task is served from a local cache so sessions open without waiting
+on the API. A cached task older than 5 minutes is refreshed in the
+background and shown meanwhile, with task_fetched_at and the context noting
+its age. Without a usable cache the task is fetched live.`,
+	RunE: runHookStart,
 }
 
 var hookStopCmd = &cobra.Command{
@@ -55,6 +62,10 @@ type HookStartOutput struct {
 	CurrentTask *HookTaskInfo  `json:"current_task,omitempty"`
 	Context     string         `json:"context,omitempty"`
 	Error       string         `json:"error,omitempty"`
+
+	// TaskFetchedAt is when the task (or the absence of one) was read from
+	// the API; older than the session start when served from the cache
+	TaskFetchedAt *time.Time `json:"task_fetched_at,omitempty"`
 }
 
 // HookAgentInfo represents agent info in hook output
@@ -102,8 +113,24 @@ func runHookStart(cmd *cobra.Command, args []string) error {
 		Slug: repoConfig.AgentSlug,
 	}
 
-	// Try to get next task
-	task, err := fetchNextTask(ctx, repoConfig.AgentID)
+	// Serve the next task from the cache, refreshing it in the background
+	// when stale, so the session never waits on the API
+	var task *api.TaskInfo
+	var fetchedAt time.Time
+	cached, fromCache := cachedTaskFor(ctx, repoConfig.AgentID)
+	if fromCache {
+		task, fetchedAt = cached.Task, cached.FetchedAt
+		if time.Since(fetchedAt) >= hookTaskCacheTTL {
+			startHookRefresh(repoConfig.AgentID)
+		}
+	} else {
+		task, err = refreshNextTask(ctx, repoConfig.AgentID, hookLiveFetchTimeout)
+		fetchedAt = time.Now()
+	}
+	if err == nil {
+		output.TaskFetchedAt = &fetchedAt
+	}
+
 	if err != nil {
 		output.Context = fmt.Sprintf("Could not fetch current task: %v", err)
 	} else if task != nil {
@@ -117,10 +144,77 @@ func runHookStart(cmd *cobra.Command, args []string) error {
 	} else {
 		output.Context = "No pending tasks. Use '/kindship plan submit' to create tasks."
 	}
+	if fromCache {
+		output.Context += fmt.Sprintf("\n\n(Task info cached, fetched %s.)", hookTaskAge(fetchedAt))
+	}
+
+	if gitContext := hookGitContext(); gitContext != "" {
+		output.Context += "\n\n" + gitContext
+	}
 
 	return printJSON(output)
 }
 
+// maxHookModifiedFiles caps the modified-file list in hook start context
+const maxHookModifiedFiles = 10
+
+// hookGitContext describes in-progress work in the current repository:
+// the branch, how it compares to its upstream and the modified files.
+// Returns "" outside a git repository.
+func hookGitContext() string {
+	root, err := config.FindRepoRoot()
+	if err != nil {
+		return ""
+	}
+	git := func(args ...string) (string, error) {
+		out, err := exec.Command("git", append([]string{"-C", root}, args...)...).Output()
+		return strings.TrimSpace(string(out)), err
+	}
+
+	branch, err := git("branch", "--show-current")
+	if err != nil {
+		return ""
+	}
+	if branch == "" {
+		commit, _ := git("rev-parse", "--short", "HEAD")
+		branch = "detached HEAD at " + commit
+	}
+
+	var b strings.Builder
+	b.WriteString("Branch: " + branch)
+	if upstream, err := git("rev-parse", "--abbrev-ref", "@{upstream}"); err != nil {
+		b.WriteString(" (no upstream)")
+	} else if counts, err := git("rev-list", "--left-right", "--count", "HEAD...@{upstream}"); err == nil {
+		var ahead, behind int
+		fmt.Sscanf(counts, "%d %d", &ahead, &behind)
+		if ahead == 0 && behind == 0 {
+			b.WriteString(fmt.Sprintf(" (up to date with %s)", upstream))
+		} else {
+			b.WriteString(fmt.Sprintf(" (%d ahead, %d behind %s)", ahead, behind, upstream))
+		}
+	}
+	b.WriteString("\n")
+
+	status, err := git("status", "--porcelain")
+	if err != nil {
+		return strings.TrimSpace(b.String())
+	}
+	if status == "" {
+		b.WriteString("Working tree: clean")
+		return b.String()
+	}
+	lines := strings.Split(status, "\n")
+	b.WriteString(fmt.Sprintf("Modified files (%d):\n", len(lines)))
+	for i, line := range lines {
+		if i == maxHookModifiedFiles {
+			b.WriteString(fmt.Sprintf("- ... and %d more\n", len(lines)-i))
+			break
+		}
+		b.WriteString(fmt.Sprintf("- %s\n", strings.TrimSpace(line)))
+	}
+	return strings.TrimSpace(b.String())
+}
+
 func runHookStop(cmd *cobra.Command, args []string) error {
 	// Hook stop is called with summary file
 	if hookSummaryFile == "" {
@@ -157,7 +251,9 @@ func runHookStop(cmd *cobra.Command, args []string) error {
 	return nil
 }
 
-func fetchNextTask(ctx *auth.Context, agentID string) (*api.TaskInfo, error) {
+// fetchNextTask asks plan/next for the agent's next task, waiting at most
+// timeout
+func fetchNextTask(ctx *auth.Context, agentID string, timeout time.Duration) (*api.TaskInfo, error) {
 	endpoint := fmt.Sprintf("%s/api/cli/plan/next?agent_id=%s", ctx.APIBaseURL, agentID)
 
 	req, err := http.NewRequest(http.MethodGet, endpoint, nil)
@@ -165,11 +261,11 @@ func fetchNextTask(ctx *auth.Context, agentID string) (*api.TaskInfo, error) {
 		return nil, err
 	}
 
-	req.Header.Set("Authorization", ctx.GetAuthHeader())
+	ctx.SetAuthHeaders(req)
 	req.Header.Set("X-Kindship-CLI-Version", Version)
 	req.Header.Set("X-Kindship-Hook-Version", "1")
 
-	client := &http.Client{Timeout: 10 * time.Second}
+	client := api.NewHTTPClient(timeout)
 	resp, err := client.Do(req)
 	if err != nil {
 		return nil, err
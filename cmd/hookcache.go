@@ -0,0 +1,237 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"time"
+
+	"github.com/kindship-ai/kindship-cli/internal/api"
+	"github.com/kindship-ai/kindship-cli/internal/auth"
+	"github.com/kindship-ai/kindship-cli/internal/config"
+
+	"github.com/spf13/cobra"
+)
+
+const (
+	// hookTaskCacheFile caches the last plan/next answer per agent in the
+	// state dir, so hook start does not wait on the API
+	hookTaskCacheFile = "hook-next-task.json"
+	// hookTaskCacheTTL is how old a cached answer may be before hook start
+	// refreshes it in the background
+	hookTaskCacheTTL = 5 * time.Minute
+	// hookTaskCacheMaxAge is how old a cached answer may be and still be
+	// shown; older answers are fetched live
+	hookTaskCacheMaxAge = 24 * time.Hour
+	// hookTaskRefreshBackoff is how long a started background refresh
+	// suppresses starting another
+	hookTaskRefreshBackoff = 30 * time.Second
+	// hookLiveFetchTimeout bounds the live fetch when nothing is cached
+	hookLiveFetchTimeout = 3 * time.Second
+	// hookRefreshTimeout bounds the background refresh
+	hookRefreshTimeout = 10 * time.Second
+	// hookTaskLockTimeout is how long an update waits for the cache lock
+	// before it is skipped, so hook start is never held up
+	hookTaskLockTimeout = 500 * time.Millisecond
+	// hookTaskLockStale is the age after which a lock is assumed to be left
+	// behind by a killed process
+	hookTaskLockStale = 10 * time.Second
+)
+
+// cachedNextTask is a plan/next answer saved in hookTaskCacheFile. A nil
+// Task records that no task was pending.
+type cachedNextTask struct {
+	APIURL           string        `json:"api_url"`
+	FetchedAt        time.Time     `json:"fetched_at"`
+	Task             *api.TaskInfo `json:"task,omitempty"`
+	RefreshStartedAt time.Time     `json:"refresh_started_at,omitempty"`
+}
+
+var hookRefreshCmd = &cobra.Command{
+	Use:    "refresh",
+	Short:  "Refresh the cached next task (internal)",
+	Long:   `Fetches the next task from the API and caches it for hook start. Started in the background by 'kindship hook start' when its cached task is stale.`,
+	Hidden: true,
+	RunE:   runHookRefresh,
+}
+
+func init() {
+	hookCmd.AddCommand(hookRefreshCmd)
+}
+
+func runHookRefresh(cmd *cobra.Command, args []string) error {
+	ctx := auth.GetAuthContextOrNil()
+	if ctx == nil {
+		return nil
+	}
+	repoConfig, err := config.LoadRepoConfig()
+	if err != nil {
+		return nil
+	}
+	// Failures keep the previous answer; the next hook start tries again
+	// once the backoff has passed
+	_, _ = refreshNextTask(ctx, repoConfig.AgentID, hookRefreshTimeout)
+	return nil
+}
+
+// refreshNextTask fetches the agent's next task and caches the answer
+func refreshNextTask(ctx *auth.Context, agentID string, timeout time.Duration) (*api.TaskInfo, error) {
+	task, err := fetchNextTask(ctx, agentID, timeout)
+	if err != nil {
+		return nil, err
+	}
+	updateHookTaskCache(func(cache map[string]cachedNextTask) bool {
+		cache[agentID] = cachedNextTask{APIURL: ctx.APIBaseURL, FetchedAt: time.Now(), Task: task}
+		return true
+	})
+	return task, nil
+}
+
+// cachedTaskFor returns the agent's cached answer for the API, if one is
+// recent enough to show
+func cachedTaskFor(ctx *auth.Context, agentID string) (cachedNextTask, bool) {
+	cached, ok := loadHookTaskCache()[agentID]
+	if !ok || cached.APIURL != ctx.APIBaseURL || cached.FetchedAt.IsZero() {
+		return cachedNextTask{}, false
+	}
+	if time.Since(cached.FetchedAt) >= hookTaskCacheMaxAge {
+		return cachedNextTask{}, false
+	}
+	return cached, true
+}
+
+// startHookRefresh runs 'kindship hook refresh' detached from hook start,
+// unless another refresh was started within hookTaskRefreshBackoff. The
+// refresh outlives hook start, which returns the cached answer at once.
+// The start is recorded before the refresh runs, so its answer is never
+// overwritten with the stale one.
+func startHookRefresh(agentID string) {
+	claimed := updateHookTaskCache(func(cache map[string]cachedNextTask) bool {
+		cached, ok := cache[agentID]
+		if !ok || time.Since(cached.RefreshStartedAt) < hookTaskRefreshBackoff {
+			return false
+		}
+		cached.RefreshStartedAt = time.Now()
+		cache[agentID] = cached
+		return true
+	})
+	if !claimed {
+		return
+	}
+	execPath, err := os.Executable()
+	if err != nil {
+		return
+	}
+	refresh := exec.Command(execPath, "hook", "refresh")
+	refresh.Env = os.Environ()
+	if err := refresh.Start(); err != nil {
+		return
+	}
+	_ = refresh.Process.Release()
+}
+
+// hookTaskAge describes how long ago a cached answer was fetched
+func hookTaskAge(fetchedAt time.Time) string {
+	age := time.Since(fetchedAt)
+	switch {
+	case age < time.Minute:
+		return "just now"
+	case age < time.Hour:
+		return fmt.Sprintf("%dm ago", int(age.Minutes()))
+	default:
+		return fmt.Sprintf("%dh ago", int(age.Hours()))
+	}
+}
+
+// loadHookTaskCache reads the cache; a missing or unreadable cache is
+// empty
+func loadHookTaskCache() map[string]cachedNextTask {
+	cache := map[string]cachedNextTask{}
+	dir, err := config.GetStateDir()
+	if err != nil {
+		return cache
+	}
+	if data, err := os.ReadFile(filepath.Join(dir, hookTaskCacheFile)); err == nil {
+		_ = json.Unmarshal(data, &cache)
+	}
+	return cache
+}
+
+// updateHookTaskCache re-reads the cache, applies update and saves it,
+// holding a lock because concurrent sessions and refreshes all write it.
+// update reports whether it changed the cache; the result reports whether
+// a change was saved. When the lock is not taken in time nothing changes.
+func updateHookTaskCache(update func(map[string]cachedNextTask) bool) bool {
+	dir, err := config.GetStateDir()
+	if err != nil {
+		return false
+	}
+	if err := os.MkdirAll(dir, config.ConfigDirMode); err != nil {
+		return false
+	}
+	unlock, ok := lockHookTaskCache(dir)
+	if !ok {
+		return false
+	}
+	defer unlock()
+
+	cache := loadHookTaskCache()
+	if !update(cache) {
+		return false
+	}
+	return saveHookTaskCache(dir, cache)
+}
+
+// lockHookTaskCache takes the cache lock, a file created exclusively next
+// to the cache, waiting up to hookTaskLockTimeout. A lock older than
+// hookTaskLockStale is taken over.
+func lockHookTaskCache(dir string) (unlock func(), ok bool) {
+	path := filepath.Join(dir, hookTaskCacheFile+".lock")
+	deadline := time.Now().Add(hookTaskLockTimeout)
+	for {
+		f, err := os.OpenFile(path, os.O_WRONLY|os.O_CREATE|os.O_EXCL, config.ConfigFileMode)
+		if err == nil {
+			f.Close()
+			return func() { os.Remove(path) }, true
+		}
+		if !os.IsExist(err) {
+			return nil, false
+		}
+		if info, err := os.Stat(path); err == nil && time.Since(info.ModTime()) > hookTaskLockStale {
+			os.Remove(path)
+			continue
+		}
+		if time.Now().After(deadline) {
+			return nil, false
+		}
+		time.Sleep(20 * time.Millisecond)
+	}
+}
+
+// saveHookTaskCache writes the cache to dir, reporting failures only by
+// its result: without it the next hook start fetches live. The file is
+// replaced by a rename so hook start, which reads without the lock, never
+// sees a partial file.
+func saveHookTaskCache(dir string, cache map[string]cachedNextTask) bool {
+	data, err := json.MarshalIndent(cache, "", "  ")
+	if err != nil {
+		return false
+	}
+	tmp, err := os.CreateTemp(dir, hookTaskCacheFile+".*")
+	if err != nil {
+		return false
+	}
+	_, writeErr := tmp.Write(data)
+	closeErr := tmp.Close()
+	if writeErr != nil || closeErr != nil || os.Chmod(tmp.Name(), config.ConfigFileMode) != nil {
+		os.Remove(tmp.Name())
+		return false
+	}
+	if err := os.Rename(tmp.Name(), filepath.Join(dir, hookTaskCacheFile)); err != nil {
+		os.Remove(tmp.Name())
+		return false
+	}
+	return true
+}
@@ -0,0 +1,300 @@
+package cmd
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/kindship-ai/kindship-cli/internal/api"
+	"github.com/kindship-ai/kindship-cli/internal/config"
+	"github.com/kindship-ai/kindship-cli/internal/executor"
+	"github.com/kindship-ai/kindship-cli/internal/testharness"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+)
+
+// newIntegrationAPI starts a fake API and isolates the CLI from the
+// machine: config and state go to temporary directories, tasks run in a
+// temporary workspace, and nothing is sent to telemetry or the log sink.
+func newIntegrationAPI(t *testing.T) *testharness.FakeAPI {
+	t.Helper()
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	t.Setenv("KINDSHIP_CONFIG_DIR", home)
+	t.Setenv("KINDSHIP_NO_VERSION_CHECK", "1")
+	t.Setenv("DO_NOT_TRACK", "1")
+	for _, name := range []string{"AXIOM_TOKEN", "AGENT_ID", "KINDSHIP_SERVICE_KEY", "KINDSHIP_API_URL", "KINDSHIP_AUDIT_LOG"} {
+		t.Setenv(name, "")
+	}
+
+	// Run from a repository whose config points executions at a temporary
+	// workspace
+	repoRoot := t.TempDir()
+	repoConfig := &config.RepoConfig{RepoSettings: config.RepoSettings{Workspace: t.TempDir()}}
+	if err := config.SaveRepoConfig(repoConfig, repoRoot); err != nil {
+		t.Fatal(err)
+	}
+	prevDir, err := os.Getwd()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Chdir(repoRoot); err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { os.Chdir(prevDir) })
+
+	return testharness.NewFakeAPI(t)
+}
+
+// runCLI runs the CLI in-process with every flag back at its default, so
+// flags set by an earlier test do not leak into this one
+func runCLI(t *testing.T, args ...string) testharness.CommandResult {
+	t.Helper()
+	resetFlags(rootCmd)
+	return testharness.RunCommand(t, Execute, args...)
+}
+
+func resetFlags(cmd *cobra.Command) {
+	reset := func(f *pflag.Flag) {
+		if slice, ok := f.Value.(pflag.SliceValue); ok {
+			slice.Replace(nil)
+		} else {
+			f.Value.Set(f.DefValue)
+		}
+		f.Changed = false
+	}
+	cmd.Flags().VisitAll(reset)
+	cmd.PersistentFlags().VisitAll(reset)
+	for _, child := range cmd.Commands() {
+		resetFlags(child)
+	}
+}
+
+func bashEntity(title, code string) testharness.Entity {
+	e := testharness.Entity{}
+	e.Title = title
+	e.ExecutionMode = api.ExecutionModeBash
+	e.Code = &code
+	return e
+}
+
+func TestRunBashEntity(t *testing.T) {
+	fake := newIntegrationAPI(t)
+	fake.SetSecret("API_TOKEN", "s3cret")
+	entity := fake.AddEntity(bashEntity("Answer", `test "$API_TOKEN" = s3cret && echo '{"answer": 42}' > "$OUTPUT_FILE"`))
+
+	args := append([]string{"run", entity.ID, "--secrets", "API_TOKEN"}, fake.Flags("agent-1")...)
+	res := runCLI(t, args...)
+	if res.Err != nil {
+		t.Fatalf("run failed: %v\nstderr: %s", res.Err, res.Stderr)
+	}
+
+	if status := fake.EntityStatus(entity.ID); status != testharness.StatusCompleted {
+		t.Fatalf("entity status = %s, want %s", status, testharness.StatusCompleted)
+	}
+	execs := fake.ExecutionsFor(entity.ID)
+	if len(execs) != 1 || execs[0].Complete == nil {
+		t.Fatalf("want one completed execution, got %+v", execs)
+	}
+	outputs := execs[0].Complete.Outputs
+	if outputs == nil || outputs.Structured["answer"] != float64(42) {
+		t.Fatalf("structured output = %+v, want answer 42", outputs)
+	}
+}
+
+func TestRunUsesRepoWorkspace(t *testing.T) {
+	fake := newIntegrationAPI(t)
+	entity := fake.AddEntity(bashEntity("Where", `echo "{\"dir\": \"$(pwd -P)\"}" > "$OUTPUT_FILE"`))
+
+	res := runCLI(t, append([]string{"run", entity.ID}, fake.Flags("agent-1")...)...)
+	if res.Err != nil {
+		t.Fatalf("run failed: %v\nstderr: %s", res.Err, res.Stderr)
+	}
+
+	want, err := filepath.EvalSymlinks(executionWorkDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+	execs := fake.ExecutionsFor(entity.ID)
+	if len(execs) != 1 || execs[0].Complete == nil || execs[0].Complete.Outputs == nil {
+		t.Fatalf("want one completed execution with outputs, got %+v", execs)
+	}
+	if dir := execs[0].Complete.Outputs.Structured["dir"]; dir != want {
+		t.Errorf("task ran in %v, want the repo workspace %s", dir, want)
+	}
+}
+
+func TestRunBashFailureRedactsSecrets(t *testing.T) {
+	fake := newIntegrationAPI(t)
+	fake.SetSecret("API_TOKEN", "s3cret")
+	entity := fake.AddEntity(bashEntity("Leaky", `echo "token is $API_TOKEN" >&2; exit 3`))
+
+	args := append([]string{"run", entity.ID, "--secrets", "API_TOKEN"}, fake.Flags("agent-1")...)
+	res := runCLI(t, args...)
+	if res.Err == nil {
+		t.Fatal("run succeeded, want failure")
+	}
+	if code := ExitCode(res.Err); code != ExitExecutionFailed {
+		t.Errorf("exit code = %d, want %d", code, ExitExecutionFailed)
+	}
+
+	if status := fake.EntityStatus(entity.ID); status != testharness.StatusFailed {
+		t.Fatalf("entity status = %s, want %s", status, testharness.StatusFailed)
+	}
+	execs := fake.ExecutionsFor(entity.ID)
+	if len(execs) != 1 || execs[0].Complete == nil {
+		t.Fatalf("want one completed execution, got %+v", execs)
+	}
+	complete := execs[0].Complete
+	if complete.Status != api.ExecutionAttemptStatusFailed {
+		t.Errorf("completion status = %s, want FAILED", complete.Status)
+	}
+	if complete.Outputs != nil && strings.Contains(complete.Outputs.Stderr, "s3cret") {
+		t.Errorf("secret reported to the API: %q", complete.Outputs.Stderr)
+	}
+}
+
+func TestRunKillsLeftoverProcesses(t *testing.T) {
+	if runtime.GOOS != "linux" {
+		t.Skip("reads process state from /proc")
+	}
+	fake := newIntegrationAPI(t)
+	pidFile := filepath.Join(t.TempDir(), "pid")
+	entity := fake.AddEntity(bashEntity("Leaves a server", `sleep 60 >/dev/null 2>&1 & echo $! > `+pidFile))
+
+	res := runCLI(t, append([]string{"run", entity.ID}, fake.Flags("agent-1")...)...)
+	if res.Err != nil {
+		t.Fatalf("run failed: %v\nstderr: %s", res.Err, res.Stderr)
+	}
+
+	data, err := os.ReadFile(pidFile)
+	if err != nil {
+		t.Fatal(err)
+	}
+	pid, err := strconv.Atoi(strings.TrimSpace(string(data)))
+	if err != nil {
+		t.Fatal(err)
+	}
+	deadline := time.Now().Add(5 * time.Second)
+	for processAlive(pid) {
+		if time.Now().After(deadline) {
+			t.Fatalf("background process %d still running after the run ended", pid)
+		}
+		time.Sleep(50 * time.Millisecond)
+	}
+}
+
+// processAlive reports whether pid is running; a zombie waiting to be
+// reaped counts as dead
+func processAlive(pid int) bool {
+	stat, err := os.ReadFile(filepath.Join("/proc", strconv.Itoa(pid), "stat"))
+	if err != nil {
+		return false
+	}
+	fields := strings.Fields(string(stat[strings.LastIndexByte(string(stat), ')')+1:]))
+	return len(fields) > 0 && fields[0] != "Z"
+}
+
+func TestRunOrchestrateRunsChildrenInOrder(t *testing.T) {
+	fake := newIntegrationAPI(t)
+	parent := testharness.Entity{}
+	parent.Title = "Process"
+	parent.ExecutionMode = api.ExecutionModeOrchestrate
+	parentID := fake.AddEntity(parent).ID
+
+	first := bashEntity("First", `echo '{"n": 1}' > "$OUTPUT_FILE"`)
+	first.Parent = parentID
+	firstID := fake.AddEntity(first).ID
+
+	second := bashEntity("Second", `test "$(cat "$INPUT_PREV_FILE")" = '{"n":1}'`)
+	second.Parent = parentID
+	second.DependenciesLabeled = map[string]string{"prev": firstID}
+	secondID := fake.AddEntity(second).ID
+
+	res := runCLI(t, append([]string{"run", parentID}, fake.Flags("agent-1")...)...)
+	if res.Err != nil {
+		t.Fatalf("run failed: %v\nstderr: %s", res.Err, res.Stderr)
+	}
+
+	for _, id := range []string{firstID, secondID} {
+		if status := fake.EntityStatus(id); status != testharness.StatusCompleted {
+			t.Errorf("child %s status = %s, want %s", id, status, testharness.StatusCompleted)
+		}
+	}
+	execs := fake.Executions()
+	var order []string
+	for _, e := range execs {
+		if e.Mode == api.ExecutionModeBash {
+			order = append(order, e.EntityID)
+		}
+	}
+	if strings.Join(order, ",") != firstID+","+secondID {
+		t.Errorf("children ran in order %v, want %s then %s", order, firstID, secondID)
+	}
+}
+
+func TestAgentLoopDrainsQueue(t *testing.T) {
+	fake := newIntegrationAPI(t)
+	var ids []string
+	for _, title := range []string{"One", "Two"} {
+		ids = append(ids, fake.AddEntity(bashEntity(title, "true")).ID)
+	}
+
+	args := append([]string{"agent", "loop", "--idle-exit", "1", "--poll-interval", "1", "--poll-jitter", "0",
+		"--heartbeat-interval", "0", "--min-free-disk", "0", "--min-free-memory", "0"}, fake.Flags("agent-1")...)
+	res := runCLI(t, args...)
+	if res.Err != nil {
+		t.Fatalf("agent loop failed: %v\nstderr: %s", res.Err, res.Stderr)
+	}
+
+	for _, id := range ids {
+		if status := fake.EntityStatus(id); status != testharness.StatusCompleted {
+			t.Errorf("entity %s status = %s, want %s", id, status, testharness.StatusCompleted)
+		}
+		if execs := fake.ExecutionsFor(id); len(execs) != 1 || execs[0].AgentID != "agent-1" {
+			t.Errorf("entity %s executions = %+v, want one by agent-1", id, execs)
+		}
+	}
+	var events []string
+	for _, e := range fake.Events() {
+		events = append(events, string(e.Event))
+	}
+	if len(events) == 0 || events[0] != string(api.AgentEventStarted) || events[len(events)-1] != string(api.AgentEventStopped) {
+		t.Errorf("agent events = %v, want started first and stopped last", events)
+	}
+}
+
+func TestAgentLoopSurvivesExecutorPanic(t *testing.T) {
+	fake := newIntegrationAPI(t)
+	const panicMode = api.ExecutionMode("TEST_PANIC")
+	executor.Register(panicMode, executor.ExecutorFunc(func(ctx context.Context, entity *api.PlanningEntity, inputs map[string]interface{}) *executor.ExecutionResult {
+		panic("executor bug")
+	}))
+	t.Cleanup(func() { executor.Register(panicMode, nil) })
+
+	crashing := testharness.Entity{}
+	crashing.Title = "Crashes"
+	crashing.ExecutionMode = panicMode
+	crashingID := fake.AddEntity(crashing).ID
+	nextID := fake.AddEntity(bashEntity("Runs after", "true")).ID
+
+	args := append([]string{"agent", "loop", "--idle-exit", "1", "--poll-interval", "1", "--poll-jitter", "0",
+		"--heartbeat-interval", "0", "--min-free-disk", "0", "--min-free-memory", "0", "--watchdog", "60"}, fake.Flags("agent-1")...)
+	res := runCLI(t, args...)
+	if res.Err != nil {
+		t.Fatalf("agent loop failed: %v\nstderr: %s", res.Err, res.Stderr)
+	}
+
+	if status := fake.EntityStatus(crashingID); status != testharness.StatusFailed {
+		t.Errorf("crashing entity status = %s, want %s", status, testharness.StatusFailed)
+	}
+	if status := fake.EntityStatus(nextID); status != testharness.StatusCompleted {
+		t.Errorf("next entity status = %s, want %s", status, testharness.StatusCompleted)
+	}
+}
@@ -0,0 +1,61 @@
+package cmd
+
+import (
+	"context"
+	"os"
+	"os/signal"
+	"sync"
+	"sync/atomic"
+	"syscall"
+
+	"github.com/kindship-ai/kindship-cli/internal/logging"
+)
+
+// interruptCtx is cancelled when the process receives SIGTERM or SIGINT
+// after forwardInterrupts was called. Executions derive their context from
+// it so the signal reaches the executor's process group.
+var interruptCtx, interrupt = context.WithCancel(context.Background())
+
+var (
+	interruptOnce sync.Once
+	// interruptSignal is the name of the signal that cancelled interruptCtx
+	interruptSignal atomic.Value
+)
+
+// forwardInterrupts makes SIGTERM and SIGINT cancel interruptCtx instead of
+// killing the CLI outright, which would orphan running child processes.
+// Running executions then terminate their process group (SIGTERM, SIGKILL
+// after a grace period) and are completed before the CLI exits. A second
+// signal exits immediately.
+func forwardInterrupts(log *logging.Logger) {
+	interruptOnce.Do(func() {
+		sigCh := make(chan os.Signal, 2)
+		signal.Notify(sigCh, syscall.SIGTERM, syscall.SIGINT)
+		go func() {
+			sig := <-sigCh
+			name := "SIGTERM"
+			if sig == syscall.SIGINT {
+				name = "SIGINT"
+			}
+			interruptSignal.Store(name)
+			log.Warn("Received signal, stopping running execution", map[string]interface{}{
+				"signal": name,
+			})
+			interrupt()
+
+			<-sigCh
+			log.FlushSync()
+			os.Exit(130)
+		}()
+	})
+}
+
+// interruptedBy returns the signal that interrupted executions, or "" if
+// none did
+func interruptedBy() string {
+	if interruptCtx.Err() == nil {
+		return ""
+	}
+	sig, _ := interruptSignal.Load().(string)
+	return sig
+}
@@ -0,0 +1,68 @@
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/kindship-ai/kindship-cli/internal/boundaries"
+	"github.com/kindship-ai/kindship-cli/internal/executor"
+	"github.com/spf13/cobra"
+)
+
+// Agent-wide LLM permission settings, overridable per entity via boundaries.llm
+var (
+	llmPermissionMode  string
+	llmAllowedTools    []string
+	llmDisallowedTools []string
+	llmMaxTurns        int
+	llmBackends        []string
+	noRepoContext      bool
+	repoContextFiles   []string
+)
+
+// llmFlagsHelp documents the LLM permission flags in command help
+const llmFlagsHelp = `LLM permissions (LLM_REASONING and HYBRID; boundaries.llm overrides per entity):
+  --permission-mode   Claude Code permission mode (default, acceptEdits, plan, bypassPermissions)
+  --allowed-tools     Tools Claude may use without prompting (e.g. "Bash(git:*),Edit")
+  --disallowed-tools  Tools Claude may never use; entities can add but not remove entries
+  --max-turns         Maximum agentic turns per execution
+  --llm-backends      LLM CLIs to try in order (claude, codex, gemini); the next is
+                      used when one is missing or fails with a quota/auth error
+
+Repository context: when the workspace is bound with 'kindship setup', LLM
+prompts include AGENTS.md / CLAUDE.md, the current branch and uncommitted files.
+  --no-repo-context     Leave repository context out of prompts
+  --repo-context-files  Convention files to include (default AGENTS.md,CLAUDE.md)`
+
+// addLLMFlags registers the LLM permission and prompt flags on cmd
+func addLLMFlags(cmd *cobra.Command) {
+	cmd.Flags().StringVar(&llmPermissionMode, "permission-mode", "", "Claude Code permission mode for LLM executions")
+	cmd.Flags().StringSliceVar(&llmAllowedTools, "allowed-tools", nil, "Tools Claude may use without prompting (comma-separated)")
+	cmd.Flags().StringSliceVar(&llmDisallowedTools, "disallowed-tools", nil, "Tools Claude may never use (comma-separated)")
+	cmd.Flags().IntVar(&llmMaxTurns, "max-turns", 0, "Maximum agentic turns per LLM execution (0 for no limit)")
+	cmd.Flags().StringSliceVar(&llmBackends, "llm-backends", nil, "LLM CLIs to try in order (default claude)")
+	cmd.Flags().BoolVar(&noRepoContext, "no-repo-context", false, "Do not include repository context in LLM prompts")
+	cmd.Flags().StringSliceVar(&repoContextFiles, "repo-context-files", executor.DefaultRepoContextFiles, "Convention files included in LLM prompts")
+}
+
+// executionOptions validates the LLM flags and returns the executor options
+// of this invocation: the repository's workspace and shell settings with
+// the LLM flags applied
+func executionOptions() (executor.Options, error) {
+	policy := &boundaries.LLMPolicy{
+		PermissionMode:  llmPermissionMode,
+		AllowedTools:    llmAllowedTools,
+		DisallowedTools: llmDisallowedTools,
+		MaxTurns:        llmMaxTurns,
+		Backends:        llmBackends,
+	}
+	if err := policy.Validate(); err != nil {
+		return executor.Options{}, withExitCode(ExitUsage, fmt.Errorf("invalid LLM flags: %w", err))
+	}
+	opts := repoExecutionOptions()
+	opts.LLM = policy
+	opts.RepoContext = executor.RepoContextOptions{
+		Enabled: !noRepoContext,
+		Files:   repoContextFiles,
+	}
+	return opts, nil
+}
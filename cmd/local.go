@@ -0,0 +1,369 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/kindship-ai/kindship-cli/internal/api"
+	"github.com/kindship-ai/kindship-cli/internal/boundaries"
+	"github.com/kindship-ai/kindship-cli/internal/config"
+	"github.com/kindship-ai/kindship-cli/internal/executor"
+	"github.com/kindship-ai/kindship-cli/internal/logging"
+	"github.com/kindship-ai/kindship-cli/internal/validator"
+)
+
+// maxLocalStderr caps how much stderr of a failed task is printed
+const maxLocalStderr = 2000
+
+// Local task states
+const (
+	localPending   = "pending"
+	localSucceeded = "succeeded"
+	localFailed    = "failed"
+	localSkipped   = "skipped"
+)
+
+// localTask tracks a single task of a plan executed with --local
+type localTask struct {
+	Index    int
+	Spec     TaskSpec
+	Deps     map[string]int // label -> task index
+	Status   string
+	Reason   string
+	Duration time.Duration
+	Output   interface{}
+}
+
+// runLocalPlan executes a plan file entirely locally, without the API.
+// Tasks run in dependency order and each task's output is passed to its
+// dependents under the dependency label. Execution stops at the first
+// failed task.
+func runLocalPlan(path string, settings executionSettings, log *logging.Logger) error {
+	data, err := readPlan(path)
+	if err != nil {
+		return err
+	}
+	var plan planFile
+	if err := json.Unmarshal(data, &plan); err != nil {
+		return fmt.Errorf("failed to parse plan: %w", err)
+	}
+	if len(plan.Tasks) == 0 {
+		return fmt.Errorf("plan has no tasks")
+	}
+	if err := checkPlan(planSource(path), data, plan.Tasks); err != nil {
+		return withExitCode(ExitValidation, err)
+	}
+
+	tasks, err := resolveLocalTasks(plan.Tasks)
+	if err != nil {
+		return err
+	}
+	order, err := orderLocalTasks(tasks)
+	if err != nil {
+		return err
+	}
+
+	opts, err := localOptions(settings.Exec)
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("Running plan '%s' locally (%d tasks) in %s\n\n", plan.Title, len(tasks), opts.Dir())
+
+	stopped := false
+	for _, i := range order {
+		task := tasks[i]
+		if stopped {
+			task.Status = localSkipped
+			task.Reason = "an earlier task failed"
+			continue
+		}
+
+		fmt.Printf("→ [%d] %s (%s)\n", task.Index+1, task.Spec.Title, localExecutionMode(task.Spec))
+		start := time.Now()
+		runLocalTask(task, tasks, opts, log)
+		task.Duration = time.Since(start)
+
+		if task.Status == localFailed {
+			fmt.Printf("  ✗ failed after %s: %s\n", formatLocalDuration(task.Duration), task.Reason)
+			stopped = true
+		} else {
+			fmt.Printf("  ✓ done in %s\n", formatLocalDuration(task.Duration))
+		}
+	}
+
+	// Summary
+	counts := map[string]int{}
+	fmt.Printf("\nSummary:\n")
+	for _, task := range tasks {
+		counts[task.Status]++
+		switch task.Status {
+		case localSucceeded:
+			fmt.Printf("  [%d] ✓ %s (%s)\n", task.Index+1, task.Spec.Title, formatLocalDuration(task.Duration))
+		case localFailed:
+			fmt.Printf("  [%d] ✗ %s (%s): %s\n", task.Index+1, task.Spec.Title, formatLocalDuration(task.Duration), task.Reason)
+		default:
+			fmt.Printf("  [%d] - %s (skipped)\n", task.Index+1, task.Spec.Title)
+		}
+	}
+	fmt.Printf("\n%d succeeded, %d failed, %d skipped\n", counts[localSucceeded], counts[localFailed], counts[localSkipped])
+
+	if counts[localFailed] > 0 {
+		return withExitCode(ExitExecutionFailed, fmt.Errorf("local plan execution failed"))
+	}
+	return nil
+}
+
+// localOptions returns opts for local tasks, which run in the current
+// directory rather than the agent container's workspace unless the
+// repository configures one
+func localOptions(opts executor.Options) (executor.Options, error) {
+	if repoConfig, err := config.LoadRepoConfig(); err == nil && repoConfig.Workspace != "" {
+		return opts, nil
+	}
+	dir, err := os.Getwd()
+	if err != nil {
+		return opts, fmt.Errorf("failed to determine working directory: %w", err)
+	}
+	opts.WorkDir = dir
+	return opts, nil
+}
+
+// resolveLocalTasks maps each task's labeled dependencies to task indexes.
+// A dependency may name another task by title, sequence_order, or 1-based
+// position in the plan file.
+func resolveLocalTasks(specs []TaskSpec) ([]*localTask, error) {
+	tasks := make([]*localTask, len(specs))
+	for i, spec := range specs {
+		tasks[i] = &localTask{Index: i, Spec: spec, Deps: map[string]int{}, Status: localPending}
+	}
+
+	for _, task := range tasks {
+		for label, ref := range task.Spec.DependenciesLabeled {
+			dep := resolveTaskRef(specs, ref)
+			if dep == -1 {
+				return nil, fmt.Errorf("task '%s': dependency '%s' refers to unknown task '%s'", task.Spec.Title, label, ref)
+			}
+			if dep == task.Index {
+				return nil, fmt.Errorf("task '%s': dependency '%s' refers to itself", task.Spec.Title, label)
+			}
+			task.Deps[label] = dep
+		}
+	}
+	return tasks, nil
+}
+
+// resolveTaskRef returns the index of the task a dependency refers to by
+// title, sequence_order or 1-based position, or -1 if there is none
+func resolveTaskRef(specs []TaskSpec, ref string) int {
+	for j, spec := range specs {
+		if spec.Title == ref {
+			return j
+		}
+	}
+	n, err := strconv.Atoi(ref)
+	if err != nil {
+		return -1
+	}
+	for j, spec := range specs {
+		if spec.SequenceOrder == n {
+			return j
+		}
+	}
+	if n >= 1 && n <= len(specs) {
+		return n - 1
+	}
+	return -1
+}
+
+// orderLocalTasks returns task indexes in dependency order, keeping the
+// plan's sequence_order (then file order) among independent tasks
+func orderLocalTasks(tasks []*localTask) ([]int, error) {
+	remaining := make(map[int]int, len(tasks)) // index -> unmet dependency count
+	dependents := make(map[int][]int)
+	for _, task := range tasks {
+		seen := map[int]bool{}
+		for _, dep := range task.Deps {
+			if seen[dep] {
+				continue
+			}
+			seen[dep] = true
+			remaining[task.Index]++
+			dependents[dep] = append(dependents[dep], task.Index)
+		}
+	}
+
+	less := func(a, b int) bool {
+		if tasks[a].Spec.SequenceOrder != tasks[b].Spec.SequenceOrder {
+			return tasks[a].Spec.SequenceOrder < tasks[b].Spec.SequenceOrder
+		}
+		return a < b
+	}
+
+	var ready, order []int
+	for _, task := range tasks {
+		if remaining[task.Index] == 0 {
+			ready = append(ready, task.Index)
+		}
+	}
+	for len(ready) > 0 {
+		sort.Slice(ready, func(i, j int) bool { return less(ready[i], ready[j]) })
+		next := ready[0]
+		ready = ready[1:]
+		order = append(order, next)
+		for _, d := range dependents[next] {
+			remaining[d]--
+			if remaining[d] == 0 {
+				ready = append(ready, d)
+			}
+		}
+	}
+
+	if len(order) != len(tasks) {
+		var cyclic []string
+		for _, task := range tasks {
+			if remaining[task.Index] > 0 {
+				cyclic = append(cyclic, task.Spec.Title)
+			}
+		}
+		return nil, fmt.Errorf("plan has circular dependencies between: %s", strings.Join(cyclic, ", "))
+	}
+	return order, nil
+}
+
+// runLocalTask executes a single task, recording its status and output
+func runLocalTask(task *localTask, tasks []*localTask, opts executor.Options, log *logging.Logger) {
+	fail := func(format string, args ...interface{}) {
+		task.Status = localFailed
+		task.Reason = fmt.Sprintf(format, args...)
+	}
+
+	// Gather inputs from completed dependencies
+	inputs := map[string]interface{}{}
+	for label, dep := range task.Deps {
+		inputs[label] = tasks[dep].Output
+	}
+	policy, err := boundaries.Parse(task.Spec.Boundaries)
+	if err != nil {
+		fail("%v", err)
+		return
+	}
+	if inputs, err = policy.Inputs.MapInputs(inputs); err != nil {
+		fail("input mapping failed: %v", err)
+		return
+	}
+	if err := validator.ValidateInputs(inputs, task.Spec.InputSchema); err != nil {
+		fail("input validation failed: %v", err)
+		return
+	}
+
+	entity := &api.PlanningEntity{
+		ID:            fmt.Sprintf("local-%d", task.Index+1),
+		Type:          "TASK",
+		Title:         task.Spec.Title,
+		Description:   task.Spec.Description,
+		ExecutionMode: localExecutionMode(task.Spec),
+		InputSchema:   task.Spec.InputSchema,
+		OutputSchema:  task.Spec.OutputSchema,
+		SequenceOrder: task.Spec.SequenceOrder,
+		Boundaries:    task.Spec.Boundaries,
+	}
+	if task.Spec.SuccessCriteria != nil {
+		entity.SuccessCriteria = *task.Spec.SuccessCriteria
+	}
+	if task.Spec.Code != "" {
+		code := task.Spec.Code
+		entity.Code = &code
+	}
+
+	switch entity.ExecutionMode {
+	case api.ExecutionModeAskUser, api.ExecutionModeOrchestrate:
+		fail("%s tasks are not supported in local mode", entity.ExecutionMode)
+		return
+	}
+
+	if err := executor.Preflight(entity.ExecutionMode, policy, opts); err != nil {
+		fail("%v", err)
+		return
+	}
+
+	// Apply the same code preparation and boundaries as 'kindship run'
+	if entity.Code != nil {
+		code, err := executor.ResolveCode(opts.Dir(), *entity.Code)
+		if err != nil {
+			fail("%v", err)
+			return
+		}
+		if policy.Template {
+			if code, err = executor.RenderCode(code, inputs); err != nil {
+				fail("%v", err)
+				return
+			}
+		}
+		entity.Code = &code
+
+		if entity.ExecutionMode == api.ExecutionModeBash || entity.ExecutionMode == api.ExecutionModeTest {
+			if violations := policy.Commands.CheckScript(code, opts.Dir()); len(violations) > 0 {
+				fail("boundary violation: %s", violations[0].Detail)
+				return
+			}
+		}
+	}
+
+	forwardInterrupts(log)
+	result := dispatchExecution(executor.WithOptions(interruptCtx, opts), entity, inputs, log)
+	if !result.Success {
+		reason := fmt.Sprintf("exit code %d", result.ExitCode)
+		if result.Error != nil {
+			reason = fmt.Sprintf("%s: %v", reason, result.Error)
+		}
+		if sig := interruptedBy(); sig != "" && result.Cancelled {
+			reason = fmt.Sprintf("interrupted by %s", sig)
+		}
+		fail("%s", reason)
+		if stderr := strings.TrimSpace(result.Stderr); stderr != "" {
+			if len(stderr) > maxLocalStderr {
+				stderr = "..." + stderr[len(stderr)-maxLocalStderr:]
+			}
+			fmt.Printf("  stderr:\n    %s\n", strings.ReplaceAll(stderr, "\n", "\n    "))
+		}
+		return
+	}
+
+	// Dependents receive the structured output, or raw stdout without one
+	task.Status = localSucceeded
+	structured, err := extractStructuredOutput(result, policy.ExtractionStrategy())
+	if err != nil {
+		task.Output = strings.TrimSpace(result.Stdout)
+		if len(task.Spec.OutputSchema) > 0 {
+			fmt.Printf("  ⚠ could not extract structured output: %v\n", err)
+		}
+		return
+	}
+	task.Output = validator.UnwrapStructured(structured)
+	if len(task.Spec.OutputSchema) > 0 {
+		if err := validator.ValidateOutputs(structured, task.Spec.OutputSchema); err != nil {
+			fmt.Printf("  ⚠ output validation failed: %v\n", err)
+		}
+	}
+}
+
+// localExecutionMode returns a task's execution mode, defaulting to LLM
+func localExecutionMode(spec TaskSpec) api.ExecutionMode {
+	if spec.ExecutionMode == "" {
+		return api.ExecutionModeLLMReasoning
+	}
+	return api.ExecutionMode(spec.ExecutionMode)
+}
+
+func formatLocalDuration(d time.Duration) string {
+	if d < time.Second {
+		return d.Round(time.Millisecond).String()
+	}
+	return d.Round(100 * time.Millisecond).String()
+}
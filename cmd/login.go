@@ -1,6 +1,7 @@
 package cmd
 
 import (
+	"bufio"
 	"bytes"
 	"context"
 	"crypto/rand"
@@ -15,8 +16,11 @@ import (
 	"os"
 	"os/exec"
 	"runtime"
+	"strconv"
+	"strings"
 	"time"
 
+	"github.com/kindship-ai/kindship-cli/internal/api"
 	"github.com/kindship-ai/kindship-cli/internal/config"
 
 	"github.com/spf13/cobra"
@@ -30,17 +34,32 @@ var loginCmd = &cobra.Command{
 This command opens your browser for authentication and stores
 the credentials securely in ~/.kindship/config.json.
 
-Example:
-  kindship login`,
+If you belong to several accounts, you are asked which one the CLI should
+act in (or pass --account with its ID or slug). The choice is saved and sent
+with subsequent API requests; log in again to switch.
+
+With --no-browser no local callback server is started: the CLI prints the
+sign-in URL, which you can open on any device, and reads the code shown
+afterwards from the terminal. Use this when localhost callbacks are blocked
+or the CLI runs in a remote devcontainer or over SSH.
+
+Examples:
+  kindship login
+  kindship login --account acme
+  kindship login --no-browser`,
 	RunE: runLogin,
 }
 
 var (
-	loginAPIURL string
+	loginAPIURL    string
+	loginAccount   string
+	loginNoBrowser bool
 )
 
 func init() {
 	loginCmd.Flags().StringVar(&loginAPIURL, "api-url", "", "API base URL (default: https://kindship.ai)")
+	loginCmd.Flags().StringVar(&loginAccount, "account", "", "Account ID or slug to use (skips interactive selection)")
+	loginCmd.Flags().BoolVar(&loginNoBrowser, "no-browser", false, "Print the sign-in URL and paste the code instead of using a local callback")
 	rootCmd.AddCommand(loginCmd)
 }
 
@@ -68,7 +87,24 @@ type AuthCallbackResponse struct {
 	UserID      string `json:"user_id"`
 	UserEmail   string `json:"user_email"`
 	ExpiresAt   string `json:"expires_at"`
-	Error       string `json:"error,omitempty"`
+	// Scopes and RateLimitTier are optional; older servers omit them
+	Scopes        []string `json:"scopes,omitempty"`
+	RateLimitTier string   `json:"rate_limit_tier,omitempty"`
+	Error         string   `json:"error,omitempty"`
+}
+
+// AccountsResponse is the response from /api/cli/accounts
+type AccountsResponse struct {
+	Accounts []AccountInfo `json:"accounts"`
+	Error    string        `json:"error,omitempty"`
+}
+
+// AccountInfo is an account the user belongs to
+type AccountInfo struct {
+	ID         string `json:"id"`
+	Slug       string `json:"slug"`
+	Name       string `json:"name"`
+	IsPersonal bool   `json:"is_personal"`
 }
 
 func runLogin(cmd *cobra.Command, args []string) error {
@@ -78,7 +114,7 @@ func runLogin(cmd *cobra.Command, args []string) error {
 		apiURL = os.Getenv("KINDSHIP_API_URL")
 	}
 	if apiURL == "" {
-		apiURL = "https://kindship.ai"
+		apiURL = config.DefaultAPIBaseURL()
 	}
 
 	fmt.Println("Authenticating with Kindship...")
@@ -92,6 +128,10 @@ func runLogin(cmd *cobra.Command, args []string) error {
 	// Compute code_challenge = SHA256(code_verifier) - PKCE S256 method
 	codeChallenge := computeCodeChallenge(codeVerifier)
 
+	if loginNoBrowser {
+		return runManualLogin(apiURL, codeVerifier, codeChallenge)
+	}
+
 	// Step 2: Find an available port and start local callback server
 	listener, port, err := findAvailablePort()
 	if err != nil {
@@ -139,37 +179,213 @@ func runLogin(cmd *cobra.Command, args []string) error {
 			return fmt.Errorf("state mismatch: possible CSRF attack")
 		}
 
-		// Step 6: Exchange auth code for token
-		tokenResp, err := exchangeAuthCode(apiURL, result.code, codeVerifier, startResp.State)
-		if err != nil {
-			return err
+		return completeLogin(apiURL, result.code, codeVerifier, startResp.State)
+
+	case <-time.After(10 * time.Minute):
+		return fmt.Errorf("authentication timed out")
+	}
+}
+
+// runManualLogin authenticates without a local callback server: the user
+// opens the auth URL anywhere and pastes the code shown after signing in
+func runManualLogin(apiURL, codeVerifier, codeChallenge string) error {
+	hostname, _ := os.Hostname()
+	startURL := fmt.Sprintf("%s/api/cli/auth/start?flow=manual&hostname=%s&cli_version=%s&code_challenge=%s",
+		apiURL, url.QueryEscape(hostname), url.QueryEscape(Version), url.QueryEscape(codeChallenge))
+
+	startResp, err := callAuthStart(startURL)
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("\nOpen this URL in a browser on any device and sign in:\n%s\n\n", startResp.AuthURL)
+	fmt.Print("Paste the code shown after signing in: ")
+
+	reader := bufio.NewReader(os.Stdin)
+	input, err := reader.ReadString('\n')
+	if err != nil && strings.TrimSpace(input) == "" {
+		return fmt.Errorf("failed to read code: %w", err)
+	}
+
+	code, state := parsePastedCode(strings.TrimSpace(input))
+	if code == "" {
+		return withExitCode(ExitUsage, fmt.Errorf("no authorization code entered"))
+	}
+	if state != "" && state != startResp.State {
+		return fmt.Errorf("state mismatch: possible CSRF attack")
+	}
+
+	return completeLogin(apiURL, code, codeVerifier, startResp.State)
+}
+
+// parsePastedCode accepts either the bare code or the whole callback URL
+// the browser was sent to (…/callback?code=…&state=…), in which case the
+// state is returned for verification too
+func parsePastedCode(input string) (code, state string) {
+	if u, err := url.Parse(input); err == nil && u.Query().Get("code") != "" {
+		return u.Query().Get("code"), u.Query().Get("state")
+	}
+	return input, ""
+}
+
+// completeLogin exchanges the auth code for a token, asks for the account
+// and saves the credentials
+func completeLogin(apiURL, code, codeVerifier, state string) error {
+	// Exchange auth code for token
+	tokenResp, err := exchangeAuthCode(apiURL, code, codeVerifier, state)
+	if err != nil {
+		return err
+	}
+
+	// Choose the account to act in
+	account, err := selectLoginAccount(apiURL, tokenResp.Token)
+	if err != nil {
+		return err
+	}
+
+	// Save token to config
+	expiresAt, _ := time.Parse(time.RFC3339, tokenResp.ExpiresAt)
+
+	cfg := &config.GlobalConfig{
+		Token:         tokenResp.Token,
+		TokenID:       tokenResp.TokenID,
+		TokenPrefix:   tokenResp.TokenPrefix,
+		TokenExpiry:   expiresAt,
+		UserID:        tokenResp.UserID,
+		UserEmail:     tokenResp.UserEmail,
+		Scopes:        tokenResp.Scopes,
+		RateLimitTier: tokenResp.RateLimitTier,
+		APIBaseURL:    apiURL,
+	}
+	if account != nil {
+		cfg.AccountID = account.ID
+		cfg.AccountSlug = account.Slug
+		cfg.AccountName = account.Name
+	}
+
+	if err := config.SaveGlobalConfig(cfg); err != nil {
+		return fmt.Errorf("failed to save config: %w", err)
+	}
+
+	fmt.Printf("\n✓ Successfully authenticated as %s\n", tokenResp.UserEmail)
+	if account != nil {
+		fmt.Printf("  Account: %s\n", accountLabel(*account))
+	}
+	fmt.Printf("  Token expires: %s\n", expiresAt.Format(time.RFC1123))
+
+	return nil
+}
+
+// selectLoginAccount picks the account to save: the --account match, the
+// only account, or the user's choice. Returns nil to leave the choice to
+// the server, e.g. when the account list is unavailable or nobody is at the
+// terminal to choose.
+func selectLoginAccount(apiURL, token string) (*AccountInfo, error) {
+	accounts, err := fetchAccounts(apiURL, token)
+	if err != nil {
+		if loginAccount != "" {
+			return nil, fmt.Errorf("failed to fetch accounts: %w", err)
 		}
+		fmt.Fprintf(os.Stderr, "Warning: could not list accounts, using your default account: %v\n", err)
+		return nil, nil
+	}
 
-		// Step 7: Save token to config
-		expiresAt, _ := time.Parse(time.RFC3339, tokenResp.ExpiresAt)
-
-		cfg := &config.GlobalConfig{
-			Token:       tokenResp.Token,
-			TokenID:     tokenResp.TokenID,
-			TokenPrefix: tokenResp.TokenPrefix,
-			TokenExpiry: expiresAt,
-			UserID:      tokenResp.UserID,
-			UserEmail:   tokenResp.UserEmail,
-			APIBaseURL:  apiURL,
+	if loginAccount != "" {
+		for i := range accounts {
+			if accounts[i].ID == loginAccount || accounts[i].Slug == loginAccount {
+				return &accounts[i], nil
+			}
 		}
+		return nil, withExitCode(ExitUsage, fmt.Errorf("account not found: %s", loginAccount))
+	}
+
+	switch {
+	case len(accounts) == 0:
+		return nil, nil
+	case len(accounts) == 1:
+		return &accounts[0], nil
+	case !stdinIsTerminal():
+		fmt.Fprintln(os.Stderr, "Note: you belong to several accounts; using your default. Pass --account to choose one.")
+		return nil, nil
+	}
+	return promptSelectAccount(accounts)
+}
+
+// fetchAccounts lists the accounts the token's user belongs to
+func fetchAccounts(apiURL, token string) ([]AccountInfo, error) {
+	req, err := http.NewRequest(http.MethodGet, fmt.Sprintf("%s/api/cli/accounts", apiURL), nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Authorization", "Bearer "+token)
+	req.Header.Set("X-Kindship-CLI-Version", Version)
+
+	resp, err := api.NewHTTPClient(30 * time.Second).Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
 
-		if err := config.SaveGlobalConfig(cfg); err != nil {
-			return fmt.Errorf("failed to save config: %w", err)
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		var errResp AccountsResponse
+		if json.Unmarshal(body, &errResp) == nil && errResp.Error != "" {
+			return nil, fmt.Errorf("API error: %s", errResp.Error)
 		}
+		return nil, fmt.Errorf("API error (%d): %s", resp.StatusCode, string(body))
+	}
 
-		fmt.Printf("\n✓ Successfully authenticated as %s\n", tokenResp.UserEmail)
-		fmt.Printf("  Token expires: %s\n", expiresAt.Format(time.RFC1123))
+	var accountsResp AccountsResponse
+	if err := json.Unmarshal(body, &accountsResp); err != nil {
+		return nil, err
+	}
+	return accountsResp.Accounts, nil
+}
 
-		return nil
+// promptSelectAccount asks the user to pick one of accounts
+func promptSelectAccount(accounts []AccountInfo) (*AccountInfo, error) {
+	fmt.Println("\nYou belong to several accounts:")
+	fmt.Println()
+	for i, account := range accounts {
+		fmt.Printf("  [%d] %s\n", i+1, accountLabel(account))
+	}
+	fmt.Println()
+	fmt.Print("Select an account (enter number): ")
 
-	case <-time.After(10 * time.Minute):
-		return fmt.Errorf("authentication timed out")
+	reader := bufio.NewReader(os.Stdin)
+	input, err := reader.ReadString('\n')
+	if err != nil {
+		return nil, fmt.Errorf("failed to read input: %w (pass --account to choose non-interactively)", err)
+	}
+
+	input = strings.TrimSpace(input)
+	num, err := strconv.Atoi(input)
+	if err != nil || num < 1 || num > len(accounts) {
+		return nil, fmt.Errorf("invalid selection: %s", input)
+	}
+	return &accounts[num-1], nil
+}
+
+// accountLabel names an account for display
+func accountLabel(account AccountInfo) string {
+	name := account.Name
+	if name == "" {
+		name = account.Slug
+	}
+	if account.IsPersonal {
+		name += " (personal)"
 	}
+	return name
+}
+
+// stdinIsTerminal reports whether someone can answer prompts
+func stdinIsTerminal() bool {
+	info, err := os.Stdin.Stat()
+	return err == nil && info.Mode()&os.ModeCharDevice != 0
 }
 
 // generateCodeVerifier generates a random code verifier for PKCE
@@ -299,7 +515,7 @@ func exchangeAuthCode(apiURL, authCode, codeVerifier, state string) (*AuthCallba
 	req.Header.Set("Content-Type", "application/json")
 	req.Header.Set("X-Kindship-CLI-Version", Version)
 
-	client := &http.Client{Timeout: 30 * time.Second}
+	client := api.NewHTTPClient(30 * time.Second)
 	resp, err := client.Do(req)
 	if err != nil {
 		return nil, fmt.Errorf("failed to exchange token: %w", err)
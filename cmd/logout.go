@@ -5,9 +5,14 @@ import (
 	"io"
 	"net/http"
 	"os"
+	"path/filepath"
 	"time"
 
+	"github.com/kindship-ai/kindship-cli/internal/api"
 	"github.com/kindship-ai/kindship-cli/internal/config"
+	"github.com/kindship-ai/kindship-cli/internal/executor"
+	"github.com/kindship-ai/kindship-cli/internal/logging"
+	"github.com/kindship-ai/kindship-cli/internal/output"
 
 	"github.com/spf13/cobra"
 )
@@ -20,18 +25,31 @@ var logoutCmd = &cobra.Command{
 By default, only the current token is revoked. Use --all to revoke
 all tokens for your account (useful after a security incident).
 
+Use --purge on shared machines to also remove everything else the CLI
+keeps locally:
+  - the .kindship/config.json binding of the current repository
+  - the agent registration (agent.json)
+  - cached task details of hook start (hook-next-task.json) and server
+    capabilities (capabilities.json)
+  - unsent log batches and crash reports
+  - input and output files left behind by interrupted executions
+The audit log is kept; remove it by hand if required.
+
 Examples:
   kindship logout           # Revoke current token
-  kindship logout --all     # Revoke all tokens`,
+  kindship logout --all     # Revoke all tokens
+  kindship logout --purge   # Revoke the token and remove local data`,
 	RunE: runLogout,
 }
 
 var (
-	logoutAll bool
+	logoutAll   bool
+	logoutPurge bool
 )
 
 func init() {
 	logoutCmd.Flags().BoolVar(&logoutAll, "all", false, "Revoke all tokens for your account")
+	logoutCmd.Flags().BoolVar(&logoutPurge, "purge", false, "Also remove the repo binding, agent registration, caches, unsent logs and input files")
 	rootCmd.AddCommand(logoutCmd)
 }
 
@@ -43,6 +61,9 @@ func runLogout(cmd *cobra.Command, args []string) error {
 
 	if cfg.Token == "" {
 		fmt.Println("Not currently logged in.")
+		if logoutPurge {
+			return purgeLocalData()
+		}
 		return nil
 	}
 
@@ -63,6 +84,52 @@ func runLogout(cmd *cobra.Command, args []string) error {
 		fmt.Println("✓ Logged out successfully")
 	}
 
+	if logoutPurge {
+		return purgeLocalData()
+	}
+	return nil
+}
+
+// purgeLocalData removes the local state listed in the logout help,
+// reporting each path removed. Missing paths are skipped.
+func purgeLocalData() error {
+	var paths []string
+	if dir, err := config.GetRepoConfigDir(); err == nil {
+		paths = append(paths, filepath.Join(dir, config.RepoConfigFile))
+	}
+	if dir, err := config.GetStateDir(); err == nil {
+		paths = append(paths,
+			filepath.Join(dir, config.AgentStateFile),
+			filepath.Join(dir, hookTaskCacheFile),
+			filepath.Join(dir, capabilitiesFile),
+			filepath.Join(dir, filepath.FromSlash(crashDir)))
+	}
+	if dir, err := logging.PendingDir(); err == nil {
+		paths = append(paths, dir)
+	}
+	paths = append(paths, executor.LeftoverRunDirs()...)
+
+	var failed []string
+	for _, path := range paths {
+		if _, err := os.Lstat(path); err != nil {
+			continue
+		}
+		if err := os.RemoveAll(path); err != nil {
+			fmt.Fprintf(os.Stderr, "%s\n", output.Fail(fmt.Sprintf("Failed to remove %s: %v", path, err)))
+			failed = append(failed, path)
+			continue
+		}
+		fmt.Println(output.OK("Removed " + path))
+		// Drop the repo's .kindship directory once the binding was its
+		// only content; a non-empty directory is left alone
+		if filepath.Base(path) == config.RepoConfigFile {
+			_ = os.Remove(filepath.Dir(path))
+		}
+	}
+
+	if len(failed) > 0 {
+		return fmt.Errorf("failed to remove %d path(s)", len(failed))
+	}
 	return nil
 }
 
@@ -80,7 +147,7 @@ func revokeToken(cfg *config.GlobalConfig, all bool) error {
 	req.Header.Set("Authorization", fmt.Sprintf("Bearer %s", cfg.Token))
 	req.Header.Set("X-Kindship-CLI-Version", Version)
 
-	client := &http.Client{Timeout: 10 * time.Second}
+	client := api.NewHTTPClient(10 * time.Second)
 	resp, err := client.Do(req)
 	if err != nil {
 		return fmt.Errorf("request failed: %w", err)
@@ -0,0 +1,66 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/kindship-ai/kindship-cli/internal/logging"
+
+	"github.com/spf13/cobra"
+)
+
+var logsCmd = &cobra.Command{
+	Use:   "logs",
+	Short: "Structured log commands",
+	Long: `Commands for the structured logs sent to Axiom.
+
+When a flush to Axiom fails, the batch is saved under logs/pending in the state
+dir (see 'kindship env') and sent after the next successful flush, so ingest outages don't drop
+entries.
+
+Subcommands:
+  sync   Send saved log batches to Axiom now`,
+}
+
+var logsSyncCmd = &cobra.Command{
+	Use:   "sync",
+	Short: "Send saved log batches to Axiom",
+	Long: `Send log batches saved by failed flushes to Axiom, oldest first.
+Requires AXIOM_TOKEN (and AXIOM_DATASET if not the default).
+
+Examples:
+  kindship logs sync`,
+	Args:         usageArgs(cobra.NoArgs),
+	SilenceUsage: true,
+	RunE:         runLogsSync,
+}
+
+func init() {
+	logsCmd.AddCommand(logsSyncCmd)
+	rootCmd.AddCommand(logsCmd)
+}
+
+func runLogsSync(cmd *cobra.Command, args []string) error {
+	pending, err := logging.PendingCount()
+	if err != nil {
+		return err
+	}
+	if pending == 0 {
+		fmt.Println("No pending log batches.")
+		return nil
+	}
+
+	log := logging.Init("", "logs-sync", false)
+	if !log.IsEnabled() {
+		return withExitCode(ExitAuth, fmt.Errorf("AXIOM_TOKEN is required to send %d pending log batches", pending))
+	}
+
+	sent, err := log.SyncPending()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Sent %d of %d pending log batches\n", sent, pending)
+		return withExitCode(ExitAPI, err)
+	}
+
+	fmt.Printf("✓ Sent %d pending log batches\n", sent)
+	return nil
+}
@@ -0,0 +1,57 @@
+package cmd
+
+import (
+	"fmt"
+	"sync"
+
+	"github.com/kindship-ai/kindship-cli/internal/sysinfo"
+)
+
+// resourceGate keeps the agent loop from claiming tasks while free disk
+// under the workspace or available memory is below the configured minimum,
+// so tasks do not start only to die on ENOSPC or the OOM killer.
+type resourceGate struct {
+	workDir        string
+	minDiskBytes   uint64
+	minMemoryBytes uint64
+
+	// reason is why the agent is degraded, "" while healthy. Read by the
+	// heartbeat goroutine.
+	mu     sync.Mutex
+	reason string
+}
+
+// check measures free resources and records the outcome. It returns why
+// the agent is degraded ("" when healthy) and whether that changed since
+// the last check. Resources that cannot be measured are not held against
+// the agent.
+func (g *resourceGate) check() (reason string, changed bool) {
+	if g.minDiskBytes > 0 {
+		if free, err := sysinfo.FreeDiskBytes(g.workDir); err == nil && free < g.minDiskBytes {
+			reason = fmt.Sprintf("free disk under %s is %s, below the %s minimum", g.workDir, formatMB(free), formatMB(g.minDiskBytes))
+		}
+	}
+	if reason == "" && g.minMemoryBytes > 0 {
+		if available, err := sysinfo.AvailableMemoryBytes(); err == nil && available < g.minMemoryBytes {
+			reason = fmt.Sprintf("available memory is %s, below the %s minimum", formatMB(available), formatMB(g.minMemoryBytes))
+		}
+	}
+
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	changed = (reason == "") != (g.reason == "")
+	g.reason = reason
+	return reason, changed
+}
+
+// degradedReason returns the reason recorded by the last check
+func (g *resourceGate) degradedReason() string {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	return g.reason
+}
+
+// formatMB renders a byte count in whole megabytes
+func formatMB(bytes uint64) string {
+	return fmt.Sprintf("%d MB", bytes/(1<<20))
+}
@@ -0,0 +1,118 @@
+package cmd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"github.com/kindship-ai/kindship-cli/internal/mockapi"
+
+	"github.com/spf13/cobra"
+)
+
+var mockServerCmd = &cobra.Command{
+	Use:    "mock-server",
+	Short:  "Serve recorded API fixtures for hermetic testing",
+	Hidden: true,
+	Long: `Serve recorded Kindship API responses from a fixture file, or record them
+by proxying to a real API.
+
+Point the CLI at the mock server with --api-url or KINDSHIP_API_URL to run
+'kindship run' and 'kindship agent loop' without network access, e.g. in CI
+or to reproduce a bug deterministically.
+
+Fixture file format:
+  {"interactions": [
+    {"method": "GET", "path": "/api/cli/plan/next", "status": 200, "body": {...}},
+    ...
+  ]}
+
+Interactions for the same method, path and query are served in order; the
+last one repeats. "query" optionally pins a query string. Requests without a
+fixture get a 404.
+
+Examples:
+  # Record a session against production
+  kindship mock-server --fixtures session.json --record --upstream https://kindship.ai
+
+  # Replay it
+  kindship mock-server --fixtures session.json --addr 127.0.0.1:8787
+  KINDSHIP_API_URL=http://127.0.0.1:8787 kindship run <entity-id>`,
+	Args:         cobra.NoArgs,
+	SilenceUsage: true,
+	RunE:         runMockServer,
+}
+
+var (
+	mockFixtures string
+	mockAddr     string
+	mockRecord   bool
+	mockUpstream string
+)
+
+func init() {
+	mockServerCmd.Flags().StringVar(&mockFixtures, "fixtures", "", "Fixture file to replay from (or record to)")
+	mockServerCmd.Flags().StringVar(&mockAddr, "addr", "127.0.0.1:8787", "Address to listen on")
+	mockServerCmd.Flags().BoolVar(&mockRecord, "record", false, "Proxy to --upstream and record interactions")
+	mockServerCmd.Flags().StringVar(&mockUpstream, "upstream", "", "API base URL to proxy to when recording")
+	mockServerCmd.MarkFlagRequired("fixtures")
+
+	rootCmd.AddCommand(mockServerCmd)
+}
+
+func runMockServer(cmd *cobra.Command, args []string) error {
+	var handler http.Handler
+	if mockRecord {
+		if mockUpstream == "" {
+			return fmt.Errorf("--upstream is required with --record")
+		}
+		recorder, err := mockapi.NewRecorder(mockUpstream, mockFixtures)
+		if err != nil {
+			return err
+		}
+		handler = recorder
+	} else {
+		fixtures, err := mockapi.Load(mockFixtures)
+		if err != nil {
+			return err
+		}
+		replayer := mockapi.NewReplayer(fixtures)
+		replayer.Unmatched = func(r *http.Request) {
+			fmt.Fprintf(os.Stderr, "No fixture for %s %s\n", r.Method, r.URL.RequestURI())
+		}
+		handler = replayer
+	}
+
+	listener, err := net.Listen("tcp", mockAddr)
+	if err != nil {
+		return fmt.Errorf("failed to listen on %s: %w", mockAddr, err)
+	}
+	server := &http.Server{Handler: handler}
+
+	if mockRecord {
+		fmt.Printf("Recording %s to %s on http://%s\n", mockUpstream, mockFixtures, listener.Addr())
+	} else {
+		fmt.Printf("Serving %s on http://%s\n", mockFixtures, listener.Addr())
+	}
+
+	sigCh := make(chan os.Signal, 1)
+	signal.Notify(sigCh, syscall.SIGINT, syscall.SIGTERM)
+	defer signal.Stop(sigCh)
+	go func() {
+		<-sigCh
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		server.Shutdown(ctx)
+	}()
+
+	if err := server.Serve(listener); err != nil && !errors.Is(err, http.ErrServerClosed) {
+		return err
+	}
+	return nil
+}
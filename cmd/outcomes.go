@@ -0,0 +1,156 @@
+package cmd
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/kindship-ai/kindship-cli/internal/api"
+	"github.com/kindship-ai/kindship-cli/internal/executor"
+)
+
+// outcomeCheck is the result of one check of a measurable outcome
+type outcomeCheck struct {
+	Check  string `json:"check"`
+	Passed bool   `json:"passed"`
+	Detail string `json:"detail,omitempty"`
+}
+
+// outcomeRecords builds one OUTCOME validation record per measurable
+// outcome of the success criteria. The checks for an outcome come from
+// success_criteria.validation_rules.outcomes, keyed by the outcome text:
+//
+//	"validation_rules": {"outcomes": {
+//	  "Users are exported": ["exit_code", {"output_field": "users"}],
+//	  "Export is logged":   [{"stdout_contains": "export complete"}]
+//	}}
+//
+// Supported checks are "exit_code", "output_schema", "tests",
+// {"output_field": "a.b"} and {"stdout_contains": "text"}. An outcome
+// without configured checks is judged by the exit code plus the output
+// schema and test results when those exist. An outcome passes when all of
+// its checks pass, fails when none do, and is PARTIAL otherwise.
+func outcomeRecords(criteria api.SuccessCriteria, result *executor.ExecutionResult, structured map[string]interface{}, schemaRecord *api.ValidationRecord) []api.ValidationRecord {
+	configured, _ := criteria.ValidationRules["outcomes"].(map[string]interface{})
+
+	records := make([]api.ValidationRecord, 0, len(criteria.MeasurableOutcomes))
+	for _, outcome := range criteria.MeasurableOutcomes {
+		specs, _ := configured[outcome].([]interface{})
+		if len(specs) == 0 {
+			specs = defaultOutcomeChecks(result, schemaRecord)
+		}
+
+		var checks []outcomeCheck
+		var failed []string
+		for _, spec := range specs {
+			check := runOutcomeCheck(spec, result, structured, schemaRecord)
+			checks = append(checks, check)
+			if !check.Passed {
+				failed = append(failed, fmt.Sprintf("%s: %s", check.Check, check.Detail))
+			}
+		}
+
+		record := api.ValidationRecord{
+			ValidationType: "OUTCOME",
+			Outcome:        api.ValidationOutcomePass,
+			Severity:       api.ValidationSeverityInfo,
+			Target:         outcome,
+			Actual: map[string]interface{}{
+				"checks": checks,
+				"passed": len(checks) - len(failed),
+				"total":  len(checks),
+			},
+		}
+		if len(failed) > 0 {
+			reason := strings.Join(failed, "; ")
+			record.FailureReason = &reason
+			record.Outcome = api.ValidationOutcomePartial
+			record.Severity = api.ValidationSeverityWarning
+			if len(failed) == len(checks) {
+				record.Outcome = api.ValidationOutcomeFail
+				record.Severity = api.ValidationSeverityCritical
+			}
+		}
+		records = append(records, record)
+	}
+	return records
+}
+
+// defaultOutcomeChecks returns the checks for an outcome that has none
+// configured
+func defaultOutcomeChecks(result *executor.ExecutionResult, schemaRecord *api.ValidationRecord) []interface{} {
+	specs := []interface{}{"exit_code"}
+	if schemaRecord != nil {
+		specs = append(specs, "output_schema")
+	}
+	if result.Tests != nil {
+		specs = append(specs, "tests")
+	}
+	return specs
+}
+
+// runOutcomeCheck evaluates a single check spec against the execution
+func runOutcomeCheck(spec interface{}, result *executor.ExecutionResult, structured map[string]interface{}, schemaRecord *api.ValidationRecord) outcomeCheck {
+	switch spec := spec.(type) {
+	case string:
+		switch spec {
+		case "exit_code":
+			if result.Success {
+				return outcomeCheck{Check: spec, Passed: true}
+			}
+			return outcomeCheck{Check: spec, Detail: fmt.Sprintf("exit code %d", result.ExitCode)}
+		case "output_schema":
+			if schemaRecord == nil {
+				return outcomeCheck{Check: spec, Detail: "no validated output"}
+			}
+			if schemaRecord.Outcome != api.ValidationOutcomePass {
+				detail := "output did not match output_schema"
+				if schemaRecord.FailureReason != nil {
+					detail = *schemaRecord.FailureReason
+				}
+				return outcomeCheck{Check: spec, Detail: detail}
+			}
+			return outcomeCheck{Check: spec, Passed: true}
+		case "tests":
+			if result.Tests == nil {
+				return outcomeCheck{Check: spec, Detail: "no test results"}
+			}
+			if result.Tests.Failed > 0 {
+				return outcomeCheck{Check: spec, Detail: fmt.Sprintf("%d of %d tests failed", result.Tests.Failed, result.Tests.Total)}
+			}
+			return outcomeCheck{Check: spec, Passed: true}
+		}
+		return outcomeCheck{Check: spec, Detail: "unknown check"}
+	case map[string]interface{}:
+		if path, ok := spec["output_field"].(string); ok {
+			check := "output_field " + path
+			if _, found := lookupOutputField(structured, path); !found {
+				return outcomeCheck{Check: check, Detail: "field not present in structured output"}
+			}
+			return outcomeCheck{Check: check, Passed: true}
+		}
+		if text, ok := spec["stdout_contains"].(string); ok {
+			check := fmt.Sprintf("stdout_contains %q", text)
+			if !strings.Contains(result.Stdout, text) {
+				return outcomeCheck{Check: check, Detail: "not found in stdout"}
+			}
+			return outcomeCheck{Check: check, Passed: true}
+		}
+	}
+	return outcomeCheck{Check: fmt.Sprintf("%v", spec), Detail: "unknown check"}
+}
+
+// lookupOutputField finds a dotted path in structured output, treating
+// null values as missing
+func lookupOutputField(structured map[string]interface{}, path string) (interface{}, bool) {
+	var value interface{} = structured
+	for _, key := range strings.Split(path, ".") {
+		m, ok := value.(map[string]interface{})
+		if !ok {
+			return nil, false
+		}
+		if value = m[key]; value == nil {
+			return nil, false
+		}
+	}
+	return value, true
+}
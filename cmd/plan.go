@@ -11,6 +11,7 @@ import (
 
 	"github.com/kindship-ai/kindship-cli/internal/api"
 	"github.com/kindship-ai/kindship-cli/internal/auth"
+	"github.com/kindship-ai/kindship-cli/internal/output"
 
 	"github.com/spf13/cobra"
 )
@@ -21,12 +22,16 @@ var planCmd = &cobra.Command{
 	Long: `Commands for managing planning entities.
 
 Subcommands:
+  create   Create a plan file (interactively with --interactive)
   submit   Submit a plan from file or stdin
-  next     Get the next executable task`,
+  next     Get the next executable task
+  graph    Show the dependency graph of a plan
+  status   Show the status of every task in a project (--watch to follow)
+  diff     Compare a plan file with a project on the server`,
 }
 
 var planSubmitCmd = &cobra.Command{
-	Use:   "submit [file]",
+	Use:   "submit [file|dir]",
 	Short: "Submit a plan",
 	Long: `Submit a plan to create planning entities.
 
@@ -42,8 +47,24 @@ The plan should be in JSON format with the following structure:
 
 If no file is provided, reads from stdin.
 
+The argument may also be a plan directory with one file per task, which is
+assembled into a single submission:
+  plan-dir/
+    plan.yaml       title, description (optional)
+    task-01.yaml    a task, e.g. {title: ..., execution_mode: PYTHON, code_file: etl.py}
+    task-02.yaml
+    etl.py          script referenced by code_file
+
+Task files (YAML or JSON) are submitted in file name order.
+
+Before submitting, the labeled dependencies are checked: references to
+unknown tasks, dependency cycles, and tasks that could never run because
+of them are all reported, with their line and column in a plan file. The
+code of BASH, TEST and PYTHON tasks is dry-run to check that it parses.
+
 Examples:
   kindship plan submit plan.json
+  kindship plan submit ./plan-dir/
   cat plan.json | kindship plan submit`,
 	RunE: runPlanSubmit,
 }
@@ -59,21 +80,35 @@ A task is executable when:
 
 Output format:
   --format json    JSON output (default)
-  --format text    Human-readable text
+  --format table   Human-readable table
+  --format yaml    YAML output
+
+With --wait, polls with backoff (1s up to 30s between polls) until a task
+becomes available or the timeout passes. If none shows up, the empty result
+is printed as usual.
 
 Examples:
   kindship plan next
-  kindship plan next --format text`,
+  kindship plan next --format table
+  kindship plan next --wait 300`,
 	RunE: runPlanNext,
 }
 
+// Polling backoff for plan next --wait
+const (
+	planNextInitialBackoff = 1 * time.Second
+	planNextMaxBackoff     = 30 * time.Second
+)
+
 var (
-	planFormat string
+	planFormat   string
+	planNextWait int
 )
 
 func init() {
-	planSubmitCmd.Flags().StringVar(&planFormat, "format", "text", "Output format (json, text)")
-	planNextCmd.Flags().StringVar(&planFormat, "format", "json", "Output format (json, text)")
+	planSubmitCmd.Flags().StringVar(&planFormat, "format", "table", output.FormatUsage)
+	planNextCmd.Flags().StringVar(&planFormat, "format", "json", output.FormatUsage)
+	planNextCmd.Flags().IntVar(&planNextWait, "wait", 0, "Wait up to this many seconds for a task to become available")
 
 	planCmd.AddCommand(planSubmitCmd)
 	planCmd.AddCommand(planNextCmd)
@@ -90,8 +125,18 @@ type PlanSubmitRequest struct {
 	SkipBootstrap bool       `json:"skip_bootstrap,omitempty"`
 }
 
+// planFile is a plan document as accepted by 'kindship plan submit'
+type planFile struct {
+	Title       string     `json:"title"`
+	Description string     `json:"description"`
+	Tasks       []TaskSpec `json:"tasks"`
+}
+
 // TaskSpec represents a task in the plan
 type TaskSpec struct {
+	// Key identifies the task across versions of the plan for 'plan apply'
+	// and 'plan diff'; tasks without one are matched by title
+	Key                 string                 `json:"key,omitempty"`
 	Title               string                 `json:"title"`
 	Description         string                 `json:"description,omitempty"`
 	SequenceOrder       int                    `json:"sequence_order,omitempty"`
@@ -120,6 +165,11 @@ type PlanSubmitResponse struct {
 }
 
 func runPlanSubmit(cmd *cobra.Command, args []string) error {
+	format, err := output.ParseFormat(planFormat)
+	if err != nil {
+		return err
+	}
+
 	ctx, err := auth.GetAuthContext()
 	if err != nil {
 		return err
@@ -134,10 +184,10 @@ func runPlanSubmit(cmd *cobra.Command, args []string) error {
 	var planData []byte
 
 	if len(args) > 0 {
-		// Read from file
-		planData, err = os.ReadFile(args[0])
+		// Read from file or plan directory
+		planData, err = readPlan(args[0])
 		if err != nil {
-			return fmt.Errorf("failed to read plan file: %w", err)
+			return err
 		}
 	} else {
 		// Read from stdin
@@ -151,6 +201,16 @@ func runPlanSubmit(cmd *cobra.Command, args []string) error {
 		return fmt.Errorf("no plan data provided")
 	}
 
+	source := "<stdin>"
+	if len(args) > 0 {
+		source = planSource(args[0])
+	}
+	return submitPlan(ctx, agentID, source, planData, format)
+}
+
+// submitPlan submits a plan document for agentID and prints the result.
+// source names the document in validation errors (see checkPlan).
+func submitPlan(ctx *auth.Context, agentID, source string, planData []byte, format output.Format) error {
 	// Parse the plan
 	var plan struct {
 		Title         string     `json:"title"`
@@ -163,6 +223,9 @@ func runPlanSubmit(cmd *cobra.Command, args []string) error {
 	if err := json.Unmarshal(planData, &plan); err != nil {
 		return fmt.Errorf("failed to parse plan: %w", err)
 	}
+	if err := checkPlan(source, planData, plan.Tasks); err != nil {
+		return withExitCode(ExitValidation, err)
+	}
 
 	// Build request
 	reqBody := PlanSubmitRequest{
@@ -191,7 +254,7 @@ func runPlanSubmit(cmd *cobra.Command, args []string) error {
 	req.Header.Set("Content-Type", "application/json")
 	req.Header.Set("X-Kindship-CLI-Version", Version)
 
-	client := &http.Client{Timeout: 30 * time.Second}
+	client := api.NewHTTPClient(30 * time.Second)
 	resp, err := client.Do(req)
 	if err != nil {
 		return fmt.Errorf("failed to submit plan: %w", err)
@@ -216,21 +279,24 @@ func runPlanSubmit(cmd *cobra.Command, args []string) error {
 		return fmt.Errorf("failed to parse response: %w", err)
 	}
 
-	if planFormat == "json" {
-		return printJSON(submitResp)
-	}
-
-	// Human-readable output
-	fmt.Printf("✓ Created project '%s' with %d tasks\n", submitResp.Project.Title, len(submitResp.Tasks))
-	fmt.Printf("  Project ID: %s\n", submitResp.Project.ID)
-	for i, task := range submitResp.Tasks {
-		fmt.Printf("  [%d] %s (%s)\n", i+1, task.Title, task.ID)
-	}
-
-	return nil
+	return output.Render(os.Stdout, format, submitResp, func() error {
+		fmt.Println(output.OK(fmt.Sprintf("Created project '%s' with %d tasks", submitResp.Project.Title, len(submitResp.Tasks))))
+		fmt.Printf("  Project ID: %s\n\n", submitResp.Project.ID)
+		table := output.NewTable("#", "Title", "ID")
+		table.Indent = "  "
+		for i, task := range submitResp.Tasks {
+			table.Row(fmt.Sprintf("%d", i+1), task.Title, task.ID)
+		}
+		return table.Render(os.Stdout)
+	})
 }
 
 func runPlanNext(cmd *cobra.Command, args []string) error {
+	format, err := output.ParseFormat(planFormat)
+	if err != nil {
+		return err
+	}
+
 	ctx, err := auth.GetAuthContext()
 	if err != nil {
 		return err
@@ -241,64 +307,95 @@ func runPlanNext(cmd *cobra.Command, args []string) error {
 		return err
 	}
 
-	// Call plan/next API
+	nextResp, err := fetchPlanNext(ctx, agentID)
+	if err != nil {
+		return err
+	}
+
+	// --wait: poll with backoff until a task shows up or time runs out
+	if planNextWait > 0 && nextResp.Task == nil {
+		deadline := time.Now().Add(time.Duration(planNextWait) * time.Second)
+		backoff := planNextInitialBackoff
+		for nextResp.Task == nil {
+			remaining := time.Until(deadline)
+			if remaining <= 0 {
+				break
+			}
+			if backoff > remaining {
+				backoff = remaining
+			}
+			time.Sleep(backoff)
+
+			if nextResp, err = fetchPlanNext(ctx, agentID); err != nil {
+				return err
+			}
+			backoff *= 2
+			if backoff > planNextMaxBackoff {
+				backoff = planNextMaxBackoff
+			}
+		}
+	}
+
+	return output.Render(os.Stdout, format, nextResp, func() error {
+		if nextResp.Task == nil {
+			fmt.Println("No executable tasks found.")
+			if nextResp.Message != "" {
+				fmt.Printf("Message: %s\n", nextResp.Message)
+			}
+			return nil
+		}
+
+		fmt.Printf("Next task: %s\n", output.Bold(nextResp.Task.Title))
+		table := output.NewTable()
+		table.Indent = "  "
+		table.Row("ID", nextResp.Task.ID)
+		if nextResp.Task.Description != "" {
+			table.Row("Description", nextResp.Task.Description)
+		}
+		if nextResp.Task.Rationale != "" {
+			table.Row("Rationale", nextResp.Task.Rationale)
+		}
+		table.Row("Execution mode", nextResp.Task.ExecutionMode)
+		return table.Render(os.Stdout)
+	})
+}
+
+// fetchPlanNext calls the plan/next API once
+func fetchPlanNext(ctx *auth.Context, agentID string) (*api.PlanNextResponse, error) {
 	endpoint := fmt.Sprintf("%s/api/cli/plan/next?agent_id=%s", ctx.APIBaseURL, agentID)
 
 	req, err := http.NewRequest(http.MethodGet, endpoint, nil)
 	if err != nil {
-		return fmt.Errorf("failed to create request: %w", err)
+		return nil, fmt.Errorf("failed to create request: %w", err)
 	}
 
 	ctx.SetAuthHeaders(req)
 	req.Header.Set("X-Kindship-CLI-Version", Version)
 
-	client := &http.Client{Timeout: 30 * time.Second}
+	client := api.NewHTTPClient(30 * time.Second)
 	resp, err := client.Do(req)
 	if err != nil {
-		return fmt.Errorf("failed to fetch next task: %w", err)
+		return nil, fmt.Errorf("failed to fetch next task: %w", err)
 	}
 	defer resp.Body.Close()
 
 	body, err := io.ReadAll(resp.Body)
 	if err != nil {
-		return fmt.Errorf("failed to read response: %w", err)
+		return nil, fmt.Errorf("failed to read response: %w", err)
 	}
 
 	if resp.StatusCode != http.StatusOK {
 		var errResp api.PlanNextResponse
 		if json.Unmarshal(body, &errResp) == nil && errResp.Error != "" {
-			return fmt.Errorf("failed: %s", errResp.Error)
+			return nil, fmt.Errorf("failed: %s", errResp.Error)
 		}
-		return fmt.Errorf("failed (%d): %s", resp.StatusCode, string(body))
+		return nil, fmt.Errorf("failed (%d): %s", resp.StatusCode, string(body))
 	}
 
 	var nextResp api.PlanNextResponse
 	if err := json.Unmarshal(body, &nextResp); err != nil {
-		return fmt.Errorf("failed to parse response: %w", err)
-	}
-
-	if planFormat == "json" {
-		return printJSON(nextResp)
-	}
-
-	// Human-readable output
-	if nextResp.Task == nil {
-		fmt.Println("No executable tasks found.")
-		if nextResp.Message != "" {
-			fmt.Printf("Message: %s\n", nextResp.Message)
-		}
-		return nil
-	}
-
-	fmt.Printf("Next task: %s\n", nextResp.Task.Title)
-	fmt.Printf("  ID: %s\n", nextResp.Task.ID)
-	if nextResp.Task.Description != "" {
-		fmt.Printf("  Description: %s\n", nextResp.Task.Description)
-	}
-	if nextResp.Task.Rationale != "" {
-		fmt.Printf("  Rationale: %s\n", nextResp.Task.Rationale)
+		return nil, fmt.Errorf("failed to parse response: %w", err)
 	}
-	fmt.Printf("  Execution mode: %s\n", nextResp.Task.ExecutionMode)
 
-	return nil
+	return &nextResp, nil
 }
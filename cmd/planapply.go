@@ -0,0 +1,341 @@
+package cmd
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/kindship-ai/kindship-cli/internal/api"
+	"github.com/kindship-ai/kindship-cli/internal/auth"
+	"github.com/kindship-ai/kindship-cli/internal/output"
+
+	"github.com/spf13/cobra"
+)
+
+var planApplyCmd = &cobra.Command{
+	Use:   "apply <file|dir> --project <id>",
+	Short: "Update a project to match a plan file",
+	Long: `Updates an existing project so its tasks match a local plan (file or plan
+directory), instead of submitting a new project each time the plan changes.
+
+Tasks are matched as in 'kindship plan diff': by "key" when the plan sets
+one, otherwise by title. Matched tasks are updated in place, new tasks are
+created under the project, and tasks no longer in the plan are left alone
+unless --archive-removed is given, which cancels them.
+
+Give each task a stable key so renaming it updates the existing task:
+  {"key": "load", "title": "Load orders", ...}
+
+Schemas, boundaries and dependencies removed from a task in the plan are
+not cleared on the server; use 'kindship entity update' for that.
+
+Examples:
+  kindship plan apply plan.json --project 550e8400-e29b-41d4-a716-446655440000
+  kindship plan apply ./plan-dir/ --project 550e8400-e29b-41d4-a716-446655440000 --archive-removed
+  kindship plan apply plan.json --project 550e8400-e29b-41d4-a716-446655440000 --dry-run`,
+	Args:         usageArgs(cobra.ExactArgs(1)),
+	SilenceUsage: true,
+	RunE:         runPlanApply,
+}
+
+var (
+	planApplyProject        string
+	planApplyArchiveRemoved bool
+	planApplyDryRun         bool
+	planApplyFormat         string
+)
+
+func init() {
+	planApplyCmd.Flags().StringVar(&planApplyProject, "project", "", "Project ID to update (required)")
+	planApplyCmd.Flags().BoolVar(&planApplyArchiveRemoved, "archive-removed", false, "Cancel tasks that are no longer in the plan")
+	planApplyCmd.Flags().BoolVar(&planApplyDryRun, "dry-run", false, "Show what would change without applying it")
+	planApplyCmd.Flags().StringVar(&planApplyFormat, "format", "table", output.FormatUsage)
+
+	planCmd.AddCommand(planApplyCmd)
+}
+
+// Actions taken on a task by plan apply
+const (
+	planActionCreated  = "created"
+	planActionUpdated  = "updated"
+	planActionArchived = "archived"
+	planActionKept     = "kept"
+)
+
+// PlanApplyTask is a task that plan apply created, updated or archived, or
+// a removed task it kept
+type PlanApplyTask struct {
+	Title    string   `json:"title"`
+	EntityID string   `json:"entity_id"`
+	Action   string   `json:"action"`
+	Fields   []string `json:"fields,omitempty"`
+}
+
+// PlanApplyResult is printed by 'kindship plan apply'
+type PlanApplyResult struct {
+	ProjectID    string          `json:"project_id"`
+	ProjectTitle string          `json:"project_title"`
+	Tasks        []PlanApplyTask `json:"tasks"`
+	Unchanged    int             `json:"unchanged"`
+}
+
+func runPlanApply(cmd *cobra.Command, args []string) error {
+	format, err := output.ParseFormat(planApplyFormat)
+	if err != nil {
+		return withExitCode(ExitUsage, err)
+	}
+	if planApplyProject == "" {
+		return withExitCode(ExitUsage, fmt.Errorf("--project is required"))
+	}
+
+	plan, err := loadPlanFile(args[0])
+	if err != nil {
+		return withExitCode(ExitValidation, err)
+	}
+	localTasks, err := resolveLocalTasks(plan.Tasks)
+	if err != nil {
+		return withExitCode(ExitValidation, err)
+	}
+	order, err := orderLocalTasks(localTasks)
+	if err != nil {
+		return withExitCode(ExitValidation, err)
+	}
+
+	ctx, err := auth.GetAuthContext()
+	if err != nil {
+		return withExitCode(ExitAuth, err)
+	}
+	tree, err := fetchPlanTree(ctx, planApplyProject)
+	if err != nil {
+		return withExitCode(ExitAPI, err)
+	}
+	diff, err := computePlanDiff(plan.Tasks, tree)
+	if err != nil {
+		return withExitCode(ExitValidation, err)
+	}
+
+	if planApplyDryRun {
+		return output.Render(os.Stdout, format, diff, func() error {
+			renderPlanDiff(diff)
+			fmt.Printf("\n%s\n", output.Dim("Dry run: no changes applied"))
+			return nil
+		})
+	}
+
+	result := PlanApplyResult{
+		ProjectID:    diff.ProjectID,
+		ProjectTitle: diff.ProjectTitle,
+		Tasks:        []PlanApplyTask{},
+		Unchanged:    diff.Unchanged,
+	}
+	failed := func(err error) error {
+		return withExitCode(ExitAPI, fmt.Errorf("%w (%d change(s) applied before the failure)", err, len(result.Tasks)))
+	}
+
+	ids := map[int]string{}
+	for i, e := range diff.Matches {
+		ids[i] = e.ID
+	}
+	changed := map[int]PlanTaskDiff{}
+	for _, t := range diff.Changed {
+		changed[t.Index] = t
+	}
+
+	// Dependencies come first in order, so their IDs are known by the time
+	// a task that depends on them is created or updated
+	for _, i := range order {
+		spec := plan.Tasks[i]
+		deps := map[string]string{}
+		for label, dep := range localTasks[i].Deps {
+			deps[label] = ids[dep]
+		}
+
+		if _, ok := ids[i]; !ok {
+			entity, err := createPlanTask(ctx, diff.ProjectID, spec, deps)
+			if err != nil {
+				return failed(fmt.Errorf("failed to create task %q: %w", spec.Title, err))
+			}
+			ids[i] = entity.ID
+			result.Tasks = append(result.Tasks, PlanApplyTask{Title: spec.Title, EntityID: entity.ID, Action: planActionCreated})
+			continue
+		}
+
+		t, ok := changed[i]
+		if !ok {
+			continue
+		}
+		fields := make([]string, len(t.Fields))
+		for j, f := range t.Fields {
+			fields[j] = f.Field
+		}
+		if _, err := sendPlanEntity(ctx, http.MethodPatch, "/api/cli/entity/"+t.EntityID, planTaskUpdate(spec, fields, deps)); err != nil {
+			return failed(fmt.Errorf("failed to update task %q: %w", spec.Title, err))
+		}
+		result.Tasks = append(result.Tasks, PlanApplyTask{Title: spec.Title, EntityID: t.EntityID, Action: planActionUpdated, Fields: fields})
+	}
+
+	for _, t := range diff.Removed {
+		action := planActionKept
+		if planApplyArchiveRemoved {
+			if err := cancelPlanTask(ctx, t.EntityID); err != nil {
+				return failed(fmt.Errorf("failed to archive task %q: %w", t.Title, err))
+			}
+			action = planActionArchived
+		}
+		result.Tasks = append(result.Tasks, PlanApplyTask{Title: t.Title, EntityID: t.EntityID, Action: action})
+	}
+
+	return output.Render(os.Stdout, format, result, func() error {
+		renderPlanApply(result)
+		return nil
+	})
+}
+
+// createPlanTask creates a task from the plan under the project. deps maps
+// dependency labels to entity IDs.
+func createPlanTask(ctx *auth.Context, projectID string, spec TaskSpec, deps map[string]string) (*api.PlanningEntity, error) {
+	req := api.EntityCreateRequest{
+		ParentID:            projectID,
+		AgentID:             ctx.AgentID,
+		Key:                 spec.Key,
+		Title:               spec.Title,
+		Description:         spec.Description,
+		SequenceOrder:       spec.SequenceOrder,
+		ExecutionMode:       spec.ExecutionMode,
+		Code:                spec.Code,
+		DependenciesLabeled: deps,
+		InputSchema:         spec.InputSchema,
+		OutputSchema:        spec.OutputSchema,
+		SuccessCriteria:     spec.SuccessCriteria,
+		Boundaries:          spec.Boundaries,
+	}
+	resp, err := sendPlanEntity(ctx, http.MethodPost, "/api/cli/entity", req)
+	if err != nil {
+		return nil, err
+	}
+	return &resp.Entity, nil
+}
+
+// planTaskUpdate builds an update that sets the given fields of a task to
+// their values in the plan. deps maps dependency labels to entity IDs.
+func planTaskUpdate(spec TaskSpec, fields []string, deps map[string]string) api.EntityUpdateRequest {
+	var req api.EntityUpdateRequest
+	for _, field := range fields {
+		switch field {
+		case "key":
+			req.Key = &spec.Key
+		case "title":
+			req.Title = &spec.Title
+		case "description":
+			req.Description = &spec.Description
+		case "execution_mode":
+			mode := string(localExecutionMode(spec))
+			req.ExecutionMode = &mode
+		case "code":
+			req.Code = &spec.Code
+		case "sequence_order":
+			req.SequenceOrder = &spec.SequenceOrder
+		case "input_schema":
+			req.InputSchema = spec.InputSchema
+		case "output_schema":
+			req.OutputSchema = spec.OutputSchema
+		case "success_criteria":
+			req.SuccessCriteria = spec.SuccessCriteria
+		case "boundaries":
+			req.Boundaries = spec.Boundaries
+		case "dependencies":
+			req.DependenciesLabeled = deps
+		}
+	}
+	return req
+}
+
+// cancelPlanTask cancels a task that is no longer in the plan
+func cancelPlanTask(ctx *auth.Context, entityID string) error {
+	_, err := sendPlanEntity(ctx, http.MethodPost, "/api/cli/entity/"+entityID+"/cancel", nil)
+	return err
+}
+
+// sendPlanEntity sends an entity request with the user's credentials
+func sendPlanEntity(ctx *auth.Context, method, path string, payload interface{}) (*api.EntityResponse, error) {
+	var reqBody io.Reader
+	if payload != nil {
+		data, err := json.Marshal(payload)
+		if err != nil {
+			return nil, fmt.Errorf("failed to marshal request: %w", err)
+		}
+		reqBody = bytes.NewReader(data)
+	}
+
+	req, err := http.NewRequest(method, ctx.APIBaseURL+path, reqBody)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+
+	ctx.SetAuthHeaders(req)
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("X-Kindship-CLI-Version", Version)
+
+	client := api.NewHTTPClient(30 * time.Second)
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("request failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read response: %w", err)
+	}
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		var errResp api.EntityResponse
+		if json.Unmarshal(body, &errResp) == nil && errResp.Error != "" {
+			return nil, fmt.Errorf("failed: %s", errResp.Error)
+		}
+		return nil, fmt.Errorf("failed (%d): %s", resp.StatusCode, string(body))
+	}
+
+	var entityResp api.EntityResponse
+	if len(body) > 0 {
+		if err := json.Unmarshal(body, &entityResp); err != nil {
+			return nil, fmt.Errorf("failed to parse response: %w", err)
+		}
+	}
+	return &entityResp, nil
+}
+
+// renderPlanApply prints the human-readable apply report
+func renderPlanApply(r PlanApplyResult) {
+	fmt.Printf("%s %s (%s)\n\n", output.Bold("Applied plan to"), r.ProjectTitle, r.ProjectID)
+	if len(r.Tasks) == 0 {
+		fmt.Println(output.OK(fmt.Sprintf("Already up to date (%d tasks match)", r.Unchanged)))
+		return
+	}
+
+	counts := map[string]int{}
+	for _, t := range r.Tasks {
+		counts[t.Action]++
+		switch t.Action {
+		case planActionCreated:
+			fmt.Printf("%s %s %s\n", output.Green("+"), t.Title, output.Dim(t.EntityID))
+		case planActionUpdated:
+			fmt.Printf("%s %s %s\n", output.Yellow("~"), t.Title, output.Dim(t.EntityID))
+			fmt.Printf("    %s\n", output.Dim(strings.Join(t.Fields, ", ")))
+		case planActionArchived:
+			fmt.Printf("%s %s %s\n", output.Red("-"), t.Title, output.Dim(t.EntityID))
+		case planActionKept:
+			fmt.Printf("%s %s %s\n", output.Dim("="), t.Title, output.Dim(t.EntityID+" (not in plan, kept)"))
+		}
+	}
+	fmt.Printf("\n%d created, %d updated, %d archived, %d unchanged\n",
+		counts[planActionCreated], counts[planActionUpdated], counts[planActionArchived], r.Unchanged)
+	if counts[planActionKept] > 0 {
+		fmt.Println(output.Dim(fmt.Sprintf("%d task(s) not in the plan were kept; use --archive-removed to cancel them", counts[planActionKept])))
+	}
+}
@@ -0,0 +1,296 @@
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/kindship-ai/kindship-cli/internal/api"
+	"github.com/kindship-ai/kindship-cli/internal/boundaries"
+	"github.com/kindship-ai/kindship-cli/internal/executor"
+)
+
+// entityIDPattern matches a dependency that names an existing entity by ID
+// rather than a task in the plan
+var entityIDPattern = regexp.MustCompile(`^[0-9a-fA-F]{8}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{12}$`)
+
+// planIssue is a problem with one task in a plan
+type planIssue struct {
+	Task    int
+	Message string
+}
+
+// checkPlan checks a plan before it is run or submitted. It reports, all
+// at once:
+//   - dependencies that refer to no task in the plan
+//   - dependency cycles, including tasks that depend on themselves
+//   - tasks that can never run because they depend on one of the above
+//   - BASH, TEST and PYTHON code that does not parse (see checkTaskCode)
+//
+// Dependencies may name a task as resolveTaskRef does, or an existing
+// entity by ID. When source names a plan file, each problem is prefixed
+// with the file, line and column of the task in data.
+func checkPlan(source string, data []byte, specs []TaskSpec) error {
+	var issues []planIssue
+	deps := make([][]int, len(specs))
+	broken := make([]bool, len(specs))
+	for i, spec := range specs {
+		labels := make([]string, 0, len(spec.DependenciesLabeled))
+		for label := range spec.DependenciesLabeled {
+			labels = append(labels, label)
+		}
+		sort.Strings(labels)
+		for _, label := range labels {
+			ref := spec.DependenciesLabeled[label]
+			dep := resolveTaskRef(specs, ref)
+			switch {
+			case dep == -1 && entityIDPattern.MatchString(ref):
+				// An existing entity outside the plan
+			case dep == -1:
+				issues = append(issues, planIssue{i, fmt.Sprintf("dependency '%s' refers to unknown task '%s'", label, ref)})
+				broken[i] = true
+			case dep == i:
+				issues = append(issues, planIssue{i, fmt.Sprintf("dependency '%s' refers to itself", label)})
+				broken[i] = true
+			default:
+				deps[i] = append(deps[i], dep)
+			}
+		}
+	}
+
+	cycles := findPlanCycles(deps)
+	onCycle := make([]bool, len(specs))
+	for _, cycle := range cycles {
+		titles := make([]string, 0, len(cycle)+1)
+		for _, i := range cycle {
+			onCycle[i] = true
+			titles = append(titles, specs[i].Title)
+		}
+		titles = append(titles, specs[cycle[0]].Title)
+		issues = append(issues, planIssue{cycle[0], "dependency cycle: " + strings.Join(titles, " → ")})
+	}
+
+	// Anything downstream of a cycle or a broken dependency never runs
+	stuck := make([]bool, len(specs))
+	blockedBy := make([]int, len(specs))
+	for i := range specs {
+		stuck[i] = onCycle[i] || broken[i]
+	}
+	for changed := true; changed; {
+		changed = false
+		for i := range specs {
+			if stuck[i] {
+				continue
+			}
+			for _, dep := range deps[i] {
+				if stuck[dep] {
+					stuck[i], blockedBy[i], changed = true, dep, true
+					break
+				}
+			}
+		}
+	}
+	for i := range specs {
+		if !stuck[i] || onCycle[i] || broken[i] {
+			continue
+		}
+		dep := blockedBy[i]
+		reason := "can never run"
+		switch {
+		case onCycle[dep]:
+			reason = "is on a dependency cycle"
+		case broken[dep]:
+			reason = "has an invalid dependency"
+		}
+		issues = append(issues, planIssue{i, fmt.Sprintf("can never run: it depends on '%s', which %s", specs[dep].Title, reason)})
+	}
+
+	for i, spec := range specs {
+		if msg := checkTaskCode(spec); msg != "" {
+			issues = append(issues, planIssue{i, msg})
+		}
+	}
+
+	if len(issues) == 0 {
+		return nil
+	}
+	sort.SliceStable(issues, func(a, b int) bool { return issues[a].Task < issues[b].Task })
+
+	var offsets []int
+	if source != "" {
+		offsets = planTaskOffsets(data)
+	}
+	lines := make([]string, len(issues))
+	for n, issue := range issues {
+		location := ""
+		if issue.Task < len(offsets) {
+			line, col := lineColumn(data, offsets[issue.Task])
+			location = fmt.Sprintf("%s:%d:%d: ", source, line, col)
+		}
+		lines[n] = fmt.Sprintf("%stask '%s': %s", location, specs[issue.Task].Title, issue.Message)
+	}
+	if len(lines) == 1 {
+		return fmt.Errorf("%s", lines[0])
+	}
+	return fmt.Errorf("plan has %d problems:\n  %s", len(lines), strings.Join(lines, "\n  "))
+}
+
+// checkTaskCode dry-runs the code of a BASH, TEST or PYTHON task to check
+// that it parses, returning the problem or "". Code references and
+// templates are only resolved at execution time and are not checked, nor is
+// anything that depends on the machine (such as a missing interpreter).
+func checkTaskCode(spec TaskSpec) string {
+	mode := localExecutionMode(spec)
+	if spec.Code == "" || !supportsDryRun(mode) {
+		return ""
+	}
+	if _, ok := executor.ParseCodeRef(spec.Code); ok {
+		return ""
+	}
+	if policy, err := boundaries.Parse(spec.Boundaries); err != nil || policy.Template {
+		return ""
+	}
+	e, ok := executor.Lookup(mode)
+	if !ok {
+		return ""
+	}
+
+	// Only the shell applies; network rules would start a proxy or need
+	// a network namespace
+	code := spec.Code
+	entity := &api.PlanningEntity{Title: spec.Title, ExecutionMode: mode, Code: &code}
+	if shell, ok := spec.Boundaries["shell"]; ok {
+		entity.Boundaries = map[string]interface{}{"shell": shell}
+	}
+	result := e.Execute(executor.WithDryRun(context.Background()), entity, nil)
+	if result.Success || result.Stdout == "" || result.Error == nil {
+		// Preparation failures are reported when the task runs
+		return ""
+	}
+	return "code does not parse: " + result.Error.Error()
+}
+
+// findPlanCycles returns each distinct dependency cycle in the graph, as
+// task indexes starting from the lowest one
+func findPlanCycles(deps [][]int) [][]int {
+	const (
+		unvisited = iota
+		visiting
+		done
+	)
+	state := make([]int, len(deps))
+	var stack []int
+	var cycles [][]int
+	seen := map[string]bool{}
+
+	var visit func(i int)
+	visit = func(i int) {
+		state[i] = visiting
+		stack = append(stack, i)
+		for _, dep := range deps[i] {
+			switch state[dep] {
+			case unvisited:
+				visit(dep)
+			case visiting:
+				start := len(stack) - 1
+				for stack[start] != dep {
+					start--
+				}
+				cycle := rotateCycle(append([]int(nil), stack[start:]...))
+				key := fmt.Sprint(cycle)
+				if !seen[key] {
+					seen[key] = true
+					cycles = append(cycles, cycle)
+				}
+			}
+		}
+		stack = stack[:len(stack)-1]
+		state[i] = done
+	}
+	for i := range deps {
+		if state[i] == unvisited {
+			visit(i)
+		}
+	}
+	// The stack walks dependency edges, so reverse each cycle to read in
+	// execution order
+	for _, cycle := range cycles {
+		for a, b := 1, len(cycle)-1; a < b; a, b = a+1, b-1 {
+			cycle[a], cycle[b] = cycle[b], cycle[a]
+		}
+	}
+	return cycles
+}
+
+// rotateCycle rotates a cycle so it starts at its lowest task index
+func rotateCycle(cycle []int) []int {
+	low := 0
+	for i, v := range cycle {
+		if v < cycle[low] {
+			low = i
+		}
+	}
+	return append(cycle[low:], cycle[:low]...)
+}
+
+// planSource returns the name under which problems in the plan at path are
+// located, or "" for a plan directory, whose tasks come from several files
+func planSource(path string) string {
+	if info, err := os.Stat(path); err != nil || info.IsDir() {
+		return ""
+	}
+	return path
+}
+
+// planTaskOffsets returns the byte offset of each element of the "tasks"
+// array in a plan document, or nil if it cannot be parsed
+func planTaskOffsets(data []byte) []int {
+	dec := json.NewDecoder(bytes.NewReader(data))
+	if tok, err := dec.Token(); err != nil || tok != json.Delim('{') {
+		return nil
+	}
+	for dec.More() {
+		tok, err := dec.Token()
+		if err != nil {
+			return nil
+		}
+		if key, _ := tok.(string); !strings.EqualFold(key, "tasks") {
+			var skip json.RawMessage
+			if err := dec.Decode(&skip); err != nil {
+				return nil
+			}
+			continue
+		}
+		if tok, err := dec.Token(); err != nil || tok != json.Delim('[') {
+			return nil
+		}
+		var offsets []int
+		for dec.More() {
+			// The offset is just past the previous token; skip to the task
+			offset := int(dec.InputOffset())
+			for offset < len(data) && strings.IndexByte(" \t\r\n,", data[offset]) >= 0 {
+				offset++
+			}
+			offsets = append(offsets, offset)
+			var skip json.RawMessage
+			if err := dec.Decode(&skip); err != nil {
+				return nil
+			}
+		}
+		return offsets
+	}
+	return nil
+}
+
+// lineColumn converts a byte offset in data to a 1-based line and column
+func lineColumn(data []byte, offset int) (int, int) {
+	before := data[:offset]
+	line := bytes.Count(before, []byte("\n")) + 1
+	col := offset - bytes.LastIndexByte(before, '\n')
+	return line, col
+}
@@ -0,0 +1,356 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/kindship-ai/kindship-cli/internal/api"
+	"github.com/kindship-ai/kindship-cli/internal/auth"
+	"github.com/kindship-ai/kindship-cli/internal/output"
+
+	"github.com/spf13/cobra"
+)
+
+var planDiffCmd = &cobra.Command{
+	Use:   "diff <file|dir> --project <id>",
+	Short: "Compare a plan file with a project on the server",
+	Long: `Compares a local plan (file or plan directory) with the tasks of an
+existing project and reports which tasks would be added, changed or removed
+by the local version.
+
+Tasks are matched by their "key" when the plan sets one, otherwise by
+title. Give tasks a key to keep them matched when they are renamed. For
+matched tasks the key, title, description, execution mode, code, schemas,
+success criteria, boundaries, sequence order and labeled dependencies are
+compared; dependencies are compared by the title of the task they point to.
+
+Use 'kindship plan apply' to make the project match the plan.
+
+Examples:
+  kindship plan diff plan.json --project 550e8400-e29b-41d4-a716-446655440000
+  kindship plan diff ./plan-dir/ --project 550e8400-e29b-41d4-a716-446655440000 --format json`,
+	Args:         usageArgs(cobra.ExactArgs(1)),
+	SilenceUsage: true,
+	RunE:         runPlanDiff,
+}
+
+var (
+	planDiffProject string
+	planDiffFormat  string
+)
+
+func init() {
+	planDiffCmd.Flags().StringVar(&planDiffProject, "project", "", "Project ID to compare against (required)")
+	planDiffCmd.Flags().StringVar(&planDiffFormat, "format", "table", output.FormatUsage)
+
+	planCmd.AddCommand(planDiffCmd)
+}
+
+// Kinds of task change reported by plan diff
+const (
+	planChangeAdded   = "added"
+	planChangeChanged = "changed"
+	planChangeRemoved = "removed"
+)
+
+// PlanFieldChange is one field that differs between server and local task
+type PlanFieldChange struct {
+	Field  string `json:"field"`
+	Server string `json:"server"`
+	Local  string `json:"local"`
+}
+
+// PlanTaskDiff is a task that was added, changed or removed locally
+type PlanTaskDiff struct {
+	Title    string            `json:"title"`
+	EntityID string            `json:"entity_id,omitempty"`
+	Change   string            `json:"change"`
+	Fields   []PlanFieldChange `json:"fields,omitempty"`
+
+	// Index is the task's position in the local plan (-1 when removed)
+	Index int `json:"-"`
+}
+
+// PlanDiff is the result of comparing a local plan with a project
+type PlanDiff struct {
+	ProjectID    string         `json:"project_id"`
+	ProjectTitle string         `json:"project_title"`
+	Added        []PlanTaskDiff `json:"added"`
+	Changed      []PlanTaskDiff `json:"changed"`
+	Removed      []PlanTaskDiff `json:"removed"`
+	Unchanged    int            `json:"unchanged"`
+
+	// Matches maps local task indexes to the server entities they match
+	Matches map[int]api.PlanningEntity `json:"-"`
+}
+
+// HasChanges reports whether the local plan differs from the server
+func (d *PlanDiff) HasChanges() bool {
+	return len(d.Added)+len(d.Changed)+len(d.Removed) > 0
+}
+
+func runPlanDiff(cmd *cobra.Command, args []string) error {
+	format, err := output.ParseFormat(planDiffFormat)
+	if err != nil {
+		return withExitCode(ExitUsage, err)
+	}
+	if planDiffProject == "" {
+		return withExitCode(ExitUsage, fmt.Errorf("--project is required"))
+	}
+
+	plan, err := loadPlanFile(args[0])
+	if err != nil {
+		return withExitCode(ExitValidation, err)
+	}
+
+	ctx, err := auth.GetAuthContext()
+	if err != nil {
+		return withExitCode(ExitAuth, err)
+	}
+	tree, err := fetchPlanTree(ctx, planDiffProject)
+	if err != nil {
+		return withExitCode(ExitAPI, err)
+	}
+
+	diff, err := computePlanDiff(plan.Tasks, tree)
+	if err != nil {
+		return withExitCode(ExitValidation, err)
+	}
+	return output.Render(os.Stdout, format, diff, func() error {
+		renderPlanDiff(diff)
+		return nil
+	})
+}
+
+// loadPlanFile reads and parses a plan file or plan directory
+func loadPlanFile(path string) (*planFile, error) {
+	data, err := readPlan(path)
+	if err != nil {
+		return nil, err
+	}
+	var plan planFile
+	if err := json.Unmarshal(data, &plan); err != nil {
+		return nil, fmt.Errorf("failed to parse plan: %w", err)
+	}
+	if err := checkPlan(planSource(path), data, plan.Tasks); err != nil {
+		return nil, err
+	}
+	return &plan, nil
+}
+
+// computePlanDiff compares local task specs with the tasks of a project
+// tree. Tasks with a key are matched by key; the rest are matched by title,
+// in order when titles repeat.
+func computePlanDiff(specs []TaskSpec, tree *PlanTreeResponse) (*PlanDiff, error) {
+	localTasks, err := resolveLocalTasks(specs)
+	if err != nil {
+		return nil, err
+	}
+	keys := map[string]bool{}
+	for _, spec := range specs {
+		if spec.Key == "" {
+			continue
+		}
+		if keys[spec.Key] {
+			return nil, fmt.Errorf("duplicate task key %q", spec.Key)
+		}
+		keys[spec.Key] = true
+	}
+
+	var server []api.PlanningEntity
+	for _, e := range tree.Entities {
+		if e.ID == tree.Project.ID || (e.Type != "" && e.Type != "TASK") {
+			continue
+		}
+		server = append(server, e)
+	}
+	sort.SliceStable(server, func(i, j int) bool {
+		return server[i].SequenceOrder < server[j].SequenceOrder
+	})
+
+	diff := &PlanDiff{
+		ProjectID:    tree.Project.ID,
+		ProjectTitle: tree.Project.Title,
+		Added:        []PlanTaskDiff{},
+		Changed:      []PlanTaskDiff{},
+		Removed:      []PlanTaskDiff{},
+		Matches:      map[int]api.PlanningEntity{},
+	}
+	matched := make([]bool, len(server))
+	match := func(i int, ok func(api.PlanningEntity) bool) {
+		for j, e := range server {
+			if !matched[j] && ok(e) {
+				matched[j] = true
+				diff.Matches[i] = e
+				return
+			}
+		}
+	}
+	for i, spec := range specs {
+		if spec.Key != "" {
+			match(i, func(e api.PlanningEntity) bool { return e.Key == spec.Key })
+		}
+	}
+	for i, spec := range specs {
+		if _, ok := diff.Matches[i]; ok {
+			continue
+		}
+		// A server task with a different key is a different task
+		match(i, func(e api.PlanningEntity) bool {
+			return e.Title == spec.Title && (e.Key == "" || e.Key == spec.Key)
+		})
+	}
+
+	// Name dependencies by the local title of the task they point to, so
+	// renaming a task does not show up as a change in its dependents
+	serverTitles := map[string]string{}
+	for _, e := range server {
+		serverTitles[e.ID] = e.Title
+	}
+	for i, e := range diff.Matches {
+		serverTitles[e.ID] = specs[i].Title
+	}
+
+	for i, spec := range specs {
+		entity, ok := diff.Matches[i]
+		if !ok {
+			diff.Added = append(diff.Added, PlanTaskDiff{Title: spec.Title, Change: planChangeAdded, Index: i})
+			continue
+		}
+		localDeps := map[string]string{}
+		for label, dep := range localTasks[i].Deps {
+			localDeps[label] = specs[dep].Title
+		}
+		fields := diffTaskFields(spec, entity, localDeps, serverTitles)
+		if len(fields) == 0 {
+			diff.Unchanged++
+			continue
+		}
+		diff.Changed = append(diff.Changed, PlanTaskDiff{
+			Title:    spec.Title,
+			EntityID: entity.ID,
+			Change:   planChangeChanged,
+			Fields:   fields,
+			Index:    i,
+		})
+	}
+	for j, e := range server {
+		if !matched[j] {
+			diff.Removed = append(diff.Removed, PlanTaskDiff{Title: e.Title, EntityID: e.ID, Change: planChangeRemoved, Index: -1})
+		}
+	}
+	return diff, nil
+}
+
+// diffTaskFields lists the fields in which a local task differs from its
+// server entity. localDeps and the server's dependencies are compared as
+// label -> dependency title.
+func diffTaskFields(spec TaskSpec, e api.PlanningEntity, localDeps, serverTitles map[string]string) []PlanFieldChange {
+	var fields []PlanFieldChange
+	add := func(field, server, local string) {
+		if server != local {
+			fields = append(fields, PlanFieldChange{Field: field, Server: server, Local: local})
+		}
+	}
+
+	if spec.Key != "" {
+		add("key", e.Key, spec.Key)
+	}
+	add("title", e.Title, spec.Title)
+	add("description", e.Description, spec.Description)
+	add("execution_mode", string(e.ExecutionMode), string(localExecutionMode(spec)))
+	serverCode := ""
+	if e.Code != nil {
+		serverCode = *e.Code
+	}
+	add("code", serverCode, spec.Code)
+	if spec.SequenceOrder != 0 {
+		add("sequence_order", fmt.Sprint(e.SequenceOrder), fmt.Sprint(spec.SequenceOrder))
+	}
+	add("input_schema", canonicalJSON(e.InputSchema), canonicalJSON(spec.InputSchema))
+	add("output_schema", canonicalJSON(e.OutputSchema), canonicalJSON(spec.OutputSchema))
+	if spec.SuccessCriteria != nil {
+		add("success_criteria", canonicalJSON(e.SuccessCriteria), canonicalJSON(*spec.SuccessCriteria))
+	}
+	add("boundaries", canonicalJSON(e.Boundaries), canonicalJSON(spec.Boundaries))
+
+	serverDeps := map[string]string{}
+	for label, id := range e.DependenciesLabeled {
+		title, ok := serverTitles[id]
+		if !ok {
+			title = id
+		}
+		serverDeps[label] = title
+	}
+	add("dependencies", formatDeps(serverDeps), formatDeps(localDeps))
+	return fields
+}
+
+// canonicalJSON renders a value as compact JSON with sorted keys, treating
+// empty objects as absent
+func canonicalJSON(v interface{}) string {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return fmt.Sprint(v)
+	}
+	s := string(data)
+	if s == "null" || s == "{}" {
+		return ""
+	}
+	return s
+}
+
+// formatDeps renders labeled dependencies as "label=title" in label order
+func formatDeps(deps map[string]string) string {
+	parts := make([]string, 0, len(deps))
+	for label, title := range deps {
+		parts = append(parts, label+"="+title)
+	}
+	sort.Strings(parts)
+	return strings.Join(parts, ", ")
+}
+
+// renderPlanDiff prints the human-readable diff report
+func renderPlanDiff(d *PlanDiff) {
+	fmt.Printf("%s %s (%s)\n\n", output.Bold("Plan diff against"), d.ProjectTitle, d.ProjectID)
+	if !d.HasChanges() {
+		fmt.Println(output.OK(fmt.Sprintf("No changes (%d tasks match)", d.Unchanged)))
+		return
+	}
+
+	for _, t := range d.Added {
+		fmt.Printf("%s %s\n", output.Green("+"), t.Title)
+	}
+	for _, t := range d.Changed {
+		fmt.Printf("%s %s %s\n", output.Yellow("~"), t.Title, output.Dim(t.EntityID))
+		for _, f := range t.Fields {
+			fmt.Printf("    %s: %s → %s\n", f.Field, diffValue(f.Server), diffValue(f.Local))
+		}
+	}
+	for _, t := range d.Removed {
+		fmt.Printf("%s %s %s\n", output.Red("-"), t.Title, output.Dim(t.EntityID))
+	}
+	fmt.Printf("\n%d added, %d changed, %d removed, %d unchanged\n", len(d.Added), len(d.Changed), len(d.Removed), d.Unchanged)
+}
+
+// maxDiffValue caps how much of a changed value is shown
+const maxDiffValue = 60
+
+// diffValue renders a field value on one line for the diff report
+func diffValue(s string) string {
+	if s == "" {
+		return output.Dim("(none)")
+	}
+	lines := strings.Count(s, "\n") + 1
+	s = strings.Join(strings.Fields(s), " ")
+	if len(s) > maxDiffValue {
+		s = s[:maxDiffValue] + "..."
+	}
+	if lines > 1 {
+		return fmt.Sprintf("%q %s", s, output.Dim(fmt.Sprintf("(%d lines)", lines)))
+	}
+	return fmt.Sprintf("%q", s)
+}
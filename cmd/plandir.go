@@ -0,0 +1,157 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// planDirTaskPattern matches task files in a plan directory
+const planDirTaskPattern = "task-*"
+
+// planDirProjectFiles are the accepted names for a plan directory's
+// project-level settings, in order of preference
+var planDirProjectFiles = []string{"plan.yaml", "plan.yml", "plan.json"}
+
+// planDirTask is a task file in a plan directory. CodeFile names a script,
+// relative to the task file, whose contents become the task's code.
+type planDirTask struct {
+	TaskSpec
+	CodeFile string `json:"code_file,omitempty"`
+}
+
+// readPlan reads a plan document from a file, or assembles one from a
+// plan directory
+func readPlan(path string) ([]byte, error) {
+	info, err := os.Stat(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read plan: %w", err)
+	}
+	if info.IsDir() {
+		return loadPlanDir(path)
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read plan file: %w", err)
+	}
+	return data, nil
+}
+
+// loadPlanDir assembles a plan from a directory laid out as:
+//
+//	plan-dir/
+//	  plan.yaml        # title, description, type, skip_bootstrap (optional)
+//	  task-01.yaml     # one TaskSpec per file, in file name order
+//	  task-02.yaml     #   code_file: etl.py loads code from a sibling file
+//	  etl.py
+//
+// Files may be YAML or JSON. Tasks without a sequence_order are numbered by
+// their position. The project title defaults to the directory name.
+func loadPlanDir(dir string) ([]byte, error) {
+	plan := map[string]interface{}{}
+	for _, name := range planDirProjectFiles {
+		path := filepath.Join(dir, name)
+		if _, err := os.Stat(path); err != nil {
+			continue
+		}
+		if err := decodePlanDirFile(path, &plan); err != nil {
+			return nil, err
+		}
+		break
+	}
+	if title, _ := plan["title"].(string); title == "" {
+		abs, err := filepath.Abs(dir)
+		if err != nil {
+			return nil, fmt.Errorf("failed to resolve plan directory: %w", err)
+		}
+		plan["title"] = filepath.Base(abs)
+	}
+
+	paths, err := filepath.Glob(filepath.Join(dir, planDirTaskPattern))
+	if err != nil {
+		return nil, fmt.Errorf("failed to list task files: %w", err)
+	}
+	var taskFiles []string
+	for _, path := range paths {
+		switch strings.ToLower(filepath.Ext(path)) {
+		case ".yaml", ".yml", ".json":
+			taskFiles = append(taskFiles, path)
+		}
+	}
+	sort.Strings(taskFiles)
+	if len(taskFiles) == 0 {
+		return nil, fmt.Errorf("no task files (%s.yaml) found in %s", planDirTaskPattern, dir)
+	}
+
+	tasks := make([]TaskSpec, 0, len(taskFiles))
+	for i, path := range taskFiles {
+		var task planDirTask
+		if err := decodePlanDirFile(path, &task); err != nil {
+			return nil, err
+		}
+		if task.Title == "" {
+			return nil, fmt.Errorf("%s: task title is required", path)
+		}
+		if task.CodeFile != "" {
+			if task.Code != "" {
+				return nil, fmt.Errorf("%s: set either code or code_file, not both", path)
+			}
+			code, err := readCodeFile(path, task.CodeFile)
+			if err != nil {
+				return nil, err
+			}
+			task.Code = code
+		}
+		if task.SequenceOrder == 0 {
+			task.SequenceOrder = i + 1
+		}
+		tasks = append(tasks, task.TaskSpec)
+	}
+	plan["tasks"] = tasks
+
+	data, err := json.Marshal(plan)
+	if err != nil {
+		return nil, fmt.Errorf("failed to assemble plan: %w", err)
+	}
+	return data, nil
+}
+
+// readCodeFile reads the code_file referenced by the task file at taskPath.
+// Relative paths are resolved against the task file's directory.
+func readCodeFile(taskPath, codeFile string) (string, error) {
+	codePath := codeFile
+	if !filepath.IsAbs(codePath) {
+		codePath = filepath.Join(filepath.Dir(taskPath), codePath)
+	}
+	code, err := os.ReadFile(codePath)
+	if err != nil {
+		return "", fmt.Errorf("%s: failed to read code_file: %w", taskPath, err)
+	}
+	return string(code), nil
+}
+
+// decodePlanDirFile decodes a YAML or JSON file into v using v's json tags
+func decodePlanDirFile(path string, v interface{}) error {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return fmt.Errorf("failed to read %s: %w", path, err)
+	}
+	if strings.ToLower(filepath.Ext(path)) != ".json" {
+		var doc interface{}
+		if err := yaml.Unmarshal(data, &doc); err != nil {
+			return fmt.Errorf("failed to parse %s: %w", path, err)
+		}
+		if data, err = json.Marshal(doc); err != nil {
+			return fmt.Errorf("failed to parse %s: %w", path, err)
+		}
+	}
+	if err := json.Unmarshal(data, v); err != nil {
+		return fmt.Errorf("failed to parse %s: %w", path, err)
+	}
+	return nil
+}
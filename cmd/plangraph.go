@@ -0,0 +1,323 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/kindship-ai/kindship-cli/internal/api"
+	"github.com/kindship-ai/kindship-cli/internal/auth"
+	"github.com/kindship-ai/kindship-cli/internal/output"
+
+	"github.com/spf13/cobra"
+)
+
+var planGraphCmd = &cobra.Command{
+	Use:   "graph <project-id>",
+	Short: "Show the dependency graph of a plan",
+	Long: `Prints the dependency DAG of a project's tasks, built from their
+dependencies_labeled, so cycles and unintended serial chains can be spotted
+before anything is executed.
+
+Formats:
+  --format ascii     Tasks grouped into layers that can run in parallel (default)
+  --format dot       Graphviz DOT (render with: dot -Tsvg)
+  --format mermaid   Mermaid flowchart (paste into Markdown)
+
+Edges point from a dependency to the task consuming it and are labeled with
+the input label. Tasks on a dependency cycle are reported; the command exits
+with a validation error if any cycle exists.
+
+Examples:
+  kindship plan graph <project-id>
+  kindship plan graph <project-id> --format dot | dot -Tsvg > plan.svg
+  kindship plan graph <project-id> --format mermaid`,
+	Args:         usageArgs(cobra.ExactArgs(1)),
+	SilenceUsage: true,
+	RunE:         runPlanGraph,
+}
+
+var planGraphFormat string
+
+func init() {
+	planGraphCmd.Flags().StringVar(&planGraphFormat, "format", "ascii", "Graph format (ascii, dot, mermaid)")
+
+	planCmd.AddCommand(planGraphCmd)
+}
+
+// PlanTreeResponse is the response from the plan tree endpoint: the project
+// and every entity below it
+type PlanTreeResponse struct {
+	Project struct {
+		ID    string `json:"id"`
+		Title string `json:"title"`
+	} `json:"project"`
+	Entities []api.PlanningEntity `json:"entities"`
+	Error    string               `json:"error,omitempty"`
+}
+
+// planGraphEdge is a labeled dependency: To consumes From's output as Label
+type planGraphEdge struct {
+	From  string
+	To    string
+	Label string
+}
+
+// planGraph is the dependency DAG of a plan's executable tasks
+type planGraph struct {
+	Nodes []api.PlanningEntity
+	Edges []planGraphEdge
+	// Layers groups node IDs by depth; tasks in one layer can run in parallel
+	Layers [][]string
+	// Cyclic lists the IDs of nodes on (or behind) a dependency cycle
+	Cyclic []string
+}
+
+func runPlanGraph(cmd *cobra.Command, args []string) error {
+	switch planGraphFormat {
+	case "ascii", "dot", "mermaid":
+	default:
+		return withExitCode(ExitUsage, fmt.Errorf("unsupported graph format %q (expected ascii, dot or mermaid)", planGraphFormat))
+	}
+
+	ctx, err := auth.GetAuthContext()
+	if err != nil {
+		return withExitCode(ExitAuth, err)
+	}
+
+	tree, err := fetchPlanTree(ctx, args[0])
+	if err != nil {
+		return withExitCode(ExitAPI, err)
+	}
+
+	graph := buildPlanGraph(tree.Entities)
+
+	switch planGraphFormat {
+	case "dot":
+		writePlanGraphDOT(os.Stdout, tree.Project.Title, graph)
+	case "mermaid":
+		writePlanGraphMermaid(os.Stdout, graph)
+	default:
+		writePlanGraphASCII(os.Stdout, tree.Project.Title, graph)
+	}
+
+	if len(graph.Cyclic) > 0 {
+		return withExitCode(ExitValidation, fmt.Errorf("dependency cycle among %d tasks", len(graph.Cyclic)))
+	}
+	return nil
+}
+
+// fetchPlanTree fetches a project and all entities below it
+func fetchPlanTree(ctx *auth.Context, projectID string) (*PlanTreeResponse, error) {
+	endpoint := fmt.Sprintf("%s/api/cli/plan/%s/tree", ctx.APIBaseURL, projectID)
+
+	req, err := http.NewRequest(http.MethodGet, endpoint, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+
+	ctx.SetAuthHeaders(req)
+	req.Header.Set("X-Kindship-CLI-Version", Version)
+
+	client := api.NewHTTPClient(30 * time.Second)
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch plan: %w", err)
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read response: %w", err)
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		var errResp PlanTreeResponse
+		if json.Unmarshal(body, &errResp) == nil && errResp.Error != "" {
+			return nil, fmt.Errorf("failed: %s", errResp.Error)
+		}
+		return nil, fmt.Errorf("failed (%d): %s", resp.StatusCode, string(body))
+	}
+
+	var treeResp PlanTreeResponse
+	if err := json.Unmarshal(body, &treeResp); err != nil {
+		return nil, fmt.Errorf("failed to parse response: %w", err)
+	}
+
+	return &treeResp, nil
+}
+
+// buildPlanGraph builds the dependency DAG of the given entities and
+// layers it with Kahn's algorithm. Dependencies on entities outside the
+// tree are ignored. Nodes left over after layering are on a cycle or
+// depend on one.
+func buildPlanGraph(entities []api.PlanningEntity) *planGraph {
+	nodes := make([]api.PlanningEntity, 0, len(entities))
+	known := make(map[string]bool, len(entities))
+	for _, e := range entities {
+		// Containers (the project itself, objectives) have no dependencies
+		// and would only clutter the graph
+		if e.ExecutionMode == "" && len(e.DependenciesLabeled) == 0 {
+			continue
+		}
+		nodes = append(nodes, e)
+		known[e.ID] = true
+	}
+	sort.SliceStable(nodes, func(i, j int) bool {
+		return nodes[i].SequenceOrder < nodes[j].SequenceOrder
+	})
+
+	g := &planGraph{Nodes: nodes}
+	indegree := make(map[string]int, len(nodes))
+	consumers := make(map[string][]string)
+	for _, n := range nodes {
+		labels := make([]string, 0, len(n.DependenciesLabeled))
+		for label := range n.DependenciesLabeled {
+			labels = append(labels, label)
+		}
+		sort.Strings(labels)
+		for _, label := range labels {
+			from := n.DependenciesLabeled[label]
+			if !known[from] {
+				continue
+			}
+			g.Edges = append(g.Edges, planGraphEdge{From: from, To: n.ID, Label: label})
+			indegree[n.ID]++
+			consumers[from] = append(consumers[from], n.ID)
+		}
+	}
+
+	var layer []string
+	for _, n := range nodes {
+		if indegree[n.ID] == 0 {
+			layer = append(layer, n.ID)
+		}
+	}
+	placed := 0
+	for len(layer) > 0 {
+		g.Layers = append(g.Layers, layer)
+		placed += len(layer)
+		var next []string
+		for _, id := range layer {
+			for _, c := range consumers[id] {
+				indegree[c]--
+				if indegree[c] == 0 {
+					next = append(next, c)
+				}
+			}
+		}
+		layer = next
+	}
+	if placed < len(nodes) {
+		for _, n := range nodes {
+			if indegree[n.ID] > 0 {
+				g.Cyclic = append(g.Cyclic, n.ID)
+			}
+		}
+	}
+	return g
+}
+
+// titles maps node IDs to titles
+func (g *planGraph) titles() map[string]string {
+	titles := make(map[string]string, len(g.Nodes))
+	for _, n := range g.Nodes {
+		titles[n.ID] = n.Title
+	}
+	return titles
+}
+
+// writePlanGraphASCII prints the graph as parallel layers, followed by any
+// tasks on a cycle
+func writePlanGraphASCII(w io.Writer, title string, g *planGraph) {
+	titles := g.titles()
+	deps := make(map[string][]planGraphEdge)
+	for _, e := range g.Edges {
+		deps[e.To] = append(deps[e.To], e)
+	}
+
+	fmt.Fprintf(w, "%s (%d tasks, %d dependencies)\n", output.Bold(title), len(g.Nodes), len(g.Edges))
+	for i, layer := range g.Layers {
+		fmt.Fprintf(w, "\nLayer %d", i+1)
+		if len(layer) > 1 {
+			fmt.Fprintf(w, " (%d in parallel)", len(layer))
+		}
+		fmt.Fprintln(w)
+		for _, id := range layer {
+			fmt.Fprintf(w, "  %s %s\n", titles[id], output.Dim("["+id+"]"))
+			for _, e := range deps[id] {
+				fmt.Fprintf(w, "    <- %s as %s\n", titles[e.From], e.Label)
+			}
+		}
+	}
+
+	if len(g.Cyclic) > 0 {
+		fmt.Fprintf(w, "\n%s\n", output.Fail("Dependency cycle: these tasks can never become executable"))
+		for _, id := range g.Cyclic {
+			fmt.Fprintf(w, "  %s %s\n", titles[id], output.Dim("["+id+"]"))
+			for _, e := range deps[id] {
+				fmt.Fprintf(w, "    <- %s as %s\n", titles[e.From], e.Label)
+			}
+		}
+	}
+}
+
+// writePlanGraphDOT prints the graph in Graphviz DOT format. Tasks on a
+// cycle are highlighted in red.
+func writePlanGraphDOT(w io.Writer, title string, g *planGraph) {
+	cyclic := make(map[string]bool, len(g.Cyclic))
+	for _, id := range g.Cyclic {
+		cyclic[id] = true
+	}
+
+	fmt.Fprintln(w, "digraph plan {")
+	fmt.Fprintf(w, "  label=%s;\n", dotQuote(title))
+	fmt.Fprintln(w, "  rankdir=LR;")
+	fmt.Fprintln(w, "  node [shape=box];")
+	for _, n := range g.Nodes {
+		attrs := fmt.Sprintf("label=%s", dotQuote(fmt.Sprintf("%s\n%s", n.Title, n.ExecutionMode)))
+		if cyclic[n.ID] {
+			attrs += ", color=red"
+		}
+		fmt.Fprintf(w, "  %s [%s];\n", dotQuote(n.ID), attrs)
+	}
+	for _, e := range g.Edges {
+		fmt.Fprintf(w, "  %s -> %s [label=%s];\n", dotQuote(e.From), dotQuote(e.To), dotQuote(e.Label))
+	}
+	fmt.Fprintln(w, "}")
+}
+
+// writePlanGraphMermaid prints the graph as a Mermaid flowchart. Node IDs
+// are numbered since entity UUIDs are not valid Mermaid identifiers.
+func writePlanGraphMermaid(w io.Writer, g *planGraph) {
+	ids := make(map[string]string, len(g.Nodes))
+	fmt.Fprintln(w, "flowchart LR")
+	for i, n := range g.Nodes {
+		ids[n.ID] = fmt.Sprintf("t%d", i+1)
+		fmt.Fprintf(w, "  %s[%s]\n", ids[n.ID], mermaidQuote(n.Title))
+	}
+	for _, e := range g.Edges {
+		fmt.Fprintf(w, "  %s -->|%s| %s\n", ids[e.From], mermaidQuote(e.Label), ids[e.To])
+	}
+	for _, id := range g.Cyclic {
+		fmt.Fprintf(w, "  style %s stroke:#f00,stroke-width:2px\n", ids[id])
+	}
+}
+
+// dotQuote returns s as a quoted DOT string
+func dotQuote(s string) string {
+	s = strings.ReplaceAll(s, `\`, `\\`)
+	s = strings.ReplaceAll(s, `"`, `\"`)
+	s = strings.ReplaceAll(s, "\n", `\n`)
+	return `"` + s + `"`
+}
+
+// mermaidQuote returns s as a quoted Mermaid label
+func mermaidQuote(s string) string {
+	return `"` + strings.ReplaceAll(s, `"`, "#quot;") + `"`
+}
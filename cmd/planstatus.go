@@ -0,0 +1,271 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/kindship-ai/kindship-cli/internal/api"
+	"github.com/kindship-ai/kindship-cli/internal/auth"
+	"github.com/kindship-ai/kindship-cli/internal/output"
+
+	"github.com/spf13/cobra"
+)
+
+var planStatusCmd = &cobra.Command{
+	Use:   "status <project-id>",
+	Short: "Show the status of every task in a project",
+	Long: `Shows a project's objectives and tasks as a tree with the status and
+execution mode of each, followed by a count per status.
+
+With --watch the view is refreshed every --interval until every task has
+reached a terminal status (COMPLETED, FAILED or CANCELLED) or Ctrl-C is
+pressed. Tasks whose status changed since the previous refresh are
+highlighted with their previous status, which makes it a lightweight live
+monitor for long Process runs. --watch only supports the table format.
+
+Examples:
+  kindship plan status 550e8400-e29b-41d4-a716-446655440000
+  kindship plan status 550e8400-e29b-41d4-a716-446655440000 --format json
+  kindship plan status 550e8400-e29b-41d4-a716-446655440000 --watch --interval 10s`,
+	Args:         usageArgs(cobra.ExactArgs(1)),
+	SilenceUsage: true,
+	RunE:         runPlanStatus,
+}
+
+var (
+	planStatusFormat   string
+	planStatusWatch    bool
+	planStatusInterval time.Duration
+)
+
+func init() {
+	planStatusCmd.Flags().StringVar(&planStatusFormat, "format", "table", output.FormatUsage)
+	planStatusCmd.Flags().BoolVar(&planStatusWatch, "watch", false, "Refresh the view until every task has finished")
+	planStatusCmd.Flags().DurationVar(&planStatusInterval, "interval", 5*time.Second, "Time between refreshes with --watch")
+
+	planCmd.AddCommand(planStatusCmd)
+}
+
+// PlanStatusEntity is an entity in the plan status tree
+type PlanStatusEntity struct {
+	ID            string `json:"id"`
+	Title         string `json:"title"`
+	Type          string `json:"type"`
+	Status        string `json:"status"`
+	ExecutionMode string `json:"execution_mode,omitempty"`
+	ParentID      string `json:"parent_id,omitempty"`
+	Depth         int    `json:"depth"`
+}
+
+// PlanStatusOutput is printed by 'kindship plan status'
+type PlanStatusOutput struct {
+	ProjectID    string             `json:"project_id"`
+	ProjectTitle string             `json:"project_title"`
+	Counts       map[string]int     `json:"counts"`
+	Entities     []PlanStatusEntity `json:"entities"`
+}
+
+func runPlanStatus(cmd *cobra.Command, args []string) error {
+	format, err := output.ParseFormat(planStatusFormat)
+	if err != nil {
+		return withExitCode(ExitUsage, err)
+	}
+	if planStatusWatch && format != output.FormatTable {
+		return withExitCode(ExitUsage, fmt.Errorf("--watch only supports the table format"))
+	}
+	if planStatusWatch && planStatusInterval <= 0 {
+		return withExitCode(ExitUsage, fmt.Errorf("--interval must be positive"))
+	}
+
+	ctx, err := auth.GetAuthContext()
+	if err != nil {
+		return withExitCode(ExitAuth, err)
+	}
+
+	tree, err := fetchPlanTree(ctx, args[0])
+	if err != nil {
+		return withExitCode(ExitAPI, err)
+	}
+	status := buildPlanStatus(tree)
+	if !planStatusWatch {
+		return output.Render(os.Stdout, format, status, func() error {
+			return renderPlanStatus(status, nil)
+		})
+	}
+	return watchPlanStatus(ctx, args[0], status)
+}
+
+// watchPlanStatus redraws the status tree every --interval, highlighting
+// changes, until every task has finished
+func watchPlanStatus(ctx *auth.Context, projectID string, status *PlanStatusOutput) error {
+	clear := stdoutIsTerminal()
+	var previous map[string]string
+	for {
+		if clear {
+			fmt.Print("\x1b[H\x1b[2J")
+		} else if previous != nil {
+			fmt.Println()
+		}
+		if err := renderPlanStatus(status, previous); err != nil {
+			return err
+		}
+
+		changes := 0
+		current := make(map[string]string, len(status.Entities))
+		for _, e := range status.Entities {
+			current[e.ID] = e.Status
+			if previous != nil && previous[e.ID] != e.Status {
+				changes++
+			}
+		}
+		fmt.Printf("\n%s\n", output.Dim(fmt.Sprintf("Updated %s · %d change(s) · refreshing every %s (Ctrl-C to stop)",
+			time.Now().Format("15:04:05"), changes, planStatusInterval)))
+		if planStatusFinished(status) {
+			fmt.Println(output.OK("Every task has finished"))
+			return nil
+		}
+		previous = current
+
+		time.Sleep(planStatusInterval)
+		tree, err := fetchPlanTree(ctx, projectID)
+		if err != nil {
+			// Keep showing the last view; the next refresh may succeed
+			fmt.Fprintf(os.Stderr, "%s failed to refresh: %v\n", time.Now().Format("15:04:05"), err)
+			continue
+		}
+		status = buildPlanStatus(tree)
+	}
+}
+
+// buildPlanStatus arranges the entities of a project tree depth-first by
+// parent, ordering siblings by sequence_order
+func buildPlanStatus(tree *PlanTreeResponse) *PlanStatusOutput {
+	known := map[string]bool{}
+	for _, e := range tree.Entities {
+		known[e.ID] = true
+	}
+	children := map[string][]api.PlanningEntity{}
+	for _, e := range tree.Entities {
+		if e.ID == tree.Project.ID {
+			continue
+		}
+		parent := tree.Project.ID
+		if e.ParentID != nil && known[*e.ParentID] {
+			parent = *e.ParentID
+		}
+		children[parent] = append(children[parent], e)
+	}
+
+	status := &PlanStatusOutput{
+		ProjectID:    tree.Project.ID,
+		ProjectTitle: tree.Project.Title,
+		Counts:       map[string]int{},
+		Entities:     []PlanStatusEntity{},
+	}
+	var walk func(parent string, depth int)
+	walk = func(parent string, depth int) {
+		siblings := children[parent]
+		sort.SliceStable(siblings, func(i, j int) bool {
+			return siblings[i].SequenceOrder < siblings[j].SequenceOrder
+		})
+		for _, e := range siblings {
+			entityStatus := strings.ToUpper(e.Status)
+			status.Entities = append(status.Entities, PlanStatusEntity{
+				ID:            e.ID,
+				Title:         e.Title,
+				Type:          e.Type,
+				Status:        entityStatus,
+				ExecutionMode: string(e.ExecutionMode),
+				ParentID:      parent,
+				Depth:         depth,
+			})
+			status.Counts[entityStatus]++
+			walk(e.ID, depth+1)
+		}
+	}
+	walk(tree.Project.ID, 0)
+	return status
+}
+
+// planStatusFinished reports whether every task of the project has reached
+// a terminal status
+func planStatusFinished(status *PlanStatusOutput) bool {
+	tasks := 0
+	for _, e := range status.Entities {
+		if e.Type != "" && e.Type != "TASK" {
+			continue
+		}
+		tasks++
+		if !isTerminalEntityStatus(e.Status) {
+			return false
+		}
+	}
+	return tasks > 0
+}
+
+// renderPlanStatus prints the status tree. Entities whose status differs
+// from previous (when given) are highlighted with the status they had.
+func renderPlanStatus(status *PlanStatusOutput, previous map[string]string) error {
+	fmt.Printf("%s %s (%s)\n\n", output.Bold("Plan status:"), status.ProjectTitle, status.ProjectID)
+	if len(status.Entities) == 0 {
+		fmt.Println(output.Dim("  No tasks in this project"))
+		return nil
+	}
+
+	table := output.NewTable()
+	table.Indent = "  "
+	for _, e := range status.Entities {
+		change := ""
+		statusCell := colorEntityStatus(e.Status)
+		if previous != nil {
+			if was, ok := previous[e.ID]; !ok {
+				change = output.Yellow("new")
+			} else if was != e.Status {
+				statusCell = output.Bold(statusCell)
+				change = output.Yellow("was " + was)
+			}
+		}
+		table.Row(strings.Repeat("  ", e.Depth)+e.Title, statusCell, e.ExecutionMode, change)
+	}
+	if err := table.Render(os.Stdout); err != nil {
+		return err
+	}
+
+	names := make([]string, 0, len(status.Counts))
+	for name := range status.Counts {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	counts := make([]string, len(names))
+	for i, name := range names {
+		counts[i] = fmt.Sprintf("%d %s", status.Counts[name], strings.ToLower(name))
+	}
+	fmt.Printf("\n%s\n", strings.Join(counts, ", "))
+	return nil
+}
+
+// colorEntityStatus renders an entity status in the color of its outcome
+func colorEntityStatus(status string) string {
+	switch status {
+	case "COMPLETED":
+		return output.Green(status)
+	case "FAILED":
+		return output.Red(status)
+	case "IN_PROGRESS":
+		return output.Yellow(status)
+	case "CANCELLED":
+		return output.Dim(status)
+	default:
+		return status
+	}
+}
+
+// stdoutIsTerminal reports whether output goes to a terminal that can be
+// redrawn in place
+func stdoutIsTerminal() bool {
+	info, err := os.Stdout.Stat()
+	return err == nil && info.Mode()&os.ModeCharDevice != 0
+}
@@ -0,0 +1,380 @@
+package cmd
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/kindship-ai/kindship-cli/internal/api"
+	"github.com/kindship-ai/kindship-cli/internal/auth"
+	"github.com/kindship-ai/kindship-cli/internal/output"
+
+	"github.com/spf13/cobra"
+)
+
+var planCreateCmd = &cobra.Command{
+	Use:   "create",
+	Short: "Create a plan file",
+	Long: `Create a plan file for 'kindship plan submit'.
+
+With --interactive, walks through the project title, tasks, execution modes,
+dependencies and schemas with terminal prompts, writes the plan file and
+offers to submit it. Without it, writes a starter plan to edit by hand.
+
+Examples:
+  kindship plan create --interactive
+  kindship plan create --interactive --output my-plan.json
+  kindship plan create --output my-plan.json`,
+	Args:         cobra.NoArgs,
+	SilenceUsage: true,
+	RunE:         runPlanCreate,
+}
+
+var (
+	planCreateInteractive bool
+	planCreateOutput      string
+)
+
+// planWizardModes are the execution modes offered by the wizard
+var planWizardModes = []api.ExecutionMode{
+	api.ExecutionModeLLMReasoning,
+	api.ExecutionModeBash,
+	api.ExecutionModePython,
+	api.ExecutionModeTest,
+	api.ExecutionModeAskUser,
+}
+
+// schemaTypes are the JSON schema types accepted for schema fields
+var schemaTypes = map[string]bool{
+	"string": true, "number": true, "integer": true, "boolean": true, "object": true, "array": true,
+}
+
+var labelSanitizer = regexp.MustCompile(`[^a-z0-9]+`)
+
+func init() {
+	planCreateCmd.Flags().BoolVarP(&planCreateInteractive, "interactive", "i", false, "Build the plan with interactive prompts")
+	planCreateCmd.Flags().StringVarP(&planCreateOutput, "output", "o", "plan.json", "Plan file to write")
+
+	planCmd.AddCommand(planCreateCmd)
+}
+
+func runPlanCreate(cmd *cobra.Command, args []string) error {
+	p := &prompter{in: bufio.NewReader(os.Stdin), out: os.Stdout}
+
+	if _, err := os.Stat(planCreateOutput); err == nil {
+		if !planCreateInteractive {
+			return fmt.Errorf("%s already exists", planCreateOutput)
+		}
+		overwrite, err := p.confirm(fmt.Sprintf("%s already exists. Overwrite?", planCreateOutput), false)
+		if err != nil {
+			return err
+		}
+		if !overwrite {
+			return nil
+		}
+	}
+
+	var plan *planFile
+	if planCreateInteractive {
+		var err error
+		if plan, err = runPlanWizard(p); err != nil {
+			return err
+		}
+	} else {
+		plan = starterPlan()
+	}
+
+	data, err := json.MarshalIndent(plan, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal plan: %w", err)
+	}
+	data = append(data, '\n')
+	if err := os.WriteFile(planCreateOutput, data, 0644); err != nil {
+		return fmt.Errorf("failed to write plan file: %w", err)
+	}
+	fmt.Println(output.OK(fmt.Sprintf("Wrote %s (%d tasks)", planCreateOutput, len(plan.Tasks))))
+
+	if !planCreateInteractive {
+		fmt.Printf("Edit it, then run: kindship plan submit %s\n", planCreateOutput)
+		return nil
+	}
+
+	submit, err := p.confirm("Submit this plan now?", false)
+	if err != nil {
+		return err
+	}
+	if !submit {
+		fmt.Printf("Submit it later with: kindship plan submit %s\n", planCreateOutput)
+		return nil
+	}
+
+	ctx, err := auth.GetAuthContext()
+	if err != nil {
+		return err
+	}
+	agentID, err := ctx.RequireAgentID()
+	if err != nil {
+		return err
+	}
+	return submitPlan(ctx, agentID, "", data, output.FormatTable)
+}
+
+// starterPlan is the template written by 'plan create' without --interactive
+func starterPlan() *planFile {
+	return &planFile{
+		Title:       "My project",
+		Description: "What this project achieves",
+		Tasks: []TaskSpec{
+			{
+				Title:         "Gather data",
+				Description:   "Collect the inputs the project needs",
+				ExecutionMode: string(api.ExecutionModeBash),
+				Code:          `echo '{"items": []}' > "$OUTPUT_FILE"`,
+			},
+			{
+				Title:               "Summarize",
+				Description:         "Summarize the gathered data",
+				ExecutionMode:       string(api.ExecutionModeLLMReasoning),
+				DependenciesLabeled: map[string]string{"data": "Gather data"},
+			},
+		},
+	}
+}
+
+// runPlanWizard prompts for a complete plan
+func runPlanWizard(p *prompter) (*planFile, error) {
+	plan := &planFile{}
+	var err error
+
+	fmt.Fprintln(p.out, output.Bold("Project"))
+	if plan.Title, err = p.askRequired("Title"); err != nil {
+		return nil, err
+	}
+	if plan.Description, err = p.ask("Description", ""); err != nil {
+		return nil, err
+	}
+
+	for {
+		fmt.Fprintln(p.out)
+		fmt.Fprintln(p.out, output.Bold(fmt.Sprintf("Task %d", len(plan.Tasks)+1)))
+		title, err := p.ask("Title (leave empty to finish)", "")
+		if err != nil {
+			return nil, err
+		}
+		if title == "" {
+			if len(plan.Tasks) == 0 {
+				fmt.Fprintln(p.out, "A plan needs at least one task.")
+				continue
+			}
+			break
+		}
+		task, err := promptTask(p, title, plan.Tasks)
+		if err != nil {
+			return nil, err
+		}
+		plan.Tasks = append(plan.Tasks, *task)
+	}
+
+	return plan, nil
+}
+
+// promptTask prompts for the remaining fields of a task
+func promptTask(p *prompter, title string, previous []TaskSpec) (*TaskSpec, error) {
+	task := &TaskSpec{Title: title, SequenceOrder: len(previous) + 1}
+	var err error
+
+	if task.Description, err = p.ask("Description", ""); err != nil {
+		return nil, err
+	}
+
+	modes := make([]string, len(planWizardModes))
+	for i, m := range planWizardModes {
+		modes[i] = string(m)
+	}
+	if task.ExecutionMode, err = p.choose("Execution mode", modes, 0); err != nil {
+		return nil, err
+	}
+
+	switch api.ExecutionMode(task.ExecutionMode) {
+	case api.ExecutionModeBash, api.ExecutionModePython, api.ExecutionModeTest:
+		code, err := p.askRequired("Code (inline, @path to read a file, or file://path@ref)")
+		if err != nil {
+			return nil, err
+		}
+		if strings.HasPrefix(code, "@") {
+			data, readErr := os.ReadFile(strings.TrimPrefix(code, "@"))
+			if readErr != nil {
+				return nil, fmt.Errorf("failed to read code file: %w", readErr)
+			}
+			code = string(data)
+		}
+		task.Code = code
+	}
+
+	// Dependencies on earlier tasks, with labels suggested from their titles
+	if len(previous) > 0 {
+		fmt.Fprintln(p.out, "  Earlier tasks:")
+		for i, t := range previous {
+			fmt.Fprintf(p.out, "    [%d] %s\n", i+1, t.Title)
+		}
+		answer, err := p.ask("Depends on (comma-separated numbers)", "")
+		if err != nil {
+			return nil, err
+		}
+		for _, field := range strings.Split(answer, ",") {
+			field = strings.TrimSpace(field)
+			if field == "" {
+				continue
+			}
+			n, convErr := strconv.Atoi(field)
+			if convErr != nil || n < 1 || n > len(previous) {
+				fmt.Fprintf(p.out, "  Ignoring invalid task number: %s\n", field)
+				continue
+			}
+			dep := previous[n-1]
+			label, err := p.ask(fmt.Sprintf("Label for '%s'", dep.Title), suggestLabel(dep.Title))
+			if err != nil {
+				return nil, err
+			}
+			if task.DependenciesLabeled == nil {
+				task.DependenciesLabeled = map[string]string{}
+			}
+			task.DependenciesLabeled[label] = dep.Title
+		}
+	}
+
+	if task.InputSchema, err = promptSchema(p, "input"); err != nil {
+		return nil, err
+	}
+	if task.OutputSchema, err = promptSchema(p, "output"); err != nil {
+		return nil, err
+	}
+	return task, nil
+}
+
+// promptSchema optionally builds a flat object schema from name:type fields
+func promptSchema(p *prompter, kind string) (map[string]interface{}, error) {
+	define, err := p.confirm(fmt.Sprintf("Define an %s schema?", kind), false)
+	if err != nil || !define {
+		return nil, err
+	}
+
+	for {
+		answer, err := p.askRequired("Fields as name:type, comma-separated (types: string, number, integer, boolean, object, array)")
+		if err != nil {
+			return nil, err
+		}
+		properties := map[string]interface{}{}
+		var required []string
+		valid := true
+		for _, field := range strings.Split(answer, ",") {
+			name, typ, _ := strings.Cut(strings.TrimSpace(field), ":")
+			name, typ = strings.TrimSpace(name), strings.TrimSpace(typ)
+			if typ == "" {
+				typ = "string"
+			}
+			if name == "" || !schemaTypes[typ] {
+				fmt.Fprintf(p.out, "  Invalid field: %s\n", field)
+				valid = false
+				break
+			}
+			properties[name] = map[string]interface{}{"type": typ}
+			required = append(required, name)
+		}
+		if !valid {
+			continue
+		}
+		return map[string]interface{}{
+			"type":       "object",
+			"properties": properties,
+			"required":   required,
+		}, nil
+	}
+}
+
+// suggestLabel derives a dependency label from a task title
+func suggestLabel(title string) string {
+	label := strings.Trim(labelSanitizer.ReplaceAllString(strings.ToLower(title), "_"), "_")
+	if label == "" {
+		return "input"
+	}
+	return label
+}
+
+// prompter reads answers to terminal prompts
+type prompter struct {
+	in  *bufio.Reader
+	out io.Writer
+}
+
+func (p *prompter) ask(label, def string) (string, error) {
+	if def != "" {
+		fmt.Fprintf(p.out, "  %s [%s]: ", label, def)
+	} else {
+		fmt.Fprintf(p.out, "  %s: ", label)
+	}
+	line, err := p.in.ReadString('\n')
+	if err != nil && (err != io.EOF || line == "") {
+		return "", fmt.Errorf("failed to read input: %w", err)
+	}
+	line = strings.TrimSpace(line)
+	if line == "" {
+		return def, nil
+	}
+	return line, nil
+}
+
+func (p *prompter) askRequired(label string) (string, error) {
+	for {
+		answer, err := p.ask(label, "")
+		if err != nil || answer != "" {
+			return answer, err
+		}
+	}
+}
+
+func (p *prompter) confirm(label string, def bool) (bool, error) {
+	hint := "y/N"
+	if def {
+		hint = "Y/n"
+	}
+	for {
+		answer, err := p.ask(fmt.Sprintf("%s (%s)", label, hint), "")
+		if err != nil {
+			return false, err
+		}
+		switch strings.ToLower(answer) {
+		case "":
+			return def, nil
+		case "y", "yes":
+			return true, nil
+		case "n", "no":
+			return false, nil
+		}
+	}
+}
+
+func (p *prompter) choose(label string, options []string, def int) (string, error) {
+	for i, o := range options {
+		fmt.Fprintf(p.out, "    [%d] %s\n", i+1, o)
+	}
+	for {
+		answer, err := p.ask(label, strconv.Itoa(def+1))
+		if err != nil {
+			return "", err
+		}
+		if n, convErr := strconv.Atoi(answer); convErr == nil && n >= 1 && n <= len(options) {
+			return options[n-1], nil
+		}
+		for _, o := range options {
+			if strings.EqualFold(answer, o) {
+				return o, nil
+			}
+		}
+	}
+}
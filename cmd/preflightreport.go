@@ -0,0 +1,65 @@
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/kindship-ai/kindship-cli/internal/api"
+)
+
+// reportInputFailure records an input check that failed before a run was
+// created (input_schema validation or input mapping) as a FAILED attempt,
+// so the failure is visible in the UI and not just on stderr. Reporting
+// errors are logged; the caller still returns the original error.
+func reportInputFailure(params EntityExecutionParams, entityResp *api.EntityExecuteResponse, record api.ValidationRecord) {
+	log := params.Log
+	startResp, err := params.Client.StartExecution(api.ExecutionStartRequest{
+		EntityID:      params.EntityID,
+		ExecutionMode: entityResp.Entity.ExecutionMode,
+		AgentID:       params.AgentID,
+	}, params.ServiceKey)
+	if err != nil {
+		log.Warn("Failed to report input failure", map[string]interface{}{
+			"error": err.Error(),
+		})
+		return
+	}
+	if _, err := failBeforeExecution(params, &entityResp.Entity, startResp.ExecutionID, *record.FailureReason, []api.ValidationRecord{record}); err != nil {
+		log.Warn("Failed to report input failure", map[string]interface{}{
+			"execution_id": startResp.ExecutionID,
+			"error":        err.Error(),
+		})
+		return
+	}
+	log.Info("Reported input failure as a failed attempt", map[string]interface{}{
+		"execution_id": startResp.ExecutionID,
+	})
+}
+
+// reportUnmetDependencies reports dependencies that are not met as a
+// pre-flight failure. Unlike input failures they are not recorded as a
+// failed attempt: the entity can still run once its dependencies complete.
+func reportUnmetDependencies(params EntityExecutionParams, pending []api.PendingDependency) {
+	log := params.Log
+	if !serverCapabilities(params.Client, params.ServiceKey, log).Supports(api.FeaturePreflight) {
+		log.Info("Server does not support pre-flight reports; unmet dependencies not reported")
+		return
+	}
+	failureMsg := fmt.Sprintf("%d dependencies not met", len(pending))
+	_, err := params.Client.ReportPreflight(params.EntityID, api.PreflightReport{
+		AgentID:       params.AgentID,
+		FailureReason: failureMsg,
+		ValidationRecords: []api.ValidationRecord{{
+			ValidationType: "DEPENDENCIES",
+			Outcome:        api.ValidationOutcomeFail,
+			Severity:       api.ValidationSeverityCritical,
+			Target:         "dependencies",
+			Actual:         map[string]interface{}{"pending": pending},
+			FailureReason:  &failureMsg,
+		}},
+	}, params.ServiceKey)
+	if err != nil {
+		log.Warn("Failed to report unmet dependencies", map[string]interface{}{
+			"error": err.Error(),
+		})
+	}
+}
@@ -0,0 +1,232 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/kindship-ai/kindship-cli/internal/api"
+	"github.com/kindship-ai/kindship-cli/internal/auth"
+	"github.com/kindship-ai/kindship-cli/internal/output"
+
+	"github.com/spf13/cobra"
+)
+
+var queueCmd = &cobra.Command{
+	Use:   "queue",
+	Short: "Show runnable and blocked tasks for the bound agent",
+	Long: `Shows the execution queue of the agent bound to this repository: the
+tasks that can run now, in the order they will be claimed, and the tasks
+that are blocked together with what each one is waiting on.
+
+Use it to answer "why isn't my task running?". A blocked task lists its
+unmet dependencies with their current status, or another reason such as
+waiting for user input.
+
+Examples:
+  kindship queue
+  kindship queue --format json`,
+	Args:         usageArgs(cobra.NoArgs),
+	SilenceUsage: true,
+	RunE:         runQueue,
+}
+
+var queueFormat string
+
+func init() {
+	queueCmd.Flags().StringVar(&queueFormat, "format", "table", output.FormatUsage)
+
+	rootCmd.AddCommand(queueCmd)
+}
+
+// QueueDependency is an unmet dependency of a blocked task
+type QueueDependency struct {
+	Label    string `json:"label,omitempty"`
+	EntityID string `json:"entity_id"`
+	Title    string `json:"title,omitempty"`
+	Status   string `json:"status,omitempty"`
+}
+
+// QueueTask is a task in an agent's execution queue
+type QueueTask struct {
+	ID            string            `json:"id"`
+	Title         string            `json:"title"`
+	ExecutionMode string            `json:"execution_mode"`
+	Status        string            `json:"status,omitempty"`
+	ParentTitle   string            `json:"parent_title,omitempty"`
+	WaitingOn     []QueueDependency `json:"waiting_on,omitempty"`
+	BlockedReason string            `json:"blocked_reason,omitempty"`
+}
+
+// QueueResponse is the response from the agent queue endpoint
+type QueueResponse struct {
+	AgentID  string      `json:"agent_id"`
+	Runnable []QueueTask `json:"runnable"`
+	Blocked  []QueueTask `json:"blocked"`
+	Error    string      `json:"error,omitempty"`
+}
+
+// QueueOutput is printed by 'kindship queue'
+type QueueOutput struct {
+	AgentID      string      `json:"agent_id"`
+	NextTaskID   string      `json:"next_task_id,omitempty"`
+	PendingCount int         `json:"pending_count"`
+	Runnable     []QueueTask `json:"runnable"`
+	Blocked      []QueueTask `json:"blocked"`
+}
+
+func runQueue(cmd *cobra.Command, args []string) error {
+	format, err := output.ParseFormat(queueFormat)
+	if err != nil {
+		return withExitCode(ExitUsage, err)
+	}
+
+	ctx, err := auth.GetAuthContext()
+	if err != nil {
+		return withExitCode(ExitAuth, err)
+	}
+	agentID, err := ctx.RequireAgentID()
+	if err != nil {
+		return withExitCode(ExitUsage, err)
+	}
+
+	queue, err := fetchQueue(ctx, agentID)
+	if err != nil {
+		return withExitCode(ExitAPI, err)
+	}
+	// plan/next is what the agent actually claims, so report its view too
+	nextResp, err := fetchPlanNext(ctx, agentID)
+	if err != nil {
+		return withExitCode(ExitAPI, err)
+	}
+
+	result := QueueOutput{
+		AgentID:      agentID,
+		PendingCount: nextResp.PendingCount,
+		Runnable:     queue.Runnable,
+		Blocked:      queue.Blocked,
+	}
+	if nextResp.Task != nil {
+		result.NextTaskID = nextResp.Task.ID
+	}
+	if result.Runnable == nil {
+		result.Runnable = []QueueTask{}
+	}
+	if result.Blocked == nil {
+		result.Blocked = []QueueTask{}
+	}
+
+	return output.Render(os.Stdout, format, result, func() error {
+		return renderQueueTable(result)
+	})
+}
+
+// renderQueueTable prints the human-readable queue
+func renderQueueTable(q QueueOutput) error {
+	fmt.Printf("%s %s\n\n", output.Bold("Queue for agent"), q.AgentID)
+
+	fmt.Printf("Runnable (%d):\n", len(q.Runnable))
+	if len(q.Runnable) == 0 {
+		fmt.Println(output.Dim("  No tasks can run right now"))
+	} else {
+		table := output.NewTable("#", "Title", "Mode", "ID", "")
+		table.Indent = "  "
+		for i, t := range q.Runnable {
+			next := ""
+			if t.ID == q.NextTaskID {
+				next = output.Green("next")
+			}
+			table.Row(fmt.Sprintf("%d", i+1), queueTitle(t), t.ExecutionMode, t.ID, next)
+		}
+		if err := table.Render(os.Stdout); err != nil {
+			return err
+		}
+	}
+	fmt.Println()
+
+	fmt.Printf("Blocked (%d):\n", len(q.Blocked))
+	if len(q.Blocked) == 0 {
+		fmt.Println(output.Dim("  No blocked tasks"))
+	}
+	for _, t := range q.Blocked {
+		fmt.Printf("  %s %s\n", output.Yellow("•"), queueTitle(t))
+		fmt.Printf("    %s\n", output.Dim(t.ID))
+		if t.BlockedReason != "" {
+			fmt.Printf("    %s\n", t.BlockedReason)
+		}
+		for _, dep := range t.WaitingOn {
+			name := dep.Title
+			if name == "" {
+				name = dep.EntityID
+			}
+			if dep.Label != "" {
+				name = fmt.Sprintf("%s (as %s)", name, dep.Label)
+			}
+			status := dep.Status
+			if status == "" {
+				status = "unknown"
+			}
+			fmt.Printf("    waiting on %s: %s\n", name, strings.ToLower(status))
+		}
+	}
+
+	// The queue and plan/next are separate reads; say so if they disagree
+	if q.NextTaskID == "" && len(q.Runnable) > 0 {
+		fmt.Printf("\n%s\n", output.Dim("plan/next returned no task; runnable tasks may have been claimed since"))
+	}
+	fmt.Printf("\nplan/next reports %d more task(s) pending for this agent\n", q.PendingCount)
+	return nil
+}
+
+// queueTitle returns a task title qualified with its parent, if any
+func queueTitle(t QueueTask) string {
+	if t.ParentTitle != "" {
+		return t.ParentTitle + " › " + t.Title
+	}
+	return t.Title
+}
+
+// fetchQueue fetches the runnable and blocked tasks of an agent
+func fetchQueue(ctx *auth.Context, agentID string) (*QueueResponse, error) {
+	endpoint := fmt.Sprintf("%s/api/cli/agent/queue?agent_id=%s", ctx.APIBaseURL, url.QueryEscape(agentID))
+
+	req, err := http.NewRequest(http.MethodGet, endpoint, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+
+	ctx.SetAuthHeaders(req)
+	req.Header.Set("X-Kindship-CLI-Version", Version)
+
+	client := api.NewHTTPClient(30 * time.Second)
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch queue: %w", err)
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read response: %w", err)
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		var errResp QueueResponse
+		if json.Unmarshal(body, &errResp) == nil && errResp.Error != "" {
+			return nil, fmt.Errorf("failed: %s", errResp.Error)
+		}
+		return nil, fmt.Errorf("failed (%d): %s", resp.StatusCode, string(body))
+	}
+
+	var queueResp QueueResponse
+	if err := json.Unmarshal(body, &queueResp); err != nil {
+		return nil, fmt.Errorf("failed to parse response: %w", err)
+	}
+
+	return &queueResp, nil
+}
@@ -1,6 +1,11 @@
 package cmd
 
 import (
+	"os"
+	"runtime/debug"
+	"time"
+
+	"github.com/kindship-ai/kindship-cli/internal/logging"
 	"github.com/spf13/cobra"
 )
 
@@ -20,11 +25,75 @@ For local development:
 For agent containers:
   kindship auth        Inject secrets into subprocess environment
   kindship run <id>    Execute a planning entity (auto-detects type)
-  kindship agent loop  Run autonomous execution loop`,
+  kindship agent loop  Run autonomous execution loop
+  kindship env check   Verify the container has everything it needs
+
+Configuration directory:
+  Credentials live in ~/.kindship and state in ~/.local/state/kindship.
+  Set KINDSHIP_CONFIG_DIR to relocate both; otherwise XDG_CONFIG_HOME and
+  XDG_STATE_HOME are honored (as <dir>/kindship). Installs with an existing
+  ~/.kindship/config.json keep everything in ~/.kindship.
+
+Logging:
+  --log-level debug|info|warn|error (or KINDSHIP_LOG_LEVEL) filters what is
+  printed to stderr and sent to Axiom. Without it, -v prints debug output.
+
+Version check:
+  Once a day the CLI asks the API for the oldest version it supports and
+  warns when this build is older. Set KINDSHIP_NO_VERSION_CHECK=1 to skip it.
+
+Telemetry:
+  Anonymous usage telemetry is off by default. See 'kindship telemetry'.`,
+	PersistentPreRunE: applyGlobalSettings,
+}
+
+// logLevel is the --log-level value
+var logLevel string
+
+// applyGlobalSettings runs before every command: it installs the current
+// repository's settings as defaults, configures logging, then warns if the
+// API no longer supports this version
+func applyGlobalSettings(cmd *cobra.Command, args []string) error {
+	applyRepoSettings(cmd)
+	if err := applyLogLevel(); err != nil {
+		return err
+	}
+	checkMinimumVersion(cmd)
+	return nil
+}
+
+// applyLogLevel configures the logger from --log-level or KINDSHIP_LOG_LEVEL
+func applyLogLevel() error {
+	value := logLevel
+	if value == "" {
+		value = os.Getenv("KINDSHIP_LOG_LEVEL")
+	}
+	if value == "" {
+		return nil
+	}
+	level, err := logging.ParseLevel(value)
+	if err != nil {
+		return withExitCode(ExitUsage, err)
+	}
+	logging.SetLevel(level)
+	return nil
 }
 
-func Execute() error {
-	return rootCmd.Execute()
+// Execute runs the root command. A panic anywhere on the command's
+// goroutine is reported and exits with ExitPanic instead of leaving
+// executions stuck in RUNNING. With telemetry on, the command's outcome is
+// reported once it ends.
+func Execute() (err error) {
+	start := time.Now()
+	var executed *cobra.Command
+	defer func() { reportTelemetry(executed, start, err) }()
+	defer func() {
+		if r := recover(); r != nil {
+			err = handlePanic(r, debug.Stack(), logging.Get(), "")
+		}
+	}()
+	executed, err = rootCmd.ExecuteC()
+	return err
 }
 
 func init() {
@@ -33,4 +102,11 @@ func init() {
 	rootCmd.AddCommand(runCmd)
 
 	// Note: login, logout, whoami, version commands are registered in their respective files
+
+	rootCmd.PersistentFlags().StringVar(&logLevel, "log-level", "", logging.LevelUsage)
+
+	// Flag parsing errors are usage errors
+	rootCmd.SetFlagErrorFunc(func(cmd *cobra.Command, err error) error {
+		return withExitCode(ExitUsage, err)
+	})
 }
@@ -2,24 +2,32 @@ package cmd
 
 import (
 	"context"
+	"encoding/json"
 	"errors"
 	"fmt"
 	"os"
 	"os/signal"
+	"strings"
+	"sync"
+	"sync/atomic"
 	"syscall"
 	"time"
 
 	"github.com/kindship-ai/kindship-cli/internal/api"
+	"github.com/kindship-ai/kindship-cli/internal/audit"
+	"github.com/kindship-ai/kindship-cli/internal/boundaries"
 	"github.com/kindship-ai/kindship-cli/internal/executor"
 	"github.com/kindship-ai/kindship-cli/internal/logging"
 	"github.com/kindship-ai/kindship-cli/internal/validator"
+	"github.com/kindship-ai/kindship-cli/internal/workspace"
 	"github.com/spf13/cobra"
 )
 
 var (
-	agentID    string
-	serviceKey string
-	apiURL     string
+	budgetMinutes float64
+	budgetUSD     float64
+	runLocal      bool
+	runFollow     bool
 )
 
 // ErrAskUserSkipped is returned when an ASK_USER task is started but not
@@ -27,7 +35,7 @@ var (
 var ErrAskUserSkipped = errors.New("ASK_USER task started, awaiting user response")
 
 var runCmd = &cobra.Command{
-	Use:   "run <entity-id>",
+	Use:   "run <entity-id> | --local <plan.json>",
 	Short: "Execute a planning entity",
 	Long: `Execute a planning entity by UUID.
 
@@ -36,64 +44,202 @@ type. For PROCESS entities, it executes all child tasks in sequence. For all
 other entity types, it executes the single entity based on its execution_mode
 (LLM_REASONING, BASH, PYTHON, etc.) and reports the results back to the API.
 
+BASH and PYTHON scripts receive each input as an INPUT_<LABEL> environment
+variable holding its JSON, and INPUT_<LABEL>_FILE naming a file with the
+same JSON. With boundaries.inputs.stdin set to true the whole inputs map is
+also written to the script's stdin as one JSON object. Input files and the
+OUTPUT_FILE live in a directory private to the run, readable only by the
+CLI's user, and are overwritten and removed once the execution finishes.
+
+boundaries.env declares static, non-secret environment variables for BASH,
+TEST, PYTHON and plugin executions, e.g. {"env": {"TARGET": "staging"}}, so
+tasks sharing the same code can be parameterized without templating it.
+
+boundaries.workdir runs the task in a subdirectory of the workspace, e.g.
+{"workdir": "packages/api"} in a monorepo. It must exist and stay inside
+the workspace, symlinks included.
+
+boundaries.artifacts attaches workspace files to the run once it has
+executed, e.g. {"artifacts": {"paths": ["dist/*.whl", "reports"]}}. Paths
+are globs relative to the workspace; matched directories are uploaded
+file by file. Each artifact's content type, size and SHA-256 are recorded
+in the outputs, and large files are uploaded in chunks when the server
+supports it. All artifacts of a run, LLM transcript included, are capped
+at max_total_mb (default 1024); exceeding the cap or failing an upload
+fails the attempt.
+
+boundaries.workspace_diff records the workspace's files (paths, sizes and
+SHA-256) before and after the execution and reports what was added,
+modified and deleted in the outputs' workspace_diff, e.g.
+{"workspace_diff": {"exclude": ["node_modules"]}}. .git is always excluded.
+
+BASH and PYTHON scripts receive an OUTPUT_FILE environment variable. JSON
+written to that file is used as the structured output, taking precedence
+over JSON extracted from stdout. When stdout holds several JSON values,
+boundaries.output_extraction picks one: first (default), last, largest or
+fenced-only (only a markdown code fence is accepted).
+
+The structured output is validated against the entity's output_schema.
+boundaries.validation_policy sets what a mismatch does: "warn" (default)
+records a warning, "strict" fails the attempt and "off" skips validation.
+
+TEST entities run their code as a shell command and parse the JUnit XML or
+TAP reports it writes (boundaries.test_reports globs, or TAP on stdout).
+Each failed test is reported as a validation record.
+
+Execution modes the CLI does not implement are dispatched to an executor
+plugin named kindship-executor-<mode> on PATH (e.g. JULIA -> kindship-executor-julia).
+
+Entity code of the form file://<path>@<ref> is read from the git repository
+in the workspace at the given ref instead of being executed inline.
+
+Subcommands for driving executions from outside the CLI:
+  run next                  Print the next claimable task
+  run start <entity-id>     Create an execution attempt without executing
+  run complete <entity-id>  Complete a task with caller-supplied outputs
+  run fail <execution-id>   Mark an execution attempt as failed
+
 Configuration (flags take precedence over environment variables):
   --agent-id / AGENT_ID - The agent container ID
   --service-key / KINDSHIP_SERVICE_KEY - Service key for authentication
   --api-url / KINDSHIP_API_URL - API base URL (defaults to https://kindship.ai)
 
+Budgets (ORCHESTRATE only):
+  --budget-minutes - Stop claiming new child tasks once their cumulative
+                     duration exceeds this many minutes
+  --budget-usd     - Stop claiming new child tasks once reported LLM cost
+                     exceeds this amount
+When a budget is exhausted the run is completed with a PARTIAL outcome.
+
+Run summaries (ORCHESTRATE only):
+  --summary-url  - POST a JSON digest (status, duration, tasks executed and
+                   failed tasks with reasons) to this URL when the run ends
+  --summary-file - Write the same digest to this file
+Delivery failures are logged and do not change the exit status.
+
+` + llmFlagsHelp + `
+
+` + secretsFlagHelp + `
+
+` + eventsFlagHelp + `
+
+Live output:
+  --follow - Stream the task's stdout and stderr to the terminal as it runs
+             (still captured and reported to the API). LLM tasks show the
+             assistant's messages and the tools it calls.
+
+Dry run:
+  --dry-run - Prepare a BASH, TEST or PYTHON task as for a real execution
+              and print the resolved command, the environment variables it
+              would add (names only), the working directory, the timeout
+              and the code, then check that the code parses. No execution
+              is started and no secrets are fetched.
+
+Input provenance:
+  --explain-inputs - Before executing, print each input label with the
+                     dependency entity and attempt that produced it, its
+                     size and a truncated preview, to debug wrong or stale
+                     inputs. A dependency whose newer attempt did not
+                     succeed is flagged. Combine with --dry-run to only
+                     inspect.
+
+Timings:
+  --timings - Print how long each phase took (entity fetch, start, execute,
+              validate, complete) when the command ends, to tell API
+              latency from slow task code
+
+Local mode:
+  --local - Treat the argument as a plan file or directory (as accepted by
+            'kindship plan submit') and execute it entirely locally without
+            the API. Tasks
+            run in dependency order and outputs are passed to dependents
+            under their dependency labels. Execution stops at the first
+            failed task. Tasks run in the current directory, or the
+            repository's configured workspace.
+
 Examples:
   # Execute a single task
   kindship run 550e8400-e29b-41d4-a716-446655440000
 
   # Execute all tasks in a Process
-  kindship run 660e8400-e29b-41d4-a716-446655440000`,
-	Args: cobra.ExactArgs(1),
-	RunE: runExecute,
+  kindship run 660e8400-e29b-41d4-a716-446655440000
+
+  # Execute a Process with at most 30 minutes of task time
+  kindship run 660e8400-e29b-41d4-a716-446655440000 --budget-minutes 30
+
+  # Show what a task would run without running it
+  kindship run 550e8400-e29b-41d4-a716-446655440000 --dry-run
+
+  # Try out a plan on your machine before submitting it
+  kindship run --local plan.json
+
+` + exitCodeHelp,
+	Args:         usageArgs(cobra.ExactArgs(1)),
+	SilenceUsage: true,
+	RunE:         runExecute,
 }
 
 func runExecute(cmd *cobra.Command, args []string) error {
 	entityID := args[0]
 
-	// Read from flags first, fall back to environment variables
-	if agentID == "" {
-		agentID = os.Getenv("AGENT_ID")
+	opts := runOpts.resolved()
+	settings, err := runSettings()
+	if err != nil {
+		return err
 	}
-	if serviceKey == "" {
-		serviceKey = os.Getenv("KINDSHIP_SERVICE_KEY")
+
+	// Initialize logging
+	log := logging.Init(opts.AgentID, "run", opts.Verbose)
+	defer log.FlushSync()
+	if showTimings {
+		log.EnableTimings()
+		defer printTimings(log, time.Now())
 	}
-	if apiURL == "" {
-		apiURL = os.Getenv("KINDSHIP_API_URL")
+
+	if runDryRun && runLocal {
+		return withExitCode(ExitUsage, fmt.Errorf("--dry-run cannot be combined with --local"))
 	}
-	if apiURL == "" {
-		apiURL = "https://kindship.ai"
+	if runExplainInputs && runLocal {
+		return withExitCode(ExitUsage, fmt.Errorf("--explain-inputs cannot be combined with --local"))
 	}
 
-	// Initialize logging
-	log := logging.Init(agentID, "run", verbose)
-	defer log.FlushSync()
+	// Local mode needs no API credentials
+	if runLocal {
+		return runLocalPlan(entityID, settings, log)
+	}
 
 	// Validate required parameters
-	if agentID == "" {
-		log.Error("AGENT_ID not provided", nil)
-		return fmt.Errorf("AGENT_ID is required (use --agent-id flag or AGENT_ID environment variable)")
-	}
-	if serviceKey == "" {
-		log.Error("KINDSHIP_SERVICE_KEY not provided", nil)
-		return fmt.Errorf("KINDSHIP_SERVICE_KEY is required (use --service-key flag or KINDSHIP_SERVICE_KEY environment variable)")
+	if err := opts.requireCredentials(); err != nil {
+		log.Error("Missing credentials", err)
+		return err
 	}
 
 	// Create API client
-	client := api.NewClient(apiURL, verbose)
+	client := opts.client()
 
 	// Fetch entity to detect type before execution
 	log.Info("Fetching entity to detect type", map[string]interface{}{
 		"entity_id": entityID,
 	})
-	entityResp, err := client.FetchEntityForExecution(entityID, serviceKey)
+	settings.Events.emit(LifecycleEvent{Event: eventFetching, AgentID: opts.AgentID, EntityID: entityID})
+	fetchStart := time.Now()
+	entityResp, err := client.FetchEntityForExecution(entityID, opts.ServiceKey)
 	if err != nil {
 		log.Error("Failed to fetch entity", err)
 		return fmt.Errorf("failed to fetch entity: %w", err)
 	}
+	fetchedAt := time.Now()
+	log.WithDuration("Fetched entity", fetchedAt.Sub(fetchStart))
+
+	if runExplainInputs {
+		if err := explainInputs(entityResp, client, opts.ServiceKey, log); err != nil {
+			return err
+		}
+	}
+
+	if runDryRun {
+		return dryRunEntity(entityResp, settings, log)
+	}
 
 	// If this entity uses ORCHESTRATE mode, run the orchestration loop
 	if entityResp.Entity.ExecutionMode == api.ExecutionModeOrchestrate {
@@ -102,16 +248,28 @@ func runExecute(cmd *cobra.Command, args []string) error {
 			"entity_title": entityResp.Entity.Title,
 			"entity_type":  entityResp.Entity.Type,
 		})
-		return runOrchestration(entityID, client, log)
+		return runOrchestration(EntityExecutionParams{
+			EntityID:   entityID,
+			AgentID:    opts.AgentID,
+			ServiceKey: opts.ServiceKey,
+			Client:     client,
+			Log:        log,
+			Budget:     newBudgetTracker(budgetMinutes, budgetUSD),
+			Settings:   settings,
+		})
 	}
 
 	// Otherwise, execute a single entity
 	success, err := executeEntity(EntityExecutionParams{
-		EntityID:   entityID,
-		AgentID:    agentID,
-		ServiceKey: serviceKey,
-		Client:     client,
-		Log:        log,
+		EntityID:     entityID,
+		AgentID:      opts.AgentID,
+		ServiceKey:   opts.ServiceKey,
+		Client:       client,
+		Log:          log,
+		TriggeredBy:  "cli:run",
+		Prefetched:   entityResp,
+		PrefetchedAt: fetchedAt,
+		Settings:     settings,
 	})
 
 	if err != nil {
@@ -123,12 +281,31 @@ func runExecute(cmd *cobra.Command, args []string) error {
 	}
 
 	if !success {
-		os.Exit(1)
+		return withExitCode(ExitExecutionFailed, fmt.Errorf("execution of %s failed", entityID))
 	}
 
 	return nil
 }
 
+// runSettings validates the flags of 'kindship run' and returns the
+// settings its executions run under
+func runSettings() (executionSettings, error) {
+	execOpts, err := executionOptions()
+	if err != nil {
+		return executionSettings{}, err
+	}
+	execOpts.Follow = runFollow
+	secrets, err := secretsFlag()
+	if err != nil {
+		return executionSettings{}, err
+	}
+	events, err := eventsFlag()
+	if err != nil {
+		return executionSettings{}, err
+	}
+	return executionSettings{Exec: execOpts, Secrets: secrets, Events: events}, nil
+}
+
 // EntityExecutionParams holds parameters for executing an entity.
 // Used by both `kindship run <id>` and the agent loop.
 type EntityExecutionParams struct {
@@ -137,6 +314,115 @@ type EntityExecutionParams struct {
 	ServiceKey string
 	Client     *api.Client
 	Log        *logging.Logger
+
+	// TriggeredBy records what started the execution in the audit log
+	// (e.g. "cli:run", "agent-loop", "orchestrate:<run-id>").
+	TriggeredBy string
+
+	// Budget, when set, accumulates LLM cost reported by executors for
+	// the enclosing ORCHESTRATE run.
+	Budget *budgetTracker
+
+	// Prefetched, when set, is the entity as already fetched by the caller
+	// at PrefetchedAt; executeEntity reuses it instead of fetching again
+	// unless it is stale.
+	Prefetched   *api.EntityExecuteResponse
+	PrefetchedAt time.Time
+
+	// Settings are the invocation's execution settings, passed on to
+	// orchestrated children
+	Settings executionSettings
+}
+
+// maxPrefetchAge is how long a prefetched entity may be reused
+const maxPrefetchAge = 30 * time.Second
+
+// usablePrefetch reports whether the caller's prefetched entity can be used
+// instead of fetching it again. A response with unmet dependencies is
+// always refetched, since a dependency may have completed in the meantime.
+func (p EntityExecutionParams) usablePrefetch() bool {
+	return p.Prefetched != nil &&
+		p.Prefetched.Entity.ID == p.EntityID &&
+		time.Since(p.PrefetchedAt) < maxPrefetchAge &&
+		p.Prefetched.DependenciesStatus.AllMet
+}
+
+// budgetTracker accumulates task time and LLM cost for an ORCHESTRATE run
+// and reports when either configured limit has been reached. A nil tracker
+// never runs out. Safe for use from resumed orchestrations in goroutines.
+type budgetTracker struct {
+	mu            sync.Mutex
+	limitDuration time.Duration
+	limitUSD      float64
+	spentDuration time.Duration
+	spentUSD      float64
+}
+
+// newBudgetTracker returns a tracker for the given limits, or nil if
+// neither limit is set
+func newBudgetTracker(minutes, usd float64) *budgetTracker {
+	if minutes <= 0 && usd <= 0 {
+		return nil
+	}
+	return &budgetTracker{
+		limitDuration: time.Duration(minutes * float64(time.Minute)),
+		limitUSD:      usd,
+	}
+}
+
+func (b *budgetTracker) addDuration(d time.Duration) {
+	if b == nil {
+		return
+	}
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.spentDuration += d
+}
+
+func (b *budgetTracker) addCost(usd float64) {
+	if b == nil {
+		return
+	}
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.spentUSD += usd
+}
+
+// exceeded returns a description of the exhausted budget, or "" if there
+// is budget left
+func (b *budgetTracker) exceeded() string {
+	if b == nil {
+		return ""
+	}
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if b.limitDuration > 0 && b.spentDuration >= b.limitDuration {
+		return fmt.Sprintf("time budget exceeded (%.1f of %.1f minutes)", b.spentDuration.Minutes(), b.limitDuration.Minutes())
+	}
+	if b.limitUSD > 0 && b.spentUSD >= b.limitUSD {
+		return fmt.Sprintf("cost budget exceeded ($%.2f of $%.2f)", b.spentUSD, b.limitUSD)
+	}
+	return ""
+}
+
+// metrics returns budget usage for inclusion in run outputs
+func (b *budgetTracker) metrics() map[string]interface{} {
+	if b == nil {
+		return nil
+	}
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	m := map[string]interface{}{
+		"budget_spent_ms":  b.spentDuration.Milliseconds(),
+		"budget_spent_usd": b.spentUSD,
+	}
+	if b.limitDuration > 0 {
+		m["budget_limit_ms"] = b.limitDuration.Milliseconds()
+	}
+	if b.limitUSD > 0 {
+		m["budget_limit_usd"] = b.limitUSD
+	}
+	return m
 }
 
 // executeEntity runs the full execution lifecycle for a single entity.
@@ -145,27 +431,41 @@ type EntityExecutionParams struct {
 // Returns (false, ErrAskUserSkipped) for ASK_USER mode tasks.
 func executeEntity(params EntityExecutionParams) (bool, error) {
 	startTime := time.Now()
+	params.Log = params.Log.WithFields(map[string]interface{}{"entity_id": params.EntityID})
 	log := params.Log
 
 	log.Info("Starting entity execution", map[string]interface{}{
 		"entity_id": params.EntityID,
 	})
 
-	// Step 1: Fetch entity details
-	log.Info("Fetching entity details")
-	fetchStart := time.Now()
-	entityResp, err := params.Client.FetchEntityForExecution(params.EntityID, params.ServiceKey)
-	if err != nil {
-		log.Error("Failed to fetch entity", err, map[string]interface{}{
-			"duration_ms": time.Since(fetchStart).Milliseconds(),
+	// Step 1: Fetch entity details, unless the caller just did
+	var entityResp *api.EntityExecuteResponse
+	if params.usablePrefetch() {
+		entityResp = params.Prefetched
+		log.Info("Using prefetched entity details", map[string]interface{}{
+			"title":          entityResp.Entity.Title,
+			"execution_mode": entityResp.Entity.ExecutionMode,
+			"status":         entityResp.Entity.Status,
+			"age_ms":         time.Since(params.PrefetchedAt).Milliseconds(),
+		})
+	} else {
+		log.Info("Fetching entity details")
+		params.Settings.Events.emit(LifecycleEvent{Event: eventFetching, AgentID: params.AgentID, EntityID: params.EntityID})
+		fetchStart := time.Now()
+		var err error
+		entityResp, err = params.Client.FetchEntityForExecution(params.EntityID, params.ServiceKey)
+		if err != nil {
+			log.Error("Failed to fetch entity", err, map[string]interface{}{
+				"duration_ms": time.Since(fetchStart).Milliseconds(),
+			})
+			return false, fmt.Errorf("failed to fetch entity: %w", err)
+		}
+		log.WithDuration("Fetched entity", time.Since(fetchStart), map[string]interface{}{
+			"title":          entityResp.Entity.Title,
+			"execution_mode": entityResp.Entity.ExecutionMode,
+			"status":         entityResp.Entity.Status,
 		})
-		return false, fmt.Errorf("failed to fetch entity: %w", err)
 	}
-	log.WithDuration("Fetched entity", time.Since(fetchStart), map[string]interface{}{
-		"title":          entityResp.Entity.Title,
-		"execution_mode": entityResp.Entity.ExecutionMode,
-		"status":         entityResp.Entity.Status,
-	})
 
 	// Log inputs information
 	inputLabels := validator.GetInputLabels(entityResp.Inputs)
@@ -179,15 +479,45 @@ func executeEntity(params EntityExecutionParams) (bool, error) {
 		log.Error("Dependencies not met", nil, map[string]interface{}{
 			"pending": entityResp.DependenciesStatus.Pending,
 		})
-		return false, fmt.Errorf("dependencies not met: %v", entityResp.DependenciesStatus.Pending)
+		reportUnmetDependencies(params, entityResp.DependenciesStatus.Pending)
+		return false, withExitCode(ExitDependency, fmt.Errorf("dependencies not met: %v", entityResp.DependenciesStatus.Pending))
 	}
 
-	// Step 2b: Validate inputs against input_schema if provided
+	// Step 2b: Validate inputs against input_schema if provided. With an
+	// inputs map in boundaries the schema describes the mapped inputs.
+	// Failures are reported as a failed attempt before returning.
 	if len(entityResp.Entity.InputSchema) > 0 {
 		log.Info("Validating inputs against input_schema")
-		if err := validator.ValidateInputs(entityResp.Inputs, entityResp.Entity.InputSchema); err != nil {
+		inputs := entityResp.Inputs
+		if policy, policyErr := boundaries.Parse(entityResp.Entity.Boundaries); policyErr == nil {
+			mapped, mapErr := policy.Inputs.MapInputs(inputs)
+			if mapErr != nil {
+				log.Error("Input mapping failed", mapErr)
+				failureMsg := mapErr.Error()
+				reportInputFailure(params, entityResp, api.ValidationRecord{
+					ValidationType: "INPUT_MAPPING",
+					Outcome:        api.ValidationOutcomeFail,
+					Severity:       api.ValidationSeverityCritical,
+					Target:         "inputs",
+					Actual:         map[string]interface{}{"labels": validator.GetInputLabels(inputs)},
+					FailureReason:  &failureMsg,
+				})
+				return false, withExitCode(ExitValidation, fmt.Errorf("input mapping failed: %w", mapErr))
+			}
+			inputs = mapped
+		}
+		if err := validator.ValidateInputs(inputs, entityResp.Entity.InputSchema); err != nil {
 			log.Error("Input validation failed", err)
-			return false, fmt.Errorf("input validation failed: %w", err)
+			failureMsg := err.Error()
+			reportInputFailure(params, entityResp, api.ValidationRecord{
+				ValidationType: "INPUT_SCHEMA",
+				Outcome:        api.ValidationOutcomeFail,
+				Severity:       api.ValidationSeverityCritical,
+				Target:         "input_schema",
+				Actual:         map[string]interface{}{"labels": validator.GetInputLabels(inputs)},
+				FailureReason:  &failureMsg,
+			})
+			return false, withExitCode(ExitValidation, fmt.Errorf("input validation failed: %w", err))
 		}
 		log.Info("Input validation passed")
 	}
@@ -208,13 +538,61 @@ func executeEntity(params EntityExecutionParams) (bool, error) {
 			"run_id":    orchStartResp.ExecutionID,
 			"entity_id": params.EntityID,
 		})
-		orchLoopErr := orchestrateChildren(params.EntityID, orchStartResp.ExecutionID, params.Client, params.Log)
+		orchLoopErr := orchestrateChildren(params, orchStartResp.ExecutionID)
 		if orchLoopErr != nil {
 			return false, orchLoopErr
 		}
 		return true, nil
 	}
 
+	// Retry policy comes from boundaries; invalid boundaries are reported
+	// as a failed attempt by executeAttempt itself.
+	var retry *boundaries.RetryPolicy
+	if policy, policyErr := boundaries.Parse(entityResp.Entity.Boundaries); policyErr == nil {
+		retry = policy.Retry
+	}
+	maxAttempts := retry.Attempts()
+
+	for attempt := 1; ; attempt++ {
+		outcome, err := executeAttempt(params, entityResp, startTime)
+		if err != nil {
+			return false, err
+		}
+		if outcome.Success || !outcome.Executed || outcome.Cancelled || attempt >= maxAttempts || !retry.ShouldRetry(outcome.TimedOut) {
+			return outcome.Success, nil
+		}
+
+		delay := retry.Backoff(attempt)
+		log.Warn("Attempt failed, retrying per boundaries.retry", map[string]interface{}{
+			"attempt":      attempt,
+			"max_attempts": maxAttempts,
+			"timed_out":    outcome.TimedOut,
+			"backoff_ms":   delay.Milliseconds(),
+		})
+		if sleepWithContext(interruptCtx, delay) {
+			log.Warn("Interrupted during retry backoff; not retrying")
+			return false, nil
+		}
+	}
+}
+
+// attemptResult describes how a single execution attempt ended
+type attemptResult struct {
+	Success bool
+	// Executed is false when a pre-execution check rejected the attempt;
+	// such failures are deterministic and never retried.
+	Executed bool
+	TimedOut bool
+	// Cancelled is set when the run was stopped from the UI; cancelled
+	// attempts are never retried.
+	Cancelled bool
+}
+
+// executeAttempt creates a run for the entity, executes it once and reports
+// the outcome to the API. Returns ErrAskUserSkipped for ASK_USER entities.
+func executeAttempt(params EntityExecutionParams, entityResp *api.EntityExecuteResponse, startTime time.Time) (outcome *attemptResult, err error) {
+	log := params.Log
+
 	// Step 3: Create run
 	log.Info("Creating run")
 	startExecReq := api.ExecutionStartRequest{
@@ -222,17 +600,50 @@ func executeEntity(params EntityExecutionParams) (bool, error) {
 		ExecutionMode: entityResp.Entity.ExecutionMode,
 		AgentID:       params.AgentID,
 	}
+	startExecStart := time.Now()
 	startResp, err := params.Client.StartExecution(startExecReq, params.ServiceKey)
 	if err != nil {
 		log.Error("Failed to start execution", err)
-		return false, fmt.Errorf("failed to start execution: %w", err)
+		return nil, fmt.Errorf("failed to start execution: %w", err)
 	}
-	log.Info("Run created", map[string]interface{}{
+	log.WithDuration("Run created", time.Since(startExecStart), map[string]interface{}{
 		"execution_id":   startResp.ExecutionID,
 		"attempt_number": startResp.AttemptNumber,
 	})
 
 	executionID := startResp.ExecutionID
+	event := LifecycleEvent{
+		AgentID:       params.AgentID,
+		EntityID:      params.EntityID,
+		ExecutionID:   executionID,
+		Attempt:       startResp.AttemptNumber,
+		Title:         entityResp.Entity.Title,
+		ExecutionMode: string(entityResp.Entity.ExecutionMode),
+	}
+	event.Event = eventStarted
+	params.Settings.Events.emit(event)
+	attemptStart := time.Now()
+	defer func() {
+		if err != nil {
+			return
+		}
+		event.Event = eventCompleted
+		event.DurationMS = time.Since(attemptStart).Milliseconds()
+		switch {
+		case outcome.Success:
+			event.Status = string(api.ExecutionAttemptStatusSuccess)
+		case outcome.Cancelled:
+			event.Status = string(api.ExecutionAttemptStatusAbandoned)
+		default:
+			event.Status = string(api.ExecutionAttemptStatusFailed)
+		}
+		params.Settings.Events.emit(event)
+	}()
+
+	// Tag every later entry of this attempt, including those logged by
+	// helpers that receive params
+	log = log.WithFields(map[string]interface{}{"execution_id": executionID})
+	params.Log = log
 
 	// ASK_USER: create the run (RUNNING) but don't block — user responds via UI
 	if entityResp.Entity.ExecutionMode == api.ExecutionModeAskUser {
@@ -240,35 +651,178 @@ func executeEntity(params EntityExecutionParams) (bool, error) {
 			"execution_id": executionID,
 			"entity_id":    params.EntityID,
 		})
-		return false, ErrAskUserSkipped
+		return nil, ErrAskUserSkipped
+	}
+
+	// Fail this execution if the CLI crashes before completing it
+	defer trackExecution(executionID, params)()
+
+	// Step 3b: Parse boundaries; they decide the runtimes preflight checks
+	policy, err := boundaries.Parse(entityResp.Entity.Boundaries)
+	if err != nil {
+		log.Error("Invalid boundaries", err)
+		failureMsg := err.Error()
+		return failBeforeExecution(params, &entityResp.Entity, executionID, failureMsg, []api.ValidationRecord{{
+			ValidationType: "BOUNDARY",
+			Outcome:        api.ValidationOutcomeFail,
+			Severity:       api.ValidationSeverityCritical,
+			Target:         "boundaries",
+			Actual:         entityResp.Entity.Boundaries,
+			FailureReason:  &failureMsg,
+		}})
+	}
+
+	// Step 3c: Preflight — fail fast if a runtime the entity needs is missing
+	if preflightErr := executor.Preflight(entityResp.Entity.ExecutionMode, policy, params.Settings.Exec); preflightErr != nil {
+		log.Error("Preflight check failed", preflightErr, map[string]interface{}{
+			"mode":     entityResp.Entity.ExecutionMode,
+			"runtimes": executor.RequiredRuntimes(entityResp.Entity.ExecutionMode, policy, params.Settings.Exec),
+		})
+		failureMsg := preflightErr.Error()
+		return failBeforeExecution(params, &entityResp.Entity, executionID, failureMsg, []api.ValidationRecord{{
+			ValidationType: "PREFLIGHT",
+			Outcome:        api.ValidationOutcomeFail,
+			Severity:       api.ValidationSeverityCritical,
+			Target:         "runtime_environment",
+			Actual: map[string]interface{}{
+				"execution_mode":    entityResp.Entity.ExecutionMode,
+				"required_runtimes": executor.RequiredRuntimes(entityResp.Entity.ExecutionMode, policy, params.Settings.Exec),
+			},
+			FailureReason: &failureMsg,
+		}})
+	}
+
+	// Enforce boundaries before anything runs
+	inputs, err := policy.Inputs.MapInputs(startResp.Inputs)
+	if err != nil {
+		log.Error("Input mapping failed", err)
+		failureMsg := err.Error()
+		return failBeforeExecution(params, &entityResp.Entity, executionID, failureMsg, []api.ValidationRecord{{
+			ValidationType: "INPUT_MAPPING",
+			Outcome:        api.ValidationOutcomeFail,
+			Severity:       api.ValidationSeverityCritical,
+			Target:         "inputs",
+			Actual:         map[string]interface{}{"labels": validator.GetInputLabels(startResp.Inputs)},
+			FailureReason:  &failureMsg,
+		}})
+	}
+	if entityResp.Entity.Code != nil {
+		if ref, ok := executor.ParseCodeRef(*entityResp.Entity.Code); ok {
+			log.Info("Fetching code from repository", map[string]interface{}{
+				"path": ref.Path,
+				"ref":  ref.Ref,
+			})
+			code, refErr := executor.ResolveCode(params.Settings.Exec.Dir(), *entityResp.Entity.Code)
+			if refErr != nil {
+				log.Error("Failed to fetch code reference", refErr)
+				failureMsg := refErr.Error()
+				return failBeforeExecution(params, &entityResp.Entity, executionID, failureMsg, []api.ValidationRecord{{
+					ValidationType: "CODE_REF",
+					Outcome:        api.ValidationOutcomeFail,
+					Severity:       api.ValidationSeverityCritical,
+					Target:         "code",
+					Actual: map[string]interface{}{
+						"path": ref.Path,
+						"ref":  ref.Ref,
+					},
+					FailureReason: &failureMsg,
+				}})
+			}
+			resolvedResp := *entityResp
+			resolvedResp.Entity.Code = &code
+			entityResp = &resolvedResp
+		}
+	}
+	if policy.Template && entityResp.Entity.Code != nil {
+		rendered, renderErr := executor.RenderCode(*entityResp.Entity.Code, inputs)
+		if renderErr != nil {
+			log.Error("Failed to render code template", renderErr)
+			failureMsg := renderErr.Error()
+			return failBeforeExecution(params, &entityResp.Entity, executionID, failureMsg, []api.ValidationRecord{{
+				ValidationType: "TEMPLATE",
+				Outcome:        api.ValidationOutcomeFail,
+				Severity:       api.ValidationSeverityCritical,
+				Target:         "code",
+				FailureReason:  &failureMsg,
+			}})
+		}
+		// Render into a copy so retries start from the original code
+		renderedResp := *entityResp
+		renderedResp.Entity.Code = &rendered
+		entityResp = &renderedResp
+	}
+	isShell := entityResp.Entity.ExecutionMode == api.ExecutionModeBash || entityResp.Entity.ExecutionMode == api.ExecutionModeTest
+	if isShell && entityResp.Entity.Code != nil {
+		violations := policy.Commands.CheckScript(*entityResp.Entity.Code, params.Settings.Exec.Dir())
+		if len(violations) > 0 {
+			log.Error("Boundary violations found, refusing to execute", nil, map[string]interface{}{
+				"violations": violations,
+			})
+			records := make([]api.ValidationRecord, 0, len(violations))
+			for _, v := range violations {
+				detail := v.Detail
+				records = append(records, api.ValidationRecord{
+					ValidationType: "BOUNDARY",
+					Outcome:        api.ValidationOutcomeFail,
+					Severity:       api.ValidationSeverityCritical,
+					Target:         "boundaries.commands." + v.Rule,
+					Actual: map[string]interface{}{
+						"command": v.Command,
+					},
+					FailureReason: &detail,
+				})
+			}
+			failureMsg := fmt.Sprintf("Boundary violation: %s", violations[0].Detail)
+			if len(violations) > 1 {
+				failureMsg = fmt.Sprintf("%s (and %d more)", failureMsg, len(violations)-1)
+			}
+			return failBeforeExecution(params, &entityResp.Entity, executionID, failureMsg, records)
+		}
+	}
+
+	// Step 3d: Fetch the secrets the task asked for
+	var secrets map[string]string
+	if names := requestedSecrets(params.Settings.Secrets, policy.Secrets); len(names) > 0 && injectsSecrets(entityResp.Entity.ExecutionMode) {
+		secrets, err = fetchExecutionSecrets(params, entityResp.Entity.ExecutionMode, names)
+		if err != nil {
+			log.Error("Failed to fetch secrets for execution", err, map[string]interface{}{
+				"secrets": names,
+			})
+			failureMsg := err.Error()
+			return failBeforeExecution(params, &entityResp.Entity, executionID, failureMsg, []api.ValidationRecord{{
+				ValidationType: "SECRETS",
+				Outcome:        api.ValidationOutcomeFail,
+				Severity:       api.ValidationSeverityCritical,
+				Target:         "boundaries.secrets",
+				Actual:         map[string]interface{}{"secrets": names},
+				FailureReason:  &failureMsg,
+			}})
+		}
+		log.Info("Injecting secrets", map[string]interface{}{
+			"secrets": names,
+		})
+	}
+
+	// Step 3e: Record the workspace files to report what the execution changed
+	var beforeManifest workspace.Manifest
+	if policy.WorkspaceDiff != nil {
+		beforeManifest = snapshotWorkspace(log, params.Settings.Exec.Dir(), policy.WorkspaceDiff, nil)
 	}
 
 	// Step 4: Execute based on execution mode
 	log.Info("Executing entity", map[string]interface{}{
 		"mode": entityResp.Entity.ExecutionMode,
 	})
+	event.Event = eventExecuting
+	params.Settings.Events.emit(event)
 	execStart := time.Now()
 
-	var result *executor.ExecutionResult
-	switch entityResp.Entity.ExecutionMode {
-	case api.ExecutionModeLLMReasoning:
-		result = executor.ExecuteLLM(&entityResp.Entity, startResp.Inputs)
-	case api.ExecutionModeBash:
-		result = executor.ExecuteBash(&entityResp.Entity, startResp.Inputs)
-	case api.ExecutionModePython:
-		result = executor.ExecutePython(&entityResp.Entity, startResp.Inputs)
-	case api.ExecutionModePythonSandbox:
-		// Legacy mode — treat as PYTHON
-		result = executor.ExecutePython(&entityResp.Entity, startResp.Inputs)
-	case api.ExecutionModeHybrid:
-		// HYBRID uses LLM with entity context + code as reference
-		result = executor.ExecuteLLM(&entityResp.Entity, startResp.Inputs)
-	default:
-		log.Error("Unknown execution mode", nil, map[string]interface{}{
-			"mode": entityResp.Entity.ExecutionMode,
-		})
-		return false, fmt.Errorf("unknown execution mode: %s", entityResp.Entity.ExecutionMode)
-	}
+	forwardInterrupts(log)
+	execCtx, cancelExec := context.WithCancel(executor.WithSecrets(executor.WithOptions(interruptCtx, params.Settings.Exec), secrets))
+	cancelled := watchCancellation(execCtx, params, executionID, cancelExec)
+	result, watchdogFired := dispatchWithWatchdog(execCtx, cancelExec, &entityResp.Entity, inputs, log, params.Settings.Watchdog)
+	cancelExec()
+	executor.RedactSecrets(result, secrets)
 
 	execDuration := time.Since(execStart)
 	log.WithDuration("Execution completed", execDuration, map[string]interface{}{
@@ -276,16 +830,31 @@ func executeEntity(params EntityExecutionParams) (bool, error) {
 		"exit_code": result.ExitCode,
 	})
 
-	// Step 4b: Validate outputs against output_schema if provided (only for successful executions)
+	if watchdogFired {
+		return failWatchdog(params, &entityResp.Entity, executionID, result, execDuration)
+	}
+	if cancelled.Load() {
+		return abandonCancelled(params, &entityResp.Entity, executionID, result, execDuration, "Cancelled by user")
+	}
+	if sig := interruptedBy(); sig != "" && result.Cancelled {
+		return abandonCancelled(params, &entityResp.Entity, executionID, result, execDuration, fmt.Sprintf("Interrupted by %s", sig))
+	}
+
+	// Step 4b: Validate outputs against output_schema if provided (only for
+	// successful executions), as boundaries.validation_policy asks
 	var structuredOutput map[string]interface{}
 	var outputValidationRecord *api.ValidationRecord
-	if result.Success && len(entityResp.Entity.OutputSchema) > 0 {
+	validationPolicy := policy.OutputValidation()
+	validateStart := time.Now()
+	if result.Success && len(entityResp.Entity.OutputSchema) > 0 && validationPolicy != boundaries.ValidationOff {
 		log.Info("Validating outputs against output_schema")
+		event.Event = eventValidating
+		params.Settings.Events.emit(event)
 
-		// Try to extract structured JSON from stdout
-		extracted, extractErr := validator.ExtractJSONFromOutput(result.Stdout)
+		// Prefer the OUTPUT_FILE written by the script, falling back to stdout
+		extracted, extractErr := extractStructuredOutput(result, policy.ExtractionStrategy())
 		if extractErr != nil {
-			log.Warn("Could not extract structured output from stdout", map[string]interface{}{
+			log.Warn("Could not extract structured output", map[string]interface{}{
 				"error": extractErr.Error(),
 			})
 			failReason := fmt.Sprintf("Failed to extract structured output: %v", extractErr)
@@ -327,11 +896,30 @@ func executeEntity(params EntityExecutionParams) (bool, error) {
 				}
 			}
 		}
+		// A strict policy fails the attempt on anything but a passing record
+		if validationPolicy == boundaries.ValidationStrict && outputValidationRecord.Outcome != api.ValidationOutcomePass {
+			outputValidationRecord.Outcome = api.ValidationOutcomeFail
+			outputValidationRecord.Severity = api.ValidationSeverityCritical
+		}
+	} else if result.Success && result.OutputFile != nil {
+		// No schema to validate against, but keep what the script reported
+		extracted, err := extractStructuredOutput(result, policy.ExtractionStrategy())
+		if err != nil {
+			log.Warn("Ignoring invalid OUTPUT_FILE contents", map[string]interface{}{
+				"error": err.Error(),
+			})
+		}
+		structuredOutput = extracted
 	}
+	log.WithDuration("Outputs validated", time.Since(validateStart))
 
 	// Step 5: Prepare completion request
 	var completeReq api.ExecutionCompleteRequest
-	if result.Success {
+	if len(entityResp.Entity.OutputSchema) > 0 {
+		completeReq.ValidationPolicy = validationPolicy
+	}
+	outputRejected := outputValidationRecord != nil && outputValidationRecord.Severity == api.ValidationSeverityCritical
+	if result.Success && !outputRejected {
 		completeReq.Status = api.ExecutionAttemptStatusSuccess
 		outputs := &api.ExecutionOutputs{
 			Stdout: result.Stdout,
@@ -347,7 +935,8 @@ func executeEntity(params EntityExecutionParams) (bool, error) {
 		}
 		completeReq.Outputs = outputs
 
-		// Create validation record for successful execution
+		// Create validation records for successful execution: one per
+		// measurable outcome, or a single completion record without them
 		validationRecord := api.ValidationRecord{
 			ValidationType: "OUTPUT",
 			Outcome:        api.ValidationOutcomePass,
@@ -359,6 +948,9 @@ func executeEntity(params EntityExecutionParams) (bool, error) {
 			},
 		}
 		completeReq.ValidationRecords = []api.ValidationRecord{validationRecord}
+		if len(entityResp.Entity.SuccessCriteria.MeasurableOutcomes) > 0 {
+			completeReq.ValidationRecords = outcomeRecords(entityResp.Entity.SuccessCriteria, result, structuredOutput, outputValidationRecord)
+		}
 
 		// Add output schema validation record if present
 		if outputValidationRecord != nil {
@@ -370,6 +962,9 @@ func executeEntity(params EntityExecutionParams) (bool, error) {
 		if result.Error != nil {
 			failureMsg = fmt.Sprintf("%s: %v", failureMsg, result.Error)
 		}
+		if outputRejected {
+			failureMsg = fmt.Sprintf("Output validation failed (validation_policy strict): %s", *outputValidationRecord.FailureReason)
+		}
 		completeReq.FailureReason = &failureMsg
 		outputs := &api.ExecutionOutputs{
 			Stdout: result.Stdout,
@@ -379,9 +974,13 @@ func executeEntity(params EntityExecutionParams) (bool, error) {
 				"exit_code":   result.ExitCode,
 			},
 		}
+		// Keep the rejected output so it can be inspected
+		if structuredOutput != nil {
+			outputs.Structured = structuredOutput
+		}
 		completeReq.Outputs = outputs
 
-		// Create validation record for failed execution
+		// Create validation records for failed execution
 		validationRecord := api.ValidationRecord{
 			ValidationType: "OUTPUT",
 			Outcome:        api.ValidationOutcomeFail,
@@ -394,67 +993,407 @@ func executeEntity(params EntityExecutionParams) (bool, error) {
 			FailureReason: &failureMsg,
 		}
 		completeReq.ValidationRecords = []api.ValidationRecord{validationRecord}
+		if len(entityResp.Entity.SuccessCriteria.MeasurableOutcomes) > 0 {
+			// Set only when output was rejected, so its schema criterion fails
+			completeReq.ValidationRecords = outcomeRecords(entityResp.Entity.SuccessCriteria, result, structuredOutput, outputValidationRecord)
+		}
+		if outputRejected {
+			completeReq.ValidationRecords = append(completeReq.ValidationRecords, *outputValidationRecord)
+		}
+	}
+
+	// Step 5b: Attach parsed test results for TEST executions
+	if result.Tests != nil {
+		attachTestResults(&completeReq, result.Tests)
 	}
 
+	// Step 5c: Record the process's CPU, memory and output volume
+	if result.Usage != nil {
+		for k, v := range result.Usage.Metrics() {
+			completeReq.Outputs.Metrics[k] = v
+		}
+	}
+
+	// Step 5d: Record which LLM served the task and what it reported
+	if result.LLMBackend != "" {
+		completeReq.Outputs.Metrics["llm_backend"] = result.LLMBackend
+	}
+	if result.LLM != nil {
+		completeReq.Outputs.Metrics["llm_turns"] = result.LLM.NumTurns
+		completeReq.Outputs.Metrics["llm_cost_usd"] = result.LLM.CostUSD
+		if result.LLM.SessionID != "" {
+			completeReq.Outputs.Metrics["llm_session_id"] = result.LLM.SessionID
+		}
+	}
+
+	// Step 5e: Upload the LLM transcript so the conversation can be audited,
+	// then the artifacts the task declared
+	artifacts := newArtifactUploader(params, executionID, policy.Artifacts)
+	if result.Transcript != nil {
+		artifacts.uploadTranscript(result.Transcript, completeReq.Outputs)
+	}
+	if policy.Artifacts != nil && len(policy.Artifacts.Paths) > 0 {
+		if err := artifacts.uploadFiles(policy.Artifacts.Paths, completeReq.Outputs); err != nil {
+			log.Error("Failed to upload artifacts", err)
+			failArtifacts(&completeReq, policy.Artifacts, err)
+		}
+	}
+
+	// Step 5f: Report the files the execution added, modified and deleted
+	if beforeManifest != nil {
+		if after := snapshotWorkspace(log, params.Settings.Exec.Dir(), policy.WorkspaceDiff, beforeManifest); after != nil {
+			diff := workspace.Diff(beforeManifest, after)
+			completeReq.Outputs.Workspace = diff
+			log.Info("Workspace changes", map[string]interface{}{
+				"added":    diff.Added,
+				"modified": diff.Modified,
+				"deleted":  diff.Deleted,
+			})
+		}
+	}
+
+	recordAudit(params, &entityResp.Entity, executionID, completeReq.Status, result.ExitCode, execDuration, completeReq.FailureReason)
+
 	// Step 6: Complete execution
 	log.Info("Completing execution", map[string]interface{}{
 		"status": completeReq.Status,
 	})
+	completeStart := time.Now()
 	_, err = params.Client.CompleteExecution(executionID, completeReq, params.ServiceKey)
 	if err != nil {
 		log.Error("Failed to complete execution", err)
-		return false, fmt.Errorf("failed to complete execution: %w", err)
+		return nil, fmt.Errorf("failed to complete execution: %w", err)
 	}
+	log.WithDuration("Execution reported", time.Since(completeStart))
 
+	succeeded := completeReq.Status == api.ExecutionAttemptStatusSuccess
 	totalDuration := time.Since(startTime)
 	log.WithDuration("Run command completed", totalDuration, map[string]interface{}{
-		"success":      result.Success,
+		"success":      succeeded,
 		"execution_id": executionID,
 	})
 
-	return result.Success, nil
+	params.Budget.addCost(result.CostUSD)
+
+	return &attemptResult{Success: succeeded, Executed: true, TimedOut: result.TimedOut}, nil
+}
+
+// maxTestFailureRecords caps per-test validation records so a broken suite
+// does not produce an oversized completion request
+const maxTestFailureRecords = 100
+
+// attachTestResults adds pass/fail counts to the run outputs and one
+// validation record per failed test
+func attachTestResults(completeReq *api.ExecutionCompleteRequest, tests *executor.TestSummary) {
+	counts := map[string]interface{}{
+		"total":   tests.Total,
+		"passed":  tests.Passed,
+		"failed":  tests.Failed,
+		"skipped": tests.Skipped,
+		"reports": tests.Reports,
+	}
+	if completeReq.Outputs.Structured == nil {
+		completeReq.Outputs.Structured = map[string]interface{}{}
+	}
+	completeReq.Outputs.Structured["tests"] = counts
+	completeReq.Outputs.Metrics["tests_total"] = tests.Total
+	completeReq.Outputs.Metrics["tests_failed"] = tests.Failed
+
+	for i, f := range tests.Failures {
+		if i == maxTestFailureRecords {
+			reason := fmt.Sprintf("%d more failed tests not recorded individually", len(tests.Failures)-i)
+			completeReq.ValidationRecords = append(completeReq.ValidationRecords, api.ValidationRecord{
+				ValidationType: "TEST",
+				Outcome:        api.ValidationOutcomeFail,
+				Severity:       api.ValidationSeverityCritical,
+				Target:         "tests",
+				FailureReason:  &reason,
+			})
+			break
+		}
+		target := f.Name
+		if f.Suite != "" {
+			target = f.Suite + "." + f.Name
+		}
+		reason := f.Message
+		if reason == "" {
+			reason = "test failed"
+		}
+		completeReq.ValidationRecords = append(completeReq.ValidationRecords, api.ValidationRecord{
+			ValidationType: "TEST",
+			Outcome:        api.ValidationOutcomeFail,
+			Severity:       api.ValidationSeverityCritical,
+			Target:         "test:" + target,
+			FailureReason:  &reason,
+		})
+	}
+}
+
+// dispatchExecution runs an entity with the executor registered for its
+// execution mode. Cancelling ctx stops the execution and kills its process
+// group.
+func dispatchExecution(ctx context.Context, entity *api.PlanningEntity, inputs map[string]interface{}, log *logging.Logger) *executor.ExecutionResult {
+	warnOversizedInputs(log, entity.ExecutionMode, inputs)
+	if !executor.IsDryRun(ctx) {
+		recordTelemetryMode(entity.ExecutionMode)
+	}
+	if e, ok := executor.Lookup(entity.ExecutionMode); ok {
+		return e.Execute(ctx, entity, inputs)
+	}
+	// Modes without a registered executor go to a kindship-executor-<mode> plugin
+	log.Info("Dispatching to executor plugin", map[string]interface{}{
+		"mode":   entity.ExecutionMode,
+		"plugin": executor.PluginBinary(entity.ExecutionMode),
+	})
+	return executor.ExecutePluginWithContext(ctx, entity, inputs)
+}
+
+// warnOversizedInputs logs the inputs too large for INPUT_<LABEL>
+// environment variables, which processes only receive as
+// INPUT_<LABEL>_FILE. LLM modes pass inputs in the prompt instead.
+func warnOversizedInputs(log *logging.Logger, mode api.ExecutionMode, inputs map[string]interface{}) {
+	switch mode {
+	case api.ExecutionModeLLMReasoning, api.ExecutionModeHybrid, api.ExecutionModeAskUser, api.ExecutionModeOrchestrate:
+		return
+	}
+	if oversized := executor.OversizedInputs(inputs); len(oversized) > 0 {
+		log.Warn("Inputs too large for environment variables, exposed only as INPUT_<LABEL>_FILE: "+strings.Join(oversized, ", "), map[string]interface{}{
+			"inputs": oversized,
+		})
+	}
+}
+
+// cancelPollInterval is how often a running execution checks whether it
+// was cancelled from the UI
+const cancelPollInterval = 15 * time.Second
+
+// watchCancellation polls the execution's server-side status until ctx is
+// done. When the server reports CANCELLED, the returned flag is set and
+// cancel is called to stop the executor. Poll errors are logged at debug
+// level and ignored; a flaky API must not kill a healthy execution.
+func watchCancellation(ctx context.Context, params EntityExecutionParams, executionID string, cancel context.CancelFunc) *atomic.Bool {
+	cancelled := &atomic.Bool{}
+	go func() {
+		ticker := time.NewTicker(cancelPollInterval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+			}
+			statusResp, err := params.Client.GetExecutionStatus(executionID, params.ServiceKey)
+			if err != nil {
+				params.Log.Debug("Failed to check execution status", map[string]interface{}{
+					"error": err.Error(),
+				})
+				continue
+			}
+			if statusResp.Status == api.ExecutionAttemptStatusCancelled {
+				params.Log.Warn("Execution cancelled from the UI, stopping", map[string]interface{}{
+					"execution_id": executionID,
+				})
+				cancelled.Store(true)
+				cancel()
+				return
+			}
+		}
+	}()
+	return cancelled
+}
+
+// abandonCancelled completes a run that was cancelled mid-execution (from
+// the UI or by a signal) as ABANDONED with the given reason. The attempt is
+// reported as executed but never retried.
+func abandonCancelled(params EntityExecutionParams, entity *api.PlanningEntity, executionID string, result *executor.ExecutionResult, execDuration time.Duration, reason string) (*attemptResult, error) {
+	failureMsg := reason
+	recordAudit(params, entity, executionID, api.ExecutionAttemptStatusAbandoned, result.ExitCode, execDuration, &failureMsg)
+
+	completeReq := api.ExecutionCompleteRequest{
+		Status:        api.ExecutionAttemptStatusAbandoned,
+		FailureReason: &failureMsg,
+		Outputs: &api.ExecutionOutputs{
+			Stdout: result.Stdout,
+			Stderr: result.Stderr,
+		},
+	}
+	if result.Transcript != nil {
+		newArtifactUploader(params, executionID, nil).uploadTranscript(result.Transcript, completeReq.Outputs)
+	}
+	if _, err := params.Client.CompleteExecution(executionID, completeReq, params.ServiceKey); err != nil {
+		// The server already considers the run cancelled; nothing to retry
+		params.Log.Warn("Failed to complete cancelled execution", map[string]interface{}{
+			"error": err.Error(),
+		})
+	}
+	params.Budget.addCost(result.CostUSD)
+	return &attemptResult{Executed: true, Cancelled: true}, nil
+}
+
+// failWatchdog completes a run stopped by the loop watchdog as FAILED. The
+// attempt counts as timed out for boundaries.retry.
+func failWatchdog(params EntityExecutionParams, entity *api.PlanningEntity, executionID string, result *executor.ExecutionResult, execDuration time.Duration) (*attemptResult, error) {
+	failureMsg := result.Error.Error()
+	recordAudit(params, entity, executionID, api.ExecutionAttemptStatusFailed, result.ExitCode, execDuration, &failureMsg)
+
+	completeReq := api.ExecutionCompleteRequest{
+		Status:        api.ExecutionAttemptStatusFailed,
+		FailureReason: &failureMsg,
+		Outputs: &api.ExecutionOutputs{
+			Stdout: result.Stdout,
+			Stderr: result.Stderr,
+			Metrics: map[string]interface{}{
+				"duration_ms": execDuration.Milliseconds(),
+				"exit_code":   result.ExitCode,
+			},
+		},
+		ValidationRecords: []api.ValidationRecord{{
+			ValidationType: "WATCHDOG",
+			Outcome:        api.ValidationOutcomeFail,
+			Severity:       api.ValidationSeverityCritical,
+			Target:         "execution_completion",
+			Actual: map[string]interface{}{
+				"duration_ms": execDuration.Milliseconds(),
+				"limit_ms":    params.Settings.Watchdog.Milliseconds(),
+			},
+			FailureReason: &failureMsg,
+		}},
+	}
+	if result.Transcript != nil {
+		newArtifactUploader(params, executionID, nil).uploadTranscript(result.Transcript, completeReq.Outputs)
+	}
+	if _, err := params.Client.CompleteExecution(executionID, completeReq, params.ServiceKey); err != nil {
+		params.Log.Error("Failed to complete execution", err)
+		return nil, fmt.Errorf("failed to complete execution: %w", err)
+	}
+	params.Budget.addCost(result.CostUSD)
+	return &attemptResult{Executed: true, TimedOut: true}, nil
+}
+
+// extractStructuredOutput returns the structured output of an execution.
+// JSON written to $OUTPUT_FILE wins over JSON extracted from stdout, which
+// is fragile when scripts log freely; strategy picks among several JSON
+// values in stdout. Arrays and scalars are wrapped for transport with
+// validator.WrapStructured.
+func extractStructuredOutput(result *executor.ExecutionResult, strategy string) (map[string]interface{}, error) {
+	if result.OutputFile != nil {
+		var value interface{}
+		if err := json.Unmarshal(result.OutputFile, &value); err != nil {
+			return nil, fmt.Errorf("invalid JSON in OUTPUT_FILE: %w", err)
+		}
+		if value == nil {
+			return nil, fmt.Errorf("OUTPUT_FILE must not be null")
+		}
+		return validator.WrapStructured(value), nil
+	}
+	return validator.ExtractJSONFromOutputWith(result.Stdout, strategy)
+}
+
+// failBeforeExecution completes a run as FAILED without executing anything,
+// attaching the given validation records. Used when a pre-execution check
+// (preflight, boundaries) rejects the entity. Reports an unsuccessful,
+// non-executed attempt unless the completion call itself fails.
+func failBeforeExecution(params EntityExecutionParams, entity *api.PlanningEntity, executionID, failureMsg string, records []api.ValidationRecord) (*attemptResult, error) {
+	recordAudit(params, entity, executionID, api.ExecutionAttemptStatusFailed, -1, 0, &failureMsg)
+
+	completeReq := api.ExecutionCompleteRequest{
+		Status:            api.ExecutionAttemptStatusFailed,
+		FailureReason:     &failureMsg,
+		ValidationRecords: records,
+	}
+	if _, err := params.Client.CompleteExecution(executionID, completeReq, params.ServiceKey); err != nil {
+		params.Log.Error("Failed to complete execution", err)
+		return nil, fmt.Errorf("failed to complete execution: %w", err)
+	}
+	return &attemptResult{}, nil
+}
+
+// recordAudit appends the attempt to the local audit log. Audit failures are
+// logged but never fail the execution itself.
+func recordAudit(params EntityExecutionParams, entity *api.PlanningEntity, executionID string, status api.ExecutionAttemptStatus, exitCode int, duration time.Duration, reason *string) {
+	command := ""
+	if entity.Code != nil {
+		command = *entity.Code
+	}
+	entry := audit.Entry{
+		EntityID:    entity.ID,
+		ExecutionID: executionID,
+		Mode:        string(entity.ExecutionMode),
+		CommandHash: audit.HashCommand(command),
+		Status:      string(status),
+		ExitCode:    exitCode,
+		DurationMs:  duration.Milliseconds(),
+		AgentID:     params.AgentID,
+		TriggeredBy: params.TriggeredBy,
+	}
+	if entry.EntityID == "" {
+		entry.EntityID = params.EntityID
+	}
+	if reason != nil {
+		entry.Reason = *reason
+	}
+	if err := audit.Record(entry); err != nil {
+		params.Log.Warn("Failed to write audit log entry", map[string]interface{}{
+			"error": err.Error(),
+		})
+	}
 }
 
-// runOrchestration creates a new ORCHESTRATE run for any entity with
-// execution_mode=ORCHESTRATE and orchestrates its child tasks.
-func runOrchestration(entityID string, client *api.Client, log *logging.Logger) error {
+// runOrchestration creates a new ORCHESTRATE run for parent.EntityID, an
+// entity with execution_mode=ORCHESTRATE, and orchestrates its child tasks.
+func runOrchestration(parent EntityExecutionParams) error {
 	// Create Run for the entity
 	startReq := api.ExecutionStartRequest{
-		EntityID:      entityID,
+		EntityID:      parent.EntityID,
 		ExecutionMode: api.ExecutionModeOrchestrate,
-		AgentID:       agentID,
+		AgentID:       parent.AgentID,
 	}
 
-	startResp, err := client.StartExecution(startReq, serviceKey)
+	startResp, err := parent.Client.StartExecution(startReq, parent.ServiceKey)
 	if err != nil {
 		return fmt.Errorf("failed to start ORCHESTRATE run: %w", err)
 	}
 
 	runID := startResp.ExecutionID
-	log.Info("Created ORCHESTRATE run", map[string]interface{}{
+	parent.Log.Info("Created ORCHESTRATE run", map[string]interface{}{
 		"run_id":    runID,
-		"entity_id": entityID,
+		"entity_id": parent.EntityID,
 	})
 
-	return orchestrateChildren(entityID, runID, client, log)
+	return orchestrateChildren(parent, runID)
 }
 
 // resumeOrchestration resumes an existing ORCHESTRATE run after container
 // restart, re-entering the child orchestration polling loop.
 // Works for any entity type (PROCESS, PROJECT, TASK-with-children).
-func resumeOrchestration(entityID, runID string, client *api.Client, log *logging.Logger) error {
-	log.Info("Resuming ORCHESTRATE run", map[string]interface{}{
-		"entity_id": entityID,
+func resumeOrchestration(parent EntityExecutionParams, runID string) error {
+	parent.Log.Info("Resuming ORCHESTRATE run", map[string]interface{}{
+		"entity_id": parent.EntityID,
 		"run_id":    runID,
 	})
 
-	return orchestrateChildren(entityID, runID, client, log)
+	return orchestrateChildren(parent, runID)
 }
 
 // orchestrateChildren is the shared polling loop for ORCHESTRATE runs.
 // It polls for runnable child tasks, executes them, and completes the
-// parent run when all children are done.
-func orchestrateChildren(entityID, runID string, client *api.Client, log *logging.Logger) error {
+// parent run when all children are done. Children run with parent's agent
+// ID, service key, budget and settings, so several agents can orchestrate
+// from one process. When the budget is set, no new tasks are claimed once
+// it is exhausted and the run is completed with a PARTIAL outcome. A digest of the run goes to
+// --summary-url and --summary-file when they are set.
+func orchestrateChildren(parent EntityExecutionParams, runID string) error {
+	log := parent.Log.WithFields(map[string]interface{}{"process_run_id": runID})
+	summary := ProcessSummary{EntityID: parent.EntityID, RunID: runID, AgentID: parent.AgentID, StartedAt: time.Now()}
+	runEvent := LifecycleEvent{
+		AgentID:       parent.AgentID,
+		EntityID:      parent.EntityID,
+		ExecutionID:   runID,
+		ExecutionMode: string(api.ExecutionModeOrchestrate),
+	}
+	runEvent.Event = eventStarted
+	parent.Settings.Events.emit(runEvent)
+
 	// Set up graceful shutdown
 	ctx, cancel := context.WithCancel(context.Background())
 	defer cancel()
@@ -472,6 +1411,7 @@ func orchestrateChildren(entityID, runID string, client *api.Client, log *loggin
 	tasksExecuted := 0
 	var lastError error
 	interrupted := false
+	budgetExceeded := ""
 	const initialBackoff = 1 * time.Second
 	const maxBackoff = 30 * time.Second
 	backoff := initialBackoff
@@ -489,8 +1429,18 @@ func orchestrateChildren(entityID, runID string, client *api.Client, log *loggin
 		default:
 		}
 
+		// Stop claiming new tasks once the budget is exhausted
+		if reason := parent.Budget.exceeded(); reason != "" {
+			log.Warn("Budget exhausted, not claiming further tasks", map[string]interface{}{
+				"reason":         reason,
+				"tasks_executed": tasksExecuted,
+			})
+			budgetExceeded = reason
+			break
+		}
+
 		// Fetch next task scoped to this entity
-		nextResp, err := client.FetchNextTaskScoped(agentID, entityID, serviceKey)
+		nextResp, err := parent.Client.FetchNextTaskScoped(parent.AgentID, parent.EntityID, parent.ServiceKey)
 		if err != nil {
 			log.Error("Failed to fetch next task", err, nil)
 			lastError = err
@@ -535,13 +1485,38 @@ func orchestrateChildren(entityID, runID string, client *api.Client, log *loggin
 			"task_title": nextResp.Task.Title,
 		})
 
+		taskStart := time.Now()
 		success, err := executeEntity(EntityExecutionParams{
-			EntityID:   nextResp.Task.ID,
-			AgentID:    agentID,
-			ServiceKey: serviceKey,
-			Client:     client,
-			Log:        log,
+			EntityID:    nextResp.Task.ID,
+			AgentID:     parent.AgentID,
+			ServiceKey:  parent.ServiceKey,
+			Client:      parent.Client,
+			Log:         log,
+			TriggeredBy: "orchestrate:" + runID,
+			Budget:      parent.Budget,
+			Settings:    parent.Settings,
 		})
+		parent.Budget.addDuration(time.Since(taskStart))
+
+		summaryTask := ProcessSummaryTask{
+			ID:         nextResp.Task.ID,
+			Title:      nextResp.Task.Title,
+			Status:     string(api.ExecutionAttemptStatusSuccess),
+			DurationMS: time.Since(taskStart).Milliseconds(),
+		}
+		switch {
+		case errors.Is(err, ErrAskUserSkipped):
+			summaryTask.Status = "AWAITING_USER"
+		case err != nil:
+			summaryTask.Status = string(api.ExecutionAttemptStatusFailed)
+			summaryTask.FailureReason = err.Error()
+		case !success:
+			summaryTask.Status = string(api.ExecutionAttemptStatusFailed)
+			if summaryEnabled() {
+				summaryTask.FailureReason = lastFailureReason(parent.Client, nextResp.Task.ID, parent.ServiceKey)
+			}
+		}
+		summary.Tasks = append(summary.Tasks, summaryTask)
 
 		if err != nil {
 			if errors.Is(err, ErrAskUserSkipped) {
@@ -585,21 +1560,52 @@ complete:
 		},
 	}
 
+	for k, v := range parent.Budget.metrics() {
+		completeReq.Outputs.Metrics[k] = v
+	}
+
 	if interrupted {
 		completeReq.Status = api.ExecutionAttemptStatusAbandoned
 		errorMsg := "Orchestration interrupted by signal"
 		completeReq.FailureReason = &errorMsg
+	} else if budgetExceeded != "" {
+		// Budget stop: the work done so far stands, the rest was not attempted
+		completeReq.Status = api.ExecutionAttemptStatusAbandoned
+		errorMsg := fmt.Sprintf("Orchestration stopped: %s", budgetExceeded)
+		completeReq.FailureReason = &errorMsg
+		completeReq.Outputs.Metrics["budget_exceeded"] = true
+		completeReq.ValidationRecords = []api.ValidationRecord{{
+			ValidationType: "BUDGET",
+			Outcome:        api.ValidationOutcomePartial,
+			Severity:       api.ValidationSeverityWarning,
+			Target:         "process_budget",
+			Actual:         completeReq.Outputs.Metrics,
+			FailureReason:  &errorMsg,
+		}}
 	} else if lastError != nil {
 		completeReq.Status = api.ExecutionAttemptStatusFailed
 		errorMsg := lastError.Error()
 		completeReq.FailureReason = &errorMsg
 	}
 
-	_, err := client.CompleteExecution(runID, completeReq, serviceKey)
+	summary.Status = completeReq.Status
+	if completeReq.FailureReason != nil {
+		summary.FailureReason = *completeReq.FailureReason
+	}
+	summary.TasksExecuted = tasksExecuted
+	summary.FinishedAt = time.Now()
+	summary.DurationMS = summary.FinishedAt.Sub(summary.StartedAt).Milliseconds()
+	deliverProcessSummary(summary, log)
+
+	_, err := parent.Client.CompleteExecution(runID, completeReq, parent.ServiceKey)
 	if err != nil {
 		log.Error("Failed to complete orchestration run", err, nil)
 		return err
 	}
+	runEvent.Event = eventCompleted
+	runEvent.Status = string(completeReq.Status)
+	runEvent.DurationMS = summary.DurationMS
+	parent.Settings.Events.emit(runEvent)
 
 	log.Info("Orchestration completed", map[string]interface{}{
 		"run_id":         runID,
@@ -612,6 +1618,10 @@ complete:
 		return fmt.Errorf("orchestration interrupted")
 	}
 
+	if budgetExceeded != "" {
+		return fmt.Errorf("orchestration stopped: %s", budgetExceeded)
+	}
+
 	if lastError != nil {
 		return fmt.Errorf("orchestration completed with errors: %w", lastError)
 	}
@@ -620,8 +1630,38 @@ complete:
 }
 
 func init() {
-	runCmd.Flags().BoolVarP(&verbose, "verbose", "v", false, "Enable verbose logging for debugging")
-	runCmd.Flags().StringVar(&agentID, "agent-id", "", "Agent container ID (defaults to AGENT_ID env var)")
-	runCmd.Flags().StringVar(&serviceKey, "service-key", "", "Service key for authentication (defaults to KINDSHIP_SERVICE_KEY env var)")
-	runCmd.Flags().StringVar(&apiURL, "api-url", "", "API base URL (defaults to KINDSHIP_API_URL env var or https://kindship.ai)")
+	runCmd.Flags().BoolVarP(&runOpts.Verbose, "verbose", "v", false, "Enable verbose logging for debugging")
+	runCmd.Flags().BoolVar(&showTimings, "timings", false, timingsUsage)
+	runCmd.Flags().BoolVar(&runFollow, "follow", false, "Stream the task's output to the terminal while it runs")
+	runCmd.Flags().StringVar(&runOpts.AgentID, "agent-id", "", "Agent container ID (defaults to AGENT_ID env var)")
+	runCmd.Flags().StringVar(&runOpts.ServiceKey, "service-key", "", "Service key for authentication (defaults to KINDSHIP_SERVICE_KEY env var)")
+	runCmd.Flags().StringVar(&runOpts.APIURL, "api-url", "", "API base URL (defaults to KINDSHIP_API_URL env var or https://kindship.ai)")
+	runCmd.Flags().BoolVar(&runLocal, "local", false, "Execute a plan file locally without the API")
+	runCmd.Flags().BoolVar(&runDryRun, "dry-run", false, "Print what a BASH, TEST or PYTHON task would run without executing it")
+	runCmd.Flags().BoolVar(&runExplainInputs, "explain-inputs", false, "Print where each input came from before executing")
+	runCmd.Flags().Float64Var(&budgetMinutes, "budget-minutes", 0, "Stop an ORCHESTRATE run after this many minutes of cumulative task time")
+	runCmd.Flags().Float64Var(&budgetUSD, "budget-usd", 0, "Stop an ORCHESTRATE run after this much reported LLM cost (USD)")
+	runCmd.Flags().StringVar(&summaryURL, "summary-url", "", "POST a JSON digest of an ORCHESTRATE run to this URL when it ends")
+	runCmd.Flags().StringVar(&summaryFile, "summary-file", "", "Write a JSON digest of an ORCHESTRATE run to this file when it ends")
+	addLLMFlags(runCmd)
+	addSecretsFlag(runCmd)
+	addEventsFlag(runCmd)
+}
+
+// snapshotWorkspace records the workspace manifest for
+// boundaries.workspace_diff. The diff is a review aid, so a failed
+// snapshot is logged and returns nil rather than failing the execution.
+func snapshotWorkspace(log *logging.Logger, workDir string, policy *boundaries.WorkspaceDiffPolicy, prev workspace.Manifest) workspace.Manifest {
+	start := time.Now()
+	manifest, err := workspace.Snapshot(workDir, policy.Exclude, prev)
+	if err != nil {
+		log.Warn("Failed to snapshot workspace; no workspace diff reported", map[string]interface{}{
+			"error": err.Error(),
+		})
+		return nil
+	}
+	log.WithDuration("Workspace snapshot taken", time.Since(start), map[string]interface{}{
+		"files": len(manifest),
+	})
+	return manifest
 }
@@ -0,0 +1,471 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/kindship-ai/kindship-cli/internal/api"
+	"github.com/kindship-ai/kindship-cli/internal/logging"
+	"github.com/kindship-ai/kindship-cli/internal/output"
+	"github.com/kindship-ai/kindship-cli/internal/validator"
+
+	"github.com/spf13/cobra"
+)
+
+var runNextCmd = &cobra.Command{
+	Use:   "next",
+	Short: "Get the next claimable task for this agent",
+	Long: `Returns the next task the agent can claim and execute.
+
+Unlike 'kindship run <id>', nothing is executed: the task is only printed,
+so an external executor (or a human) can do the work and report back with
+'kindship run complete'.
+
+Output format:
+  --format json    JSON output (default)
+  --format table   Human-readable table
+  --format yaml    YAML output
+
+Examples:
+  kindship run next
+  kindship run next --format table
+
+` + exitCodeHelp,
+	Args:         usageArgs(cobra.NoArgs),
+	SilenceUsage: true,
+	RunE:         runNext,
+}
+
+var runCompleteCmd = &cobra.Command{
+	Use:   "complete <entity-id>",
+	Short: "Report a task as completed with caller-supplied outputs",
+	Long: `Complete a planning entity with structured outputs produced outside the CLI.
+
+A new execution attempt is started for the entity unless --execution-id
+names an existing one (e.g. from 'kindship run start'). The outputs are validated against the entity's
+output_schema and recorded the same way as for 'kindship run'.
+
+--outputs accepts JSON, @<file> to read it from a file, or - to read it
+from stdin. Outputs that are not an object (e.g. an array) are sent
+wrapped as {"$value": ...}.
+
+Examples:
+  kindship run complete 550e8400-e29b-41d4-a716-446655440000 --outputs '{"rows": 42}'
+  kindship run complete 550e8400-e29b-41d4-a716-446655440000 --outputs @result.json
+  ./report.sh | kindship run complete 550e8400-e29b-41d4-a716-446655440000 --outputs -
+
+` + exitCodeHelp,
+	Args:         usageArgs(cobra.ExactArgs(1)),
+	SilenceUsage: true,
+	RunE:         runComplete,
+}
+
+var runStartCmd = &cobra.Command{
+	Use:   "start <entity-id>",
+	Short: "Claim a task and create an execution attempt without executing it",
+	Long: `Claim a planning entity and create a RUNNING execution attempt for it.
+
+Nothing is executed. Dependencies and inputs are checked as for
+'kindship run', and the execution ID and resolved inputs are printed so a
+script or a human can do the work and finish the attempt with
+'kindship run complete --execution-id' or 'kindship run fail'.
+
+Examples:
+  kindship run start 550e8400-e29b-41d4-a716-446655440000
+  EXEC_ID=$(kindship run start 550e8400-e29b-41d4-a716-446655440000 --format json | jq -r .execution_id)
+
+` + exitCodeHelp,
+	Args:         usageArgs(cobra.ExactArgs(1)),
+	SilenceUsage: true,
+	RunE:         runStart,
+}
+
+var runFailCmd = &cobra.Command{
+	Use:   "fail <execution-id>",
+	Short: "Mark an execution attempt as failed",
+	Long: `Complete an execution attempt as FAILED with the given reason.
+
+A failed validation record is attached so the failure shows up alongside
+attempts made by 'kindship run'.
+
+Examples:
+  kindship run fail 770e8400-e29b-41d4-a716-446655440000 --reason "upstream export was empty"
+
+` + exitCodeHelp,
+	Args:         usageArgs(cobra.ExactArgs(1)),
+	SilenceUsage: true,
+	RunE:         runFail,
+}
+
+var (
+	runTaskFormat  string
+	runOutputs     string
+	runExecutionID string
+	runFailReason  string
+)
+
+func init() {
+	for _, c := range []*cobra.Command{runNextCmd, runStartCmd, runCompleteCmd, runFailCmd} {
+		c.Flags().StringVar(&runTaskOpts.AgentID, "agent-id", "", "Agent container ID (defaults to AGENT_ID env var)")
+		c.Flags().StringVar(&runTaskOpts.ServiceKey, "service-key", "", "Service key for authentication (defaults to KINDSHIP_SERVICE_KEY env var)")
+		c.Flags().StringVar(&runTaskOpts.APIURL, "api-url", "", "API base URL (defaults to KINDSHIP_API_URL env var or https://kindship.ai)")
+		c.Flags().BoolVarP(&runTaskOpts.Verbose, "verbose", "v", false, "Enable verbose logging for debugging")
+	}
+	runNextCmd.Flags().StringVar(&runTaskFormat, "format", "json", output.FormatUsage)
+	runCompleteCmd.Flags().StringVar(&runTaskFormat, "format", "table", output.FormatUsage)
+	runCompleteCmd.Flags().StringVar(&runOutputs, "outputs", "", "Structured outputs as JSON, @file or - for stdin")
+	runCompleteCmd.Flags().StringVar(&runExecutionID, "execution-id", "", "Complete this existing execution attempt instead of starting one")
+	runStartCmd.Flags().StringVar(&runTaskFormat, "format", "table", output.FormatUsage)
+	runFailCmd.Flags().StringVar(&runTaskFormat, "format", "table", output.FormatUsage)
+	runFailCmd.Flags().StringVar(&runFailReason, "reason", "", "Why the execution failed (required)")
+
+	runCmd.AddCommand(runNextCmd)
+	runCmd.AddCommand(runStartCmd)
+	runCmd.AddCommand(runCompleteCmd)
+	runCmd.AddCommand(runFailCmd)
+}
+
+func runNext(cmd *cobra.Command, args []string) error {
+	format, err := output.ParseFormat(runTaskFormat)
+	if err != nil {
+		return withExitCode(ExitUsage, err)
+	}
+
+	opts := runTaskOpts.resolved()
+	if err := opts.requireCredentials(); err != nil {
+		return err
+	}
+
+	client := opts.client()
+	nextResp, err := client.FetchNextTask(opts.AgentID, opts.ServiceKey)
+	if err != nil {
+		return fmt.Errorf("failed to fetch next task: %w", err)
+	}
+
+	return output.Render(os.Stdout, format, nextResp, func() error {
+		if nextResp.Task == nil {
+			fmt.Println("No claimable tasks found.")
+			if nextResp.PendingCount > 0 {
+				fmt.Printf("Pending: %d\n", nextResp.PendingCount)
+			}
+			return nil
+		}
+
+		fmt.Printf("Next task: %s\n", output.Bold(nextResp.Task.Title))
+		table := output.NewTable()
+		table.Indent = "  "
+		table.Row("ID", nextResp.Task.ID)
+		if nextResp.Task.Description != "" {
+			table.Row("Description", nextResp.Task.Description)
+		}
+		table.Row("Execution mode", nextResp.Task.ExecutionMode)
+		return table.Render(os.Stdout)
+	})
+}
+
+// runStartResult is printed by 'kindship run start'
+type runStartResult struct {
+	EntityID      string                 `json:"entity_id"`
+	ExecutionID   string                 `json:"execution_id"`
+	AttemptNumber int                    `json:"attempt_number"`
+	ExecutionMode api.ExecutionMode      `json:"execution_mode"`
+	Inputs        map[string]interface{} `json:"inputs"`
+}
+
+func runStart(cmd *cobra.Command, args []string) error {
+	entityID := args[0]
+
+	format, err := output.ParseFormat(runTaskFormat)
+	if err != nil {
+		return withExitCode(ExitUsage, err)
+	}
+
+	opts := runTaskOpts.resolved()
+	if err := opts.requireCredentials(); err != nil {
+		return err
+	}
+
+	log := logging.Init(opts.AgentID, "run-start", opts.Verbose)
+	defer log.FlushSync()
+
+	client := opts.client()
+	entityResp, err := client.FetchEntityForExecution(entityID, opts.ServiceKey)
+	if err != nil {
+		log.Error("Failed to fetch entity", err)
+		return fmt.Errorf("failed to fetch entity: %w", err)
+	}
+
+	// Same pre-start checks as executeEntity
+	if !entityResp.DependenciesStatus.AllMet {
+		return withExitCode(ExitDependency, fmt.Errorf("dependencies not met: %v", entityResp.DependenciesStatus.Pending))
+	}
+	if len(entityResp.Entity.InputSchema) > 0 {
+		if err := validator.ValidateInputs(entityResp.Inputs, entityResp.Entity.InputSchema); err != nil {
+			return withExitCode(ExitValidation, fmt.Errorf("input validation failed: %w", err))
+		}
+	}
+
+	startResp, err := client.StartExecution(api.ExecutionStartRequest{
+		EntityID:      entityID,
+		ExecutionMode: entityResp.Entity.ExecutionMode,
+		AgentID:       opts.AgentID,
+	}, opts.ServiceKey)
+	if err != nil {
+		log.Error("Failed to start execution", err)
+		return fmt.Errorf("failed to start execution: %w", err)
+	}
+	log.Info("Run created", map[string]interface{}{
+		"execution_id":   startResp.ExecutionID,
+		"attempt_number": startResp.AttemptNumber,
+		"triggered_by":   "cli:run-start",
+	})
+
+	result := runStartResult{
+		EntityID:      entityID,
+		ExecutionID:   startResp.ExecutionID,
+		AttemptNumber: startResp.AttemptNumber,
+		ExecutionMode: entityResp.Entity.ExecutionMode,
+		Inputs:        startResp.Inputs,
+	}
+	return output.Render(os.Stdout, format, result, func() error {
+		fmt.Println(output.OK(fmt.Sprintf("Started '%s' (attempt %d)", entityResp.Entity.Title, startResp.AttemptNumber)))
+		table := output.NewTable()
+		table.Indent = "  "
+		table.Row("Execution ID", startResp.ExecutionID)
+		table.Row("Execution mode", string(entityResp.Entity.ExecutionMode))
+		if len(startResp.Inputs) > 0 {
+			table.Row("Inputs", strings.Join(validator.GetInputLabels(startResp.Inputs), ", "))
+		}
+		return table.Render(os.Stdout)
+	})
+}
+
+// runFailResult is printed by 'kindship run fail'
+type runFailResult struct {
+	ExecutionID string                     `json:"execution_id"`
+	Status      api.ExecutionAttemptStatus `json:"status"`
+	Reason      string                     `json:"failure_reason"`
+}
+
+func runFail(cmd *cobra.Command, args []string) error {
+	executionID := args[0]
+
+	format, err := output.ParseFormat(runTaskFormat)
+	if err != nil {
+		return withExitCode(ExitUsage, err)
+	}
+	if strings.TrimSpace(runFailReason) == "" {
+		return withExitCode(ExitUsage, fmt.Errorf("--reason must not be empty"))
+	}
+
+	opts := runTaskOpts.resolved()
+	if err := opts.requireCredentials(); err != nil {
+		return err
+	}
+
+	log := logging.Init(opts.AgentID, "run-fail", opts.Verbose)
+	defer log.FlushSync()
+
+	reason := runFailReason
+	completeReq := api.ExecutionCompleteRequest{
+		Status:        api.ExecutionAttemptStatusFailed,
+		FailureReason: &reason,
+		ValidationRecords: []api.ValidationRecord{{
+			ValidationType: "OUTPUT",
+			Outcome:        api.ValidationOutcomeFail,
+			Severity:       api.ValidationSeverityCritical,
+			Target:         "execution_completion",
+			Actual: map[string]interface{}{
+				"completed_by": "cli:run-fail",
+			},
+			FailureReason: &reason,
+		}},
+	}
+
+	client := opts.client()
+	if _, err := client.CompleteExecution(executionID, completeReq, opts.ServiceKey); err != nil {
+		log.Error("Failed to complete execution", err)
+		return fmt.Errorf("failed to complete execution: %w", err)
+	}
+
+	result := runFailResult{
+		ExecutionID: executionID,
+		Status:      completeReq.Status,
+		Reason:      reason,
+	}
+	return output.Render(os.Stdout, format, result, func() error {
+		fmt.Println(output.Fail(fmt.Sprintf("Marked execution %s as failed", executionID)))
+		fmt.Printf("  Reason: %s\n", reason)
+		return nil
+	})
+}
+
+// runCompleteResult is printed by 'kindship run complete'
+type runCompleteResult struct {
+	EntityID    string                     `json:"entity_id"`
+	ExecutionID string                     `json:"execution_id"`
+	Status      api.ExecutionAttemptStatus `json:"status"`
+	Validations []api.ValidationRecord     `json:"validation_records"`
+}
+
+func runComplete(cmd *cobra.Command, args []string) error {
+	entityID := args[0]
+
+	format, err := output.ParseFormat(runTaskFormat)
+	if err != nil {
+		return withExitCode(ExitUsage, err)
+	}
+
+	structured, err := readOutputsArg(runOutputs)
+	if err != nil {
+		return withExitCode(ExitUsage, err)
+	}
+
+	opts := runTaskOpts.resolved()
+	if err := opts.requireCredentials(); err != nil {
+		return err
+	}
+
+	log := logging.Init(opts.AgentID, "run-complete", opts.Verbose)
+	defer log.FlushSync()
+
+	client := opts.client()
+	entityResp, err := client.FetchEntityForExecution(entityID, opts.ServiceKey)
+	if err != nil {
+		log.Error("Failed to fetch entity", err)
+		return fmt.Errorf("failed to fetch entity: %w", err)
+	}
+
+	// Start an attempt unless the caller already has one
+	executionID := runExecutionID
+	if executionID == "" {
+		startResp, err := client.StartExecution(api.ExecutionStartRequest{
+			EntityID:      entityID,
+			ExecutionMode: entityResp.Entity.ExecutionMode,
+			AgentID:       opts.AgentID,
+		}, opts.ServiceKey)
+		if err != nil {
+			log.Error("Failed to start execution", err)
+			return fmt.Errorf("failed to start execution: %w", err)
+		}
+		executionID = startResp.ExecutionID
+		log.Info("Run created", map[string]interface{}{
+			"execution_id":   executionID,
+			"attempt_number": startResp.AttemptNumber,
+		})
+	}
+
+	completeReq := api.ExecutionCompleteRequest{
+		Status: api.ExecutionAttemptStatusSuccess,
+		Outputs: &api.ExecutionOutputs{
+			Structured: structured,
+		},
+		ValidationRecords: []api.ValidationRecord{{
+			ValidationType: "OUTPUT",
+			Outcome:        api.ValidationOutcomePass,
+			Severity:       api.ValidationSeverityInfo,
+			Target:         "execution_completion",
+			Actual: map[string]interface{}{
+				"completed_by": "cli:run-complete",
+			},
+		}},
+	}
+	if record := validateSuppliedOutputs(structured, entityResp.Entity.OutputSchema); record != nil {
+		completeReq.ValidationRecords = append(completeReq.ValidationRecords, *record)
+	}
+
+	params := EntityExecutionParams{
+		EntityID:    entityID,
+		AgentID:     opts.AgentID,
+		ServiceKey:  opts.ServiceKey,
+		Client:      client,
+		Log:         log,
+		TriggeredBy: "cli:run-complete",
+	}
+	recordAudit(params, &entityResp.Entity, executionID, completeReq.Status, 0, 0, nil)
+
+	if _, err := client.CompleteExecution(executionID, completeReq, opts.ServiceKey); err != nil {
+		log.Error("Failed to complete execution", err)
+		return fmt.Errorf("failed to complete execution: %w", err)
+	}
+
+	result := runCompleteResult{
+		EntityID:    entityID,
+		ExecutionID: executionID,
+		Status:      completeReq.Status,
+		Validations: completeReq.ValidationRecords,
+	}
+	return output.Render(os.Stdout, format, result, func() error {
+		fmt.Println(output.OK(fmt.Sprintf("Completed '%s'", entityResp.Entity.Title)))
+		table := output.NewTable()
+		table.Indent = "  "
+		table.Row("Execution ID", executionID)
+		for _, r := range result.Validations {
+			table.Row(r.ValidationType, string(r.Outcome))
+		}
+		return table.Render(os.Stdout)
+	})
+}
+
+// validateSuppliedOutputs checks caller-supplied outputs against the
+// entity's output_schema. Returns nil when there is no schema.
+func validateSuppliedOutputs(structured, schema map[string]interface{}) *api.ValidationRecord {
+	if len(schema) == 0 {
+		return nil
+	}
+	if err := validator.ValidateOutputs(structured, schema); err != nil {
+		failReason := err.Error()
+		return &api.ValidationRecord{
+			ValidationType: "OUTPUT_SCHEMA",
+			Outcome:        api.ValidationOutcomeFail,
+			Severity:       api.ValidationSeverityWarning,
+			Target:         "output_schema",
+			Actual:         structured,
+			FailureReason:  &failReason,
+		}
+	}
+	return &api.ValidationRecord{
+		ValidationType: "OUTPUT_SCHEMA",
+		Outcome:        api.ValidationOutcomePass,
+		Severity:       api.ValidationSeverityInfo,
+		Target:         "output_schema",
+		Actual:         structured,
+	}
+}
+
+// readOutputsArg parses an --outputs value: inline JSON, @file, or - for
+// stdin. An empty value means no structured outputs; non-object values are
+// wrapped with validator.WrapStructured.
+func readOutputsArg(value string) (map[string]interface{}, error) {
+	var data []byte
+	switch {
+	case value == "":
+		return nil, nil
+	case value == "-":
+		b, err := io.ReadAll(os.Stdin)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read outputs from stdin: %w", err)
+		}
+		data = b
+	case strings.HasPrefix(value, "@"):
+		b, err := os.ReadFile(value[1:])
+		if err != nil {
+			return nil, fmt.Errorf("failed to read outputs file: %w", err)
+		}
+		data = b
+	default:
+		data = []byte(value)
+	}
+
+	var outputs interface{}
+	if err := json.Unmarshal(data, &outputs); err != nil {
+		return nil, fmt.Errorf("outputs must be valid JSON: %w", err)
+	}
+	if outputs == nil {
+		return nil, fmt.Errorf("outputs must not be null")
+	}
+	return validator.WrapStructured(outputs), nil
+}
@@ -0,0 +1,82 @@
+package cmd
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/kindship-ai/kindship-cli/internal/api"
+	"github.com/kindship-ai/kindship-cli/internal/boundaries"
+	"github.com/spf13/cobra"
+)
+
+// injectSecrets is the --secrets value
+var injectSecrets []string
+
+// secretsFlagHelp documents --secrets in command help
+const secretsFlagHelp = `Secrets (BASH, TEST, PYTHON and executor plugins):
+  --secrets  Agent secrets to inject into the task's environment
+             (comma-separated); entities add more with boundaries.secrets.
+             Values are redacted from output reported to the API.`
+
+// addSecretsFlag registers --secrets on cmd
+func addSecretsFlag(cmd *cobra.Command) {
+	cmd.Flags().StringSliceVar(&injectSecrets, "secrets", nil, "Agent secrets to inject into task environments (comma-separated)")
+}
+
+// secretsFlag validates the --secrets names and returns them
+func secretsFlag() ([]string, error) {
+	if err := boundaries.SecretList(injectSecrets).Validate(); err != nil {
+		return nil, withExitCode(ExitUsage, fmt.Errorf("invalid --secrets: %w", err))
+	}
+	return append([]string(nil), injectSecrets...), nil
+}
+
+// injectsSecrets reports whether mode runs task code that receives secrets.
+// LLM modes get their credentials through 'kindship auth' instead.
+func injectsSecrets(mode api.ExecutionMode) bool {
+	switch mode {
+	case api.ExecutionModeLLMReasoning, api.ExecutionModeHybrid, api.ExecutionModeAskUser, api.ExecutionModeOrchestrate:
+		return false
+	default:
+		return true
+	}
+}
+
+// requestedSecrets returns the injected --secrets names plus the entity's
+// own, without duplicates
+func requestedSecrets(injected []string, entitySecrets boundaries.SecretList) []string {
+	seen := map[string]bool{}
+	var names []string
+	for _, name := range append(append([]string(nil), injected...), entitySecrets...) {
+		if !seen[name] {
+			seen[name] = true
+			names = append(names, name)
+		}
+	}
+	return names
+}
+
+// fetchExecutionSecrets fetches the agent's secrets and returns the named
+// ones. Names the agent has no secret for are an error.
+func fetchExecutionSecrets(params EntityExecutionParams, mode api.ExecutionMode, names []string) (map[string]string, error) {
+	all, err := params.Client.FetchSecrets(params.AgentID, strings.ToLower(string(mode)), params.ServiceKey)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch secrets: %w", err)
+	}
+	secrets := make(map[string]string, len(names))
+	var missing []string
+	for _, name := range names {
+		value, ok := all[name]
+		if !ok {
+			missing = append(missing, name)
+			continue
+		}
+		secrets[name] = value
+	}
+	if len(missing) > 0 {
+		sort.Strings(missing)
+		return nil, fmt.Errorf("secrets not available to agent %s: %s", params.AgentID, strings.Join(missing, ", "))
+	}
+	return secrets, nil
+}
@@ -0,0 +1,184 @@
+package cmd
+
+import (
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/kindship-ai/kindship-cli/internal/api"
+	"github.com/kindship-ai/kindship-cli/internal/config"
+	"github.com/kindship-ai/kindship-cli/internal/output"
+
+	"github.com/spf13/cobra"
+)
+
+var configSelfHostedCmd = &cobra.Command{
+	Use:   "self-hosted",
+	Short: "Manage the self-hosted profile",
+	Long: `Point the CLI at an on-prem Kindship deployment instead of kindship.ai.
+
+While the self-hosted profile is active:
+  - its API URL is the default for every command (KINDSHIP_API_URL, --api-url
+    and the URL saved at login still take precedence)
+  - 'kindship update' downloads from the profile's update URL, and refuses
+    to run without one
+  - hosted-only integrations are off: logs are not shipped to Axiom and no
+    telemetry is sent
+
+In agent containers, KINDSHIP_SELF_HOSTED_URL (and KINDSHIP_UPDATE_URL)
+activate the profile without a config file.
+
+Subcommands:
+  enable   Activate the profile for an API URL
+  disable  Return to kindship.ai
+  show     Print the active profile`,
+}
+
+var configSelfHostedEnableCmd = &cobra.Command{
+	Use:   "enable <api-url>",
+	Short: "Activate the self-hosted profile",
+	Long: `Activate the self-hosted profile for the API at api-url.
+
+The URL must be an absolute https URL (http only for localhost) and the API
+must answer there, unless --skip-check is given.
+
+Examples:
+  kindship config self-hosted enable https://kindship.internal.example.com
+  kindship config self-hosted enable https://kindship.internal.example.com \
+    --update-url https://kindship.internal.example.com/cli/kindship`,
+	Args:         usageArgs(cobra.ExactArgs(1)),
+	SilenceUsage: true,
+	RunE:         runConfigSelfHostedEnable,
+}
+
+var configSelfHostedDisableCmd = &cobra.Command{
+	Use:          "disable",
+	Short:        "Deactivate the self-hosted profile",
+	Args:         usageArgs(cobra.NoArgs),
+	SilenceUsage: true,
+	RunE:         runConfigSelfHostedDisable,
+}
+
+var configSelfHostedShowCmd = &cobra.Command{
+	Use:          "show",
+	Short:        "Print the active self-hosted profile",
+	Args:         usageArgs(cobra.NoArgs),
+	SilenceUsage: true,
+	RunE:         runConfigSelfHostedShow,
+}
+
+var (
+	selfHostedUpdateURL string
+	selfHostedSkipCheck bool
+)
+
+// selfHostedCheckTimeout bounds the reachability check of enable
+const selfHostedCheckTimeout = 10 * time.Second
+
+func init() {
+	configSelfHostedEnableCmd.Flags().StringVar(&selfHostedUpdateURL, "update-url", "", "URL serving the CLI binary for 'kindship update' (empty disables updates)")
+	configSelfHostedEnableCmd.Flags().BoolVar(&selfHostedSkipCheck, "skip-check", false, "Do not check that the API answers at the URL")
+
+	configSelfHostedCmd.AddCommand(configSelfHostedEnableCmd)
+	configSelfHostedCmd.AddCommand(configSelfHostedDisableCmd)
+	configSelfHostedCmd.AddCommand(configSelfHostedShowCmd)
+	configCmd.AddCommand(configSelfHostedCmd)
+}
+
+func runConfigSelfHostedEnable(cmd *cobra.Command, args []string) error {
+	apiURL, err := config.ValidateBaseURL(args[0])
+	if err != nil {
+		return withExitCode(ExitValidation, err)
+	}
+	profile := &config.SelfHostedProfile{APIBaseURL: apiURL}
+	if selfHostedUpdateURL != "" {
+		if profile.UpdateURL, err = config.ValidateBaseURL(selfHostedUpdateURL); err != nil {
+			return withExitCode(ExitValidation, fmt.Errorf("--update-url: %w", err))
+		}
+	}
+	if !selfHostedSkipCheck {
+		if err := checkSelfHostedAPI(apiURL); err != nil {
+			return withExitCode(ExitDependency, fmt.Errorf("%w (pass --skip-check to save the profile anyway)", err))
+		}
+	}
+
+	cfg, err := config.LoadGlobalConfig()
+	if err != nil {
+		return fmt.Errorf("failed to load config: %w", err)
+	}
+	cfg.SelfHosted = profile
+	if err := config.SaveGlobalConfig(cfg); err != nil {
+		return err
+	}
+
+	fmt.Println(output.OK(fmt.Sprintf("Self-hosted profile enabled for %s", apiURL)))
+	if cfg.APIBaseURL != "" && cfg.APIBaseURL != apiURL {
+		fmt.Printf("  Note: you are logged in to %s; run 'kindship login' to log in to %s\n", cfg.APIBaseURL, apiURL)
+	}
+	if profile.UpdateURL == "" {
+		fmt.Println(output.Dim("  'kindship update' is disabled until --update-url is set"))
+	}
+	return nil
+}
+
+func runConfigSelfHostedDisable(cmd *cobra.Command, args []string) error {
+	cfg, err := config.LoadGlobalConfig()
+	if err != nil {
+		return fmt.Errorf("failed to load config: %w", err)
+	}
+	if cfg.SelfHosted == nil {
+		fmt.Println("Self-hosted profile is not enabled")
+		return nil
+	}
+	cfg.SelfHosted = nil
+	if err := config.SaveGlobalConfig(cfg); err != nil {
+		return err
+	}
+	fmt.Println(output.OK(fmt.Sprintf("Self-hosted profile disabled; using %s", config.ProductionAPIBaseURL)))
+	return nil
+}
+
+func runConfigSelfHostedShow(cmd *cobra.Command, args []string) error {
+	profile := config.LoadSelfHostedProfile()
+	if profile == nil {
+		fmt.Printf("Self-hosted profile is not enabled (using %s)\n", config.ProductionAPIBaseURL)
+		return nil
+	}
+	updateURL := profile.UpdateURL
+	if updateURL == "" {
+		updateURL = output.Dim("(none; 'kindship update' disabled)")
+	}
+	fmt.Println(output.Bold("Self-hosted profile"))
+	fmt.Printf("  API URL:    %s\n", profile.APIBaseURL)
+	fmt.Printf("  Update URL: %s\n", updateURL)
+	return nil
+}
+
+// checkSelfHostedAPI checks that a Kindship API answers at apiURL. Any
+// response to the CLI version endpoint other than a server error will do:
+// older servers without it answer 404.
+func checkSelfHostedAPI(apiURL string) error {
+	req, err := http.NewRequest(http.MethodGet, apiURL+"/api/cli/version", nil)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("X-Kindship-CLI-Version", Version)
+
+	resp, err := api.NewHTTPClient(selfHostedCheckTimeout).Do(req)
+	if err != nil {
+		return fmt.Errorf("cannot reach %s: %w", apiURL, err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode >= 500 {
+		return fmt.Errorf("%s answered %s", apiURL, resp.Status)
+	}
+	return nil
+}
+
+// selfHostedURL returns the API URL of cfg's self-hosted profile, if any
+func selfHostedURL(cfg *config.GlobalConfig) string {
+	if cfg.SelfHosted == nil {
+		return ""
+	}
+	return cfg.SelfHosted.APIBaseURL
+}
@@ -11,6 +11,7 @@ import (
 	"strings"
 	"time"
 
+	"github.com/kindship-ai/kindship-cli/internal/api"
 	"github.com/kindship-ai/kindship-cli/internal/auth"
 	"github.com/kindship-ai/kindship-cli/internal/config"
 
@@ -139,6 +140,10 @@ func runSetup(cmd *cobra.Command, args []string) error {
 		AccountID: selectedAgent.AccountID,
 		BoundAt:   time.Now(),
 	}
+	if existingConfig != nil {
+		// Rebinding keeps settings made with 'kindship config set --repo'
+		repoConfig.RepoSettings = existingConfig.RepoSettings
+	}
 
 	if err := config.SaveRepoConfig(repoConfig, repoRoot); err != nil {
 		return fmt.Errorf("failed to save config: %w", err)
@@ -172,10 +177,10 @@ func fetchAgents(ctx *auth.Context) ([]AgentInfo, error) {
 		return nil, err
 	}
 
-	req.Header.Set("Authorization", ctx.GetAuthHeader())
+	ctx.SetAuthHeaders(req)
 	req.Header.Set("X-Kindship-CLI-Version", Version)
 
-	client := &http.Client{Timeout: 30 * time.Second}
+	client := api.NewHTTPClient(30 * time.Second)
 	resp, err := client.Do(req)
 	if err != nil {
 		return nil, err
@@ -3,9 +3,11 @@ package cmd
 import (
 	"fmt"
 	"os"
+	"strings"
 
 	"github.com/kindship-ai/kindship-cli/internal/auth"
 	"github.com/kindship-ai/kindship-cli/internal/config"
+	"github.com/kindship-ai/kindship-cli/internal/output"
 
 	"github.com/spf13/cobra"
 )
@@ -20,143 +22,231 @@ Shows:
 - Current repository binding (if any)
 - Agent information
 
+With --introspect the token's scopes, account memberships and rate-limit
+tier are fetched from the server (see 'kindship whoami'). Without it, the
+scopes recorded at login are shown.
+
 Examples:
   kindship status
+  kindship status --introspect
+  kindship status --format yaml
   kindship status --json`,
 	RunE: runStatus,
 }
 
 var (
-	statusJSON bool
+	statusJSON       bool
+	statusFormat     string
+	statusIntrospect bool
 )
 
 func init() {
-	statusCmd.Flags().BoolVar(&statusJSON, "json", false, "Output in JSON format")
+	statusCmd.Flags().BoolVar(&statusJSON, "json", false, "Output in JSON format (same as --format json)")
+	statusCmd.Flags().StringVar(&statusFormat, "format", "table", output.FormatUsage)
+	statusCmd.Flags().BoolVar(&statusIntrospect, "introspect", false, "Fetch the token's scopes, memberships and rate-limit tier from the server")
 	rootCmd.AddCommand(statusCmd)
 }
 
 type StatusOutput struct {
-	Authenticated  bool   `json:"authenticated"`
-	AuthMethod     string `json:"auth_method,omitempty"`
-	UserEmail      string `json:"user_email,omitempty"`
-	UserID         string `json:"user_id,omitempty"`
-	TokenPrefix    string `json:"token_prefix,omitempty"`
-	TokenExpiry    string `json:"token_expiry,omitempty"`
-	InRepo         bool   `json:"in_repo"`
-	RepoRoot       string `json:"repo_root,omitempty"`
-	AgentID        string `json:"agent_id,omitempty"`
-	AgentSlug      string `json:"agent_slug,omitempty"`
-	AccountID      string `json:"account_id,omitempty"`
-	BoundAt        string `json:"bound_at,omitempty"`
-	APIBaseURL     string `json:"api_base_url,omitempty"`
-	HooksInstalled bool   `json:"hooks_installed"`
-	Error          string `json:"error,omitempty"`
+	Authenticated  bool                `json:"authenticated"`
+	AuthMethod     string              `json:"auth_method,omitempty"`
+	UserEmail      string              `json:"user_email,omitempty"`
+	UserID         string              `json:"user_id,omitempty"`
+	LoginAccount   string              `json:"login_account,omitempty"`
+	LoginAccountID string              `json:"login_account_id,omitempty"`
+	TokenPrefix    string              `json:"token_prefix,omitempty"`
+	TokenExpiry    string              `json:"token_expiry,omitempty"`
+	Scopes         []string            `json:"scopes,omitempty"`
+	RateLimitTier  string              `json:"rate_limit_tier,omitempty"`
+	Memberships    []AccountMembership `json:"memberships,omitempty"`
+	// IntrospectError is why --introspect could not fetch the above
+	IntrospectError string `json:"introspect_error,omitempty"`
+	InRepo          bool   `json:"in_repo"`
+	RepoRoot        string `json:"repo_root,omitempty"`
+	AgentID         string `json:"agent_id,omitempty"`
+	AgentSlug       string `json:"agent_slug,omitempty"`
+	AccountID       string `json:"account_id,omitempty"`
+	BoundAt         string `json:"bound_at,omitempty"`
+	APIBaseURL      string `json:"api_base_url,omitempty"`
+	HooksInstalled  bool   `json:"hooks_installed"`
+	Error           string `json:"error,omitempty"`
 }
 
 func runStatus(cmd *cobra.Command, args []string) error {
-	output := StatusOutput{}
+	status := StatusOutput{}
 
 	// Check authentication
 	ctx := auth.GetAuthContextOrNil()
 	if ctx != nil {
-		output.Authenticated = true
-		output.AuthMethod = string(ctx.Method)
-		output.UserEmail = ctx.UserEmail
-		output.UserID = ctx.UserID
-		output.TokenPrefix = ctx.TokenPrefix
-		output.APIBaseURL = ctx.APIBaseURL
+		status.Authenticated = true
+		status.AuthMethod = string(ctx.Method)
+		status.UserEmail = ctx.UserEmail
+		status.UserID = ctx.UserID
+		status.LoginAccount = ctx.AccountName
+		status.LoginAccountID = ctx.AccountID
+		status.TokenPrefix = ctx.TokenPrefix
+		status.APIBaseURL = ctx.APIBaseURL
 		if !ctx.TokenExpiry.IsZero() {
-			output.TokenExpiry = ctx.TokenExpiry.Format("2006-01-02 15:04:05")
+			status.TokenExpiry = ctx.TokenExpiry.Format("2006-01-02 15:04:05")
+		}
+		status.Scopes = ctx.Scopes
+		status.RateLimitTier = ctx.RateLimitTier
+
+		if statusIntrospect {
+			introspection, err := fetchIntrospection(ctx)
+			if err != nil {
+				status.IntrospectError = err.Error()
+			} else {
+				status.Scopes = introspection.Scopes
+				status.RateLimitTier = introspection.RateLimitTier
+				status.Memberships = introspection.Memberships
+			}
 		}
 	}
 
 	// Check repository
 	repoRoot, err := config.FindRepoRoot()
 	if err == nil {
-		output.InRepo = true
-		output.RepoRoot = repoRoot
+		status.InRepo = true
+		status.RepoRoot = repoRoot
 
 		// Check for kindship config
 		repoConfig, err := config.LoadRepoConfig()
 		if err == nil {
-			output.AgentID = repoConfig.AgentID
-			output.AgentSlug = repoConfig.AgentSlug
-			output.AccountID = repoConfig.AccountID
+			status.AgentID = repoConfig.AgentID
+			status.AgentSlug = repoConfig.AgentSlug
+			status.AccountID = repoConfig.AccountID
 			if !repoConfig.BoundAt.IsZero() {
-				output.BoundAt = repoConfig.BoundAt.Format("2006-01-02 15:04:05")
+				status.BoundAt = repoConfig.BoundAt.Format("2006-01-02 15:04:05")
 			}
 		}
 
 		// Check for Claude Code hooks
-		output.HooksInstalled = checkHooksInstalled(repoRoot)
+		status.HooksInstalled = checkHooksInstalled(repoRoot)
 	}
 
 	if statusJSON {
-		return printJSON(output)
+		statusFormat = string(output.FormatJSON)
+	}
+	format, err := output.ParseFormat(statusFormat)
+	if err != nil {
+		return err
 	}
+	return output.Render(os.Stdout, format, status, func() error {
+		return renderStatusTable(status)
+	})
+}
 
-	// Human-readable output
-	fmt.Println("Kindship CLI Status")
-	fmt.Println("===================")
+// renderStatusTable prints the human-readable status report
+func renderStatusTable(status StatusOutput) error {
+	fmt.Println(output.Bold("Kindship CLI Status"))
 	fmt.Println()
 
 	// Authentication section
 	fmt.Println("Authentication:")
-	if output.Authenticated {
-		if output.AuthMethod == "oauth" {
-			fmt.Printf("  ✓ Logged in as %s\n", output.UserEmail)
-			if output.TokenPrefix != "" {
-				fmt.Printf("  Token: %s...\n", output.TokenPrefix)
+	authTable := output.NewTable()
+	authTable.Indent = "  "
+	if status.Authenticated {
+		if status.AuthMethod == "oauth" {
+			authTable.Row("Status", output.OK("Logged in as "+status.UserEmail))
+			if status.LoginAccount != "" {
+				authTable.Row("Account", status.LoginAccount)
 			}
-			if output.TokenExpiry != "" {
-				fmt.Printf("  Token expires: %s\n", output.TokenExpiry)
+			if status.TokenPrefix != "" {
+				authTable.Row("Token", status.TokenPrefix+"...")
+			}
+			if status.TokenExpiry != "" {
+				authTable.Row("Token expires", status.TokenExpiry)
 			}
 		} else {
-			fmt.Println("  ✓ Running in container mode (service key)")
+			authTable.Row("Status", output.OK("Running in container mode (service key)"))
+		}
+		if len(status.Scopes) > 0 {
+			authTable.Row("Scopes", strings.Join(status.Scopes, ", "))
+		}
+		if status.RateLimitTier != "" {
+			authTable.Row("Rate limit tier", status.RateLimitTier)
+		}
+		if status.IntrospectError != "" {
+			authTable.Row("Introspection", output.Fail(status.IntrospectError))
 		}
 	} else {
-		fmt.Println("  ✗ Not authenticated")
-		fmt.Println("    Run 'kindship login' to authenticate")
+		authTable.Row("Status", output.Fail("Not authenticated")+output.Dim(" (run 'kindship login')"))
+	}
+	if err := authTable.Render(os.Stdout); err != nil {
+		return err
 	}
 	fmt.Println()
 
+	// Memberships section (--introspect only)
+	if len(status.Memberships) > 0 {
+		fmt.Println("Account memberships:")
+		membersTable := output.NewTable("Account", "Role", "")
+		membersTable.Indent = "  "
+		for _, m := range status.Memberships {
+			name := m.Name
+			if name == "" {
+				name = m.ID
+			}
+			if m.Slug != "" {
+				name += " (" + m.Slug + ")"
+			}
+			selected := ""
+			if m.ID != "" && m.ID == status.LoginAccountID {
+				selected = output.Dim("selected")
+			}
+			membersTable.Row(name, m.Role, selected)
+		}
+		if err := membersTable.Render(os.Stdout); err != nil {
+			return err
+		}
+		fmt.Println()
+	}
+
 	// Repository section
 	fmt.Println("Repository:")
-	if output.InRepo {
-		fmt.Printf("  ✓ Git repository: %s\n", output.RepoRoot)
-
-		if output.AgentID != "" {
-			fmt.Printf("  ✓ Agent bound: %s\n", output.AgentID)
-			if output.AgentSlug != "" {
-				fmt.Printf("    Slug: %s\n", output.AgentSlug)
+	repoTable := output.NewTable()
+	repoTable.Indent = "  "
+	if status.InRepo {
+		repoTable.Row("Git repository", output.OK(status.RepoRoot))
+		if status.AgentID != "" {
+			repoTable.Row("Agent", output.OK(status.AgentID))
+			if status.AgentSlug != "" {
+				repoTable.Row("Slug", status.AgentSlug)
 			}
-			if output.BoundAt != "" {
-				fmt.Printf("    Bound at: %s\n", output.BoundAt)
+			if status.BoundAt != "" {
+				repoTable.Row("Bound at", status.BoundAt)
 			}
 		} else {
-			fmt.Println("  ✗ No agent configured")
-			fmt.Println("    Run 'kindship setup' to link an agent")
+			repoTable.Row("Agent", output.Fail("No agent configured")+output.Dim(" (run 'kindship setup')"))
 		}
 	} else {
-		fmt.Println("  ✗ Not in a git repository")
+		repoTable.Row("Git repository", output.Fail("Not in a git repository"))
+	}
+	if err := repoTable.Render(os.Stdout); err != nil {
+		return err
 	}
 	fmt.Println()
 
 	// Hooks section
-	if output.InRepo {
+	if status.InRepo {
 		fmt.Println("Claude Code Integration:")
-		if output.HooksInstalled {
-			fmt.Println("  ✓ Hooks installed")
+		hooksTable := output.NewTable()
+		hooksTable.Indent = "  "
+		if status.HooksInstalled {
+			hooksTable.Row("Hooks", output.OK("Installed"))
 		} else {
-			fmt.Println("  ✗ Hooks not installed")
-			fmt.Println("    Run 'kindship setup' to install hooks")
+			hooksTable.Row("Hooks", output.Fail("Not installed")+output.Dim(" (run 'kindship setup')"))
+		}
+		if err := hooksTable.Render(os.Stdout); err != nil {
+			return err
 		}
 		fmt.Println()
 	}
 
 	// API section
-	if output.APIBaseURL != "" {
-		fmt.Printf("API: %s\n", output.APIBaseURL)
+	if status.APIBaseURL != "" {
+		fmt.Printf("API: %s\n", status.APIBaseURL)
 	}
 
 	return nil
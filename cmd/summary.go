@@ -0,0 +1,160 @@
+package cmd
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/kindship-ai/kindship-cli/internal/api"
+	"github.com/kindship-ai/kindship-cli/internal/logging"
+)
+
+// Destinations for the digest of an ORCHESTRATE run, set by
+// 'kindship run --summary-url/--summary-file'
+var (
+	summaryURL  string
+	summaryFile string
+)
+
+// webhookTimeout bounds a webhook request
+const webhookTimeout = 10 * time.Second
+
+// ProcessSummaryTask is one child task executed by an ORCHESTRATE run
+type ProcessSummaryTask struct {
+	ID            string `json:"id"`
+	Title         string `json:"title"`
+	Status        string `json:"status"`
+	DurationMS    int64  `json:"duration_ms"`
+	FailureReason string `json:"failure_reason,omitempty"`
+}
+
+// ProcessSummary is the digest of an ORCHESTRATE run delivered to
+// --summary-url and --summary-file
+type ProcessSummary struct {
+	EntityID      string                     `json:"entity_id"`
+	RunID         string                     `json:"run_id"`
+	AgentID       string                     `json:"agent_id"`
+	Status        api.ExecutionAttemptStatus `json:"status"`
+	FailureReason string                     `json:"failure_reason,omitempty"`
+	StartedAt     time.Time                  `json:"started_at"`
+	FinishedAt    time.Time                  `json:"finished_at"`
+	DurationMS    int64                      `json:"duration_ms"`
+	TasksExecuted int                        `json:"tasks_executed"`
+	Tasks         []ProcessSummaryTask       `json:"tasks"`
+	Failed        []ProcessSummaryTask       `json:"failed"`
+}
+
+// summaryEnabled reports whether a run summary will be delivered
+func summaryEnabled() bool {
+	return summaryURL != "" || summaryFile != ""
+}
+
+// lastFailureReason returns the failure reason of the most recent attempt
+// of an entity, or a generic reason when the API cannot tell
+func lastFailureReason(client *api.Client, entityID, serviceKey string) string {
+	attempts, err := client.ListAttempts(entityID, serviceKey)
+	if err == nil {
+		var latest *api.ExecutionAttempt
+		for i := range attempts.Attempts {
+			a := &attempts.Attempts[i]
+			if latest == nil || a.StartedAt.After(latest.StartedAt) {
+				latest = a
+			}
+		}
+		if latest != nil && latest.FailureReason != nil {
+			return *latest.FailureReason
+		}
+	}
+	return "execution failed"
+}
+
+// deliverProcessSummary writes the summary to --summary-file and posts it
+// to --summary-url. Delivery problems are logged and never change the
+// outcome of the run.
+func deliverProcessSummary(summary ProcessSummary, log *logging.Logger) {
+	if !summaryEnabled() {
+		return
+	}
+	if summary.Tasks == nil {
+		summary.Tasks = []ProcessSummaryTask{}
+	}
+	summary.Failed = []ProcessSummaryTask{}
+	for _, task := range summary.Tasks {
+		if task.Status == string(api.ExecutionAttemptStatusFailed) {
+			summary.Failed = append(summary.Failed, task)
+		}
+	}
+
+	data, err := json.MarshalIndent(summary, "", "  ")
+	if err != nil {
+		log.Warn("Failed to encode run summary", map[string]interface{}{"error": err.Error()})
+		return
+	}
+
+	if summaryFile != "" {
+		if err := writeSummaryFile(summaryFile, data); err != nil {
+			log.Warn("Failed to write run summary", map[string]interface{}{
+				"path":  summaryFile,
+				"error": err.Error(),
+			})
+		} else {
+			log.Info("Wrote run summary", map[string]interface{}{"path": summaryFile})
+		}
+	}
+
+	if summaryURL != "" {
+		if err := postWebhook(summaryURL, data); err != nil {
+			log.Warn("Failed to post run summary", map[string]interface{}{
+				"url":   summaryURL,
+				"error": err.Error(),
+			})
+		} else {
+			log.Info("Posted run summary", map[string]interface{}{"url": summaryURL})
+		}
+	}
+}
+
+// writeSummaryFile replaces path with data through a temporary file so
+// readers never see a partial summary
+func writeSummaryFile(path string, data []byte) error {
+	tmp, err := os.CreateTemp(filepath.Dir(path), ".kindship-summary-*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+	if _, err := tmp.Write(append(data, '\n')); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	if err := os.Chmod(tmp.Name(), 0644); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), path)
+}
+
+// postWebhook posts a JSON payload to a webhook URL
+func postWebhook(url string, data []byte) error {
+	req, err := http.NewRequest(http.MethodPost, url, bytes.NewReader(data))
+	if err != nil {
+		return fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", "kindship-cli/1.0")
+
+	resp, err := api.NewHTTPClient(webhookTimeout).Do(req)
+	if err != nil {
+		return fmt.Errorf("request failed: %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return fmt.Errorf("webhook responded with %d", resp.StatusCode)
+	}
+	return nil
+}
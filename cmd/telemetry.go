@@ -0,0 +1,227 @@
+package cmd
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"os"
+	"runtime"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/kindship-ai/kindship-cli/internal/api"
+	"github.com/kindship-ai/kindship-cli/internal/config"
+	"github.com/kindship-ai/kindship-cli/internal/logging"
+	"github.com/kindship-ai/kindship-cli/internal/output"
+
+	"github.com/spf13/cobra"
+)
+
+var telemetryCmd = &cobra.Command{
+	Use:   "telemetry",
+	Short: "Manage anonymous usage telemetry",
+	Long: `Telemetry is off unless you turn it on. When on, each command sends one
+event when it ends so the maintainers can see which commands and execution
+modes are used. An event holds only:
+  - the command name (e.g. "plan submit"), never its arguments
+  - how long it took, whether it succeeded and its exit code
+  - the CLI version, operating system and architecture
+  - how many tasks of each execution mode it executed
+
+No identifiers (user, account, agent, entity, host), inputs, outputs,
+code or file paths are sent.
+
+KINDSHIP_TELEMETRY=on|off overrides the setting, e.g. for containers, and
+DO_NOT_TRACK=1 or a self-hosted profile always turn telemetry off.
+
+Subcommands:
+  on      Turn telemetry on
+  off     Turn telemetry off
+  status  Show whether telemetry is on and why`,
+}
+
+var telemetryOnCmd = &cobra.Command{
+	Use:          "on",
+	Short:        "Turn anonymous usage telemetry on",
+	Args:         usageArgs(cobra.NoArgs),
+	SilenceUsage: true,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return setTelemetry(true)
+	},
+}
+
+var telemetryOffCmd = &cobra.Command{
+	Use:          "off",
+	Short:        "Turn anonymous usage telemetry off",
+	Args:         usageArgs(cobra.NoArgs),
+	SilenceUsage: true,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return setTelemetry(false)
+	},
+}
+
+var telemetryStatusCmd = &cobra.Command{
+	Use:          "status",
+	Short:        "Show whether anonymous usage telemetry is on",
+	Args:         usageArgs(cobra.NoArgs),
+	SilenceUsage: true,
+	RunE:         runTelemetryStatus,
+}
+
+func init() {
+	telemetryCmd.AddCommand(telemetryOnCmd)
+	telemetryCmd.AddCommand(telemetryOffCmd)
+	telemetryCmd.AddCommand(telemetryStatusCmd)
+	rootCmd.AddCommand(telemetryCmd)
+}
+
+// telemetryTimeout bounds how long a command's exit waits for its event
+const telemetryTimeout = 2 * time.Second
+
+// TelemetryEvent is sent when a command ends with telemetry on. It must
+// never carry identifiers or payloads.
+type TelemetryEvent struct {
+	Command        string         `json:"command"`
+	DurationMS     int64          `json:"duration_ms"`
+	Success        bool           `json:"success"`
+	ExitCode       int            `json:"exit_code"`
+	Version        string         `json:"version"`
+	OS             string         `json:"os"`
+	Arch           string         `json:"arch"`
+	ExecutionModes map[string]int `json:"execution_modes,omitempty"`
+}
+
+var (
+	telemetryMu    sync.Mutex
+	telemetryModes = map[string]int{}
+)
+
+// recordTelemetryMode counts an execution of mode for the command's event
+func recordTelemetryMode(mode api.ExecutionMode) {
+	telemetryMu.Lock()
+	defer telemetryMu.Unlock()
+	telemetryModes[string(mode)]++
+}
+
+// telemetryEnabled reports whether telemetry is on and what decided it
+func telemetryEnabled() (bool, string) {
+	if v := os.Getenv("DO_NOT_TRACK"); v != "" && v != "0" {
+		return false, "DO_NOT_TRACK"
+	}
+	if config.LoadSelfHostedProfile() != nil {
+		return false, "self-hosted profile"
+	}
+	switch strings.ToLower(os.Getenv("KINDSHIP_TELEMETRY")) {
+	case "1", "on", "true":
+		return true, "KINDSHIP_TELEMETRY"
+	case "0", "off", "false":
+		return false, "KINDSHIP_TELEMETRY"
+	}
+	cfg, err := config.LoadGlobalConfig()
+	if err != nil || cfg == nil {
+		return false, "default"
+	}
+	if cfg.Telemetry {
+		return true, "kindship telemetry on"
+	}
+	return false, "default"
+}
+
+func setTelemetry(on bool) error {
+	cfg, err := config.LoadGlobalConfig()
+	if err != nil {
+		return fmt.Errorf("failed to load config: %w", err)
+	}
+	cfg.Telemetry = on
+	if err := config.SaveGlobalConfig(cfg); err != nil {
+		return err
+	}
+
+	if on {
+		fmt.Println(output.OK("Telemetry is on. Thank you!"))
+	} else {
+		fmt.Println(output.OK("Telemetry is off"))
+	}
+	if enabled, source := telemetryEnabled(); enabled != on {
+		fmt.Printf("  Note: %s overrides this setting\n", source)
+	}
+	return nil
+}
+
+func runTelemetryStatus(cmd *cobra.Command, args []string) error {
+	enabled, source := telemetryEnabled()
+	state := "off"
+	if enabled {
+		state = "on"
+	}
+	fmt.Printf("Telemetry is %s (%s)\n", output.Bold(state), source)
+	fmt.Println(output.Dim("Run 'kindship telemetry --help' for what is collected"))
+	return nil
+}
+
+// reportTelemetry sends the event of a finished command when telemetry is
+// on. Failures are only logged at debug level; telemetry never changes a
+// command's outcome.
+func reportTelemetry(cmd *cobra.Command, start time.Time, err error) {
+	if cmd == nil {
+		return
+	}
+	if enabled, _ := telemetryEnabled(); !enabled {
+		return
+	}
+
+	telemetryMu.Lock()
+	modes := telemetryModes
+	telemetryModes = map[string]int{}
+	telemetryMu.Unlock()
+
+	// Commands are named without the root ("plan submit"); the root itself,
+	// run bare or with an unknown command, keeps its name
+	name := strings.TrimSpace(strings.TrimPrefix(cmd.CommandPath(), rootCmd.Name()))
+	if name == "" {
+		name = rootCmd.Name()
+	}
+	event := TelemetryEvent{
+		Command:    name,
+		DurationMS: time.Since(start).Milliseconds(),
+		Success:    err == nil,
+		ExitCode:   ExitCode(err),
+		Version:    Version,
+		OS:         runtime.GOOS,
+		Arch:       runtime.GOARCH,
+	}
+	if len(modes) > 0 {
+		event.ExecutionModes = modes
+	}
+	if sendErr := sendTelemetry(event); sendErr != nil {
+		logging.Get().Debug("Failed to send telemetry", map[string]interface{}{
+			"error": sendErr.Error(),
+		})
+	}
+}
+
+// sendTelemetry posts an event to the API without credentials
+func sendTelemetry(event TelemetryEvent) error {
+	data, err := json.Marshal(event)
+	if err != nil {
+		return err
+	}
+	req, err := http.NewRequest(http.MethodPost, defaultAPIBaseURL()+"/api/cli/telemetry", bytes.NewReader(data))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("X-Kindship-CLI-Version", Version)
+
+	resp, err := api.NewHTTPClient(telemetryTimeout).Do(req)
+	if err != nil {
+		return err
+	}
+	resp.Body.Close()
+	if resp.StatusCode >= 300 {
+		return fmt.Errorf("telemetry endpoint returned %s", resp.Status)
+	}
+	return nil
+}
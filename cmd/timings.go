@@ -0,0 +1,45 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/kindship-ai/kindship-cli/internal/logging"
+	"github.com/kindship-ai/kindship-cli/internal/output"
+)
+
+// timingsUsage is the description of the --timings flag
+const timingsUsage = "Print a breakdown of where time was spent when the command ends"
+
+// showTimings is the --timings flag of run and agent loop
+var showTimings bool
+
+// printTimings writes the durations accumulated by log's WithDuration calls
+// to stderr, followed by the wall time since start. Must be deferred right
+// after logging.Init so every phase is recorded.
+func printTimings(log *logging.Logger, start time.Time) {
+	timings := log.Timings()
+	wall := time.Since(start)
+
+	fmt.Fprintf(os.Stderr, "\n%s\n", output.Bold("Timings"))
+	table := output.NewTable("Phase", "Count", "Total", "Share")
+	table.Indent = "  "
+	for _, t := range timings {
+		share := ""
+		if wall > 0 {
+			share = fmt.Sprintf("%.0f%%", 100*float64(t.Total)/float64(wall))
+		}
+		table.Row(t.Name, fmt.Sprintf("%d", t.Count), formatTiming(t.Total), share)
+	}
+	table.Row(output.Bold("Total"), "", output.Bold(formatTiming(wall)), "")
+	table.Render(os.Stderr)
+}
+
+// formatTiming rounds d for display
+func formatTiming(d time.Duration) string {
+	if d < time.Second {
+		return d.Round(time.Millisecond).String()
+	}
+	return d.Round(10 * time.Millisecond).String()
+}
@@ -6,7 +6,11 @@ import (
 	"net/http"
 	"os"
 	"os/exec"
+	"path/filepath"
 	"runtime"
+	"strings"
+
+	"github.com/kindship-ai/kindship-cli/internal/config"
 
 	"github.com/spf13/cobra"
 )
@@ -14,11 +18,21 @@ import (
 // Binary download URL base - proxied through kindship.ai
 const binaryBaseURL = "https://kindship.ai/cli/kindship"
 
-// getBinaryURL returns the platform-specific download URL
-func getBinaryURL() string {
-	os := runtime.GOOS
-	arch := runtime.GOARCH
-	return fmt.Sprintf("%s?os=%s&arch=%s", binaryBaseURL, os, arch)
+// getBinaryURL returns the platform-specific download URL. KINDSHIP_UPDATE_URL
+// or the self-hosted profile's update URL replace the kindship.ai proxy; a
+// self-hosted profile without one has no download URL.
+func getBinaryURL() (string, error) {
+	base := binaryBaseURL
+	if profile := config.LoadSelfHostedProfile(); profile != nil {
+		base = profile.UpdateURL
+	}
+	if url := os.Getenv("KINDSHIP_UPDATE_URL"); url != "" {
+		base = url
+	}
+	if base == "" {
+		return "", withExitCode(ExitUsage, fmt.Errorf("no update URL is configured for the self-hosted profile (set one with 'kindship config self-hosted enable <api-url> --update-url <url>' or KINDSHIP_UPDATE_URL)"))
+	}
+	return fmt.Sprintf("%s?os=%s&arch=%s", base, runtime.GOOS, runtime.GOARCH), nil
 }
 
 var updateCmd = &cobra.Command{
@@ -26,10 +40,66 @@ var updateCmd = &cobra.Command{
 	Short: "Update kindship CLI to latest version",
 	Long: `Download and install the latest version of the kindship CLI.
 
+Binaries installed by Homebrew, apt or Scoop are not replaced in place:
+doing so would leave the package manager's records out of sync with the
+file on disk. The command prints the package manager's upgrade command
+instead. Pass --force to replace the binary anyway.
+
 Example:
-  kindship update`,
-	Args: cobra.NoArgs,
-	RunE: runUpdate,
+  kindship update
+  kindship update --force`,
+	Args:         cobra.NoArgs,
+	SilenceUsage: true,
+	RunE:         runUpdate,
+}
+
+var updateForce bool
+
+// packageManager is a package manager that may own the kindship binary
+type packageManager struct {
+	Name    string
+	Upgrade string
+}
+
+var (
+	homebrew = &packageManager{Name: "homebrew", Upgrade: "brew upgrade kindship-ai/tap/kindship"}
+	apt      = &packageManager{Name: "apt", Upgrade: "sudo apt-get update && sudo apt-get install --only-upgrade kindship"}
+	scoop    = &packageManager{Name: "scoop", Upgrade: "scoop update kindship"}
+)
+
+// detectPackageManager reports the package manager that installed the
+// binary at execPath: the InstallMethod build flag wins, otherwise the
+// install location is checked. Returns nil for direct downloads.
+func detectPackageManager(execPath string) *packageManager {
+	for _, pm := range []*packageManager{homebrew, apt, scoop} {
+		if InstallMethod == pm.Name {
+			return pm
+		}
+	}
+
+	// Homebrew and Scoop expose the binary through symlinks and shims;
+	// judge by the real location
+	if resolved, err := filepath.EvalSymlinks(execPath); err == nil {
+		execPath = resolved
+	}
+	path := filepath.ToSlash(strings.ToLower(execPath))
+	switch {
+	case strings.Contains(path, "/cellar/"), strings.Contains(path, "/homebrew/"), strings.Contains(path, "/linuxbrew/"):
+		return homebrew
+	case strings.Contains(path, "/scoop/apps/"), strings.Contains(path, "/scoop/shims/"):
+		return scoop
+	case runtime.GOOS == "linux" && strings.HasPrefix(path, "/usr/"):
+		// Debian packages list their files under /var/lib/dpkg/info
+		if _, err := os.Stat("/var/lib/dpkg/info/kindship.list"); err == nil {
+			return apt
+		}
+	}
+	return nil
+}
+
+func init() {
+	updateCmd.Flags().BoolVar(&updateForce, "force", false, "Replace the binary even if a package manager installed it")
+	rootCmd.AddCommand(updateCmd)
 }
 
 func runUpdate(cmd *cobra.Command, args []string) error {
@@ -39,8 +109,18 @@ func runUpdate(cmd *cobra.Command, args []string) error {
 		return fmt.Errorf("failed to get executable path: %w", err)
 	}
 
+	if pm := detectPackageManager(execPath); pm != nil {
+		if !updateForce {
+			return withExitCode(ExitUsage, fmt.Errorf("kindship was installed with %s; upgrade it with:\n  %s\n(or pass --force to replace %s anyway)", pm.Name, pm.Upgrade, execPath))
+		}
+		fmt.Fprintf(os.Stderr, "Warning: replacing a binary managed by %s; '%s' may undo this\n", pm.Name, pm.Upgrade)
+	}
+
 	// Get platform-specific download URL
-	downloadURL := getBinaryURL()
+	downloadURL, err := getBinaryURL()
+	if err != nil {
+		return err
+	}
 
 	fmt.Printf("Downloading latest kindship...\n")
 	fmt.Printf("Platform: %s/%s\n", runtime.GOOS, runtime.GOARCH)
@@ -117,7 +197,3 @@ func runUpdate(cmd *cobra.Command, args []string) error {
 	fmt.Println("Update complete!")
 	return nil
 }
-
-func init() {
-	rootCmd.AddCommand(updateCmd)
-}
@@ -18,6 +18,11 @@ var GitCommit = "unknown"
 // BuildDate is set at build time via ldflags
 var BuildDate = "unknown"
 
+// InstallMethod is set at build time via ldflags by package builds
+// (homebrew, apt, scoop) so 'kindship update' leaves them to the package
+// manager. Empty for direct downloads.
+var InstallMethod = ""
+
 var versionCmd = &cobra.Command{
 	Use:   "version",
 	Short: "Display version information",
@@ -42,8 +47,10 @@ type VersionOutput struct {
 	Version   string `json:"version"`
 	GitCommit string `json:"git_commit"`
 	BuildDate string `json:"build_date"`
-	GoVersion string `json:"go_version"`
-	Platform  string `json:"platform"`
+	// InstallMethod is the package manager that installed the binary, if any
+	InstallMethod string `json:"install_method,omitempty"`
+	GoVersion     string `json:"go_version"`
+	Platform      string `json:"platform"`
 }
 
 func runVersion(cmd *cobra.Command, args []string) error {
@@ -54,6 +61,11 @@ func runVersion(cmd *cobra.Command, args []string) error {
 		GoVersion: runtime.Version(),
 		Platform:  fmt.Sprintf("%s/%s", runtime.GOOS, runtime.GOARCH),
 	}
+	if execPath, err := os.Executable(); err == nil {
+		if pm := detectPackageManager(execPath); pm != nil {
+			output.InstallMethod = pm.Name
+		}
+	}
 
 	if versionJSON {
 		return printJSON(output)
@@ -64,6 +76,9 @@ func runVersion(cmd *cobra.Command, args []string) error {
 	fmt.Printf("  Build date: %s\n", BuildDate)
 	fmt.Printf("  Go version: %s\n", runtime.Version())
 	fmt.Printf("  Platform:   %s/%s\n", runtime.GOOS, runtime.GOARCH)
+	if output.InstallMethod != "" {
+		fmt.Printf("  Installed:  via %s\n", output.InstallMethod)
+	}
 
 	return nil
 }
@@ -0,0 +1,188 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/kindship-ai/kindship-cli/internal/api"
+	"github.com/kindship-ai/kindship-cli/internal/config"
+	"github.com/kindship-ai/kindship-cli/internal/logging"
+	"github.com/kindship-ai/kindship-cli/internal/output"
+
+	"github.com/spf13/cobra"
+)
+
+const (
+	// versionCheckFile caches the API's supported versions in the state dir
+	versionCheckFile = "version-check.json"
+	// versionCheckInterval is how often the API is asked, and how often an
+	// unsupported version is warned about
+	versionCheckInterval = 24 * time.Hour
+	// versionCheckTimeout bounds the request so commands are never held up
+	versionCheckTimeout = 2 * time.Second
+)
+
+// versionCheckState is the cached result of the last version check
+type versionCheckState struct {
+	APIURL         string    `json:"api_url"`
+	CheckedAt      time.Time `json:"checked_at"`
+	MinimumVersion string    `json:"minimum_version,omitempty"`
+	LatestVersion  string    `json:"latest_version,omitempty"`
+	WarnedAt       time.Time `json:"warned_at,omitempty"`
+}
+
+// cliVersionResponse is the API's answer to GET /api/cli/version
+type cliVersionResponse struct {
+	MinimumVersion string `json:"minimum_version"`
+	LatestVersion  string `json:"latest_version"`
+}
+
+// checkMinimumVersion warns on stderr, at most once per day, when Version
+// is older than the minimum version the API supports. The API is asked at
+// most once per day per API URL; failures are ignored until the next day.
+// Development builds, 'kindship update' and KINDSHIP_NO_VERSION_CHECK skip
+// the check.
+func checkMinimumVersion(cmd *cobra.Command) {
+	if v := os.Getenv("KINDSHIP_NO_VERSION_CHECK"); v != "" && v != "0" {
+		return
+	}
+	if _, ok := parseVersion(Version); !ok {
+		return
+	}
+	switch cmd.Name() {
+	case "update", "completion", cobra.ShellCompRequestCmd, cobra.ShellCompNoDescRequestCmd:
+		return
+	}
+
+	apiURL := defaultAPIBaseURL()
+	state := loadVersionCheckState()
+	now := time.Now()
+	if state.APIURL != apiURL || now.Sub(state.CheckedAt) >= versionCheckInterval {
+		if state.APIURL != apiURL {
+			state = versionCheckState{APIURL: apiURL}
+		}
+		state.CheckedAt = now
+		if resp, err := fetchCLIVersions(apiURL); err != nil {
+			logging.Get().Debug("Version check failed", map[string]interface{}{
+				"error": err.Error(),
+			})
+		} else {
+			state.MinimumVersion = resp.MinimumVersion
+			state.LatestVersion = resp.LatestVersion
+		}
+		saveVersionCheckState(state)
+	}
+
+	if state.MinimumVersion == "" || compareVersions(Version, state.MinimumVersion) >= 0 {
+		return
+	}
+	if now.Sub(state.WarnedAt) < versionCheckInterval {
+		return
+	}
+	fmt.Fprintln(os.Stderr, output.Yellow(fmt.Sprintf(
+		"Warning: kindship %s is older than %s, the oldest version %s supports. Run 'kindship update' (set KINDSHIP_NO_VERSION_CHECK=1 to silence this).",
+		Version, state.MinimumVersion, apiURL)))
+	state.WarnedAt = now
+	saveVersionCheckState(state)
+}
+
+// fetchCLIVersions asks the API which CLI versions it supports
+func fetchCLIVersions(apiURL string) (*cliVersionResponse, error) {
+	req, err := http.NewRequest(http.MethodGet, apiURL+"/api/cli/version", nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("X-Kindship-CLI-Version", Version)
+
+	resp, err := api.NewHTTPClient(versionCheckTimeout).Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("version endpoint returned %s", resp.Status)
+	}
+	var versions cliVersionResponse
+	if err := json.NewDecoder(resp.Body).Decode(&versions); err != nil {
+		return nil, fmt.Errorf("failed to parse version response: %w", err)
+	}
+	return &versions, nil
+}
+
+// loadVersionCheckState reads the cached check; a missing or unreadable
+// cache is an empty state
+func loadVersionCheckState() versionCheckState {
+	var state versionCheckState
+	dir, err := config.GetStateDir()
+	if err != nil {
+		return state
+	}
+	if data, err := os.ReadFile(filepath.Join(dir, versionCheckFile)); err == nil {
+		_ = json.Unmarshal(data, &state)
+	}
+	return state
+}
+
+// saveVersionCheckState writes the cache, ignoring failures: a read-only
+// state dir only means checking again next time
+func saveVersionCheckState(state versionCheckState) {
+	dir, err := config.GetStateDir()
+	if err != nil {
+		return
+	}
+	if err := os.MkdirAll(dir, config.ConfigDirMode); err != nil {
+		return
+	}
+	data, err := json.MarshalIndent(state, "", "  ")
+	if err != nil {
+		return
+	}
+	_ = os.WriteFile(filepath.Join(dir, versionCheckFile), data, config.ConfigFileMode)
+}
+
+// parseVersion parses "v1.2.3" style versions, ignoring any pre-release or
+// build suffix. Missing minor and patch numbers are 0.
+func parseVersion(v string) ([3]int, bool) {
+	var parts [3]int
+	v = strings.TrimPrefix(strings.TrimSpace(v), "v")
+	if i := strings.IndexAny(v, "-+"); i >= 0 {
+		v = v[:i]
+	}
+	fields := strings.Split(v, ".")
+	if v == "" || len(fields) > 3 {
+		return parts, false
+	}
+	for i, f := range fields {
+		n, err := strconv.Atoi(f)
+		if err != nil || n < 0 {
+			return parts, false
+		}
+		parts[i] = n
+	}
+	return parts, true
+}
+
+// compareVersions returns -1, 0 or 1 as a is older than, the same as or
+// newer than b. Versions that do not parse compare as equal.
+func compareVersions(a, b string) int {
+	pa, okA := parseVersion(a)
+	pb, okB := parseVersion(b)
+	if !okA || !okB {
+		return 0
+	}
+	for i := range pa {
+		switch {
+		case pa[i] < pb[i]:
+			return -1
+		case pa[i] > pb[i]:
+			return 1
+		}
+	}
+	return 0
+}
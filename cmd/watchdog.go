@@ -0,0 +1,68 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"runtime/debug"
+	"time"
+
+	"github.com/kindship-ai/kindship-cli/internal/api"
+	"github.com/kindship-ai/kindship-cli/internal/executor"
+	"github.com/kindship-ai/kindship-cli/internal/logging"
+)
+
+// watchdogGrace is how long a force-killed execution gets to return before
+// the loop stops waiting for it
+const watchdogGrace = 30 * time.Second
+
+// dispatchWithWatchdog runs dispatchExecution under the watchdog ceiling
+// (0 disables it). When the ceiling is reached the execution is cancelled
+// and its process groups are force-killed; if it still does not return
+// within watchdogGrace it is left behind and a synthetic result is
+// returned. fired reports whether the watchdog stopped the execution.
+func dispatchWithWatchdog(ctx context.Context, cancel context.CancelFunc, entity *api.PlanningEntity, inputs map[string]interface{}, log *logging.Logger, watchdog time.Duration) (result *executor.ExecutionResult, fired bool) {
+	if watchdog <= 0 {
+		return dispatchExecution(ctx, entity, inputs, log), false
+	}
+
+	ctx, kill := executor.WithKillSwitch(ctx)
+	done := make(chan *executor.ExecutionResult, 1)
+	go func() {
+		defer func() {
+			// A panic here would bypass executeEntitySafely and Execute's
+			// handler, killing the loop and leaving the execution RUNNING
+			if r := recover(); r != nil {
+				err := handlePanic(r, debug.Stack(), log, entity.ID)
+				done <- &executor.ExecutionResult{ExitCode: 1, Error: err}
+			}
+		}()
+		done <- dispatchExecution(ctx, entity, inputs, log)
+	}()
+
+	timer := time.NewTimer(watchdog)
+	defer timer.Stop()
+	select {
+	case result := <-done:
+		return result, false
+	case <-timer.C:
+	}
+
+	log.Error("Execution exceeded watchdog limit, force-killing", nil, map[string]interface{}{
+		"watchdog_s": int(watchdog.Seconds()),
+	})
+	cancel()
+	kill()
+
+	select {
+	case result = <-done:
+	case <-time.After(watchdogGrace):
+		log.Error("Execution did not stop after force-kill, abandoning it", nil, map[string]interface{}{
+			"grace_s": int(watchdogGrace.Seconds()),
+		})
+		result = &executor.ExecutionResult{ExitCode: 137}
+	}
+	result.Success = false
+	result.TimedOut = true
+	result.Error = fmt.Errorf("watchdog: execution exceeded %v and was force-killed", watchdog)
+	return result, true
+}
@@ -1,28 +1,103 @@
 package cmd
 
 import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+
+	"github.com/kindship-ai/kindship-cli/internal/api"
+	"github.com/kindship-ai/kindship-cli/internal/auth"
+	"github.com/kindship-ai/kindship-cli/internal/output"
+
 	"github.com/spf13/cobra"
 )
 
 var whoamiCmd = &cobra.Command{
-	Use:    "whoami",
-	Short:  "Display current authentication status",
-	Long:   `Alias for 'kindship status'. Use 'kindship status' instead.`,
-	Hidden: true,
+	Use:   "whoami",
+	Short: "Display the current identity and what its token may do",
+	Long: `Show the same report as 'kindship status', plus what the server says the
+current token is allowed to do: its scopes, the accounts you are a member of
+(with your role in each) and the rate-limit tier.
+
+Use it to find out why a command is refused with 403: a missing scope or a
+role that does not allow the action shows up here.
+
+Equivalent to 'kindship status --introspect'.
+
+Examples:
+  kindship whoami
+  kindship whoami --format json`,
 	RunE: func(cmd *cobra.Command, args []string) error {
-		// Forward --json flag to status
+		// Forward --json and --format flags to status
 		if whoamiJSON {
 			statusJSON = true
 		}
+		statusFormat = whoamiFormat
+		statusIntrospect = true
 		return runStatus(cmd, args)
 	},
 }
 
 var (
-	whoamiJSON bool
+	whoamiJSON   bool
+	whoamiFormat string
 )
 
 func init() {
-	whoamiCmd.Flags().BoolVar(&whoamiJSON, "json", false, "Output in JSON format")
+	whoamiCmd.Flags().BoolVar(&whoamiJSON, "json", false, "Output in JSON format (same as --format json)")
+	whoamiCmd.Flags().StringVar(&whoamiFormat, "format", "table", output.FormatUsage)
 	rootCmd.AddCommand(whoamiCmd)
 }
+
+// TokenIntrospection is the response from /api/cli/auth/introspect
+type TokenIntrospection struct {
+	Scopes        []string            `json:"scopes"`
+	RateLimitTier string              `json:"rate_limit_tier,omitempty"`
+	Memberships   []AccountMembership `json:"memberships,omitempty"`
+	Error         string              `json:"error,omitempty"`
+}
+
+// AccountMembership is an account the token's user belongs to
+type AccountMembership struct {
+	ID   string `json:"id"`
+	Slug string `json:"slug,omitempty"`
+	Name string `json:"name,omitempty"`
+	Role string `json:"role,omitempty"`
+}
+
+// fetchIntrospection asks the server what the credentials of ctx grant
+func fetchIntrospection(ctx *auth.Context) (*TokenIntrospection, error) {
+	req, err := http.NewRequest(http.MethodGet, fmt.Sprintf("%s/api/cli/auth/introspect", ctx.APIBaseURL), nil)
+	if err != nil {
+		return nil, err
+	}
+	ctx.SetAuthHeaders(req)
+	req.Header.Set("X-Kindship-CLI-Version", Version)
+
+	resp, err := api.NewHTTPClient(10 * time.Second).Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("request failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read response: %w", err)
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		var errResp TokenIntrospection
+		if json.Unmarshal(body, &errResp) == nil && errResp.Error != "" {
+			return nil, fmt.Errorf("API error: %s", errResp.Error)
+		}
+		return nil, fmt.Errorf("API error (%d): %s", resp.StatusCode, string(body))
+	}
+
+	var introspection TokenIntrospection
+	if err := json.Unmarshal(body, &introspection); err != nil {
+		return nil, fmt.Errorf("failed to parse response: %w", err)
+	}
+	return &introspection, nil
+}
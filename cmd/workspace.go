@@ -0,0 +1,272 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+
+	"github.com/kindship-ai/kindship-cli/internal/config"
+	"github.com/kindship-ai/kindship-cli/internal/output"
+	"github.com/kindship-ai/kindship-cli/internal/workspace"
+
+	"github.com/spf13/cobra"
+)
+
+var workspaceCmd = &cobra.Command{
+	Use:   "workspace",
+	Short: "Sync workspace directories with persistent storage",
+	Long: `Save workspace directories when an agent container stops and restore them
+when it starts again, so stateful Processes keep their intermediate data
+across container restarts.
+
+Directories are relative to the workspace (default /workspace). Pass them as
+arguments, or declare them once:
+  - KINDSHIP_WORKSPACE_SYNC, comma-separated (e.g. "data,cache/models")
+  - kindship config set --repo sync_dirs "data,cache/models"
+
+Each directory is stored as one archive per agent. Archives go to the
+platform's artifact store, or to an S3-compatible bucket when the agent has
+one configured, either in the environment or as agent secrets:
+  KINDSHIP_WORKSPACE_S3_BUCKET    Bucket, optionally with a key prefix
+                                  ("bucket" or "bucket/prefix")
+  KINDSHIP_WORKSPACE_S3_ENDPOINT  Endpoint of S3-compatible storage
+                                  (default: AWS S3 in the region)
+  KINDSHIP_WORKSPACE_S3_REGION    Region (default: AWS_REGION, else us-east-1)
+  AWS_ACCESS_KEY_ID, AWS_SECRET_ACCESS_KEY, AWS_SESSION_TOKEN
+
+Subcommands:
+  push  Save directories to storage
+  pull  Restore directories from storage, replacing their contents`,
+}
+
+var workspacePushCmd = &cobra.Command{
+	Use:   "push [dir...]",
+	Short: "Save workspace directories to storage",
+	Long: `Archive each directory and store it, replacing the agent's previous archive
+of it. Declared directories that do not exist are skipped.
+
+Symlinks must stay within their directory: absolute links into it are stored
+relative, and a link leaving it (e.g. a virtualenv's bin/python) fails the
+push, since it could not be restored.
+
+Examples:
+  kindship workspace push
+  kindship workspace push data cache/models`,
+	SilenceUsage: true,
+	RunE:         runWorkspacePush,
+}
+
+var workspacePullCmd = &cobra.Command{
+	Use:   "pull [dir...]",
+	Short: "Restore workspace directories from storage",
+	Long: `Download each directory's archive and replace the directory with its
+contents. Directories without a stored archive are left alone.
+
+Examples:
+  kindship workspace pull
+  kindship workspace pull data`,
+	SilenceUsage: true,
+	RunE:         runWorkspacePull,
+}
+
+func init() {
+	for _, c := range []*cobra.Command{workspacePushCmd, workspacePullCmd} {
+		c.Flags().StringVar(&workspaceOpts.AgentID, "agent-id", "", "Agent ID (defaults to AGENT_ID env var)")
+		addServiceKeyFlags(c, &workspaceOpts)
+		workspaceCmd.AddCommand(c)
+	}
+	rootCmd.AddCommand(workspaceCmd)
+}
+
+func runWorkspacePush(cmd *cobra.Command, args []string) error {
+	dirs, err := syncDirs(args)
+	if err != nil {
+		return err
+	}
+	store, err := workspaceStore()
+	if err != nil {
+		return err
+	}
+
+	workDir := executionWorkDir()
+	for _, dir := range dirs {
+		local := filepath.Join(workDir, filepath.FromSlash(dir))
+		if info, err := os.Stat(local); os.IsNotExist(err) {
+			fmt.Println(output.Dim(fmt.Sprintf("- %s does not exist; skipped", dir)))
+			continue
+		} else if err != nil {
+			return err
+		} else if !info.IsDir() {
+			return withExitCode(ExitValidation, fmt.Errorf("%s is not a directory", local))
+		}
+
+		files, size, err := pushDir(store, dir, local)
+		if err != nil {
+			return err
+		}
+		fmt.Println(output.OK(fmt.Sprintf("Pushed %s (%d files, %d bytes compressed)", dir, files, size)))
+	}
+	fmt.Println(output.Dim("Stored in " + store.Describe()))
+	return nil
+}
+
+func runWorkspacePull(cmd *cobra.Command, args []string) error {
+	dirs, err := syncDirs(args)
+	if err != nil {
+		return err
+	}
+	store, err := workspaceStore()
+	if err != nil {
+		return err
+	}
+
+	workDir := executionWorkDir()
+	for _, dir := range dirs {
+		archive, err := store.Get(dir)
+		if errors.Is(err, workspace.ErrNotFound) {
+			fmt.Println(output.Dim(fmt.Sprintf("- %s has nothing stored; left alone", dir)))
+			continue
+		}
+		if err != nil {
+			return withExitCode(ExitAPI, fmt.Errorf("failed to pull %s: %w", dir, err))
+		}
+
+		local := filepath.Join(workDir, filepath.FromSlash(dir))
+		files, err := replaceDir(local, archive)
+		archive.Close()
+		if err != nil {
+			return fmt.Errorf("failed to restore %s: %w", dir, err)
+		}
+		fmt.Println(output.OK(fmt.Sprintf("Pulled %s (%d files)", dir, files)))
+	}
+	return nil
+}
+
+// pushDir packs local into a temporary file and stores it as the archive
+// of dir, so the archive is streamed rather than held in memory. It
+// returns the number of files and the archive's size.
+func pushDir(store workspace.Store, dir, local string) (int, int64, error) {
+	tmp, err := os.CreateTemp("", "kindship-workspace-*.tar.gz")
+	if err != nil {
+		return 0, 0, err
+	}
+	defer os.Remove(tmp.Name())
+	defer tmp.Close()
+
+	files, err := workspace.Pack(local, tmp)
+	if err != nil {
+		return 0, 0, err
+	}
+	size, err := tmp.Seek(0, io.SeekCurrent)
+	if err != nil {
+		return 0, 0, err
+	}
+	if _, err := tmp.Seek(0, io.SeekStart); err != nil {
+		return 0, 0, err
+	}
+	if err := store.Put(dir, tmp, size); err != nil {
+		return 0, 0, withExitCode(ExitAPI, fmt.Errorf("failed to push %s: %w", dir, err))
+	}
+	return files, size, nil
+}
+
+// replaceDir extracts an archive next to dir, then swaps it in, so a bad
+// archive leaves dir untouched
+func replaceDir(dir string, archive io.Reader) (int, error) {
+	parent := filepath.Dir(dir)
+	if err := os.MkdirAll(parent, 0755); err != nil {
+		return 0, err
+	}
+	tmp, err := os.MkdirTemp(parent, "."+filepath.Base(dir)+".pull-")
+	if err != nil {
+		return 0, err
+	}
+	defer os.RemoveAll(tmp)
+
+	extracted := filepath.Join(tmp, "dir")
+	files, err := workspace.Unpack(archive, extracted)
+	if err != nil {
+		return 0, err
+	}
+	if err := os.RemoveAll(dir); err != nil {
+		return 0, err
+	}
+	return files, os.Rename(extracted, dir)
+}
+
+// syncDirs returns the directories to sync: args, else
+// KINDSHIP_WORKSPACE_SYNC, else the repository's sync_dirs setting
+func syncDirs(args []string) ([]string, error) {
+	dirs := args
+	if len(dirs) == 0 {
+		if env := os.Getenv("KINDSHIP_WORKSPACE_SYNC"); env != "" {
+			dirs = strings.Split(env, ",")
+		} else if repo, err := config.LoadRepoConfig(); err == nil {
+			dirs = repo.SyncDirs
+		}
+	}
+
+	var clean []string
+	for _, dir := range dirs {
+		if strings.TrimSpace(dir) == "" {
+			continue
+		}
+		c, err := cleanSyncDir(dir)
+		if err != nil {
+			return nil, withExitCode(ExitValidation, err)
+		}
+		clean = append(clean, c)
+	}
+	if len(clean) == 0 {
+		return nil, withExitCode(ExitUsage, fmt.Errorf("no directories to sync (pass them as arguments, or set KINDSHIP_WORKSPACE_SYNC or the sync_dirs repo setting)"))
+	}
+	return clean, nil
+}
+
+// cleanSyncDir returns dir as a clean slash-separated path, requiring it
+// to be inside the workspace and not the workspace itself
+func cleanSyncDir(dir string) (string, error) {
+	clean := path.Clean(filepath.ToSlash(strings.TrimSpace(dir)))
+	if filepath.IsAbs(dir) || path.IsAbs(clean) || clean == "." || clean == ".." || strings.HasPrefix(clean, "../") {
+		return "", fmt.Errorf("invalid sync directory %q: must be a subdirectory of the workspace, relative to it", dir)
+	}
+	return clean, nil
+}
+
+// workspaceStore returns the agent's S3 bucket when one is configured in the
+// environment or the agent's secrets, else the platform's artifact store
+func workspaceStore() (workspace.Store, error) {
+	opts := workspaceOpts.resolved()
+	if opts.AgentID == "" {
+		return nil, withExitCode(ExitUsage, fmt.Errorf("AGENT_ID is required (use --agent-id flag or AGENT_ID environment variable)"))
+	}
+
+	s3, err := workspace.S3StoreFromSettings(opts.AgentID, os.Getenv)
+	if err != nil {
+		return nil, withExitCode(ExitValidation, err)
+	}
+	if s3 != nil {
+		return s3, nil
+	}
+
+	if err := opts.requireServiceKey(); err != nil {
+		return nil, err
+	}
+	client := opts.client()
+	secrets, err := client.FetchSecrets(opts.AgentID, "workspace", opts.ServiceKey)
+	if err != nil {
+		return nil, withExitCode(ExitAPI, fmt.Errorf("failed to fetch agent secrets: %w", err))
+	}
+	s3, err = workspace.S3StoreFromSettings(opts.AgentID, func(name string) string { return secrets[name] })
+	if err != nil {
+		return nil, withExitCode(ExitValidation, fmt.Errorf("agent secrets: %w", err))
+	}
+	if s3 != nil {
+		return s3, nil
+	}
+	return &workspace.APIStore{Client: client, AgentID: opts.AgentID, ServiceKey: opts.ServiceKey}, nil
+}
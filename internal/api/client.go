@@ -9,6 +9,8 @@ import (
 	"net/url"
 	"os"
 	"time"
+
+	"github.com/kindship-ai/kindship-cli/internal/checksum"
 )
 
 // Client is the Kindship API client for fetching secrets
@@ -16,14 +18,36 @@ type Client struct {
 	baseURL    string
 	httpClient *http.Client
 	verbose    bool
+
+	// transferClient streams workspace archives, which can take far longer
+	// than httpClient's timeout allows
+	transferClient *http.Client
 }
 
+// transferTimeout bounds one workspace archive transfer
+const transferTimeout = 10 * time.Minute
+
 // SecretsResponse is the response from the secrets endpoint
 type SecretsResponse struct {
 	Env   map[string]string `json:"env"`
 	Error string            `json:"error,omitempty"`
 }
 
+// StatusError is returned when the API responds with a non-2xx status
+type StatusError struct {
+	StatusCode int
+	Message    string
+}
+
+func (e *StatusError) Error() string {
+	return fmt.Sprintf("API error (%d): %s", e.StatusCode, e.Message)
+}
+
+// IsAuthError reports whether the API rejected the request's credentials
+func (e *StatusError) IsAuthError() bool {
+	return e.StatusCode == http.StatusUnauthorized || e.StatusCode == http.StatusForbidden
+}
+
 // log prints a message if verbose mode is enabled
 func (c *Client) log(format string, args ...interface{}) {
 	if c.verbose {
@@ -34,12 +58,78 @@ func (c *Client) log(format string, args ...interface{}) {
 // NewClient creates a new API client
 func NewClient(baseURL string, verbose bool) *Client {
 	return &Client{
-		baseURL: baseURL,
-		httpClient: &http.Client{
-			Timeout: 30 * time.Second,
-		},
-		verbose: verbose,
+		baseURL:    baseURL,
+		httpClient: NewHTTPClient(30 * time.Second),
+		verbose:    verbose,
+
+		transferClient: NewHTTPClient(transferTimeout),
+	}
+}
+
+// BaseURL returns the API base URL the client talks to
+func (c *Client) BaseURL() string {
+	return c.baseURL
+}
+
+// WithTimeout returns a copy of the client whose requests time out after d
+func (c *Client) WithTimeout(d time.Duration) *Client {
+	copied := *c
+	copied.httpClient = NewHTTPClient(d)
+	return &copied
+}
+
+// NegotiateCapabilities sends the CLI version and returns the features the
+// server supports. Servers that predate negotiation answer 404; they get
+// LegacyFeatures, the features every server had before it.
+func (c *Client) NegotiateCapabilities(cliVersion, serviceKey string) (*Capabilities, error) {
+	endpoint := fmt.Sprintf("%s/api/cli/capabilities", c.baseURL)
+	c.log("Negotiating capabilities (cli version: %s)", cliVersion)
+
+	jsonData, err := json.Marshal(CapabilitiesRequest{CLIVersion: cliVersion})
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal request: %w", err)
+	}
+
+	req, err := http.NewRequest(http.MethodPost, endpoint, bytes.NewBuffer(jsonData))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+
+	req.Header.Set("X-Kindship-Service-Key", serviceKey)
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Accept", "application/json")
+	req.Header.Set("User-Agent", "kindship-cli/1.0")
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("request failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read response: %w", err)
+	}
+
+	if resp.StatusCode == http.StatusNotFound {
+		c.log("Server predates capability negotiation; assuming legacy features: %v", LegacyFeatures)
+		return &Capabilities{Features: LegacyFeatures, Legacy: true}, nil
+	}
+	if resp.StatusCode != http.StatusOK {
+		var errResp Capabilities
+		if json.Unmarshal(body, &errResp) == nil && errResp.Error != "" {
+			return nil, &StatusError{StatusCode: resp.StatusCode, Message: errResp.Error}
+		}
+		return nil, &StatusError{StatusCode: resp.StatusCode, Message: string(body)}
+	}
+
+	var caps Capabilities
+	if err := json.Unmarshal(body, &caps); err != nil {
+		return nil, fmt.Errorf("failed to parse response: %w", err)
 	}
+
+	c.log("Server API version %s supports: %v", caps.APIVersion, caps.Features)
+	return &caps, nil
 }
 
 // FetchSecrets retrieves secrets for a specific agent and command
@@ -99,24 +189,24 @@ func (c *Client) FetchSecrets(agentID, command, serviceKey string) (map[string]s
 
 		var errResp SecretsResponse
 		if json.Unmarshal(body, &errResp) == nil && errResp.Error != "" {
-			return nil, fmt.Errorf("API error (%d): %s", resp.StatusCode, errResp.Error)
+			return nil, &StatusError{StatusCode: resp.StatusCode, Message: errResp.Error}
 		}
 
 		// Provide more context for common errors
+		message := string(body)
 		switch resp.StatusCode {
 		case http.StatusUnauthorized:
-			return nil, fmt.Errorf("authentication failed (%d): invalid service key or IP not whitelisted", resp.StatusCode)
+			message = "authentication failed: invalid service key or IP not whitelisted"
 		case http.StatusForbidden:
-			return nil, fmt.Errorf("access denied (%d): %s", resp.StatusCode, string(body))
+			message = "access denied: " + message
 		case http.StatusNotFound:
-			return nil, fmt.Errorf("not found (%d): agent or secrets endpoint not found", resp.StatusCode)
+			message = "not found: agent or secrets endpoint not found"
 		case http.StatusTooManyRequests:
-			return nil, fmt.Errorf("rate limited (%d): too many requests, try again later", resp.StatusCode)
+			message = "rate limited: too many requests, try again later"
 		case http.StatusInternalServerError, http.StatusBadGateway, http.StatusServiceUnavailable:
-			return nil, fmt.Errorf("server error (%d): %s", resp.StatusCode, string(body))
-		default:
-			return nil, fmt.Errorf("API error (%d): %s", resp.StatusCode, string(body))
+			message = "server error: " + message
 		}
+		return nil, &StatusError{StatusCode: resp.StatusCode, Message: message}
 	}
 
 	// Parse response
@@ -158,7 +248,7 @@ func (c *Client) FetchEntityForExecution(entityID, serviceKey string) (*EntityEx
 	}
 
 	if resp.StatusCode != http.StatusOK {
-		return nil, fmt.Errorf("API error (%d): %s", resp.StatusCode, string(body))
+		return nil, &StatusError{StatusCode: resp.StatusCode, Message: string(body)}
 	}
 
 	var entityResp EntityExecuteResponse
@@ -202,7 +292,7 @@ func (c *Client) StartExecution(req ExecutionStartRequest, serviceKey string) (*
 	}
 
 	if resp.StatusCode != http.StatusOK {
-		return nil, fmt.Errorf("API error (%d): %s", resp.StatusCode, string(body))
+		return nil, &StatusError{StatusCode: resp.StatusCode, Message: string(body)}
 	}
 
 	var startResp ExecutionStartResponse
@@ -214,6 +304,49 @@ func (c *Client) StartExecution(req ExecutionStartRequest, serviceKey string) (*
 	return &startResp, nil
 }
 
+// GetExecutionStatus returns the current server-side status of an execution
+// attempt. Used while executing to notice cancellation requested from the UI.
+func (c *Client) GetExecutionStatus(executionID, serviceKey string) (*ExecutionStatusResponse, error) {
+	endpoint := fmt.Sprintf("%s/api/planning/execution/%s", c.baseURL, executionID)
+	c.log("Checking status of execution: %s", executionID)
+
+	req, err := http.NewRequest(http.MethodGet, endpoint, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+
+	req.Header.Set("X-Kindship-Service-Key", serviceKey)
+	req.Header.Set("Accept", "application/json")
+	req.Header.Set("User-Agent", "kindship-cli/1.0")
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("request failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read response: %w", err)
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		var errResp ExecutionStatusResponse
+		if json.Unmarshal(body, &errResp) == nil && errResp.Error != "" {
+			return nil, &StatusError{StatusCode: resp.StatusCode, Message: errResp.Error}
+		}
+		return nil, &StatusError{StatusCode: resp.StatusCode, Message: string(body)}
+	}
+
+	var statusResp ExecutionStatusResponse
+	if err := json.Unmarshal(body, &statusResp); err != nil {
+		return nil, fmt.Errorf("failed to parse response: %w", err)
+	}
+
+	c.log("Execution %s status: %s", executionID, statusResp.Status)
+	return &statusResp, nil
+}
+
 // CompleteExecution marks an execution as complete
 func (c *Client) CompleteExecution(executionID string, req ExecutionCompleteRequest, serviceKey string) (*ExecutionCompleteResponse, error) {
 	endpoint := fmt.Sprintf("%s/api/planning/execution/%s/complete", c.baseURL, executionID)
@@ -246,7 +379,7 @@ func (c *Client) CompleteExecution(executionID string, req ExecutionCompleteRequ
 	}
 
 	if resp.StatusCode != http.StatusOK {
-		return nil, fmt.Errorf("API error (%d): %s", resp.StatusCode, string(body))
+		return nil, &StatusError{StatusCode: resp.StatusCode, Message: string(body)}
 	}
 
 	var completeResp ExecutionCompleteResponse
@@ -258,6 +391,102 @@ func (c *Client) CompleteExecution(executionID string, req ExecutionCompleteRequ
 	return &completeResp, nil
 }
 
+// EscalateExecution raises the priority of an execution waiting on a user
+// (ASK_USER) that has gone unanswered for too long
+func (c *Client) EscalateExecution(executionID string, req EscalationRequest, serviceKey string) (*EscalationResponse, error) {
+	endpoint := fmt.Sprintf("%s/api/planning/execution/%s/escalate", c.baseURL, executionID)
+	c.log("Escalating execution: %s (reminder %d)", executionID, req.Reminder)
+
+	jsonData, err := json.Marshal(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal request: %w", err)
+	}
+
+	httpReq, err := http.NewRequest(http.MethodPost, endpoint, bytes.NewBuffer(jsonData))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+
+	httpReq.Header.Set("X-Kindship-Service-Key", serviceKey)
+	httpReq.Header.Set("Content-Type", "application/json")
+	httpReq.Header.Set("Accept", "application/json")
+	httpReq.Header.Set("User-Agent", "kindship-cli/1.0")
+
+	resp, err := c.httpClient.Do(httpReq)
+	if err != nil {
+		return nil, fmt.Errorf("request failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read response: %w", err)
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		var errResp EscalationResponse
+		if json.Unmarshal(body, &errResp) == nil && errResp.Error != "" {
+			return nil, &StatusError{StatusCode: resp.StatusCode, Message: errResp.Error}
+		}
+		return nil, &StatusError{StatusCode: resp.StatusCode, Message: string(body)}
+	}
+
+	var escalateResp EscalationResponse
+	if err := json.Unmarshal(body, &escalateResp); err != nil {
+		return nil, fmt.Errorf("failed to parse response: %w", err)
+	}
+
+	return &escalateResp, nil
+}
+
+// ReportPreflight records a pre-flight failure of an entity that was
+// stopped before any run was created
+func (c *Client) ReportPreflight(entityID string, report PreflightReport, serviceKey string) (*PreflightReportResponse, error) {
+	endpoint := fmt.Sprintf("%s/api/planning/entity/%s/preflight", c.baseURL, entityID)
+	c.log("Reporting pre-flight failure: %s", entityID)
+
+	jsonData, err := json.Marshal(report)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal request: %w", err)
+	}
+
+	httpReq, err := http.NewRequest(http.MethodPost, endpoint, bytes.NewBuffer(jsonData))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+
+	httpReq.Header.Set("X-Kindship-Service-Key", serviceKey)
+	httpReq.Header.Set("Content-Type", "application/json")
+	httpReq.Header.Set("Accept", "application/json")
+	httpReq.Header.Set("User-Agent", "kindship-cli/1.0")
+
+	resp, err := c.httpClient.Do(httpReq)
+	if err != nil {
+		return nil, fmt.Errorf("request failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read response: %w", err)
+	}
+
+	if resp.StatusCode != http.StatusOK && resp.StatusCode != http.StatusCreated {
+		var errResp PreflightReportResponse
+		if json.Unmarshal(body, &errResp) == nil && errResp.Error != "" {
+			return nil, &StatusError{StatusCode: resp.StatusCode, Message: errResp.Error}
+		}
+		return nil, &StatusError{StatusCode: resp.StatusCode, Message: string(body)}
+	}
+
+	var reportResp PreflightReportResponse
+	if err := json.Unmarshal(body, &reportResp); err != nil {
+		return nil, fmt.Errorf("failed to parse response: %w", err)
+	}
+
+	return &reportResp, nil
+}
+
 // FetchNextTask gets the next runnable task for an agent.
 // Uses X-Kindship-Service-Key header for /api/cli/* endpoints.
 func (c *Client) FetchNextTask(agentID, serviceKey string) (*PlanNextResponse, error) {
@@ -294,9 +523,9 @@ func (c *Client) FetchNextTask(agentID, serviceKey string) (*PlanNextResponse, e
 	if resp.StatusCode != http.StatusOK {
 		var errResp PlanNextResponse
 		if json.Unmarshal(body, &errResp) == nil && errResp.Error != "" {
-			return nil, fmt.Errorf("API error (%d): %s", resp.StatusCode, errResp.Error)
+			return nil, &StatusError{StatusCode: resp.StatusCode, Message: errResp.Error}
 		}
-		return nil, fmt.Errorf("API error (%d): %s", resp.StatusCode, string(body))
+		return nil, &StatusError{StatusCode: resp.StatusCode, Message: string(body)}
 	}
 
 	var nextResp PlanNextResponse
@@ -352,9 +581,9 @@ func (c *Client) FetchNextTaskScoped(agentID, parentEntityID, serviceKey string)
 	if resp.StatusCode != http.StatusOK {
 		var errResp PlanNextResponse
 		if json.Unmarshal(body, &errResp) == nil && errResp.Error != "" {
-			return nil, fmt.Errorf("API error (%d): %s", resp.StatusCode, errResp.Error)
+			return nil, &StatusError{StatusCode: resp.StatusCode, Message: errResp.Error}
 		}
-		return nil, fmt.Errorf("API error (%d): %s", resp.StatusCode, string(body))
+		return nil, &StatusError{StatusCode: resp.StatusCode, Message: string(body)}
 	}
 
 	var nextResp PlanNextResponse
@@ -416,9 +645,9 @@ func (c *Client) ActivateEntity(entityID, serviceKey string, recursive bool) (*A
 	if resp.StatusCode != http.StatusOK {
 		var errResp ActivateEntityResponse
 		if json.Unmarshal(body, &errResp) == nil && errResp.Error != "" {
-			return nil, fmt.Errorf("API error (%d): %s", resp.StatusCode, errResp.Error)
+			return nil, &StatusError{StatusCode: resp.StatusCode, Message: errResp.Error}
 		}
-		return nil, fmt.Errorf("API error (%d): %s", resp.StatusCode, string(body))
+		return nil, &StatusError{StatusCode: resp.StatusCode, Message: string(body)}
 	}
 
 	var activateResp ActivateEntityResponse
@@ -470,9 +699,9 @@ func (c *Client) RecoverRuns(agentID, serviceKey string) (*RecoverRunsResponse,
 	if resp.StatusCode != http.StatusOK {
 		var errResp RecoverRunsResponse
 		if json.Unmarshal(body, &errResp) == nil && errResp.Error != "" {
-			return nil, fmt.Errorf("API error (%d): %s", resp.StatusCode, errResp.Error)
+			return nil, &StatusError{StatusCode: resp.StatusCode, Message: errResp.Error}
 		}
-		return nil, fmt.Errorf("API error (%d): %s", resp.StatusCode, string(body))
+		return nil, &StatusError{StatusCode: resp.StatusCode, Message: string(body)}
 	}
 
 	var recoverResp RecoverRunsResponse
@@ -484,3 +713,593 @@ func (c *Client) RecoverRuns(agentID, serviceKey string) (*RecoverRunsResponse,
 		len(recoverResp.ResumedRuns), recoverResp.FailedCount, recoverResp.SkippedAskUser)
 	return &recoverResp, nil
 }
+
+// SendHeartbeat reports agent health, version and capabilities so the
+// dashboard can show live fleet status.
+func (c *Client) SendHeartbeat(req HeartbeatRequest, serviceKey string) (*HeartbeatResponse, error) {
+	endpoint := fmt.Sprintf("%s/api/cli/agent/heartbeat", c.baseURL)
+	c.log("Sending heartbeat for agent: %s (status: %s)", req.AgentID, req.Status)
+
+	jsonData, err := json.Marshal(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal request: %w", err)
+	}
+
+	httpReq, err := http.NewRequest(http.MethodPost, endpoint, bytes.NewBuffer(jsonData))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+
+	httpReq.Header.Set("X-Kindship-Service-Key", serviceKey)
+	httpReq.Header.Set("Content-Type", "application/json")
+	httpReq.Header.Set("Accept", "application/json")
+	httpReq.Header.Set("User-Agent", "kindship-cli/1.0")
+
+	resp, err := c.httpClient.Do(httpReq)
+	if err != nil {
+		return nil, fmt.Errorf("request failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read response: %w", err)
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		var errResp HeartbeatResponse
+		if json.Unmarshal(body, &errResp) == nil && errResp.Error != "" {
+			return nil, &StatusError{StatusCode: resp.StatusCode, Message: errResp.Error}
+		}
+		return nil, &StatusError{StatusCode: resp.StatusCode, Message: string(body)}
+	}
+
+	var hbResp HeartbeatResponse
+	if err := json.Unmarshal(body, &hbResp); err != nil {
+		return nil, fmt.Errorf("failed to parse response: %w", err)
+	}
+
+	return &hbResp, nil
+}
+
+// SendAgentEvent reports an agent lifecycle event (started, idle,
+// task_claimed, draining, stopped) so the platform can show state
+// transitions and spot agents that never claim work.
+func (c *Client) SendAgentEvent(req AgentEventRequest, serviceKey string) (*AgentEventResponse, error) {
+	endpoint := fmt.Sprintf("%s/api/cli/agent/events", c.baseURL)
+	c.log("Sending agent event for agent: %s (event: %s)", req.AgentID, req.Event)
+
+	jsonData, err := json.Marshal(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal request: %w", err)
+	}
+
+	httpReq, err := http.NewRequest(http.MethodPost, endpoint, bytes.NewBuffer(jsonData))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+
+	httpReq.Header.Set("X-Kindship-Service-Key", serviceKey)
+	httpReq.Header.Set("Content-Type", "application/json")
+	httpReq.Header.Set("Accept", "application/json")
+	httpReq.Header.Set("User-Agent", "kindship-cli/1.0")
+
+	resp, err := c.httpClient.Do(httpReq)
+	if err != nil {
+		return nil, fmt.Errorf("request failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read response: %w", err)
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		var errResp AgentEventResponse
+		if json.Unmarshal(body, &errResp) == nil && errResp.Error != "" {
+			return nil, &StatusError{StatusCode: resp.StatusCode, Message: errResp.Error}
+		}
+		return nil, &StatusError{StatusCode: resp.StatusCode, Message: string(body)}
+	}
+
+	var eventResp AgentEventResponse
+	if err := json.Unmarshal(body, &eventResp); err != nil {
+		return nil, fmt.Errorf("failed to parse response: %w", err)
+	}
+
+	return &eventResp, nil
+}
+
+// ListAttempts returns all execution attempts of an entity, newest first.
+// Uses X-Kindship-Service-Key header for /api/cli/* endpoints.
+func (c *Client) ListAttempts(entityID, serviceKey string) (*ListAttemptsResponse, error) {
+	return c.listAttempts(entityID, serviceKey, false)
+}
+
+// ListAttemptsWithOutputs is ListAttempts with each completed attempt's
+// outputs included
+func (c *Client) ListAttemptsWithOutputs(entityID, serviceKey string) (*ListAttemptsResponse, error) {
+	return c.listAttempts(entityID, serviceKey, true)
+}
+
+func (c *Client) listAttempts(entityID, serviceKey string, withOutputs bool) (*ListAttemptsResponse, error) {
+	endpoint := fmt.Sprintf("%s/api/cli/entity/%s/attempts", c.baseURL, entityID)
+	if withOutputs {
+		endpoint += "?include=outputs"
+	}
+	c.log("Listing attempts for entity: %s", entityID)
+
+	req, err := http.NewRequest(http.MethodGet, endpoint, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+
+	req.Header.Set("X-Kindship-Service-Key", serviceKey)
+	req.Header.Set("Accept", "application/json")
+	req.Header.Set("User-Agent", "kindship-cli/1.0")
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("request failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read response: %w", err)
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		var errResp ListAttemptsResponse
+		if json.Unmarshal(body, &errResp) == nil && errResp.Error != "" {
+			return nil, &StatusError{StatusCode: resp.StatusCode, Message: errResp.Error}
+		}
+		return nil, &StatusError{StatusCode: resp.StatusCode, Message: string(body)}
+	}
+
+	var listResp ListAttemptsResponse
+	if err := json.Unmarshal(body, &listResp); err != nil {
+		return nil, fmt.Errorf("failed to parse response: %w", err)
+	}
+
+	c.log("Found %d attempts", len(listResp.Attempts))
+	return &listResp, nil
+}
+
+// ListValidations returns the validation records of all attempts of an entity.
+// Uses X-Kindship-Service-Key header for /api/cli/* endpoints.
+func (c *Client) ListValidations(entityID, serviceKey string) (*ListValidationsResponse, error) {
+	endpoint := fmt.Sprintf("%s/api/cli/entity/%s/validations", c.baseURL, entityID)
+	c.log("Listing validation records for entity: %s", entityID)
+
+	req, err := http.NewRequest(http.MethodGet, endpoint, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+
+	req.Header.Set("X-Kindship-Service-Key", serviceKey)
+	req.Header.Set("Accept", "application/json")
+	req.Header.Set("User-Agent", "kindship-cli/1.0")
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("request failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read response: %w", err)
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		var errResp ListValidationsResponse
+		if json.Unmarshal(body, &errResp) == nil && errResp.Error != "" {
+			return nil, &StatusError{StatusCode: resp.StatusCode, Message: errResp.Error}
+		}
+		return nil, &StatusError{StatusCode: resp.StatusCode, Message: string(body)}
+	}
+
+	var listResp ListValidationsResponse
+	if err := json.Unmarshal(body, &listResp); err != nil {
+		return nil, fmt.Errorf("failed to parse response: %w", err)
+	}
+
+	c.log("Found %d validation records", len(listResp.Validations))
+	return &listResp, nil
+}
+
+// UploadArtifact attaches a file to an execution attempt. The body is sent
+// as-is with the given content type, and its SHA-256 in the
+// X-Kindship-Content-SHA256 header so the server can reject a corrupted
+// upload.
+func (c *Client) UploadArtifact(executionID, name, contentType string, data []byte, serviceKey string) (*ArtifactUploadResponse, error) {
+	u, err := url.Parse(fmt.Sprintf("%s/api/planning/execution/%s/artifacts", c.baseURL, executionID))
+	if err != nil {
+		return nil, fmt.Errorf("invalid URL: %w", err)
+	}
+	q := u.Query()
+	q.Set("name", name)
+	u.RawQuery = q.Encode()
+
+	c.log("Uploading artifact %s for execution %s (%d bytes)", name, executionID, len(data))
+
+	req, err := http.NewRequest(http.MethodPost, u.String(), bytes.NewReader(data))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+
+	req.Header.Set("X-Kindship-Service-Key", serviceKey)
+	req.Header.Set("X-Kindship-Content-SHA256", checksum.SHA256(data))
+	req.Header.Set("Content-Type", contentType)
+	req.Header.Set("Accept", "application/json")
+	req.Header.Set("User-Agent", "kindship-cli/1.0")
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("request failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read response: %w", err)
+	}
+
+	if resp.StatusCode != http.StatusOK && resp.StatusCode != http.StatusCreated {
+		var errResp ArtifactUploadResponse
+		if json.Unmarshal(body, &errResp) == nil && errResp.Error != "" {
+			return nil, &StatusError{StatusCode: resp.StatusCode, Message: errResp.Error}
+		}
+		return nil, &StatusError{StatusCode: resp.StatusCode, Message: string(body)}
+	}
+
+	var uploadResp ArtifactUploadResponse
+	if err := json.Unmarshal(body, &uploadResp); err != nil {
+		return nil, fmt.Errorf("failed to parse response: %w", err)
+	}
+
+	c.log("Uploaded artifact: %s", uploadResp.ID)
+	return &uploadResp, nil
+}
+
+// StartArtifactUpload opens a chunked upload for an artifact too large for
+// UploadArtifact. The parts are sent with UploadArtifactPart and assembled
+// by CompleteArtifactUpload.
+func (c *Client) StartArtifactUpload(executionID string, req ArtifactUploadStartRequest, serviceKey string) (*ArtifactUploadSession, error) {
+	endpoint := fmt.Sprintf("%s/api/planning/execution/%s/artifacts/uploads", c.baseURL, executionID)
+	c.log("Starting chunked upload of artifact %s for execution %s (%d bytes)", req.Name, executionID, req.Size)
+
+	jsonData, err := json.Marshal(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal request: %w", err)
+	}
+
+	httpReq, err := http.NewRequest(http.MethodPost, endpoint, bytes.NewBuffer(jsonData))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+
+	httpReq.Header.Set("X-Kindship-Service-Key", serviceKey)
+	httpReq.Header.Set("Content-Type", "application/json")
+	httpReq.Header.Set("Accept", "application/json")
+	httpReq.Header.Set("User-Agent", "kindship-cli/1.0")
+
+	resp, err := c.httpClient.Do(httpReq)
+	if err != nil {
+		return nil, fmt.Errorf("request failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read response: %w", err)
+	}
+
+	if resp.StatusCode != http.StatusOK && resp.StatusCode != http.StatusCreated {
+		var errResp ArtifactUploadSession
+		if json.Unmarshal(body, &errResp) == nil && errResp.Error != "" {
+			return nil, &StatusError{StatusCode: resp.StatusCode, Message: errResp.Error}
+		}
+		return nil, &StatusError{StatusCode: resp.StatusCode, Message: string(body)}
+	}
+
+	var session ArtifactUploadSession
+	if err := json.Unmarshal(body, &session); err != nil {
+		return nil, fmt.Errorf("failed to parse response: %w", err)
+	}
+	if session.UploadID == "" || session.ChunkSize <= 0 {
+		return nil, fmt.Errorf("invalid upload session from server")
+	}
+
+	return &session, nil
+}
+
+// UploadArtifactPart sends part number part (starting at 1) of a chunked
+// upload, with its SHA-256 in the X-Kindship-Content-SHA256 header
+func (c *Client) UploadArtifactPart(executionID, uploadID string, part int, data []byte, serviceKey string) error {
+	endpoint := fmt.Sprintf("%s/api/planning/execution/%s/artifacts/uploads/%s/parts/%d", c.baseURL, executionID, url.PathEscape(uploadID), part)
+	c.log("Uploading part %d of %s (%d bytes)", part, uploadID, len(data))
+
+	req, err := http.NewRequest(http.MethodPut, endpoint, bytes.NewReader(data))
+	if err != nil {
+		return fmt.Errorf("failed to create request: %w", err)
+	}
+
+	req.Header.Set("X-Kindship-Service-Key", serviceKey)
+	req.Header.Set("X-Kindship-Content-SHA256", checksum.SHA256(data))
+	req.Header.Set("Content-Type", "application/octet-stream")
+	req.Header.Set("Accept", "application/json")
+	req.Header.Set("User-Agent", "kindship-cli/1.0")
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("request failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return fmt.Errorf("failed to read response: %w", err)
+	}
+
+	if resp.StatusCode != http.StatusOK && resp.StatusCode != http.StatusCreated && resp.StatusCode != http.StatusNoContent {
+		var errResp ArtifactUploadResponse
+		if json.Unmarshal(body, &errResp) == nil && errResp.Error != "" {
+			return &StatusError{StatusCode: resp.StatusCode, Message: errResp.Error}
+		}
+		return &StatusError{StatusCode: resp.StatusCode, Message: string(body)}
+	}
+	return nil
+}
+
+// CompleteArtifactUpload assembles the parts of a chunked upload into an
+// artifact of the execution
+func (c *Client) CompleteArtifactUpload(executionID, uploadID string, req ArtifactUploadCompleteRequest, serviceKey string) (*ArtifactUploadResponse, error) {
+	endpoint := fmt.Sprintf("%s/api/planning/execution/%s/artifacts/uploads/%s/complete", c.baseURL, executionID, url.PathEscape(uploadID))
+	c.log("Completing upload %s (%d parts)", uploadID, req.Parts)
+
+	jsonData, err := json.Marshal(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal request: %w", err)
+	}
+
+	httpReq, err := http.NewRequest(http.MethodPost, endpoint, bytes.NewBuffer(jsonData))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+
+	httpReq.Header.Set("X-Kindship-Service-Key", serviceKey)
+	httpReq.Header.Set("Content-Type", "application/json")
+	httpReq.Header.Set("Accept", "application/json")
+	httpReq.Header.Set("User-Agent", "kindship-cli/1.0")
+
+	resp, err := c.httpClient.Do(httpReq)
+	if err != nil {
+		return nil, fmt.Errorf("request failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read response: %w", err)
+	}
+
+	if resp.StatusCode != http.StatusOK && resp.StatusCode != http.StatusCreated {
+		var errResp ArtifactUploadResponse
+		if json.Unmarshal(body, &errResp) == nil && errResp.Error != "" {
+			return nil, &StatusError{StatusCode: resp.StatusCode, Message: errResp.Error}
+		}
+		return nil, &StatusError{StatusCode: resp.StatusCode, Message: string(body)}
+	}
+
+	var uploadResp ArtifactUploadResponse
+	if err := json.Unmarshal(body, &uploadResp); err != nil {
+		return nil, fmt.Errorf("failed to parse response: %w", err)
+	}
+
+	c.log("Uploaded artifact: %s", uploadResp.ID)
+	return &uploadResp, nil
+}
+
+// UploadWorkspaceArchive stores a gzipped tar of the workspace directory
+// dir (relative to the workspace) for the agent, replacing the previous one.
+// The size bytes of archive are streamed, not buffered.
+func (c *Client) UploadWorkspaceArchive(agentID, dir string, archive io.Reader, size int64, serviceKey string) error {
+	endpoint := c.workspaceURL(agentID, dir)
+	c.log("Uploading workspace archive %s for agent %s (%d bytes)", dir, agentID, size)
+
+	req, err := http.NewRequest(http.MethodPut, endpoint, archive)
+	if err != nil {
+		return fmt.Errorf("failed to create request: %w", err)
+	}
+	req.ContentLength = size
+
+	req.Header.Set("X-Kindship-Service-Key", serviceKey)
+	req.Header.Set("Content-Type", "application/gzip")
+	req.Header.Set("Accept", "application/json")
+	req.Header.Set("User-Agent", "kindship-cli/1.0")
+
+	resp, err := c.transferClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("request failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return fmt.Errorf("failed to read response: %w", err)
+	}
+	if resp.StatusCode != http.StatusOK && resp.StatusCode != http.StatusCreated {
+		return workspaceStatusError(resp.StatusCode, body)
+	}
+	return nil
+}
+
+// DownloadWorkspaceArchive returns the agent's stored archive of the
+// workspace directory dir as a stream the caller must close. A
+// *StatusError with StatusCode 404 means none was stored.
+func (c *Client) DownloadWorkspaceArchive(agentID, dir, serviceKey string) (io.ReadCloser, error) {
+	endpoint := c.workspaceURL(agentID, dir)
+	c.log("Downloading workspace archive %s for agent %s", dir, agentID)
+
+	req, err := http.NewRequest(http.MethodGet, endpoint, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+
+	req.Header.Set("X-Kindship-Service-Key", serviceKey)
+	req.Header.Set("Accept", "application/gzip")
+	req.Header.Set("User-Agent", "kindship-cli/1.0")
+
+	resp, err := c.transferClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("request failed: %w", err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		defer resp.Body.Close()
+		body, err := io.ReadAll(io.LimitReader(resp.Body, 64<<10))
+		if err != nil {
+			return nil, fmt.Errorf("failed to read response: %w", err)
+		}
+		return nil, workspaceStatusError(resp.StatusCode, body)
+	}
+
+	c.log("Downloading workspace archive %s (%d bytes)", dir, resp.ContentLength)
+	return resp.Body, nil
+}
+
+// workspaceURL is the endpoint of an agent's archive of dir
+func (c *Client) workspaceURL(agentID, dir string) string {
+	q := url.Values{}
+	q.Set("path", dir)
+	return fmt.Sprintf("%s/api/cli/agent/%s/workspace?%s", c.baseURL, url.PathEscape(agentID), q.Encode())
+}
+
+// workspaceStatusError builds the StatusError of a failed workspace
+// archive request
+func workspaceStatusError(statusCode int, body []byte) error {
+	var errResp struct {
+		Error string `json:"error"`
+	}
+	if json.Unmarshal(body, &errResp) == nil && errResp.Error != "" {
+		return &StatusError{StatusCode: statusCode, Message: errResp.Error}
+	}
+	return &StatusError{StatusCode: statusCode, Message: string(body)}
+}
+
+// CreateEntity creates a single task under an existing parent entity.
+// Uses X-Kindship-Service-Key header for /api/cli/* endpoints.
+func (c *Client) CreateEntity(req EntityCreateRequest, serviceKey string) (*EntityResponse, error) {
+	endpoint := fmt.Sprintf("%s/api/cli/entity", c.baseURL)
+	c.log("Creating entity %q under %s", req.Title, req.ParentID)
+	return c.sendEntity(http.MethodPost, endpoint, req, serviceKey)
+}
+
+// UpdateEntity changes the given fields of an entity.
+// Uses X-Kindship-Service-Key header for /api/cli/* endpoints.
+func (c *Client) UpdateEntity(entityID string, req EntityUpdateRequest, serviceKey string) (*EntityResponse, error) {
+	endpoint := fmt.Sprintf("%s/api/cli/entity/%s", c.baseURL, entityID)
+	c.log("Updating entity: %s", entityID)
+	return c.sendEntity(http.MethodPatch, endpoint, req, serviceKey)
+}
+
+// sendEntity sends an entity create or update request
+func (c *Client) sendEntity(method, endpoint string, payload interface{}, serviceKey string) (*EntityResponse, error) {
+	jsonData, err := json.Marshal(payload)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal request: %w", err)
+	}
+
+	req, err := http.NewRequest(method, endpoint, bytes.NewBuffer(jsonData))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+
+	req.Header.Set("X-Kindship-Service-Key", serviceKey)
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Accept", "application/json")
+	req.Header.Set("User-Agent", "kindship-cli/1.0")
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("request failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read response: %w", err)
+	}
+
+	if resp.StatusCode != http.StatusOK && resp.StatusCode != http.StatusCreated {
+		var errResp EntityResponse
+		if json.Unmarshal(body, &errResp) == nil && errResp.Error != "" {
+			return nil, &StatusError{StatusCode: resp.StatusCode, Message: errResp.Error}
+		}
+		return nil, &StatusError{StatusCode: resp.StatusCode, Message: string(body)}
+	}
+
+	var entityResp EntityResponse
+	if err := json.Unmarshal(body, &entityResp); err != nil {
+		return nil, fmt.Errorf("failed to parse response: %w", err)
+	}
+
+	c.log("Saved entity: %s", entityResp.Entity.ID)
+	return &entityResp, nil
+}
+
+// CancelEntity moves an entity to CANCELLED so it is never executed.
+// Uses X-Kindship-Service-Key header for /api/cli/* endpoints.
+func (c *Client) CancelEntity(entityID, serviceKey string) (*EntityStatusResponse, error) {
+	return c.postEntityAction(entityID, "cancel", serviceKey)
+}
+
+// DeactivateEntity moves an ACTIVE entity back to DRAFT.
+// Uses X-Kindship-Service-Key header for /api/cli/* endpoints.
+func (c *Client) DeactivateEntity(entityID, serviceKey string) (*EntityStatusResponse, error) {
+	return c.postEntityAction(entityID, "deactivate", serviceKey)
+}
+
+// postEntityAction posts to /api/cli/entity/{id}/{action}
+func (c *Client) postEntityAction(entityID, action, serviceKey string) (*EntityStatusResponse, error) {
+	endpoint := fmt.Sprintf("%s/api/cli/entity/%s/%s", c.baseURL, entityID, action)
+	c.log("Entity %s: %s", action, entityID)
+
+	req, err := http.NewRequest(http.MethodPost, endpoint, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+
+	req.Header.Set("X-Kindship-Service-Key", serviceKey)
+	req.Header.Set("Accept", "application/json")
+	req.Header.Set("User-Agent", "kindship-cli/1.0")
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("request failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read response: %w", err)
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		var errResp EntityStatusResponse
+		if json.Unmarshal(body, &errResp) == nil && errResp.Error != "" {
+			return nil, &StatusError{StatusCode: resp.StatusCode, Message: errResp.Error}
+		}
+		return nil, &StatusError{StatusCode: resp.StatusCode, Message: string(body)}
+	}
+
+	var statusResp EntityStatusResponse
+	if err := json.Unmarshal(body, &statusResp); err != nil {
+		return nil, fmt.Errorf("failed to parse response: %w", err)
+	}
+
+	c.log("Entity %s is now %s", entityID, statusResp.Status)
+	return &statusResp, nil
+}
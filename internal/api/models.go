@@ -13,6 +13,7 @@ const (
 	ExecutionModePython        ExecutionMode = "PYTHON"
 	ExecutionModeAskUser       ExecutionMode = "ASK_USER"
 	ExecutionModeOrchestrate   ExecutionMode = "ORCHESTRATE"
+	ExecutionModeTest          ExecutionMode = "TEST"
 )
 
 // ExecutionAttemptStatus represents the status of an execution attempt
@@ -23,6 +24,9 @@ const (
 	ExecutionAttemptStatusSuccess   ExecutionAttemptStatus = "SUCCESS"
 	ExecutionAttemptStatusFailed    ExecutionAttemptStatus = "FAILED"
 	ExecutionAttemptStatusAbandoned ExecutionAttemptStatus = "ABANDONED"
+	// ExecutionAttemptStatusCancelled is set by the server when a user stops
+	// a running execution from the UI
+	ExecutionAttemptStatusCancelled ExecutionAttemptStatus = "CANCELLED"
 )
 
 // ValidationOutcome represents the result of a validation
@@ -56,6 +60,7 @@ type SuccessCriteria struct {
 type PlanningEntity struct {
 	ID                   string                 `json:"id"`
 	Type                 string                 `json:"type"`
+	Key                  string                 `json:"key,omitempty"`
 	Title                string                 `json:"title"`
 	Description          string                 `json:"description"`
 	ExecutionMode        ExecutionMode          `json:"execution_mode"`
@@ -88,6 +93,22 @@ type DependencyStatus struct {
 	Pending []PendingDependency `json:"pending"`
 }
 
+// PreflightReport records checks that stopped an entity before a run was
+// created, such as unmet dependencies, so the failure shows in the UI
+// without recording a failed attempt
+type PreflightReport struct {
+	AgentID           string             `json:"agent_id,omitempty"`
+	FailureReason     string             `json:"failure_reason"`
+	ValidationRecords []ValidationRecord `json:"validation_records"`
+}
+
+// PreflightReportResponse is the response from the pre-flight report endpoint
+type PreflightReportResponse struct {
+	Success bool   `json:"success"`
+	ID      string `json:"id,omitempty"`
+	Error   string `json:"error,omitempty"`
+}
+
 // EntityExecuteResponse represents the response from the entity execute endpoint
 type EntityExecuteResponse struct {
 	Entity             PlanningEntity         `json:"entity"`
@@ -113,6 +134,8 @@ type ExecutionStartResponse struct {
 // ExecutionOutputs represents the outputs from an execution attempt
 type ExecutionOutputs struct {
 	Artifacts   []string               `json:"artifacts,omitempty"`
+	Files       []ArtifactFile         `json:"artifact_files,omitempty"` // Checksums and types of the uploaded artifacts
+	Workspace   *WorkspaceDiff         `json:"workspace_diff,omitempty"` // Files the execution changed, with boundaries.workspace_diff
 	Metrics     map[string]interface{} `json:"metrics,omitempty"`
 	Stdout      string                 `json:"stdout,omitempty"`
 	Stderr      string                 `json:"stderr,omitempty"`
@@ -136,6 +159,7 @@ type ExecutionCompleteRequest struct {
 	Outputs           *ExecutionOutputs      `json:"outputs,omitempty"`
 	FailureReason     *string                `json:"failure_reason,omitempty"`
 	ValidationRecords []ValidationRecord     `json:"validation_records,omitempty"`
+	ValidationPolicy  string                 `json:"validation_policy,omitempty"` // boundaries.validation_policy applied to output_schema
 }
 
 // ExecutionCompleteResponse represents the response from completing an execution
@@ -144,6 +168,13 @@ type ExecutionCompleteResponse struct {
 	Message string `json:"message,omitempty"`
 }
 
+// ExecutionStatusResponse is the response from the execution status endpoint
+type ExecutionStatusResponse struct {
+	ExecutionID string                 `json:"execution_id"`
+	Status      ExecutionAttemptStatus `json:"status"`
+	Error       string                 `json:"error,omitempty"`
+}
+
 // PlanNextResponse is the response from plan/next
 type PlanNextResponse struct {
 	Task         *TaskInfo `json:"task"`
@@ -191,3 +222,278 @@ type RecoverRunsResponse struct {
 	SkippedAskUser int          `json:"skipped_ask_user"`
 	Error          string       `json:"error,omitempty"`
 }
+
+// HeartbeatRequest reports agent health and capabilities to the heartbeat endpoint
+type HeartbeatRequest struct {
+	AgentID       string            `json:"agent_id"`
+	CLIVersion    string            `json:"cli_version"`
+	Platform      string            `json:"platform"`
+	Hostname      string            `json:"hostname,omitempty"`
+	UptimeSeconds int64             `json:"uptime_seconds"`
+	FreeDiskBytes *uint64           `json:"free_disk_bytes,omitempty"`
+	Runtimes      map[string]string `json:"runtimes"`
+	CurrentTaskID string            `json:"current_task_id,omitempty"`
+	Status        string            `json:"status"`
+	// AvailableMemoryBytes is omitted where it cannot be measured
+	AvailableMemoryBytes *uint64 `json:"available_memory_bytes,omitempty"`
+	// DegradedReason explains a "degraded" status
+	DegradedReason string `json:"degraded_reason,omitempty"`
+}
+
+// HeartbeatResponse is the response from the heartbeat endpoint
+type HeartbeatResponse struct {
+	Success bool   `json:"success"`
+	Error   string `json:"error,omitempty"`
+}
+
+// AgentEvent is a lifecycle transition of an agent loop
+type AgentEvent string
+
+const (
+	AgentEventStarted     AgentEvent = "started"
+	AgentEventIdle        AgentEvent = "idle"
+	AgentEventTaskClaimed AgentEvent = "task_claimed"
+	AgentEventDraining    AgentEvent = "draining"
+	AgentEventStopped     AgentEvent = "stopped"
+)
+
+// AgentEventRequest reports a lifecycle event to the agent events endpoint
+type AgentEventRequest struct {
+	AgentID    string                 `json:"agent_id"`
+	Event      AgentEvent             `json:"event"`
+	OccurredAt time.Time              `json:"occurred_at"`
+	TaskID     string                 `json:"task_id,omitempty"`
+	Reason     string                 `json:"reason,omitempty"`
+	Details    map[string]interface{} `json:"details,omitempty"`
+}
+
+// AgentEventResponse is the response from the agent events endpoint
+type AgentEventResponse struct {
+	Success bool   `json:"success"`
+	Error   string `json:"error,omitempty"`
+}
+
+// ExecutionAttempt is a past or running execution attempt of an entity
+type ExecutionAttempt struct {
+	ID            string                 `json:"id"`
+	EntityID      string                 `json:"entity_id"`
+	AttemptNumber int                    `json:"attempt_number"`
+	Status        ExecutionAttemptStatus `json:"status"`
+	ExecutionMode ExecutionMode          `json:"execution_mode"`
+	AgentID       string                 `json:"agent_id,omitempty"`
+	FailureReason *string                `json:"failure_reason,omitempty"`
+	StartedAt     time.Time              `json:"started_at"`
+	CompletedAt   *time.Time             `json:"completed_at,omitempty"`
+	// Outputs are only included when requested (ListAttemptsWithOutputs)
+	Outputs       *ExecutionOutputs      `json:"outputs,omitempty"`
+}
+
+// Duration returns how long the attempt ran, or 0 if it has not completed
+func (a *ExecutionAttempt) Duration() time.Duration {
+	if a.CompletedAt == nil {
+		return 0
+	}
+	return a.CompletedAt.Sub(a.StartedAt)
+}
+
+// ListAttemptsResponse is the response from the entity attempts endpoint
+type ListAttemptsResponse struct {
+	Attempts []ExecutionAttempt `json:"attempts"`
+	Error    string             `json:"error,omitempty"`
+}
+
+// StoredValidationRecord is a validation record as stored by the API
+type StoredValidationRecord struct {
+	ValidationRecord
+	ID          string    `json:"id"`
+	ExecutionID string    `json:"execution_id"`
+	CreatedAt   time.Time `json:"created_at"`
+}
+
+// ListValidationsResponse is the response from the entity validations endpoint
+type ListValidationsResponse struct {
+	Validations []StoredValidationRecord `json:"validation_records"`
+	Error       string                   `json:"error,omitempty"`
+}
+
+// ArtifactUploadResponse is the response from the execution artifact upload endpoint
+type ArtifactUploadResponse struct {
+	ID    string `json:"id"`
+	Name  string `json:"name"`
+	URL   string `json:"url,omitempty"`
+	Error string `json:"error,omitempty"`
+}
+
+// ArtifactFile records an uploaded artifact in the execution outputs
+type ArtifactFile struct {
+	Name        string `json:"name"`
+	ID          string `json:"id"`
+	URL         string `json:"url,omitempty"`
+	ContentType string `json:"content_type"`
+	Size        int64  `json:"size"`
+	SHA256      string `json:"sha256"`
+}
+
+// File changes listed in a WorkspaceDiff
+const (
+	FileAdded    = "added"
+	FileModified = "modified"
+	FileDeleted  = "deleted"
+)
+
+// WorkspaceDiff summarizes the files an execution added, modified and
+// deleted in the workspace. Files lists the changes in path order and is
+// Truncated when there were more than the CLI lists.
+type WorkspaceDiff struct {
+	Added     int          `json:"added"`
+	Modified  int          `json:"modified"`
+	Deleted   int          `json:"deleted"`
+	Files     []FileChange `json:"files"`
+	Truncated bool         `json:"truncated,omitempty"`
+}
+
+// FileChange is one changed file of a WorkspaceDiff. The before fields are
+// empty for an added file and the after fields for a deleted one.
+type FileChange struct {
+	Path         string `json:"path"`
+	Change       string `json:"change"`
+	SizeBefore   int64  `json:"size_before,omitempty"`
+	SizeAfter    int64  `json:"size_after,omitempty"`
+	SHA256Before string `json:"sha256_before,omitempty"`
+	SHA256After  string `json:"sha256_after,omitempty"`
+}
+
+// ArtifactUploadStartRequest starts a chunked artifact upload
+type ArtifactUploadStartRequest struct {
+	Name        string `json:"name"`
+	ContentType string `json:"content_type"`
+	Size        int64  `json:"size"`
+	SHA256      string `json:"sha256"`
+}
+
+// ArtifactUploadSession is the server's answer to a chunked upload start:
+// the parts must be ChunkSize bytes, except the last
+type ArtifactUploadSession struct {
+	UploadID  string `json:"upload_id"`
+	ChunkSize int64  `json:"chunk_size"`
+	Error     string `json:"error,omitempty"`
+}
+
+// ArtifactUploadCompleteRequest assembles the uploaded parts into an
+// artifact; the server checks the assembled content against SHA256
+type ArtifactUploadCompleteRequest struct {
+	Parts  int    `json:"parts"`
+	SHA256 string `json:"sha256"`
+}
+
+// EntityCreateRequest creates a single task under an existing parent entity
+type EntityCreateRequest struct {
+	ParentID            string                 `json:"parent_id"`
+	AgentID             string                 `json:"agent_id,omitempty"`
+	Key                 string                 `json:"key,omitempty"`
+	Title               string                 `json:"title"`
+	Description         string                 `json:"description,omitempty"`
+	SequenceOrder       int                    `json:"sequence_order,omitempty"`
+	ExecutionMode       string                 `json:"execution_mode,omitempty"`
+	Code                string                 `json:"code,omitempty"`
+	DependenciesLabeled map[string]string      `json:"dependencies_labeled,omitempty"`
+	InputSchema         map[string]interface{} `json:"input_schema,omitempty"`
+	OutputSchema        map[string]interface{} `json:"output_schema,omitempty"`
+	SuccessCriteria     *SuccessCriteria       `json:"success_criteria,omitempty"`
+	Boundaries          map[string]interface{} `json:"boundaries,omitempty"`
+}
+
+// EntityUpdateRequest changes fields of an entity. Nil fields are left
+// unchanged.
+type EntityUpdateRequest struct {
+	Key                 *string                `json:"key,omitempty"`
+	Title               *string                `json:"title,omitempty"`
+	Description         *string                `json:"description,omitempty"`
+	SequenceOrder       *int                   `json:"sequence_order,omitempty"`
+	ExecutionMode       *string                `json:"execution_mode,omitempty"`
+	Code                *string                `json:"code,omitempty"`
+	DependenciesLabeled map[string]string      `json:"dependencies_labeled,omitempty"`
+	InputSchema         map[string]interface{} `json:"input_schema,omitempty"`
+	OutputSchema        map[string]interface{} `json:"output_schema,omitempty"`
+	SuccessCriteria     *SuccessCriteria       `json:"success_criteria,omitempty"`
+	Boundaries          map[string]interface{} `json:"boundaries,omitempty"`
+}
+
+// EntityResponse is the response from the entity create and update endpoints
+type EntityResponse struct {
+	Entity PlanningEntity `json:"entity"`
+	Error  string         `json:"error,omitempty"`
+}
+
+// EntityStatusResponse is the response from the entity cancel and
+// deactivate endpoints
+type EntityStatusResponse struct {
+	ID     string `json:"id"`
+	Status string `json:"status"`
+	Error  string `json:"error,omitempty"`
+}
+
+// Optional server features. Older (self-hosted) servers may lack them, so
+// the CLI only uses a feature the server advertises.
+const (
+	FeatureHeartbeats     = "heartbeats"
+	FeatureAgentEvents    = "agent_events"
+	FeatureArtifacts      = "artifacts"
+	FeatureEscalation     = "escalation"
+	FeatureArtifactChunks = "artifact_chunks"
+	FeaturePreflight      = "preflight_reports"
+)
+
+// LegacyFeatures are the features servers had before capability
+// negotiation; servers that answer 404 to it are assumed to support them
+var LegacyFeatures = []string{FeatureHeartbeats, FeatureAgentEvents, FeatureArtifacts}
+
+// CapabilitiesRequest tells the server which CLI version is negotiating
+type CapabilitiesRequest struct {
+	CLIVersion string `json:"cli_version"`
+}
+
+// Capabilities is the server's answer to a capability negotiation
+type Capabilities struct {
+	APIVersion string   `json:"api_version,omitempty"`
+	Features   []string `json:"features"`
+	Error      string   `json:"error,omitempty"`
+
+	// Unknown is set when negotiation failed for a reason other than the
+	// server predating it; every feature is then assumed to be supported
+	Unknown bool `json:"-"`
+	// Legacy is set when the server predates negotiation; Features are
+	// then LegacyFeatures
+	Legacy bool `json:"-"`
+}
+
+// Supports reports whether the server advertised feature
+func (c *Capabilities) Supports(feature string) bool {
+	if c.Unknown {
+		return true
+	}
+	for _, f := range c.Features {
+		if f == feature {
+			return true
+		}
+	}
+	return false
+}
+
+// EscalationRequest asks the platform to raise the priority of a question
+// that has been waiting on a user for too long
+type EscalationRequest struct {
+	AgentID        string `json:"agent_id"`
+	EntityID       string `json:"entity_id"`
+	WaitingSeconds int64  `json:"waiting_seconds"`
+	// Reminder counts the reminders sent for the question, starting at 1
+	Reminder int    `json:"reminder"`
+	Reason   string `json:"reason,omitempty"`
+}
+
+// EscalationResponse is the response from the execution escalate endpoint
+type EscalationResponse struct {
+	Success  bool   `json:"success"`
+	Priority string `json:"priority,omitempty"`
+	Error    string `json:"error,omitempty"`
+}
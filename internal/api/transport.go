@@ -0,0 +1,33 @@
+package api
+
+import (
+	"net"
+	"net/http"
+	"time"
+)
+
+// sharedTransport is reused by every HTTP client the CLI creates, so
+// connections (and their TLS sessions) to the API are kept alive across
+// requests instead of being re-established on every poll
+var sharedTransport = &http.Transport{
+	Proxy: http.ProxyFromEnvironment,
+	DialContext: (&net.Dialer{
+		Timeout:   10 * time.Second,
+		KeepAlive: 30 * time.Second,
+	}).DialContext,
+	ForceAttemptHTTP2:     true,
+	MaxIdleConns:          32,
+	MaxIdleConnsPerHost:   8,
+	IdleConnTimeout:       90 * time.Second,
+	TLSHandshakeTimeout:   10 * time.Second,
+	ExpectContinueTimeout: 1 * time.Second,
+}
+
+// NewHTTPClient returns an HTTP client with the given overall request
+// timeout that shares the CLI's connection pool
+func NewHTTPClient(timeout time.Duration) *http.Client {
+	return &http.Client{
+		Transport: sharedTransport,
+		Timeout:   timeout,
+	}
+}
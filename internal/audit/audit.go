@@ -0,0 +1,253 @@
+package audit
+
+import (
+	"bytes"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/kindship-ai/kindship-cli/internal/checksum"
+	"github.com/kindship-ai/kindship-cli/internal/config"
+)
+
+// AuditFile is the default audit log filename in the global config directory.
+// Override the full path with KINDSHIP_AUDIT_LOG, or set it to "off" to disable.
+const AuditFile = "audit.jsonl"
+
+// tailWindow bounds how much of the file is read to find the previous entry
+const tailWindow = 64 * 1024
+
+const (
+	// lockTimeout is how long Record waits for another process to finish
+	// appending
+	lockTimeout = 5 * time.Second
+	// lockStale is the age after which a lock is assumed to be left behind
+	// by a killed process
+	lockStale = 30 * time.Second
+)
+
+// Entry is a single execution attempt recorded in the audit log
+type Entry struct {
+	Timestamp   time.Time `json:"timestamp"`
+	EntityID    string    `json:"entity_id"`
+	ExecutionID string    `json:"execution_id,omitempty"`
+	Mode        string    `json:"mode"`
+	CommandHash string    `json:"command_hash,omitempty"`
+	Status      string    `json:"status"`
+	ExitCode    int       `json:"exit_code"`
+	DurationMs  int64     `json:"duration_ms"`
+	AgentID     string    `json:"agent_id,omitempty"`
+	TriggeredBy string    `json:"triggered_by,omitempty"`
+	Hostname    string    `json:"hostname,omitempty"`
+	User        string    `json:"user,omitempty"`
+	Reason      string    `json:"reason,omitempty"`
+
+	// Set when KINDSHIP_AUDIT_HMAC_KEY is configured. Each entry's HMAC
+	// covers the entry and the previous HMAC, so removing or editing a
+	// line breaks the chain for every later entry.
+	PrevHMAC string `json:"prev_hmac,omitempty"`
+	HMAC     string `json:"hmac,omitempty"`
+}
+
+// mu serializes Record within the process; lock serializes processes
+var mu sync.Mutex
+
+// HashCommand returns a hex SHA-256 of the executed code or prompt
+func HashCommand(command string) string {
+	if command == "" {
+		return ""
+	}
+	return checksum.SHA256([]byte(command))
+}
+
+// Path returns the audit log location, or "" if auditing is disabled
+func Path() (string, error) {
+	if path := os.Getenv("KINDSHIP_AUDIT_LOG"); path != "" {
+		if path == "off" {
+			return "", nil
+		}
+		return path, nil
+	}
+	dir, err := config.GetStateDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, AuditFile), nil
+}
+
+// Record appends an entry to the audit log, filling in host and user
+// details and chaining the HMAC when a key is configured.
+func Record(entry Entry) error {
+	path, err := Path()
+	if err != nil || path == "" {
+		return err
+	}
+
+	if entry.Timestamp.IsZero() {
+		entry.Timestamp = time.Now().UTC()
+	}
+	if entry.Hostname == "" {
+		entry.Hostname, _ = os.Hostname()
+	}
+	if entry.User == "" {
+		entry.User = currentUser()
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+
+	if err := os.MkdirAll(filepath.Dir(path), config.ConfigDirMode); err != nil {
+		return fmt.Errorf("failed to create audit directory: %w", err)
+	}
+
+	// Reading the previous HMAC and appending must not interleave with
+	// another agent or hook writing the same log, or the chain forks
+	unlock, err := lock(path)
+	if err != nil {
+		return err
+	}
+	defer unlock()
+
+	f, err := os.OpenFile(path, os.O_CREATE|os.O_RDWR|os.O_APPEND, config.ConfigFileMode)
+	if err != nil {
+		return fmt.Errorf("failed to open audit log: %w", err)
+	}
+	defer f.Close()
+
+	if key := os.Getenv("KINDSHIP_AUDIT_HMAC_KEY"); key != "" {
+		prev, err := lastHMAC(f)
+		if err != nil {
+			return err
+		}
+		entry.PrevHMAC = prev
+		entry.HMAC = ""
+		entry.HMAC, err = sign(entry, key)
+		if err != nil {
+			return err
+		}
+	}
+
+	line, err := json.Marshal(entry)
+	if err != nil {
+		return fmt.Errorf("failed to marshal audit entry: %w", err)
+	}
+	if _, err := f.Write(append(line, '\n')); err != nil {
+		return fmt.Errorf("failed to write audit entry: %w", err)
+	}
+	return nil
+}
+
+// lock takes the log's lock, a file created exclusively next to it,
+// waiting up to lockTimeout. A lock older than lockStale is taken over.
+func lock(path string) (unlock func(), err error) {
+	lockPath := path + ".lock"
+	deadline := time.Now().Add(lockTimeout)
+	for {
+		f, err := os.OpenFile(lockPath, os.O_WRONLY|os.O_CREATE|os.O_EXCL, config.ConfigFileMode)
+		if err == nil {
+			f.Close()
+			return func() { os.Remove(lockPath) }, nil
+		}
+		if !os.IsExist(err) {
+			return nil, fmt.Errorf("failed to lock audit log: %w", err)
+		}
+		if info, err := os.Stat(lockPath); err == nil && time.Since(info.ModTime()) > lockStale {
+			os.Remove(lockPath)
+			continue
+		}
+		if time.Now().After(deadline) {
+			return nil, fmt.Errorf("failed to lock audit log: %s held for over %v", lockPath, lockTimeout)
+		}
+		time.Sleep(20 * time.Millisecond)
+	}
+}
+
+// sign computes the HMAC over the entry with its HMAC field empty
+func sign(entry Entry, key string) (string, error) {
+	entry.HMAC = ""
+	payload, err := json.Marshal(entry)
+	if err != nil {
+		return "", fmt.Errorf("failed to marshal audit entry: %w", err)
+	}
+	mac := hmac.New(sha256.New, []byte(key))
+	mac.Write(payload)
+	return hex.EncodeToString(mac.Sum(nil)), nil
+}
+
+// lastHMAC returns the HMAC of the final entry in the file, if any
+func lastHMAC(f *os.File) (string, error) {
+	info, err := f.Stat()
+	if err != nil {
+		return "", fmt.Errorf("failed to stat audit log: %w", err)
+	}
+	size := info.Size()
+	if size == 0 {
+		return "", nil
+	}
+
+	offset := size - tailWindow
+	if offset < 0 {
+		offset = 0
+	}
+	buf := make([]byte, size-offset)
+	if _, err := f.ReadAt(buf, offset); err != nil && err != io.EOF {
+		return "", fmt.Errorf("failed to read audit log: %w", err)
+	}
+
+	lines := bytes.Split(bytes.TrimRight(buf, "\n"), []byte("\n"))
+	var last Entry
+	if err := json.Unmarshal(lines[len(lines)-1], &last); err != nil {
+		return "", fmt.Errorf("failed to parse last audit entry: %w", err)
+	}
+	return last.HMAC, nil
+}
+
+// Verify walks the audit log and checks the HMAC chain with key. Returns
+// the number of verified entries, or an error naming the first bad line.
+func Verify(path, key string) (int, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return 0, fmt.Errorf("failed to read audit log: %w", err)
+	}
+
+	prev := ""
+	count := 0
+	for i, line := range bytes.Split(bytes.TrimRight(data, "\n"), []byte("\n")) {
+		if len(line) == 0 {
+			continue
+		}
+		var entry Entry
+		if err := json.Unmarshal(line, &entry); err != nil {
+			return count, fmt.Errorf("line %d: invalid JSON: %w", i+1, err)
+		}
+		if entry.PrevHMAC != prev {
+			return count, fmt.Errorf("line %d: chain broken (prev_hmac mismatch)", i+1)
+		}
+		expected, err := sign(entry, key)
+		if err != nil {
+			return count, err
+		}
+		if !hmac.Equal([]byte(expected), []byte(entry.HMAC)) {
+			return count, fmt.Errorf("line %d: HMAC mismatch", i+1)
+		}
+		prev = entry.HMAC
+		count++
+	}
+	return count, nil
+}
+
+func currentUser() string {
+	for _, key := range []string{"USER", "USERNAME", "LOGNAME"} {
+		if u := os.Getenv(key); u != "" {
+			return u
+		}
+	}
+	return ""
+}
@@ -32,6 +32,15 @@ type Context struct {
 	TokenPrefix string
 	TokenExpiry time.Time
 
+	// AccountID is the account selected at login, if any
+	AccountID   string
+	AccountName string
+
+	// Scopes and RateLimitTier are those reported at login; 'kindship
+	// whoami' fetches the current values
+	Scopes        []string
+	RateLimitTier string
+
 	// API configuration
 	APIBaseURL string
 }
@@ -47,7 +56,7 @@ func GetAuthContext() (*Context, error) {
 		agentID := os.Getenv("AGENT_ID")
 		apiURL := os.Getenv("KINDSHIP_API_URL")
 		if apiURL == "" {
-			apiURL = "https://kindship.ai"
+			apiURL = config.DefaultAPIBaseURL()
 		}
 
 		return &Context{
@@ -85,15 +94,19 @@ func GetAuthContext() (*Context, error) {
 	}
 
 	return &Context{
-		Method:      AuthMethodOAuth,
-		Token:       cfg.Token,
-		AgentID:     agentID,
-		UserID:      cfg.UserID,
-		UserEmail:   cfg.UserEmail,
-		TokenID:     cfg.TokenID,
-		TokenPrefix: cfg.TokenPrefix,
-		TokenExpiry: cfg.TokenExpiry,
-		APIBaseURL:  cfg.GetAPIBaseURL(),
+		Method:        AuthMethodOAuth,
+		Token:         cfg.Token,
+		AgentID:       agentID,
+		UserID:        cfg.UserID,
+		UserEmail:     cfg.UserEmail,
+		TokenID:       cfg.TokenID,
+		TokenPrefix:   cfg.TokenPrefix,
+		TokenExpiry:   cfg.TokenExpiry,
+		AccountID:     cfg.AccountID,
+		AccountName:   cfg.AccountName,
+		Scopes:        cfg.Scopes,
+		RateLimitTier: cfg.RateLimitTier,
+		APIBaseURL:    cfg.GetAPIBaseURL(),
 	}, nil
 }
 
@@ -124,12 +137,16 @@ func (c *Context) GetAuthHeader() string {
 
 // SetAuthHeaders sets the appropriate authentication headers on the request.
 // Container mode → X-Kindship-Service-Key header
-// OAuth mode    → Authorization: Bearer <token> header
+// OAuth mode    → Authorization: Bearer <token> header, plus
+// X-Kindship-Account-ID when an account was selected at login
 func (c *Context) SetAuthHeaders(req *http.Request) {
 	if c.IsContainerMode() {
 		req.Header.Set("X-Kindship-Service-Key", c.Token)
 	} else {
 		req.Header.Set("Authorization", c.GetAuthHeader())
+		if c.AccountID != "" {
+			req.Header.Set("X-Kindship-Account-ID", c.AccountID)
+		}
 	}
 }
 
@@ -0,0 +1,42 @@
+package boundaries
+
+import "fmt"
+
+// DefaultArtifactCapMB caps the artifacts uploaded by one run, LLM
+// transcript included, unless boundaries.artifacts.max_total_mb is set
+const DefaultArtifactCapMB = 1024
+
+// ArtifactPolicy declares files to attach to the run as artifacts once the
+// task has executed.
+//
+//	"artifacts": {"paths": ["dist/*.whl", "reports"], "max_total_mb": 2048}
+//
+// Paths are glob patterns relative to the workspace; a matching directory
+// is attached file by file. Files larger than the server's single-request
+// limit are uploaded in chunks.
+type ArtifactPolicy struct {
+	Paths      []string `json:"paths,omitempty"`
+	MaxTotalMB int      `json:"max_total_mb,omitempty"`
+}
+
+// Validate checks the patterns stay inside the workspace and the cap is
+// not negative
+func (a *ArtifactPolicy) Validate() error {
+	for _, p := range a.Paths {
+		if p == "" || !insideWorkspace(p) {
+			return fmt.Errorf("invalid artifact path %q: must be relative to the workspace and inside it", p)
+		}
+	}
+	if a.MaxTotalMB < 0 {
+		return fmt.Errorf("artifacts.max_total_mb must not be negative")
+	}
+	return nil
+}
+
+// MaxTotalBytes returns the cap on the artifacts uploaded by one run
+func (a *ArtifactPolicy) MaxTotalBytes() int64 {
+	if a == nil || a.MaxTotalMB == 0 {
+		return DefaultArtifactCapMB << 20
+	}
+	return int64(a.MaxTotalMB) << 20
+}
@@ -0,0 +1,166 @@
+package boundaries
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/kindship-ai/kindship-cli/internal/validator"
+)
+
+// Policy is the typed view of a planning entity's boundaries map.
+// Unknown keys are ignored so the server can add boundaries before the
+// CLI enforces them.
+type Policy struct {
+	Commands      *CommandPolicy       `json:"commands,omitempty"`
+	Network       *NetworkPolicy       `json:"network,omitempty"`
+	Retry         *RetryPolicy         `json:"retry,omitempty"`
+	LLM           *LLMPolicy           `json:"llm,omitempty"`
+	Inputs        *InputPolicy         `json:"inputs,omitempty"`
+	Shell         *ShellPolicy         `json:"shell,omitempty"`
+	Secrets       SecretList           `json:"secrets,omitempty"`
+	Env           EnvVars              `json:"env,omitempty"`
+	Artifacts     *ArtifactPolicy      `json:"artifacts,omitempty"`
+	WorkspaceDiff *WorkspaceDiffPolicy `json:"workspace_diff,omitempty"`
+
+	// WorkDir is the directory, relative to the workspace, that executions
+	// run in (e.g. a package of a monorepo). Defaults to the workspace.
+	WorkDir string `json:"workdir,omitempty"`
+
+	// Template opts in to rendering entity code as a Go template with the
+	// run inputs before execution
+	Template bool `json:"template,omitempty"`
+
+	// TestReports lists glob patterns, relative to the workspace, of the
+	// JUnit XML or TAP reports written by a TEST execution
+	TestReports []string `json:"test_reports,omitempty"`
+
+	// OutputExtraction picks which JSON value in stdout becomes the
+	// structured output: first (the default), last, largest or fenced-only
+	OutputExtraction string `json:"output_extraction,omitempty"`
+
+	// ValidationPolicy sets what an output_schema failure does to the
+	// attempt: strict fails it, warn (the default) records a warning and
+	// off skips output validation
+	ValidationPolicy string `json:"validation_policy,omitempty"`
+}
+
+// Output validation policies accepted in boundaries.validation_policy
+const (
+	ValidationStrict = "strict"
+	ValidationWarn   = "warn"
+	ValidationOff    = "off"
+)
+
+// OutputValidation returns the output validation policy
+func (p *Policy) OutputValidation() string {
+	if p == nil || p.ValidationPolicy == "" {
+		return ValidationWarn
+	}
+	return p.ValidationPolicy
+}
+
+// ExtractionStrategy returns the output extraction strategy
+func (p *Policy) ExtractionStrategy() string {
+	if p == nil || p.OutputExtraction == "" {
+		return validator.ExtractFirst
+	}
+	return p.OutputExtraction
+}
+
+// Parse decodes an entity's boundaries into a Policy. A nil or empty map
+// yields an empty policy that permits everything.
+func Parse(raw map[string]interface{}) (*Policy, error) {
+	policy := &Policy{}
+	if len(raw) == 0 {
+		return policy, nil
+	}
+
+	data, err := json.Marshal(raw)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal boundaries: %w", err)
+	}
+	if err := json.Unmarshal(data, policy); err != nil {
+		return nil, fmt.Errorf("invalid boundaries: %w", err)
+	}
+
+	if policy.Network != nil {
+		if err := policy.Network.Validate(); err != nil {
+			return nil, fmt.Errorf("invalid boundaries: %w", err)
+		}
+	}
+	if policy.Retry != nil {
+		if err := policy.Retry.Validate(); err != nil {
+			return nil, fmt.Errorf("invalid boundaries: %w", err)
+		}
+	}
+	if policy.LLM != nil {
+		if err := policy.LLM.Validate(); err != nil {
+			return nil, fmt.Errorf("invalid boundaries: %w", err)
+		}
+	}
+	if policy.Inputs != nil {
+		if err := policy.Inputs.Validate(); err != nil {
+			return nil, fmt.Errorf("invalid boundaries: %w", err)
+		}
+	}
+	if policy.Shell != nil {
+		if err := policy.Shell.Validate(); err != nil {
+			return nil, fmt.Errorf("invalid boundaries: %w", err)
+		}
+	}
+	if policy.Artifacts != nil {
+		if err := policy.Artifacts.Validate(); err != nil {
+			return nil, fmt.Errorf("invalid boundaries: %w", err)
+		}
+	}
+	if policy.WorkspaceDiff != nil {
+		if err := policy.WorkspaceDiff.Validate(); err != nil {
+			return nil, fmt.Errorf("invalid boundaries: %w", err)
+		}
+	}
+	if err := policy.Secrets.Validate(); err != nil {
+		return nil, fmt.Errorf("invalid boundaries: %w", err)
+	}
+	if err := policy.Env.Validate(); err != nil {
+		return nil, fmt.Errorf("invalid boundaries: %w", err)
+	}
+	for _, name := range policy.Secrets {
+		if _, ok := policy.Env[name]; ok {
+			return nil, fmt.Errorf("invalid boundaries: %s is both in env and secrets", name)
+		}
+	}
+	if policy.WorkDir != "" {
+		if err := validateWorkDir(policy.WorkDir); err != nil {
+			return nil, fmt.Errorf("invalid boundaries: %w", err)
+		}
+	}
+	if policy.OutputExtraction != "" && !validExtraction(policy.OutputExtraction) {
+		return nil, fmt.Errorf("invalid boundaries: unknown output_extraction %q (expected %s)",
+			policy.OutputExtraction, strings.Join(validator.ExtractionStrategies, ", "))
+	}
+	switch policy.ValidationPolicy {
+	case "", ValidationStrict, ValidationWarn, ValidationOff:
+	default:
+		return nil, fmt.Errorf("invalid boundaries: unknown validation_policy %q (expected strict, warn or off)", policy.ValidationPolicy)
+	}
+
+	return policy, nil
+}
+
+// Violation describes a single boundary breach found before execution
+type Violation struct {
+	Rule    string `json:"rule"`
+	Command string `json:"command"`
+	Detail  string `json:"detail"`
+}
+
+// validExtraction reports whether strategy is a known extraction strategy
+func validExtraction(strategy string) bool {
+	for _, s := range validator.ExtractionStrategies {
+		if s == strategy {
+			return true
+		}
+	}
+	return false
+}
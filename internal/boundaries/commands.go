@@ -0,0 +1,186 @@
+package boundaries
+
+import (
+	"fmt"
+	"path"
+	"strings"
+)
+
+// CommandPolicy restricts which binaries a BASH script may invoke.
+//
+//	"commands": {
+//	  "allow": ["git", "jq", "python3"],   // if set, only these (plus builtins) may run
+//	  "deny":  ["curl", "wget"],           // always forbidden
+//	  "allow_rm_outside_workspace": false  // recursive rm of absolute paths outside the workspace
+//	}
+type CommandPolicy struct {
+	Allow                   []string `json:"allow,omitempty"`
+	Deny                    []string `json:"deny,omitempty"`
+	AllowRmOutsideWorkspace bool     `json:"allow_rm_outside_workspace,omitempty"`
+}
+
+// shellBuiltins are never subject to the allowlist
+var shellBuiltins = map[string]bool{
+	"cd": true, "echo": true, "printf": true, "export": true, "set": true, "unset": true,
+	"test": true, "[": true, "[[": true, "true": true, "false": true, "read": true,
+	"exit": true, "return": true, "shift": true, "local": true, "source": true, ".": true,
+	":": true, "pwd": true, "trap": true, "wait": true, "break": true, "continue": true,
+}
+
+// shellKeywords may precede the command word in a segment
+var shellKeywords = map[string]bool{
+	"if": true, "then": true, "else": true, "elif": true, "fi": true, "do": true,
+	"done": true, "while": true, "until": true, "for": true, "case": true, "esac": true,
+	"in": true, "{": true, "}": true, "!": true, "function": true,
+}
+
+// commandWrappers run their first argument as the real command
+var commandWrappers = map[string]bool{
+	"sudo": true, "env": true, "exec": true, "command": true, "nohup": true,
+	"time": true, "nice": true, "timeout": true, "xargs": true,
+}
+
+// CheckScript statically scans a shell script and returns every invocation
+// that breaks the policy. The scan is best-effort: it splits on common shell
+// separators and inspects the first word of each simple command, so it
+// catches accidental use of forbidden tools rather than deliberate evasion.
+func (p *CommandPolicy) CheckScript(script, workspace string) []Violation {
+	if p == nil {
+		return nil
+	}
+
+	allow := make(map[string]bool, len(p.Allow))
+	for _, name := range p.Allow {
+		allow[name] = true
+	}
+	deny := make(map[string]bool, len(p.Deny))
+	for _, name := range p.Deny {
+		deny[name] = true
+	}
+
+	var violations []Violation
+	for _, words := range splitCommands(script) {
+		name, args := commandWord(words)
+		if name == "" {
+			continue
+		}
+		base := path.Base(name)
+		segment := strings.Join(words, " ")
+
+		if deny[base] || deny[name] {
+			violations = append(violations, Violation{
+				Rule:    "deny",
+				Command: segment,
+				Detail:  fmt.Sprintf("command %q is denied by boundaries", base),
+			})
+			continue
+		}
+		if len(allow) > 0 && !allow[base] && !allow[name] && !shellBuiltins[base] {
+			violations = append(violations, Violation{
+				Rule:    "allow",
+				Command: segment,
+				Detail:  fmt.Sprintf("command %q is not in the allowed command list", base),
+			})
+			continue
+		}
+		if base == "rm" && !p.AllowRmOutsideWorkspace {
+			if target := rmOutsideWorkspace(args, workspace); target != "" {
+				violations = append(violations, Violation{
+					Rule:    "rm_outside_workspace",
+					Command: segment,
+					Detail:  fmt.Sprintf("recursive rm of %q outside %s", target, workspace),
+				})
+			}
+		}
+	}
+
+	return violations
+}
+
+// splitCommands breaks a script into simple commands, each as a word list
+func splitCommands(script string) [][]string {
+	replacer := strings.NewReplacer(
+		"&&", "\n", "||", "\n", ";", "\n", "|", "\n", "&", "\n",
+		"$(", "\n", "`", "\n", "(", "\n", ")", "\n",
+	)
+	var commands [][]string
+	for _, line := range strings.Split(replacer.Replace(script), "\n") {
+		if idx := strings.Index(line, "#"); idx == 0 || (idx > 0 && line[idx-1] == ' ') {
+			line = line[:idx]
+		}
+		words := strings.Fields(line)
+		if len(words) > 0 {
+			commands = append(commands, words)
+		}
+	}
+	return commands
+}
+
+// commandWord skips keywords, variable assignments and wrappers to find the
+// binary a simple command actually runs, returning it with its arguments
+func commandWord(words []string) (string, []string) {
+	for i := 0; i < len(words); i++ {
+		w := strings.Trim(words[i], `"'`)
+		switch {
+		case shellKeywords[w]:
+			continue
+		case strings.Contains(w, "=") && !strings.HasPrefix(w, "-") && !strings.HasPrefix(w, "/"):
+			continue
+		case commandWrappers[w]:
+			// Skip wrapper flags such as "timeout 10" or "nice -n 5"
+			for i+1 < len(words) && (strings.HasPrefix(words[i+1], "-") || isNumeric(words[i+1])) {
+				i++
+			}
+			continue
+		default:
+			return w, words[i+1:]
+		}
+	}
+	return "", nil
+}
+
+// rmOutsideWorkspace returns the first absolute target outside workspace
+// when args request a recursive removal, or "" otherwise
+func rmOutsideWorkspace(args []string, workspace string) string {
+	recursive := false
+	var targets []string
+	for _, a := range args {
+		a = strings.Trim(a, `"'`)
+		switch {
+		case a == "--recursive" || (strings.HasPrefix(a, "-") && !strings.HasPrefix(a, "--") && strings.ContainsAny(a, "rR")):
+			recursive = true
+		case strings.HasPrefix(a, "-"):
+		default:
+			targets = append(targets, a)
+		}
+	}
+	if !recursive {
+		return ""
+	}
+	ws := path.Clean(workspace)
+	for _, t := range targets {
+		if t == "~" || strings.HasPrefix(t, "~/") || strings.HasPrefix(t, "$HOME") {
+			return t
+		}
+		if !strings.HasPrefix(t, "/") {
+			continue
+		}
+		clean := path.Clean(t)
+		if clean != ws && !strings.HasPrefix(clean, ws+"/") {
+			return t
+		}
+	}
+	return ""
+}
+
+func isNumeric(s string) bool {
+	if s == "" {
+		return false
+	}
+	for _, r := range s {
+		if (r < '0' || r > '9') && r != '.' && r != 's' && r != 'm' {
+			return false
+		}
+	}
+	return true
+}
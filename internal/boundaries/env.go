@@ -0,0 +1,84 @@
+package boundaries
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// EnvVars are static, non-secret environment variables added to the
+// environment of BASH, TEST, PYTHON and plugin executions, so the same
+// code can be parameterized per task without templating it.
+//
+//	"env": {"TARGET": "staging", "WORKERS": 4, "VERBOSE": true}
+//
+// Numbers and booleans are passed as their JSON text. Values are reported
+// with the rest of the entity, so secrets belong in boundaries.secrets.
+type EnvVars map[string]string
+
+// UnmarshalJSON accepts string, number and boolean values
+func (e *EnvVars) UnmarshalJSON(data []byte) error {
+	var raw map[string]json.RawMessage
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return fmt.Errorf("env must be an object of variable names to values")
+	}
+	vars := make(EnvVars, len(raw))
+	for name, value := range raw {
+		if string(value) == "null" {
+			return fmt.Errorf("env %s must be a string, number or boolean", name)
+		}
+		var s string
+		if err := json.Unmarshal(value, &s); err == nil {
+			vars[name] = s
+			continue
+		}
+		var scalar interface{}
+		if err := json.Unmarshal(value, &scalar); err != nil {
+			return err
+		}
+		switch v := scalar.(type) {
+		case float64:
+			vars[name] = string(value)
+		case bool:
+			vars[name] = strconv.FormatBool(v)
+		default:
+			return fmt.Errorf("env %s must be a string, number or boolean", name)
+		}
+	}
+	*e = vars
+	return nil
+}
+
+// Validate checks every name is a valid environment variable name that
+// the executors do not set themselves
+func (e EnvVars) Validate() error {
+	for _, name := range e.Names() {
+		if !secretNamePattern.MatchString(name) {
+			return fmt.Errorf("invalid env name %q (must be a valid environment variable name)", name)
+		}
+		if name == "OUTPUT_FILE" || strings.HasPrefix(strings.ToUpper(name), "INPUT_") {
+			return fmt.Errorf("env %s is reserved (OUTPUT_FILE and INPUT_* are set by the executor)", name)
+		}
+	}
+	return nil
+}
+
+// Names returns the variable names, sorted
+func (e EnvVars) Names() []string {
+	names := make([]string, 0, len(e))
+	for name := range e {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Append appends the variables to env as KEY=value, in name order
+func (e EnvVars) Append(env []string) []string {
+	for _, name := range e.Names() {
+		env = append(env, name+"="+e[name])
+	}
+	return env
+}
@@ -0,0 +1,198 @@
+package boundaries
+
+import (
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/kindship-ai/kindship-cli/internal/validator"
+)
+
+// inputExprPattern matches the ${...} expressions of an input mapping
+var inputExprPattern = regexp.MustCompile(`\$\{([^}]*)\}`)
+
+// inputPathStep is one step of an input expression: a key or an index
+type inputPathStep struct {
+	key   string
+	index int
+	isKey bool
+}
+
+// inputPath is a parsed ${deps.<label>...} expression
+type inputPath struct {
+	expr  string
+	label string
+	steps []inputPathStep
+}
+
+// parseInputPath parses the body of a ${...} expression: deps.<label>
+// followed by .key, ["key"] and [index] steps, e.g.
+// deps.fetch_users.structured.items[0].name
+func parseInputPath(expr string) (*inputPath, error) {
+	body := strings.TrimSpace(expr)
+	if !strings.HasPrefix(body, "deps.") {
+		return nil, fmt.Errorf("${%s}: expressions must start with deps.<label>", expr)
+	}
+	rest := body[len("deps."):]
+
+	path := &inputPath{expr: expr}
+	end := strings.IndexAny(rest, ".[")
+	if end < 0 {
+		end = len(rest)
+	}
+	path.label, rest = rest[:end], rest[end:]
+	if path.label == "" {
+		return nil, fmt.Errorf("${%s}: missing dependency label", expr)
+	}
+
+	for rest != "" {
+		switch rest[0] {
+		case '.':
+			rest = rest[1:]
+			end := strings.IndexAny(rest, ".[")
+			if end < 0 {
+				end = len(rest)
+			}
+			if end == 0 {
+				return nil, fmt.Errorf("${%s}: empty key", expr)
+			}
+			path.steps = append(path.steps, inputPathStep{key: rest[:end], isKey: true})
+			rest = rest[end:]
+		case '[':
+			end := strings.IndexByte(rest, ']')
+			if end < 0 {
+				return nil, fmt.Errorf("${%s}: unterminated [", expr)
+			}
+			inner := rest[1:end]
+			rest = rest[end+1:]
+			if key, err := strconv.Unquote(inner); err == nil {
+				path.steps = append(path.steps, inputPathStep{key: key, isKey: true})
+				continue
+			}
+			index, err := strconv.Atoi(inner)
+			if err != nil || index < 0 {
+				return nil, fmt.Errorf("${%s}: [%s] must be an index or a quoted key", expr, inner)
+			}
+			path.steps = append(path.steps, inputPathStep{index: index})
+		default:
+			return nil, fmt.Errorf("${%s}: unexpected %q", expr, rest[0])
+		}
+	}
+	return path, nil
+}
+
+// resolve evaluates the path against the dependency inputs. Inputs carry a
+// dependency's structured output, so a leading "structured" step that the
+// value does not contain refers to the value itself. Array and scalar
+// outputs wrapped for transport are unwrapped along the way.
+func (p *inputPath) resolve(inputs map[string]interface{}) (interface{}, error) {
+	value, ok := inputs[p.label]
+	if !ok {
+		return nil, fmt.Errorf("${%s}: no input from dependency %q", p.expr, p.label)
+	}
+	steps := p.steps
+	if len(steps) > 0 && steps[0].isKey && steps[0].key == "structured" {
+		if m, isMap := value.(map[string]interface{}); !isMap || m["structured"] == nil {
+			steps = steps[1:]
+		}
+	}
+
+	for _, step := range steps {
+		if m, isMap := value.(map[string]interface{}); isMap {
+			value = validator.UnwrapStructured(m)
+		}
+		switch v := value.(type) {
+		case map[string]interface{}:
+			if !step.isKey {
+				return nil, fmt.Errorf("${%s}: cannot index an object with [%d]", p.expr, step.index)
+			}
+			if value, ok = v[step.key]; !ok {
+				return nil, fmt.Errorf("${%s}: key %q not found", p.expr, step.key)
+			}
+		case []interface{}:
+			if step.isKey {
+				return nil, fmt.Errorf("${%s}: cannot read key %q of an array", p.expr, step.key)
+			}
+			if step.index >= len(v) {
+				return nil, fmt.Errorf("${%s}: index %d out of range (length %d)", p.expr, step.index, len(v))
+			}
+			value = v[step.index]
+		default:
+			return nil, fmt.Errorf("${%s}: cannot descend into %T", p.expr, value)
+		}
+	}
+	if m, isMap := value.(map[string]interface{}); isMap {
+		value = validator.UnwrapStructured(m)
+	}
+	return value, nil
+}
+
+// validateInputMap checks the syntax of every expression in an input map
+func validateInputMap(mapping map[string]string) error {
+	for name, template := range mapping {
+		if name == "" {
+			return fmt.Errorf("inputs map has an empty input name")
+		}
+		for _, match := range inputExprPattern.FindAllStringSubmatch(template, -1) {
+			if _, err := parseInputPath(match[1]); err != nil {
+				return fmt.Errorf("inputs map %q: %w", name, err)
+			}
+		}
+	}
+	return nil
+}
+
+// MapInputs builds a task's inputs from its dependency inputs according
+// to the inputs map. A value that is a single ${...} expression keeps the
+// JSON type of what it selects; expressions embedded in longer strings are
+// interpolated, with non-string values rendered as JSON. Without a map the
+// inputs are returned unchanged.
+func (p *InputPolicy) MapInputs(inputs map[string]interface{}) (map[string]interface{}, error) {
+	if p == nil || len(p.Map) == 0 {
+		return inputs, nil
+	}
+
+	mapped := make(map[string]interface{}, len(p.Map))
+	for name, template := range p.Map {
+		matches := inputExprPattern.FindAllStringSubmatchIndex(template, -1)
+		if len(matches) == 1 && matches[0][0] == 0 && matches[0][1] == len(template) {
+			value, err := resolveInputExpr(template[matches[0][2]:matches[0][3]], inputs)
+			if err != nil {
+				return nil, fmt.Errorf("input %q: %w", name, err)
+			}
+			mapped[name] = value
+			continue
+		}
+
+		var b strings.Builder
+		last := 0
+		for _, m := range matches {
+			b.WriteString(template[last:m[0]])
+			value, err := resolveInputExpr(template[m[2]:m[3]], inputs)
+			if err != nil {
+				return nil, fmt.Errorf("input %q: %w", name, err)
+			}
+			if s, ok := value.(string); ok {
+				b.WriteString(s)
+			} else {
+				data, _ := json.Marshal(value)
+				b.Write(data)
+			}
+			last = m[1]
+		}
+		b.WriteString(template[last:])
+		mapped[name] = b.String()
+	}
+	return mapped, nil
+}
+
+// resolveInputExpr parses and evaluates one expression body
+func resolveInputExpr(expr string, inputs map[string]interface{}) (interface{}, error) {
+	path, err := parseInputPath(expr)
+	if err != nil {
+		return nil, err
+	}
+	return path.resolve(inputs)
+}
@@ -0,0 +1,102 @@
+package boundaries
+
+import "fmt"
+
+// Overflow strategies accepted in boundaries.inputs.overflow
+const (
+	InputOverflowTruncate = "truncate"
+	InputOverflowFile     = "file"
+)
+
+// DefaultMaxInputBytes is the per-input size limit for LLM prompts when
+// boundaries do not set one
+const DefaultMaxInputBytes = 64 * 1024
+
+// DefaultMaxTotalInputBytes is how much input an LLM prompt inlines in
+// total when boundaries do not set a limit
+const DefaultMaxTotalInputBytes = 256 * 1024
+
+// InputPolicy limits how much of each dependency output is placed into an
+// LLM prompt, and optionally selects the inputs a task receives.
+//
+//	"inputs": {"max_bytes": 20000, "overflow": "file"}
+//
+// Inputs larger than max_bytes are either truncated with a marker
+// ("truncate", the default) or written to a file that the prompt tells the
+// model to read ("file").
+//
+// Inputs are placed in label order. Once max_total_bytes of inputs are in
+// the prompt, the rest are written to pages of at most max_total_bytes
+// each, which the prompt lists by label:
+//
+//	"inputs": {"max_total_bytes": 100000}
+//
+// With a map, the task receives exactly the listed inputs instead of the
+// whole output of each dependency, each picked by a ${deps.<label>...}
+// path over the dependency outputs:
+//
+//	"inputs": {"map": {
+//	  "user_count": "${deps.fetch_users.structured.count}",
+//	  "first_user": "${deps.fetch_users.users[0].name}"
+//	}}
+//
+// With stdin, BASH, TEST and PYTHON executions also receive the whole
+// inputs map as one JSON object on stdin, for scripts written to read a
+// JSON payload and for inputs too large for environment variables:
+//
+//	"inputs": {"stdin": true}
+type InputPolicy struct {
+	MaxBytes      int               `json:"max_bytes,omitempty"`
+	MaxTotalBytes int               `json:"max_total_bytes,omitempty"`
+	Overflow      string            `json:"overflow,omitempty"`
+	Map           map[string]string `json:"map,omitempty"`
+	Stdin         bool              `json:"stdin,omitempty"`
+}
+
+// Validate checks the size limit, overflow strategy and input map
+func (p *InputPolicy) Validate() error {
+	if p.MaxBytes < 0 {
+		return fmt.Errorf("inputs max_bytes must not be negative")
+	}
+	if p.MaxTotalBytes < 0 {
+		return fmt.Errorf("inputs max_total_bytes must not be negative")
+	}
+	if err := validateInputMap(p.Map); err != nil {
+		return err
+	}
+	switch p.Overflow {
+	case "", InputOverflowTruncate, InputOverflowFile:
+		return nil
+	default:
+		return fmt.Errorf("unknown inputs overflow %q (expected truncate or file)", p.Overflow)
+	}
+}
+
+// PipesStdin reports whether inputs are piped to the process as JSON
+func (p *InputPolicy) PipesStdin() bool {
+	return p != nil && p.Stdin
+}
+
+// Limit returns the per-input size limit in bytes
+func (p *InputPolicy) Limit() int {
+	if p == nil || p.MaxBytes == 0 {
+		return DefaultMaxInputBytes
+	}
+	return p.MaxBytes
+}
+
+// TotalLimit returns how many bytes of input a prompt inlines in total
+func (p *InputPolicy) TotalLimit() int {
+	if p == nil || p.MaxTotalBytes == 0 {
+		return DefaultMaxTotalInputBytes
+	}
+	return p.MaxTotalBytes
+}
+
+// OverflowMode returns how oversized inputs are handled
+func (p *InputPolicy) OverflowMode() string {
+	if p == nil || p.Overflow == "" {
+		return InputOverflowTruncate
+	}
+	return p.Overflow
+}
@@ -0,0 +1,106 @@
+package boundaries
+
+import "fmt"
+
+// Claude Code permission modes accepted in boundaries.llm.permission_mode
+const (
+	PermissionModeDefault     = "default"
+	PermissionModeAcceptEdits = "acceptEdits"
+	PermissionModePlan        = "plan"
+	PermissionModeBypass      = "bypassPermissions"
+)
+
+// LLM CLIs accepted in boundaries.llm.backends
+const (
+	LLMBackendClaude = "claude"
+	LLMBackendCodex  = "codex"
+	LLMBackendGemini = "gemini"
+)
+
+// LLMPolicy controls what the LLM executor may do without prompting.
+//
+//	"llm": {"permission_mode": "acceptEdits", "allowed_tools": ["Bash(git:*)", "Edit"],
+//	        "disallowed_tools": ["WebFetch"], "max_turns": 30,
+//	        "backends": ["claude", "codex"]}
+//
+// Tool names use Claude Code's syntax and are passed through unchanged; the
+// permission settings only apply to Claude. Backends are tried in order,
+// falling through when one is missing or fails with a quota/auth error.
+type LLMPolicy struct {
+	PermissionMode  string   `json:"permission_mode,omitempty"`
+	AllowedTools    []string `json:"allowed_tools,omitempty"`
+	DisallowedTools []string `json:"disallowed_tools,omitempty"`
+	MaxTurns        int      `json:"max_turns,omitempty"`
+	Backends        []string `json:"backends,omitempty"`
+}
+
+// Validate checks the permission mode and backends are known and max_turns
+// is not negative
+func (l *LLMPolicy) Validate() error {
+	switch l.PermissionMode {
+	case "", PermissionModeDefault, PermissionModeAcceptEdits, PermissionModePlan, PermissionModeBypass:
+	default:
+		return fmt.Errorf("unknown llm permission_mode %q (expected default, acceptEdits, plan or bypassPermissions)", l.PermissionMode)
+	}
+	if l.MaxTurns < 0 {
+		return fmt.Errorf("llm max_turns must not be negative")
+	}
+	for _, b := range l.Backends {
+		switch b {
+		case LLMBackendClaude, LLMBackendCodex, LLMBackendGemini:
+		default:
+			return fmt.Errorf("unknown llm backend %q (expected claude, codex or gemini)", b)
+		}
+	}
+	return nil
+}
+
+// BackendChain returns the LLM CLIs to try, in order
+func (l *LLMPolicy) BackendChain() []string {
+	if l == nil || len(l.Backends) == 0 {
+		return []string{LLMBackendClaude}
+	}
+	return l.Backends
+}
+
+// Merge layers an entity's policy over agent-wide defaults. Settings the
+// entity makes replace the defaults, except disallowed tools, which
+// accumulate so an entity cannot lift a tool the agent denies.
+// Either side may be nil.
+func (l *LLMPolicy) Merge(override *LLMPolicy) *LLMPolicy {
+	merged := &LLMPolicy{}
+	if l != nil {
+		*merged = *l
+		merged.DisallowedTools = append([]string(nil), l.DisallowedTools...)
+	}
+	if override == nil {
+		return merged
+	}
+	if override.PermissionMode != "" {
+		merged.PermissionMode = override.PermissionMode
+	}
+	if len(override.AllowedTools) > 0 {
+		merged.AllowedTools = override.AllowedTools
+	}
+	if override.MaxTurns > 0 {
+		merged.MaxTurns = override.MaxTurns
+	}
+	if len(override.Backends) > 0 {
+		merged.Backends = override.Backends
+	}
+	for _, tool := range override.DisallowedTools {
+		if !containsString(merged.DisallowedTools, tool) {
+			merged.DisallowedTools = append(merged.DisallowedTools, tool)
+		}
+	}
+	return merged
+}
+
+func containsString(list []string, s string) bool {
+	for _, v := range list {
+		if v == s {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,85 @@
+package boundaries
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+)
+
+// Network modes accepted in boundaries.network
+const (
+	NetworkModeOpen      = "open"
+	NetworkModeNone      = "none"
+	NetworkModeAllowlist = "allowlist"
+)
+
+// NetworkPolicy restricts outbound network access for code executors.
+// Accepts either a bare mode string or an object:
+//
+//	"network": "none"
+//	"network": {"mode": "allowlist", "hosts": ["api.github.com", "*.pypi.org"]}
+type NetworkPolicy struct {
+	Mode  string   `json:"mode"`
+	Hosts []string `json:"hosts,omitempty"`
+}
+
+// UnmarshalJSON accepts both the string shorthand and the object form
+func (n *NetworkPolicy) UnmarshalJSON(data []byte) error {
+	var mode string
+	if err := json.Unmarshal(data, &mode); err == nil {
+		n.Mode = mode
+		return nil
+	}
+	type plain NetworkPolicy
+	var p plain
+	if err := json.Unmarshal(data, &p); err != nil {
+		return fmt.Errorf("network must be a mode string or an object: %w", err)
+	}
+	*n = NetworkPolicy(p)
+	return nil
+}
+
+// Validate checks the mode is known and an allowlist names at least one host
+func (n *NetworkPolicy) Validate() error {
+	switch n.Mode {
+	case "", NetworkModeOpen, NetworkModeNone:
+		return nil
+	case NetworkModeAllowlist:
+		if len(n.Hosts) == 0 {
+			return fmt.Errorf("network allowlist requires at least one host")
+		}
+		return nil
+	default:
+		return fmt.Errorf("unknown network mode %q (expected none or allowlist)", n.Mode)
+	}
+}
+
+// Restricted reports whether the policy limits egress at all
+func (n *NetworkPolicy) Restricted() bool {
+	return n != nil && (n.Mode == NetworkModeNone || n.Mode == NetworkModeAllowlist)
+}
+
+// AllowsHost reports whether host may be contacted. Entries match exactly
+// or, when prefixed with "*.", any subdomain of the suffix.
+func (n *NetworkPolicy) AllowsHost(host string) bool {
+	if n == nil || !n.Restricted() {
+		return true
+	}
+	if n.Mode == NetworkModeNone {
+		return false
+	}
+	host = strings.ToLower(strings.TrimSuffix(host, "."))
+	for _, allowed := range n.Hosts {
+		allowed = strings.ToLower(allowed)
+		if strings.HasPrefix(allowed, "*.") {
+			if strings.HasSuffix(host, allowed[1:]) {
+				return true
+			}
+			continue
+		}
+		if host == allowed {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,91 @@
+package boundaries
+
+import (
+	"fmt"
+	"time"
+)
+
+// Retry conditions accepted in boundaries.retry.retry_on
+const (
+	RetryOnNonzero = "nonzero"
+	RetryOnTimeout = "timeout"
+)
+
+// MaxRetryAttempts caps max_attempts so a bad plan cannot loop forever
+const MaxRetryAttempts = 10
+
+// MaxRetryBackoffSeconds caps backoff_seconds so converting it to a
+// time.Duration cannot overflow
+const MaxRetryBackoffSeconds = 3600
+
+// maxRetryBackoff caps the delay between attempts
+const maxRetryBackoff = 5 * time.Minute
+
+// RetryPolicy controls how often a failed execution is re-attempted.
+//
+//	"retry": {"max_attempts": 3, "backoff_seconds": 10, "retry_on": "timeout"}
+//
+// retry_on "nonzero" (the default) retries any failed execution, "timeout"
+// only retries executions that hit the executor timeout. The delay doubles
+// after each attempt, starting at backoff_seconds.
+type RetryPolicy struct {
+	MaxAttempts    int     `json:"max_attempts"`
+	BackoffSeconds float64 `json:"backoff_seconds"`
+	RetryOn        string  `json:"retry_on,omitempty"`
+}
+
+// Validate checks the retry settings are within supported bounds
+func (r *RetryPolicy) Validate() error {
+	if r.MaxAttempts < 0 {
+		return fmt.Errorf("retry max_attempts must not be negative")
+	}
+	if r.MaxAttempts > MaxRetryAttempts {
+		return fmt.Errorf("retry max_attempts must be at most %d", MaxRetryAttempts)
+	}
+	if r.BackoffSeconds < 0 {
+		return fmt.Errorf("retry backoff_seconds must not be negative")
+	}
+	if r.BackoffSeconds > MaxRetryBackoffSeconds {
+		return fmt.Errorf("retry backoff_seconds must be at most %d", MaxRetryBackoffSeconds)
+	}
+	switch r.RetryOn {
+	case "", RetryOnNonzero, RetryOnTimeout:
+		return nil
+	default:
+		return fmt.Errorf("unknown retry_on %q (expected nonzero or timeout)", r.RetryOn)
+	}
+}
+
+// Attempts returns the total number of attempts allowed (at least one)
+func (r *RetryPolicy) Attempts() int {
+	if r == nil || r.MaxAttempts < 1 {
+		return 1
+	}
+	return r.MaxAttempts
+}
+
+// ShouldRetry reports whether a failed attempt qualifies for a retry
+func (r *RetryPolicy) ShouldRetry(timedOut bool) bool {
+	if r == nil {
+		return false
+	}
+	if r.RetryOn == RetryOnTimeout {
+		return timedOut
+	}
+	return true
+}
+
+// Backoff returns the delay before the attempt following attempt n (1-based)
+func (r *RetryPolicy) Backoff(n int) time.Duration {
+	if r == nil || r.BackoffSeconds <= 0 {
+		return 0
+	}
+	delay := time.Duration(r.BackoffSeconds * float64(time.Second))
+	for i := 1; i < n && delay < maxRetryBackoff; i++ {
+		delay *= 2
+	}
+	if delay > maxRetryBackoff {
+		delay = maxRetryBackoff
+	}
+	return delay
+}
@@ -0,0 +1,27 @@
+package boundaries
+
+import (
+	"fmt"
+	"regexp"
+)
+
+// secretNamePattern matches names usable as environment variables
+var secretNamePattern = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*$`)
+
+// SecretList names agent secrets injected into the environment of BASH,
+// TEST, PYTHON and plugin executions.
+//
+//	"secrets": ["GITHUB_TOKEN", "NPM_TOKEN"]
+//
+// Secret values are redacted from captured output before it is reported.
+type SecretList []string
+
+// Validate checks every name is a valid environment variable name
+func (s SecretList) Validate() error {
+	for _, name := range s {
+		if !secretNamePattern.MatchString(name) {
+			return fmt.Errorf("invalid secret name %q (must be a valid environment variable name)", name)
+		}
+	}
+	return nil
+}
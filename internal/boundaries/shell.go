@@ -0,0 +1,44 @@
+package boundaries
+
+import "fmt"
+
+// Shells accepted in boundaries.shell.interpreter
+const (
+	ShellSh   = "sh"
+	ShellBash = "bash"
+	ShellZsh  = "zsh"
+)
+
+// ShellPolicy selects the interpreter BASH and TEST executions run under.
+//
+//	"shell": {"interpreter": "bash", "args": ["-e", "-o", "pipefail"]}
+//
+// Args are passed before "-c <code>". Without a policy code runs under
+// sh -c, which is dash on Debian-based images and rejects bashisms.
+type ShellPolicy struct {
+	Interpreter string   `json:"interpreter,omitempty"`
+	Args        []string `json:"args,omitempty"`
+}
+
+// Validate checks the interpreter is known
+func (s *ShellPolicy) Validate() error {
+	switch s.Interpreter {
+	case "", ShellSh, ShellBash, ShellZsh:
+		return nil
+	default:
+		return fmt.Errorf("unknown shell interpreter %q (expected sh, bash or zsh)", s.Interpreter)
+	}
+}
+
+// Merge layers an entity's shell over the agent-wide default. An entity
+// that names an interpreter replaces the default along with its args.
+// Either side may be nil.
+func (s *ShellPolicy) Merge(override *ShellPolicy) *ShellPolicy {
+	if override != nil && override.Interpreter != "" {
+		return override
+	}
+	if s == nil {
+		return &ShellPolicy{}
+	}
+	return s
+}
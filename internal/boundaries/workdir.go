@@ -0,0 +1,71 @@
+package boundaries
+
+import (
+	"fmt"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+)
+
+// validateWorkDir checks that boundaries.workdir is a path relative to the
+// workspace that stays inside it
+//
+//	"workdir": "packages/api"
+func validateWorkDir(dir string) error {
+	if !insideWorkspace(dir) {
+		return fmt.Errorf("invalid workdir %q: must be relative to the workspace and inside it", dir)
+	}
+	return nil
+}
+
+// insideWorkspace reports whether p is a relative path that does not
+// climb out of the directory it is relative to
+func insideWorkspace(p string) bool {
+	clean := path.Clean(filepath.ToSlash(p))
+	return !filepath.IsAbs(p) && !path.IsAbs(clean) && clean != ".." && !strings.HasPrefix(clean, "../")
+}
+
+// ResolveWorkDir returns the directory executions run in: workdir under
+// workspace, or workspace itself without one. The directory must exist,
+// and must not leave the workspace through a symlink.
+func (p *Policy) ResolveWorkDir(workspace string) (string, error) {
+	if p == nil || p.WorkDir == "" {
+		return workspace, nil
+	}
+	dir := filepath.Join(workspace, filepath.FromSlash(path.Clean(filepath.ToSlash(p.WorkDir))))
+	info, err := os.Stat(dir)
+	if os.IsNotExist(err) {
+		return "", fmt.Errorf("workdir %s does not exist in the workspace", p.WorkDir)
+	}
+	if err != nil {
+		return "", err
+	}
+	if !info.IsDir() {
+		return "", fmt.Errorf("workdir %s is not a directory", p.WorkDir)
+	}
+
+	inside, err := ResolvesInside(workspace, dir)
+	if err != nil {
+		return "", err
+	}
+	if !inside {
+		return "", fmt.Errorf("workdir %s resolves outside the workspace", p.WorkDir)
+	}
+	return dir, nil
+}
+
+// ResolvesInside reports whether the existing path p is still inside
+// workspace once symlinks are resolved
+func ResolvesInside(workspace, p string) (bool, error) {
+	root, err := filepath.EvalSymlinks(workspace)
+	if err != nil {
+		return false, err
+	}
+	resolved, err := filepath.EvalSymlinks(p)
+	if err != nil {
+		return false, err
+	}
+	rel, err := filepath.Rel(root, resolved)
+	return err == nil && rel != ".." && !strings.HasPrefix(rel, ".."+string(filepath.Separator)), nil
+}
@@ -0,0 +1,28 @@
+package boundaries
+
+import (
+	"fmt"
+	"path"
+)
+
+// WorkspaceDiffPolicy records a manifest of the workspace (paths, sizes
+// and hashes) before and after each execution, and reports the files that
+// changed in the execution outputs.
+//
+//	"workspace_diff": {"exclude": ["node_modules", "*.pyc"]}
+//
+// Exclude patterns are matched against the path relative to the workspace
+// and against the base name; .git is always excluded.
+type WorkspaceDiffPolicy struct {
+	Exclude []string `json:"exclude,omitempty"`
+}
+
+// Validate checks the exclude patterns are valid
+func (w *WorkspaceDiffPolicy) Validate() error {
+	for _, pattern := range w.Exclude {
+		if _, err := path.Match(pattern, ""); err != nil {
+			return fmt.Errorf("invalid workspace_diff exclude pattern %q", pattern)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,35 @@
+// Package checksum computes the hex SHA-256 digests used to verify
+// uploads, artifacts and workspace files.
+package checksum
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"io"
+	"os"
+)
+
+// SHA256 returns the hex SHA-256 of data
+func SHA256(data []byte) string {
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:])
+}
+
+// SHA256Reader returns the hex SHA-256 of everything read from r
+func SHA256Reader(r io.Reader) (string, error) {
+	h := sha256.New()
+	if _, err := io.Copy(h, r); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
+
+// SHA256File returns the hex SHA-256 of a file, read in a streaming pass
+func SHA256File(path string) (string, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return "", err
+	}
+	defer f.Close()
+	return SHA256Reader(f)
+}
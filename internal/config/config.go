@@ -19,6 +19,10 @@ const (
 	ConfigFileMode = 0600
 	// ConfigDirMode is the required directory permissions
 	ConfigDirMode = 0700
+	// AgentStateFile is the filename for locally registered agent state
+	AgentStateFile = "agent.json"
+	// xdgAppName is the directory name under XDG base directories
+	xdgAppName = "kindship"
 )
 
 // GlobalConfig represents the user's global CLI configuration
@@ -34,11 +38,27 @@ type GlobalConfig struct {
 	UserID    string `json:"user_id,omitempty"`
 	UserEmail string `json:"user_email,omitempty"`
 
+	// Account selected at login, sent with OAuth requests. Empty means the
+	// server's default account for the user.
+	AccountID   string `json:"account_id,omitempty"`
+	AccountSlug string `json:"account_slug,omitempty"`
+	AccountName string `json:"account_name,omitempty"`
+
+	// Scopes and rate-limit tier granted to the token, as reported at login
+	Scopes        []string `json:"scopes,omitempty"`
+	RateLimitTier string   `json:"rate_limit_tier,omitempty"`
+
 	// API configuration
 	APIBaseURL string `json:"api_base_url,omitempty"`
 
+	// SelfHosted is set for on-prem deployments (kindship config self-hosted)
+	SelfHosted *SelfHostedProfile `json:"self_hosted,omitempty"`
+
 	// Default agent (optional)
 	DefaultAgentID string `json:"default_agent_id,omitempty"`
+
+	// Anonymous usage telemetry, off unless turned on (kindship telemetry)
+	Telemetry bool `json:"telemetry,omitempty"`
 }
 
 // RepoConfig represents the per-repository configuration
@@ -48,15 +68,96 @@ type RepoConfig struct {
 	AgentSlug string    `json:"agent_slug,omitempty"`
 	AccountID string    `json:"account_id,omitempty"`
 	BoundAt   time.Time `json:"bound_at,omitempty"`
+
+	RepoSettings
+}
+
+// RepoSettings are per-repo defaults managed with 'kindship config set
+// --repo'. Flags and environment variables take precedence over them.
+type RepoSettings struct {
+	// OutputFormat is the default --format of commands with table/json/yaml output
+	OutputFormat string `json:"output_format,omitempty"`
+	// Workspace is the directory executions run in
+	Workspace string `json:"workspace,omitempty"`
+	// PollInterval is the default 'agent loop' --poll-interval in seconds
+	PollInterval int `json:"poll_interval,omitempty"`
+	// LLMBackend is the LLM CLI tried first for LLM executions
+	LLMBackend string `json:"llm_backend,omitempty"`
+	// Environment names the deployment (e.g. staging) in log entries
+	Environment string `json:"environment,omitempty"`
+	// Shell is the interpreter and arguments for BASH and TEST executions,
+	// e.g. "bash -e -o pipefail"
+	Shell string `json:"shell,omitempty"`
+	// SyncDirs are the workspace directories 'kindship workspace' syncs
+	SyncDirs []string `json:"sync_dirs,omitempty"`
 }
 
-// GetGlobalConfigDir returns the path to the global config directory
+// AgentState records the agent this machine registered as via
+// 'kindship agent register', stored as agent.json in the state directory.
+// The service key is deliberately not persisted here.
+type AgentState struct {
+	AgentID      string    `json:"agent_id"`
+	AgentSlug    string    `json:"agent_slug,omitempty"`
+	AccountID    string    `json:"account_id,omitempty"`
+	Title        string    `json:"title,omitempty"`
+	Labels       []string  `json:"labels,omitempty"`
+	RegisteredAt time.Time `json:"registered_at,omitempty"`
+}
+
+// GetGlobalConfigDir returns the path to the global config directory,
+// which holds credentials and the MCP catalog. In order of precedence:
+//
+//  1. $KINDSHIP_CONFIG_DIR
+//  2. ~/.kindship, if it holds a config.json from an existing install
+//  3. $XDG_CONFIG_HOME/kindship, if XDG_CONFIG_HOME is set
+//  4. ~/.kindship
 func GetGlobalConfigDir() (string, error) {
+	return resolveDir("XDG_CONFIG_HOME", func(home string) string {
+		return filepath.Join(home, ConfigDir)
+	})
+}
+
+// GetStateDir returns the path to the directory for local state: agent
+// registration, the audit log, unsent logs and crash reports. It follows
+// the same precedence as GetGlobalConfigDir with XDG_STATE_HOME in place
+// of XDG_CONFIG_HOME, except that it falls back to ~/.local/state/kindship:
+// writing state never creates ~/.kindship, which would take over the
+// config dir of an XDG install.
+func GetStateDir() (string, error) {
+	return resolveDir("XDG_STATE_HOME", func(home string) string {
+		return filepath.Join(home, ".local", "state", xdgAppName)
+	})
+}
+
+// resolveDir implements the directory precedence of GetGlobalConfigDir for
+// the given XDG base directory variable, using fallback when neither the
+// legacy directory nor the variable applies. Each directory is resolved on
+// its own, so one being created never moves the other.
+func resolveDir(xdgVar string, fallback func(home string) string) (string, error) {
+	if dir := os.Getenv("KINDSHIP_CONFIG_DIR"); dir != "" {
+		return dir, nil
+	}
+
 	home, err := os.UserHomeDir()
 	if err != nil {
+		// Without a home directory only XDG can tell us where to go
+		if base := os.Getenv(xdgVar); base != "" {
+			return filepath.Join(base, xdgAppName), nil
+		}
 		return "", fmt.Errorf("failed to get home directory: %w", err)
 	}
-	return filepath.Join(home, ConfigDir), nil
+
+	// Existing installs keep working where they are. Only a legacy
+	// directory holding config is one: a bare ~/.kindship may have been
+	// created by something else.
+	legacy := filepath.Join(home, ConfigDir)
+	if _, err := os.Stat(filepath.Join(legacy, ConfigFile)); err == nil {
+		return legacy, nil
+	}
+	if base := os.Getenv(xdgVar); base != "" {
+		return filepath.Join(base, xdgAppName), nil
+	}
+	return fallback(home), nil
 }
 
 // GetGlobalConfigPath returns the path to the global config file
@@ -152,6 +253,11 @@ func ClearGlobalConfig() error {
 	config.TokenPrefix = ""
 	config.UserID = ""
 	config.UserEmail = ""
+	config.AccountID = ""
+	config.AccountSlug = ""
+	config.AccountName = ""
+	config.Scopes = nil
+	config.RateLimitTier = ""
 
 	return SaveGlobalConfig(config)
 }
@@ -166,12 +272,19 @@ func (c *GlobalConfig) IsExpired() bool {
 	return c.Token != "" && time.Now().After(c.TokenExpiry)
 }
 
-// GetAPIBaseURL returns the API base URL, defaulting to production
+// GetAPIBaseURL returns the API base URL, defaulting to the self-hosted
+// profile's, else production
 func (c *GlobalConfig) GetAPIBaseURL() string {
 	if c.APIBaseURL != "" {
 		return c.APIBaseURL
 	}
-	return "https://kindship.ai"
+	if url := os.Getenv("KINDSHIP_SELF_HOSTED_URL"); url != "" {
+		return url
+	}
+	if c.SelfHosted != nil {
+		return c.SelfHosted.APIBaseURL
+	}
+	return ProductionAPIBaseURL
 }
 
 // GetRepoConfigDir returns the path to the repo config directory
@@ -181,9 +294,14 @@ func GetRepoConfigDir() (string, error) {
 	if err != nil {
 		return "", fmt.Errorf("failed to get current directory: %w", err)
 	}
+	return FindRepoConfigDir(cwd)
+}
 
-	// Search up from current directory for .kindship/config.json
-	dir := cwd
+// FindRepoConfigDir returns the repo config directory of the repository
+// containing start, searching up from start
+func FindRepoConfigDir(start string) (string, error) {
+	// Search up from start for .kindship/config.json
+	dir := start
 	for {
 		configDir := filepath.Join(dir, ConfigDir)
 		configPath := filepath.Join(configDir, RepoConfigFile)
@@ -270,3 +388,50 @@ func FindRepoRoot() (string, error) {
 		dir = parent
 	}
 }
+
+// LoadAgentState loads the locally registered agent state.
+// Returns nil without error if no agent has been registered.
+func LoadAgentState() (*AgentState, error) {
+	dir, err := GetStateDir()
+	if err != nil {
+		return nil, err
+	}
+
+	data, err := os.ReadFile(filepath.Join(dir, AgentStateFile))
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read agent state: %w", err)
+	}
+
+	var state AgentState
+	if err := json.Unmarshal(data, &state); err != nil {
+		return nil, fmt.Errorf("failed to parse agent state: %w", err)
+	}
+
+	return &state, nil
+}
+
+// SaveAgentState saves the locally registered agent state with secure permissions
+func SaveAgentState(state *AgentState) error {
+	dir, err := GetStateDir()
+	if err != nil {
+		return err
+	}
+
+	if err := os.MkdirAll(dir, ConfigDirMode); err != nil {
+		return fmt.Errorf("failed to create config directory: %w", err)
+	}
+
+	data, err := json.MarshalIndent(state, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal agent state: %w", err)
+	}
+
+	if err := os.WriteFile(filepath.Join(dir, AgentStateFile), data, ConfigFileMode); err != nil {
+		return fmt.Errorf("failed to write agent state: %w", err)
+	}
+
+	return nil
+}
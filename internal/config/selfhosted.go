@@ -0,0 +1,77 @@
+package config
+
+import (
+	"fmt"
+	"net"
+	"net/url"
+	"os"
+	"strings"
+)
+
+// ProductionAPIBaseURL is the hosted Kindship API
+const ProductionAPIBaseURL = "https://kindship.ai"
+
+// SelfHostedProfile points the CLI at an on-prem deployment. While it is
+// active, its API URL replaces kindship.ai as the default, 'kindship update'
+// downloads from UpdateURL (and refuses without one), and hosted-only
+// integrations (Axiom log shipping, telemetry) are off.
+type SelfHostedProfile struct {
+	APIBaseURL string `json:"api_base_url"`
+	// UpdateURL serves the CLI binary like https://kindship.ai/cli/kindship
+	// (os and arch query parameters); empty disables 'kindship update'
+	UpdateURL string `json:"update_url,omitempty"`
+}
+
+// LoadSelfHostedProfile returns the active self-hosted profile, or nil for
+// the hosted service. KINDSHIP_SELF_HOSTED_URL (with KINDSHIP_UPDATE_URL)
+// activates one without a config file, e.g. in agent containers; otherwise
+// the profile saved in the global config is used.
+func LoadSelfHostedProfile() *SelfHostedProfile {
+	if apiURL := os.Getenv("KINDSHIP_SELF_HOSTED_URL"); apiURL != "" {
+		return &SelfHostedProfile{APIBaseURL: apiURL, UpdateURL: os.Getenv("KINDSHIP_UPDATE_URL")}
+	}
+	cfg, err := LoadGlobalConfig()
+	if err != nil || cfg == nil {
+		return nil
+	}
+	return cfg.SelfHosted
+}
+
+// DefaultAPIBaseURL is the API base URL when none is configured: the
+// self-hosted profile's, else production
+func DefaultAPIBaseURL() string {
+	if p := LoadSelfHostedProfile(); p != nil {
+		return p.APIBaseURL
+	}
+	return ProductionAPIBaseURL
+}
+
+// ValidateBaseURL checks that raw is an absolute http(s) URL without a
+// query or fragment and returns it without a trailing slash. Plain http is
+// only accepted for loopback hosts, since service keys and tokens are sent
+// to it.
+func ValidateBaseURL(raw string) (string, error) {
+	u, err := url.Parse(strings.TrimSpace(raw))
+	if err != nil {
+		return "", fmt.Errorf("invalid URL %q: %w", raw, err)
+	}
+	if u.Host == "" || (u.Scheme != "https" && u.Scheme != "http") {
+		return "", fmt.Errorf("invalid URL %q: must be an absolute http(s) URL", raw)
+	}
+	if u.RawQuery != "" || u.Fragment != "" || u.User != nil {
+		return "", fmt.Errorf("invalid URL %q: must not have credentials, a query or a fragment", raw)
+	}
+	if u.Scheme == "http" && !isLoopback(u.Hostname()) {
+		return "", fmt.Errorf("invalid URL %q: use https (plain http is only allowed for localhost)", raw)
+	}
+	return strings.TrimRight(u.String(), "/"), nil
+}
+
+// isLoopback reports whether host names the local machine
+func isLoopback(host string) bool {
+	if host == "localhost" {
+		return true
+	}
+	ip := net.ParseIP(host)
+	return ip != nil && ip.IsLoopback()
+}
@@ -0,0 +1,48 @@
+package executor
+
+import (
+	"strings"
+
+	"github.com/kindship-ai/kindship-cli/internal/boundaries"
+)
+
+// fallbackErrorMarkers identify LLM CLI failures caused by quota or
+// credentials rather than by the task, which another backend may not share
+var fallbackErrorMarkers = []string{
+	"rate limit",
+	"rate_limit",
+	"quota",
+	"usage limit",
+	"credit balance",
+	"overloaded",
+	"authentication",
+	"unauthorized",
+	"invalid api key",
+	"api key not valid",
+	"not logged in",
+}
+
+// llmCLIArgs returns the headless invocation of a non-Claude backend
+func llmCLIArgs(backend, prompt string) []string {
+	switch backend {
+	case boundaries.LLMBackendCodex:
+		return []string{backend, "exec", prompt}
+	default:
+		return []string{backend, "-p", prompt}
+	}
+}
+
+// isFallbackError reports whether a failed execution looks like a quota or
+// authentication problem, so the next backend should be tried
+func isFallbackError(result *ExecutionResult) bool {
+	if result.Success {
+		return false
+	}
+	output := strings.ToLower(result.Stderr + "\n" + result.Stdout)
+	for _, marker := range fallbackErrorMarkers {
+		if strings.Contains(output, marker) {
+			return true
+		}
+	}
+	return false
+}
@@ -7,10 +7,13 @@ import (
 	"fmt"
 	"os"
 	"os/exec"
+	"sort"
 	"strings"
 	"time"
 
 	"github.com/kindship-ai/kindship-cli/internal/api"
+	"github.com/kindship-ai/kindship-cli/internal/boundaries"
+	"github.com/kindship-ai/kindship-cli/internal/validator"
 )
 
 const maxOutputBytes = 1 << 20 // 1MB
@@ -36,24 +39,113 @@ func ExecuteBashWithContext(ctx context.Context, entity *api.PlanningEntity, inp
 	execCtx, cancel := context.WithTimeout(ctx, DefaultExecTimeout)
 	defer cancel()
 
-	cmd := exec.CommandContext(execCtx, "sh", "-c", *entity.Code)
-	cmd.Dir = "/workspace"
-	cmd.Env = buildEnvWithInputs(inputs)
+	opts := optionsFrom(ctx)
+	policy, err := boundaries.Parse(entity.Boundaries)
+	if err != nil {
+		return &ExecutionResult{
+			Success:  false,
+			ExitCode: 1,
+			Error:    err,
+		}
+	}
+	files, err := newRunFiles()
+	if err != nil {
+		return &ExecutionResult{
+			Success:  false,
+			ExitCode: 1,
+			Error:    fmt.Errorf("failed to create run files: %w", err),
+		}
+	}
+	defer files.Close()
+	outputPath, err := files.outputFile()
+	if err != nil {
+		return &ExecutionResult{
+			Success:  false,
+			ExitCode: 1,
+			Error:    fmt.Errorf("failed to create output file: %w", err),
+		}
+	}
+
+	shellArgv, err := shellArgs(opts.Shell.Merge(policy.Shell), *entity.Code)
+	if err != nil {
+		return &ExecutionResult{
+			Success:  false,
+			ExitCode: 1,
+			Error:    err,
+		}
+	}
+
+	workDir, err := policy.ResolveWorkDir(opts.Dir())
+	if err != nil {
+		return &ExecutionResult{
+			Success:  false,
+			ExitCode: 1,
+			Error:    err,
+		}
+	}
+
+	env := append(withSecretEnv(ctx, policy.Env.Append(buildEnvWithInputs(files, inputs))), "OUTPUT_FILE="+outputPath)
+	argv, env, network, err := prepareNetwork(policy.Network, shellArgv, env)
+	if err != nil {
+		return &ExecutionResult{
+			Success:  false,
+			ExitCode: 1,
+			Error:    err,
+		}
+	}
+	defer network.Close()
+
+	stdin, err := stdinInputs(policy, inputs)
+	if err != nil {
+		return &ExecutionResult{
+			Success:  false,
+			ExitCode: 1,
+			Error:    err,
+		}
+	}
+
+	if IsDryRun(ctx) {
+		return dryRunResult(entity.ExecutionMode, workDir, argv, env, stdin, shellSyntaxArgs(shellArgv, *entity.Code), "")
+	}
+
+	cmd := exec.CommandContext(execCtx, argv[0], argv[1:]...)
+	cmd.Dir = workDir
+	cmd.Env = env
+	if stdin != nil {
+		cmd.Stdin = bytes.NewReader(stdin)
+	}
+	setProcessGroup(cmd)
 
 	var stdout, stderr bytes.Buffer
-	cmd.Stdout = &limitedWriter{buf: &stdout, limit: maxOutputBytes}
-	cmd.Stderr = &limitedWriter{buf: &stderr, limit: maxOutputBytes}
+	stdoutW := &limitedWriter{buf: &stdout, limit: maxOutputBytes}
+	stderrW := &limitedWriter{buf: &stderr, limit: maxOutputBytes}
+	cmd.Stdout = followed(ctx, stdoutW, os.Stdout)
+	cmd.Stderr = followed(ctx, stderrW, os.Stderr)
 
-	err := cmd.Run()
+	err = runProcessGroup(execCtx, cmd)
+	usage := processUsage(cmd, stdoutW, stderrW)
 	exitCode := 0
 	if err != nil {
 		if execCtx.Err() == context.DeadlineExceeded {
 			return &ExecutionResult{
 				Success:  false,
 				Stdout:   stdout.String(),
-				Stderr:   stderr.String(),
+				Stderr:   network.annotate(stderr.String()),
 				ExitCode: 124, // standard timeout exit code
 				Error:    fmt.Errorf("execution timed out after %v", DefaultExecTimeout),
+				TimedOut: true,
+				Usage:    usage,
+			}
+		}
+		if ctx.Err() == context.Canceled {
+			return &ExecutionResult{
+				Success:   false,
+				Stdout:    stdout.String(),
+				Stderr:    network.annotate(stderr.String()),
+				ExitCode:  130,
+				Error:     fmt.Errorf("execution cancelled"),
+				Cancelled: true,
+				Usage:     usage,
 			}
 		}
 		if exitError, ok := err.(*exec.ExitError); ok {
@@ -63,42 +155,54 @@ func ExecuteBashWithContext(ctx context.Context, entity *api.PlanningEntity, inp
 		}
 	}
 
-	return &ExecutionResult{
+	return attachOutputFile(&ExecutionResult{
 		Success:  exitCode == 0,
 		Stdout:   stdout.String(),
-		Stderr:   stderr.String(),
+		Stderr:   network.annotate(stderr.String()),
 		ExitCode: exitCode,
 		Error:    err,
-	}
+		Usage:    usage,
+	}, outputPath)
 }
 
-// inputDir is where input JSON files are written for safe BASH consumption.
-// BASH's echo interprets \n escape sequences, corrupting JSON. File-based
-// access via INPUT_<LABEL>_FILE avoids this.
-const inputDir = "/tmp/.kindship-inputs"
+// shellArgs returns the argv that runs code under the selected shell,
+// verifying the interpreter is installed
+func shellArgs(shell *boundaries.ShellPolicy, code string) ([]string, error) {
+	if shell.Interpreter == "" {
+		return shellCommand(code), nil
+	}
+	if _, err := exec.LookPath(shell.Interpreter); err != nil {
+		return nil, fmt.Errorf("shell %q is not installed (not found in PATH)", shell.Interpreter)
+	}
+	argv := append([]string{shell.Interpreter}, shell.Args...)
+	return append(argv, "-c", code), nil
+}
 
 // buildEnvWithInputs creates an environment variable slice with the current
 // env plus INPUT_<LABEL>=<json_value> and INPUT_<LABEL>_FILE=<path> for each
 // labeled input. The _FILE variant provides safe access for BASH scripts that
 // would otherwise corrupt JSON via echo's escape sequence interpretation.
-func buildEnvWithInputs(inputs map[string]interface{}) []string {
+// Inputs too large for the environment (see OversizedInputs) only get the
+// _FILE variant. The files are written to the execution's files.
+func buildEnvWithInputs(files *runFiles, inputs map[string]interface{}) []string {
 	env := os.Environ()
 
-	if len(inputs) > 0 {
-		_ = os.MkdirAll(inputDir, 0755)
+	oversized := map[string]bool{}
+	for _, label := range OversizedInputs(inputs) {
+		oversized[label] = true
 	}
-
-	for label, value := range inputs {
-		envKey := "INPUT_" + strings.ToUpper(strings.ReplaceAll(label, "-", "_"))
-		jsonBytes, err := json.Marshal(value)
+	for _, label := range validator.GetInputLabels(inputs) {
+		envKey := inputEnvKey(label)
+		jsonBytes, err := json.Marshal(inputs[label])
 		if err != nil {
 			continue
 		}
-		env = append(env, fmt.Sprintf("%s=%s", envKey, string(jsonBytes)))
+		if !oversized[label] {
+			env = append(env, fmt.Sprintf("%s=%s", envKey, string(jsonBytes)))
+		}
 
 		// Write to file for safe BASH access (avoids echo \n interpretation)
-		filePath := fmt.Sprintf("%s/%s.json", inputDir, label)
-		if writeErr := os.WriteFile(filePath, jsonBytes, 0644); writeErr == nil {
+		if filePath, writeErr := files.writeInput(label, jsonBytes); writeErr == nil {
 			env = append(env, fmt.Sprintf("%s_FILE=%s", envKey, filePath))
 		}
 	}
@@ -106,19 +210,95 @@ func buildEnvWithInputs(inputs map[string]interface{}) []string {
 	return env
 }
 
+// inputEnvKey returns the INPUT_<LABEL> variable name of an input
+func inputEnvKey(label string) string {
+	return "INPUT_" + strings.ToUpper(strings.ReplaceAll(label, "-", "_"))
+}
+
+// OversizedInputs returns, sorted, the labels of inputs whose JSON is too
+// large to pass as an INPUT_<LABEL> environment variable: any single
+// variable over the OS limit, then the largest remaining inputs until all
+// INPUT_* variables fit together. Exec fails with "argument list too long"
+// otherwise. These inputs are only exposed through INPUT_<LABEL>_FILE.
+func OversizedInputs(inputs map[string]interface{}) []string {
+	sizes := map[string]int{}
+	var fitting []string
+	total := 0
+	for label, value := range inputs {
+		jsonBytes, err := json.Marshal(value)
+		if err != nil {
+			continue
+		}
+		size := len(inputEnvKey(label)) + 1 + len(jsonBytes)
+		sizes[label] = size
+		if size <= maxEnvVarBytes {
+			fitting = append(fitting, label)
+			total += size
+		}
+	}
+
+	// Largest first, by label for ties, so the choice is deterministic
+	sort.Slice(fitting, func(i, j int) bool {
+		if sizes[fitting[i]] != sizes[fitting[j]] {
+			return sizes[fitting[i]] > sizes[fitting[j]]
+		}
+		return fitting[i] < fitting[j]
+	})
+	dropped := 0
+	for dropped < len(fitting) && total > maxInputEnvBytes {
+		total -= sizes[fitting[dropped]]
+		dropped++
+	}
+
+	var oversized []string
+	for label, size := range sizes {
+		if size > maxEnvVarBytes {
+			oversized = append(oversized, label)
+		}
+	}
+	oversized = append(oversized, fitting[:dropped]...)
+	sort.Strings(oversized)
+	return oversized
+}
+
+// stdinInputs returns the inputs map as JSON for the process's stdin when
+// boundaries.inputs.stdin is set, or nil
+func stdinInputs(policy *boundaries.Policy, inputs map[string]interface{}) ([]byte, error) {
+	if !policy.Inputs.PipesStdin() {
+		return nil, nil
+	}
+	if inputs == nil {
+		inputs = map[string]interface{}{}
+	}
+	data, err := json.Marshal(inputs)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode inputs for stdin: %w", err)
+	}
+	return data, nil
+}
+
 // limitedWriter wraps a bytes.Buffer and stops writing after limit bytes.
+// written counts every byte offered, kept or not.
 type limitedWriter struct {
-	buf   *bytes.Buffer
-	limit int
+	buf     *bytes.Buffer
+	limit   int
+	written int64
 }
 
 func (w *limitedWriter) Write(p []byte) (int, error) {
+	w.written += int64(len(p))
 	remaining := w.limit - w.buf.Len()
 	if remaining <= 0 {
 		return len(p), nil // discard silently
 	}
-	if len(p) > remaining {
+	n := len(p)
+	if n > remaining {
 		p = p[:remaining]
 	}
-	return w.buf.Write(p)
+	// Report the whole write: a short write would stop the copy and kill
+	// the process with SIGPIPE
+	if _, err := w.buf.Write(p); err != nil {
+		return 0, err
+	}
+	return n, nil
 }
@@ -0,0 +1,91 @@
+package executor
+
+import (
+	"bytes"
+	"fmt"
+	"os/exec"
+	"strings"
+)
+
+// codeRefPrefix marks entity code that points at a file in the bound
+// repository instead of carrying the script inline
+const codeRefPrefix = "file://"
+
+// CodeRef is a pinned reference to a script in version control, written as
+// file://<path>@<ref> (e.g. file://scripts/etl.py@main). The ref defaults to
+// HEAD when omitted.
+type CodeRef struct {
+	Path string
+	Ref  string
+}
+
+func (r CodeRef) String() string {
+	return codeRefPrefix + r.Path + "@" + r.Ref
+}
+
+// ParseCodeRef returns the code reference in code, if code is one
+func ParseCodeRef(code string) (*CodeRef, bool) {
+	code = strings.TrimSpace(code)
+	if !strings.HasPrefix(code, codeRefPrefix) || strings.ContainsAny(code, "\n\r") {
+		return nil, false
+	}
+	spec := strings.TrimPrefix(code, codeRefPrefix)
+	ref := &CodeRef{Path: spec, Ref: "HEAD"}
+	if i := strings.LastIndex(spec, "@"); i != -1 {
+		ref.Path, ref.Ref = spec[:i], spec[i+1:]
+	}
+	ref.Path = strings.TrimPrefix(ref.Path, "/")
+	return ref, true
+}
+
+// ResolveCode returns the script to execute for code. Inline code is
+// returned unchanged; a file:// reference is read from the repository in
+// workDir at the pinned ref, fetching the ref from origin if it is not
+// available locally.
+func ResolveCode(workDir, code string) (string, error) {
+	ref, ok := ParseCodeRef(code)
+	if !ok {
+		return code, nil
+	}
+	if ref.Path == "" || ref.Ref == "" {
+		return "", fmt.Errorf("invalid code reference %q (expected file://<path>@<ref>)", code)
+	}
+	if strings.HasPrefix(ref.Ref, "-") {
+		return "", fmt.Errorf("invalid ref %q in code reference", ref.Ref)
+	}
+
+	content, err := gitShow(workDir, ref.Ref+":"+ref.Path)
+	if err != nil {
+		// The ref may only exist on the remote (e.g. a branch not yet fetched)
+		if _, fetchErr := git(workDir, "fetch", "--quiet", "origin", ref.Ref); fetchErr != nil {
+			return "", fmt.Errorf("failed to read %s: %w", ref, err)
+		}
+		content, err = gitShow(workDir, "FETCH_HEAD:"+ref.Path)
+		if err != nil {
+			return "", fmt.Errorf("failed to read %s: %w", ref, err)
+		}
+	}
+	if strings.TrimSpace(content) == "" {
+		return "", fmt.Errorf("%s is empty", ref)
+	}
+	return content, nil
+}
+
+func gitShow(dir, object string) (string, error) {
+	return git(dir, "show", object)
+}
+
+// git runs a git command in dir and returns its stdout
+func git(dir string, args ...string) (string, error) {
+	cmd := exec.Command("git", append([]string{"-C", dir}, args...)...)
+	var stdout, stderr bytes.Buffer
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+	if err := cmd.Run(); err != nil {
+		if msg := strings.TrimSpace(stderr.String()); msg != "" {
+			return "", fmt.Errorf("git %s: %s", args[0], msg)
+		}
+		return "", fmt.Errorf("git %s: %w", args[0], err)
+	}
+	return stdout.String(), nil
+}
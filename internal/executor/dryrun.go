@@ -0,0 +1,142 @@
+package executor
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/kindship-ai/kindship-cli/internal/api"
+)
+
+// syntaxCheckTimeout bounds the parse-only run of a dry run's code
+const syntaxCheckTimeout = 10 * time.Second
+
+// syntaxCheckShells are the shells that can parse a script without running
+// it ('-n')
+var syntaxCheckShells = map[string]bool{"sh": true, "bash": true, "dash": true, "zsh": true, "ksh": true}
+
+type dryRunKey struct{}
+
+// WithDryRun returns a context whose BASH and PYTHON executions describe
+// the command they would run (argv, added environment variable names,
+// working directory and timeout) and check that the code parses, instead
+// of running it
+func WithDryRun(ctx context.Context) context.Context {
+	return context.WithValue(ctx, dryRunKey{}, true)
+}
+
+// IsDryRun reports whether ctx was returned by WithDryRun
+func IsDryRun(ctx context.Context) bool {
+	dryRun, _ := ctx.Value(dryRunKey{}).(bool)
+	return dryRun
+}
+
+// dryRunResult describes an execution of argv in workDir with env and
+// stdin that was not started. syntax is the parse-only command for the code, given
+// syntaxInput on its stdin (nil when the interpreter cannot check syntax);
+// a parse failure fails the result.
+func dryRunResult(mode api.ExecutionMode, workDir string, argv, env []string, stdin []byte, syntax []string, syntaxInput string) *ExecutionResult {
+	var out strings.Builder
+	fmt.Fprintf(&out, "Dry run: %s execution not started\n", mode)
+	fmt.Fprintf(&out, "  Command:     %s <code>\n", strings.Join(argv[:len(argv)-1], " "))
+	fmt.Fprintf(&out, "  Work dir:    %s\n", workDir)
+	fmt.Fprintf(&out, "  Timeout:     %s\n", DefaultExecTimeout)
+	fmt.Fprintf(&out, "  Environment: %s\n", strings.Join(addedEnvNames(env), ", "))
+	if stdin != nil {
+		fmt.Fprintf(&out, "  Stdin:       inputs as JSON (%d bytes)\n", len(stdin))
+	}
+
+	result := &ExecutionResult{Success: true}
+	switch checked, err := checkSyntax(syntax, syntaxInput); {
+	case !checked:
+		fmt.Fprintf(&out, "  Syntax:      not checked\n")
+	case err != nil:
+		fmt.Fprintf(&out, "  Syntax:      FAILED\n")
+		result.Success = false
+		result.ExitCode = 2
+		result.Error = err
+	default:
+		fmt.Fprintf(&out, "  Syntax:      ok\n")
+	}
+	fmt.Fprintf(&out, "  Code:\n")
+	for _, line := range strings.Split(strings.TrimRight(argv[len(argv)-1], "\n"), "\n") {
+		fmt.Fprintf(&out, "    %s\n", line)
+	}
+	result.Stdout = out.String()
+	return result
+}
+
+// addedEnvNames returns the sorted names of the variables in env that the
+// execution adds to or changes from the CLI's own environment
+func addedEnvNames(env []string) []string {
+	inherited := map[string]bool{}
+	for _, kv := range os.Environ() {
+		inherited[kv] = true
+	}
+	var names []string
+	for _, kv := range env {
+		if inherited[kv] {
+			continue
+		}
+		name, _, _ := strings.Cut(kv, "=")
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// shellSyntaxArgs returns the parse-only argv for code run by shellArgv,
+// or nil if its shell cannot check syntax
+func shellSyntaxArgs(shellArgv []string, code string) []string {
+	shell := strings.TrimSuffix(filepath.Base(shellArgv[0]), ".exe")
+	if !syntaxCheckShells[shell] {
+		return nil
+	}
+	return []string{shellArgv[0], "-n", "-c", code}
+}
+
+// pythonSyntaxArgs is the parse-only argv for Python code passed on stdin.
+// It reports a syntax error as "line N: message".
+var pythonSyntaxArgs = []string{PythonRuntime, "-c", `import sys
+try:
+    compile(sys.stdin.read(), "<code>", "exec")
+except SyntaxError as e:
+    sys.exit("line %s: %s" % (e.lineno, e.msg))`}
+
+// checkSyntax runs a parse-only command, returning its diagnostics as the
+// error when the code does not parse. checked is false when there is no
+// such command or its interpreter is not installed; Preflight reports a
+// missing interpreter when the task runs.
+func checkSyntax(argv []string, stdin string) (checked bool, err error) {
+	if argv == nil {
+		return false, nil
+	}
+	if _, err := exec.LookPath(argv[0]); err != nil {
+		return false, nil
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), syntaxCheckTimeout)
+	defer cancel()
+	cmd := exec.CommandContext(ctx, argv[0], argv[1:]...)
+	cmd.Stdin = strings.NewReader(stdin)
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+	if err := cmd.Run(); err != nil {
+		if msg := strings.TrimSpace(stderr.String()); msg != "" {
+			return true, fmt.Errorf("syntax error: %s", lastLine(msg))
+		}
+		return true, fmt.Errorf("syntax check failed: %w", err)
+	}
+	return true, nil
+}
+
+// lastLine returns the last line of s, where interpreters put the actual
+// error after any context
+func lastLine(s string) string {
+	return s[strings.LastIndex(s, "\n")+1:]
+}
@@ -0,0 +1,87 @@
+package executor
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+)
+
+// followed returns capture, teed to terminal when the context's
+// Options.Follow is set
+func followed(ctx context.Context, capture, terminal io.Writer) io.Writer {
+	if !optionsFrom(ctx).Follow {
+		return capture
+	}
+	return io.MultiWriter(capture, terminalWriter{terminal})
+}
+
+// terminalWriter ignores write errors so a closed terminal cannot fail
+// the execution it is following
+type terminalWriter struct {
+	w io.Writer
+}
+
+func (t terminalWriter) Write(p []byte) (int, error) {
+	t.w.Write(p)
+	return len(p), nil
+}
+
+// transcriptFollower renders Claude's stream-json transcript for following:
+// the assistant's text and the tools it calls, as each event arrives.
+// Lines that are not JSON are passed through.
+type transcriptFollower struct {
+	out     io.Writer
+	pending []byte
+}
+
+func newTranscriptFollower() *transcriptFollower {
+	return &transcriptFollower{out: os.Stdout}
+}
+
+func (f *transcriptFollower) Write(p []byte) (int, error) {
+	f.pending = append(f.pending, p...)
+	for {
+		i := bytes.IndexByte(f.pending, '\n')
+		if i < 0 {
+			break
+		}
+		f.render(f.pending[:i])
+		f.pending = f.pending[i+1:]
+	}
+	return len(p), nil
+}
+
+func (f *transcriptFollower) render(line []byte) {
+	line = bytes.TrimSpace(line)
+	if len(line) == 0 {
+		return
+	}
+	var event struct {
+		Type    string `json:"type"`
+		Message struct {
+			Content []struct {
+				Type string `json:"type"`
+				Text string `json:"text"`
+				Name string `json:"name"`
+			} `json:"content"`
+		} `json:"message"`
+	}
+	if json.Unmarshal(line, &event) != nil {
+		fmt.Fprintf(f.out, "%s\n", line)
+		return
+	}
+	if event.Type != "assistant" {
+		return
+	}
+	for _, block := range event.Message.Content {
+		switch block.Type {
+		case "text":
+			fmt.Fprintln(f.out, block.Text)
+		case "tool_use":
+			fmt.Fprintf(f.out, "→ %s\n", block.Name)
+		}
+	}
+}
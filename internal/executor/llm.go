@@ -2,12 +2,18 @@ package executor
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
 	"fmt"
+	"io"
+	"os"
 	"os/exec"
+	"strconv"
 	"strings"
 
 	"github.com/kindship-ai/kindship-cli/internal/api"
+	"github.com/kindship-ai/kindship-cli/internal/boundaries"
+	"github.com/kindship-ai/kindship-cli/internal/validator"
 )
 
 // ExecutionResult represents the result of an execution attempt
@@ -17,21 +23,175 @@ type ExecutionResult struct {
 	Stderr   string
 	ExitCode int
 	Error    error
+	TimedOut bool
+	// Cancelled is set when the execution was stopped through its context
+	// (e.g. the run was cancelled from the UI)
+	Cancelled bool
+	// CostUSD is the LLM spend reported for the execution, if known
+	CostUSD float64
+	// OutputFile holds the JSON the script wrote to $OUTPUT_FILE, if any.
+	// It takes precedence over structured output extracted from stdout.
+	OutputFile []byte
+	// Tests summarises the parsed test reports of a TEST execution
+	Tests *TestSummary
+	// Usage is the resource usage of the executed process, if one ran
+	Usage *ProcessUsage
+	// Transcript is the full LLM conversation as JSON lines, if captured
+	Transcript []byte
+	// LLM is the result object reported by Claude, if it produced one
+	LLM *LLMResult
+	// LLMBackend is the LLM CLI that served the execution
+	LLMBackend string
 }
 
-// ExecuteLLM executes a planning entity using LLM reasoning (Claude Code)
+// ExecuteLLM executes a planning entity using LLM reasoning
 func ExecuteLLM(entity *api.PlanningEntity, inputs map[string]interface{}) *ExecutionResult {
-	prompt := buildPrompt(entity, inputs)
+	return ExecuteLLMWithContext(context.Background(), entity, inputs)
+}
+
+// ExecuteLLMWithContext executes a planning entity using LLM reasoning. Backends are
+// tried in the order configured by boundaries.llm.backends (default: claude);
+// the next one is used when a backend is not installed or fails with a
+// quota or authentication error.
+func ExecuteLLMWithContext(ctx context.Context, entity *api.PlanningEntity, inputs map[string]interface{}) *ExecutionResult {
+	policy, err := boundaries.Parse(entity.Boundaries)
+	if err != nil {
+		return &ExecutionResult{
+			Success:  false,
+			ExitCode: 1,
+			Error:    err,
+		}
+	}
+	opts := optionsFrom(ctx)
+	llmPolicy := opts.LLM.Merge(policy.LLM)
+	workDir, err := policy.ResolveWorkDir(opts.Dir())
+	if err != nil {
+		return &ExecutionResult{
+			Success:  false,
+			ExitCode: 1,
+			Error:    err,
+		}
+	}
 
-	// Execute Claude Code via kindship auth which injects credentials from the API
-	cmd := exec.Command("kindship", "auth", "claude", "-p", prompt)
-	cmd.Dir = "/workspace"
+	files, err := newRunFiles()
+	if err != nil {
+		return &ExecutionResult{
+			Success:  false,
+			ExitCode: 1,
+			Error:    fmt.Errorf("failed to create run files: %w", err),
+		}
+	}
+	defer files.Close()
+
+	prompt := buildPrompt(entity, inputs, promptOptions{
+		WorkDir:     workDir,
+		RepoContext: buildRepoContext(opts.Dir(), opts.RepoContext),
+		Inputs:      policy.Inputs,
+		Files:       files,
+	})
+
+	// Start MCP servers required by the entity and generate Claude's config
+	mcp, err := StartMCPServers(entity.MCPServers, opts.Dir())
+	if err != nil {
+		return &ExecutionResult{
+			Success:  false,
+			ExitCode: 1,
+			Error:    err,
+		}
+	}
+	defer mcp.Close()
+
+	backends := llmPolicy.BackendChain()
+	var result *ExecutionResult
+	for i, backend := range backends {
+		last := i == len(backends)-1
+		if _, err := exec.LookPath(backend); err != nil && !last {
+			continue
+		}
+		if backend == boundaries.LLMBackendClaude {
+			result = runClaude(ctx, workDir, prompt, mcp, llmPolicy)
+		} else {
+			result = runLLMCLI(ctx, workDir, backend, prompt)
+		}
+		result.LLMBackend = backend
+		if result.Success || result.Cancelled || last || !isFallbackError(result) {
+			break
+		}
+	}
+	return result
+}
+
+// runClaude executes Claude Code via kindship auth, which injects
+// credentials from the API
+func runClaude(ctx context.Context, workDir, prompt string, mcp *MCPSession, llmPolicy *boundaries.LLMPolicy) *ExecutionResult {
+	args := append([]string{"auth", "claude"}, mcp.ClaudeArgs()...)
+	args = append(args, claudePermissionArgs(llmPolicy)...)
+	jsonOutput := claudeSupportsJSONOutput()
+	if jsonOutput {
+		args = append(args, claudeTranscriptArgs...)
+	}
+	args = append(args, "-p", prompt)
+
+	result, stdout := runViaAuth(ctx, workDir, args, maxTranscriptBytes, jsonOutput)
+
+	// Keep the whole conversation, also of runs that crashed, timed out or
+	// were cancelled; stdout carries only the final answer
+	if jsonOutput {
+		if len(stdout) > 0 {
+			result.Transcript = stdout
+		}
+		if llm := parseLLMResult(stdout); llm == nil {
+			// No result event: the run ended early, so its last message is
+			// the closest thing to an answer
+			if text, ok := lastAssistantText(stdout); ok {
+				result.Stdout = text
+			}
+		} else {
+			result.Stdout = llm.Result
+			result.LLM = llm
+			result.CostUSD = llm.CostUSD
+			// Claude can exit 0 while reporting a failed run (e.g. max turns)
+			if llm.IsError && result.Success {
+				result.Success = false
+				result.ExitCode = 1
+				result.Error = fmt.Errorf("claude reported an error")
+				if llm.Subtype != "" {
+					result.Error = fmt.Errorf("claude reported an error (%s)", llm.Subtype)
+				}
+			}
+		}
+	}
+	if len(result.Stdout) > maxOutputBytes {
+		result.Stdout = result.Stdout[:maxOutputBytes]
+	}
+	return result
+}
+
+// runLLMCLI executes a non-Claude backend in headless mode via kindship auth
+func runLLMCLI(ctx context.Context, workDir, backend, prompt string) *ExecutionResult {
+	result, _ := runViaAuth(ctx, workDir, append([]string{"auth"}, llmCLIArgs(backend, prompt)...), maxOutputBytes, false)
+	return result
+}
+
+// runViaAuth runs 'kindship <args>' in workDir, keeping up to
+// stdoutLimit bytes of stdout. The raw stdout is returned alongside the result.
+// transcript marks stdout as a stream-json transcript, rendered when following.
+func runViaAuth(ctx context.Context, workDir string, args []string, stdoutLimit int, transcript bool) (*ExecutionResult, []byte) {
+	cmd := exec.CommandContext(ctx, "kindship", args...)
+	cmd.Dir = workDir
+	setProcessGroup(cmd)
 
 	var stdout, stderr bytes.Buffer
-	cmd.Stdout = &stdout
-	cmd.Stderr = &stderr
+	var terminal io.Writer = os.Stdout
+	if transcript {
+		terminal = newTranscriptFollower()
+	}
+	stdoutW := &limitedWriter{buf: &stdout, limit: stdoutLimit}
+	stderrW := &limitedWriter{buf: &stderr, limit: maxOutputBytes}
+	cmd.Stdout = followed(ctx, stdoutW, terminal)
+	cmd.Stderr = followed(ctx, stderrW, os.Stderr)
 
-	err := cmd.Run()
+	err := runProcessGroup(ctx, cmd)
 	exitCode := 0
 	if err != nil {
 		if exitError, ok := err.(*exec.ExitError); ok {
@@ -42,16 +202,124 @@ func ExecuteLLM(entity *api.PlanningEntity, inputs map[string]interface{}) *Exec
 	}
 
 	return &ExecutionResult{
-		Success:  exitCode == 0,
-		Stdout:   stdout.String(),
-		Stderr:   stderr.String(),
-		ExitCode: exitCode,
-		Error:    err,
+		Success:   exitCode == 0,
+		Stdout:    stdout.String(),
+		Stderr:    stderr.String(),
+		ExitCode:  exitCode,
+		Error:     err,
+		Cancelled: err != nil && ctx.Err() == context.Canceled,
+		Usage:     processUsage(cmd, stdoutW, stderrW),
+	}, stdout.Bytes()
+}
+
+// writePromptInput inlines a dependency output in the prompt, applying the
+// size limit from boundaries.inputs. Oversized inputs are truncated with a
+// marker, or written to a file the model is told to read.
+func writePromptInput(prompt *strings.Builder, label string, jsonBytes []byte, opts promptOptions) {
+	policy := opts.Inputs
+	limit := policy.Limit()
+	if len(jsonBytes) <= limit {
+		prompt.WriteString("```json\n")
+		prompt.Write(jsonBytes)
+		prompt.WriteString("\n```\n\n")
+		return
+	}
+
+	if policy.OverflowMode() == boundaries.InputOverflowFile && opts.Files != nil {
+		path, err := opts.Files.writeInput(label, jsonBytes)
+		if err == nil {
+			prompt.WriteString(fmt.Sprintf("This input is %d bytes, too large to include here. "+
+				"Read it from `%s` (JSON) when you need it.\n\n", len(jsonBytes), path))
+			return
+		}
+		// Fall back to truncation if the file cannot be written
+	}
+
+	prompt.WriteString("```json\n")
+	prompt.Write(jsonBytes[:limit])
+	prompt.WriteString(fmt.Sprintf("\n[... truncated: %d of %d bytes shown]\n```\n\n", limit, len(jsonBytes)))
+}
+
+// writeInputPages lists the inputs that did not fit in the prompt. In
+// label order, they are split into pages of at most the total input limit
+// (at least one input each), written to the run's files as JSON objects
+// keyed by label. The prompt itself is page 1.
+func writeInputPages(prompt *strings.Builder, labels []string, inputs map[string]interface{}, opts promptOptions) {
+	prompt.WriteString(fmt.Sprintf("### More Inputs\n%d more inputs did not fit in this prompt. ", len(labels)))
+	if opts.Files == nil {
+		prompt.WriteString(fmt.Sprintf("They are not available: %s\n\n", strings.Join(labels, ", ")))
+		return
+	}
+	prompt.WriteString("They are written to pages, JSON objects keyed by label; read a page when you need its inputs.\n\n")
+
+	limit := opts.Inputs.TotalLimit()
+	number := 1
+	page := map[string]json.RawMessage{}
+	var pageLabels []string
+	size := 0
+	flush := func() {
+		number++
+		where := "(could not be written)"
+		if data, err := json.MarshalIndent(page, "", "  "); err == nil {
+			if path, err := opts.Files.writeInputPage(number, data); err == nil {
+				where = "`" + path + "`"
+			}
+		}
+		prompt.WriteString(fmt.Sprintf("- Page %d %s: %s\n", number, where, strings.Join(pageLabels, ", ")))
+		page = map[string]json.RawMessage{}
+		pageLabels = nil
+		size = 0
+	}
+	for _, label := range labels {
+		data, err := json.Marshal(inputs[label])
+		if err != nil {
+			continue
+		}
+		if len(pageLabels) > 0 && size+len(data) > limit {
+			flush()
+		}
+		page[label] = data
+		pageLabels = append(pageLabels, label)
+		size += len(data)
+	}
+	if len(pageLabels) > 0 {
+		flush()
+	}
+	prompt.WriteString("\n")
+}
+
+// claudePermissionArgs converts an LLM policy into Claude Code CLI flags
+func claudePermissionArgs(policy *boundaries.LLMPolicy) []string {
+	var args []string
+	if policy.PermissionMode != "" {
+		args = append(args, "--permission-mode", policy.PermissionMode)
+	}
+	if len(policy.AllowedTools) > 0 {
+		args = append(args, "--allowedTools", strings.Join(policy.AllowedTools, ","))
+	}
+	if len(policy.DisallowedTools) > 0 {
+		args = append(args, "--disallowedTools", strings.Join(policy.DisallowedTools, ","))
 	}
+	if policy.MaxTurns > 0 {
+		args = append(args, "--max-turns", strconv.Itoa(policy.MaxTurns))
+	}
+	return args
+}
+
+// promptOptions carries the execution settings that shape an LLM prompt
+type promptOptions struct {
+	// WorkDir is the directory the task runs in
+	WorkDir string
+	// RepoContext, when set, describes the bound repository's conventions and state
+	RepoContext string
+	// Inputs bounds how much of each dependency output is inlined
+	Inputs *boundaries.InputPolicy
+	// Files receives the inputs too large to inline
+	Files *runFiles
 }
 
 // buildPrompt creates a comprehensive prompt for Claude Code
-func buildPrompt(entity *api.PlanningEntity, inputs map[string]interface{}) string {
+func buildPrompt(entity *api.PlanningEntity, inputs map[string]interface{}, opts promptOptions) string {
 	var prompt strings.Builder
 
 	prompt.WriteString("You are executing a planning entity in Kindship.\n\n")
@@ -65,25 +333,41 @@ func buildPrompt(entity *api.PlanningEntity, inputs map[string]interface{}) stri
 		prompt.WriteString(fmt.Sprintf("## Rationale\n%s\n\n", *entity.Rationale))
 	}
 
+	// Add repository conventions and state so they need not be rediscovered
+	if opts.RepoContext != "" {
+		prompt.WriteString("## Repository Context\n\n")
+		prompt.WriteString(opts.RepoContext)
+		prompt.WriteString("\n\n")
+	}
+
 	// Add inputs from dependencies
 	if len(inputs) > 0 {
 		prompt.WriteString("## Available Inputs\n\n")
 		prompt.WriteString("The following inputs are available from completed dependencies:\n\n")
-		for label, value := range inputs {
-			jsonBytes, err := json.MarshalIndent(value, "", "  ")
+		labels := validator.GetInputLabels(inputs)
+		inlined := 0
+		for i, label := range labels {
+			jsonBytes, err := json.MarshalIndent(inputs[label], "", "  ")
 			if err != nil {
 				prompt.WriteString(fmt.Sprintf("### Input: %s\n[Error marshaling input]\n\n", label))
 				continue
 			}
+			size := len(jsonBytes)
+			if limit := opts.Inputs.Limit(); size > limit {
+				size = limit
+			}
+			if i > 0 && inlined+size > opts.Inputs.TotalLimit() {
+				writeInputPages(&prompt, labels[i:], inputs, opts)
+				break
+			}
+			inlined += size
 			// Add a note for the "prev" label
 			if label == "prev" {
 				prompt.WriteString(fmt.Sprintf("### Input: %s (Previous Sibling Output)\n", label))
 			} else {
 				prompt.WriteString(fmt.Sprintf("### Input: %s\n", label))
 			}
-			prompt.WriteString("```json\n")
-			prompt.WriteString(string(jsonBytes))
-			prompt.WriteString("\n```\n\n")
+			writePromptInput(&prompt, label, jsonBytes, opts)
 		}
 	}
 
@@ -122,8 +406,8 @@ func buildPrompt(entity *api.PlanningEntity, inputs map[string]interface{}) stri
 
 	// Add constraints and guidelines
 	prompt.WriteString("## Guidelines\n")
-	prompt.WriteString("- Work in the /workspace directory\n")
-	prompt.WriteString("- All artifacts should be saved to /workspace\n")
+	prompt.WriteString(fmt.Sprintf("- Work in the %s directory\n", opts.WorkDir))
+	prompt.WriteString(fmt.Sprintf("- All artifacts should be saved to %s\n", opts.WorkDir))
 	prompt.WriteString("- Ensure all success criteria are met before completing\n")
 	prompt.WriteString("- If you encounter blockers, document them clearly\n")
 	if len(inputs) > 0 {
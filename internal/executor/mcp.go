@@ -0,0 +1,223 @@
+package executor
+
+import (
+	"encoding/json"
+	"fmt"
+	"net"
+	"net/url"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/kindship-ai/kindship-cli/internal/config"
+)
+
+// MCPCatalogFile is the filename of the MCP server catalog in the global
+// config directory. Override the full path with KINDSHIP_MCP_CONFIG.
+const MCPCatalogFile = "mcp.json"
+
+// defaultMCPReadyTimeout bounds how long a launched server may take to accept connections
+const defaultMCPReadyTimeout = 10 * time.Second
+
+// mcpLaunch describes a server process the CLI must start itself (for
+// servers reached over HTTP/SSE). Stdio servers are spawned by Claude.
+type mcpLaunch struct {
+	Command             string            `json:"command"`
+	Args                []string          `json:"args,omitempty"`
+	Env                 map[string]string `json:"env,omitempty"`
+	ReadyTimeoutSeconds int               `json:"ready_timeout_seconds,omitempty"`
+}
+
+// MCPSession holds the generated Claude MCP configuration and any server
+// processes started for a single execution. Call Close when done.
+type MCPSession struct {
+	ConfigPath string
+	Servers    []string
+	processes  []*exec.Cmd
+}
+
+// mcpCatalogPath returns the location of the MCP server catalog
+func mcpCatalogPath() (string, error) {
+	if path := os.Getenv("KINDSHIP_MCP_CONFIG"); path != "" {
+		return path, nil
+	}
+	dir, err := config.GetGlobalConfigDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, MCPCatalogFile), nil
+}
+
+// StartMCPServers resolves the named servers against the MCP catalog, starts
+// any that declare a "launch" command in workDir, and writes a Claude --mcp-config file
+// containing only those servers. Returns an empty session when names is empty.
+//
+// The catalog uses Claude's format with an optional "launch" block:
+//
+//	{"mcpServers": {
+//	  "github": {"command": "npx", "args": ["-y", "@modelcontextprotocol/server-github"]},
+//	  "search": {"type": "http", "url": "http://127.0.0.1:8931/mcp",
+//	             "launch": {"command": "search-mcp", "args": ["--port", "8931"]}}
+//	}}
+func StartMCPServers(names []string, workDir string) (*MCPSession, error) {
+	session := &MCPSession{}
+	if len(names) == 0 {
+		return session, nil
+	}
+
+	catalogPath, err := mcpCatalogPath()
+	if err != nil {
+		return nil, err
+	}
+	data, err := os.ReadFile(catalogPath)
+	if err != nil {
+		return nil, fmt.Errorf("entity requires MCP servers %v but catalog %s could not be read: %w", names, catalogPath, err)
+	}
+
+	var catalog struct {
+		MCPServers map[string]map[string]interface{} `json:"mcpServers"`
+	}
+	if err := json.Unmarshal(data, &catalog); err != nil {
+		return nil, fmt.Errorf("failed to parse MCP catalog %s: %w", catalogPath, err)
+	}
+
+	var missing []string
+	selected := make(map[string]interface{})
+	launches := make(map[string]mcpLaunch)
+	for _, name := range names {
+		entry, ok := catalog.MCPServers[name]
+		if !ok {
+			missing = append(missing, name)
+			continue
+		}
+		server := make(map[string]interface{}, len(entry))
+		for k, v := range entry {
+			if k == "launch" {
+				raw, _ := json.Marshal(v)
+				var launch mcpLaunch
+				if err := json.Unmarshal(raw, &launch); err != nil || launch.Command == "" {
+					return nil, fmt.Errorf("invalid launch block for MCP server %s", name)
+				}
+				launches[name] = launch
+				continue
+			}
+			server[k] = v
+		}
+		selected[name] = server
+		session.Servers = append(session.Servers, name)
+	}
+	if len(missing) > 0 {
+		return nil, fmt.Errorf("MCP server(s) not found in catalog %s: %s", catalogPath, strings.Join(missing, ", "))
+	}
+
+	// Start servers in a stable order so failures are reproducible
+	launchNames := make([]string, 0, len(launches))
+	for name := range launches {
+		launchNames = append(launchNames, name)
+	}
+	sort.Strings(launchNames)
+	for _, name := range launchNames {
+		if err := session.launch(name, workDir, launches[name], selected[name].(map[string]interface{})); err != nil {
+			session.Close()
+			return nil, err
+		}
+	}
+
+	configData, err := json.MarshalIndent(map[string]interface{}{"mcpServers": selected}, "", "  ")
+	if err != nil {
+		session.Close()
+		return nil, fmt.Errorf("failed to marshal MCP config: %w", err)
+	}
+	configFile, err := os.CreateTemp("", "kindship-mcp-*.json")
+	if err != nil {
+		session.Close()
+		return nil, fmt.Errorf("failed to create MCP config: %w", err)
+	}
+	session.ConfigPath = configFile.Name()
+	_, writeErr := configFile.Write(configData)
+	configFile.Close()
+	if writeErr != nil {
+		session.Close()
+		return nil, fmt.Errorf("failed to write MCP config: %w", writeErr)
+	}
+
+	return session, nil
+}
+
+// launch starts a server process in workDir and waits until its URL accepts
+// connections
+func (s *MCPSession) launch(name, workDir string, launch mcpLaunch, server map[string]interface{}) error {
+	cmd := exec.Command(launch.Command, launch.Args...)
+	cmd.Dir = workDir
+	cmd.Env = os.Environ()
+	for k, v := range launch.Env {
+		cmd.Env = append(cmd.Env, fmt.Sprintf("%s=%s", k, v))
+	}
+	if err := cmd.Start(); err != nil {
+		return fmt.Errorf("failed to start MCP server %s: %w", name, err)
+	}
+	s.processes = append(s.processes, cmd)
+
+	rawURL, _ := server["url"].(string)
+	if rawURL == "" {
+		return nil
+	}
+	u, err := url.Parse(rawURL)
+	if err != nil || u.Host == "" {
+		return fmt.Errorf("invalid url for MCP server %s: %s", name, rawURL)
+	}
+	host := u.Host
+	if u.Port() == "" {
+		if u.Scheme == "https" {
+			host = net.JoinHostPort(u.Hostname(), "443")
+		} else {
+			host = net.JoinHostPort(u.Hostname(), "80")
+		}
+	}
+
+	timeout := defaultMCPReadyTimeout
+	if launch.ReadyTimeoutSeconds > 0 {
+		timeout = time.Duration(launch.ReadyTimeoutSeconds) * time.Second
+	}
+	deadline := time.Now().Add(timeout)
+	for {
+		conn, err := net.DialTimeout("tcp", host, time.Second)
+		if err == nil {
+			conn.Close()
+			return nil
+		}
+		if time.Now().After(deadline) {
+			return fmt.Errorf("MCP server %s did not become ready at %s within %v", name, rawURL, timeout)
+		}
+		time.Sleep(200 * time.Millisecond)
+	}
+}
+
+// ClaudeArgs returns the claude flags that load this session's MCP config
+func (s *MCPSession) ClaudeArgs() []string {
+	if s == nil || s.ConfigPath == "" {
+		return nil
+	}
+	return []string{"--mcp-config", s.ConfigPath, "--strict-mcp-config"}
+}
+
+// Close stops launched servers and removes the generated config file
+func (s *MCPSession) Close() {
+	if s == nil {
+		return
+	}
+	for _, cmd := range s.processes {
+		if cmd.Process != nil {
+			_ = cmd.Process.Kill()
+			_ = cmd.Wait()
+		}
+	}
+	s.processes = nil
+	if s.ConfigPath != "" {
+		_ = os.Remove(s.ConfigPath)
+		s.ConfigPath = ""
+	}
+}
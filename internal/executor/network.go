@@ -0,0 +1,188 @@
+package executor
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"os/exec"
+	"runtime"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/kindship-ai/kindship-cli/internal/boundaries"
+)
+
+// proxyEnvKeys are the variables pointed at the egress proxy in allowlist mode
+var proxyEnvKeys = []string{"HTTP_PROXY", "HTTPS_PROXY", "ALL_PROXY", "http_proxy", "https_proxy", "all_proxy"}
+
+// networkSession applies an entity's network policy to a single execution.
+//
+// Mode "none" runs the command in a fresh network namespace via
+// 'unshare --net' (Linux only), so no traffic can leave the process tree.
+// Mode "allowlist" starts a local HTTP/CONNECT proxy that only forwards to
+// allowlisted hosts and points the proxy environment variables at it; this
+// covers proxy-aware tools (curl, pip, requests) but not raw sockets.
+type networkSession struct {
+	proxy   *http.Server
+	mu      sync.Mutex
+	blocked []string
+}
+
+// prepareNetwork rewrites argv and env according to policy. The returned
+// session must be closed once the command exits.
+func prepareNetwork(policy *boundaries.NetworkPolicy, argv, env []string) ([]string, []string, *networkSession, error) {
+	session := &networkSession{}
+	if !policy.Restricted() {
+		return argv, env, session, nil
+	}
+
+	switch policy.Mode {
+	case boundaries.NetworkModeNone:
+		if runtime.GOOS != "linux" {
+			return nil, nil, nil, fmt.Errorf("network=none requires Linux network namespaces (running on %s)", runtime.GOOS)
+		}
+		unshare, err := exec.LookPath("unshare")
+		if err != nil {
+			return nil, nil, nil, fmt.Errorf("network=none requires 'unshare' in PATH: %w", err)
+		}
+		wrapped := append([]string{unshare, "--net", "--map-root-user", "--"}, argv...)
+		return wrapped, env, session, nil
+
+	case boundaries.NetworkModeAllowlist:
+		listener, err := net.Listen("tcp", "127.0.0.1:0")
+		if err != nil {
+			return nil, nil, nil, fmt.Errorf("failed to start egress proxy: %w", err)
+		}
+		session.proxy = &http.Server{Handler: &egressProxy{policy: policy, session: session}}
+		go session.proxy.Serve(listener)
+
+		proxyURL := "http://" + listener.Addr().String()
+		filtered := make([]string, 0, len(env)+len(proxyEnvKeys))
+		for _, kv := range env {
+			key := kv
+			if idx := strings.Index(kv, "="); idx >= 0 {
+				key = kv[:idx]
+			}
+			if isProxyEnvKey(key) {
+				continue
+			}
+			filtered = append(filtered, kv)
+		}
+		for _, key := range proxyEnvKeys {
+			filtered = append(filtered, fmt.Sprintf("%s=%s", key, proxyURL))
+		}
+		return argv, filtered, session, nil
+	}
+
+	return argv, env, session, nil
+}
+
+func isProxyEnvKey(key string) bool {
+	switch strings.ToUpper(key) {
+	case "HTTP_PROXY", "HTTPS_PROXY", "ALL_PROXY", "NO_PROXY":
+		return true
+	}
+	return false
+}
+
+// Blocked returns the hosts the proxy refused during the session
+func (s *networkSession) Blocked() []string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]string(nil), s.blocked...)
+}
+
+// Close stops the egress proxy, if one was started
+func (s *networkSession) Close() {
+	if s == nil || s.proxy == nil {
+		return
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+	defer cancel()
+	_ = s.proxy.Shutdown(ctx)
+}
+
+// annotate appends a note about blocked hosts to stderr so failures caused
+// by the policy are obvious in the run output
+func (s *networkSession) annotate(stderr string) string {
+	blocked := s.Blocked()
+	if len(blocked) == 0 {
+		return stderr
+	}
+	return stderr + fmt.Sprintf("\n[kindship] network egress blocked by boundaries: %s\n", strings.Join(blocked, ", "))
+}
+
+// egressProxy forwards HTTP and CONNECT requests to allowlisted hosts only
+type egressProxy struct {
+	policy  *boundaries.NetworkPolicy
+	session *networkSession
+}
+
+func (p *egressProxy) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	host := r.URL.Hostname()
+	if r.Method == http.MethodConnect {
+		host, _, _ = net.SplitHostPort(r.Host)
+	}
+	if !p.policy.AllowsHost(host) {
+		p.session.mu.Lock()
+		p.session.blocked = append(p.session.blocked, host)
+		p.session.mu.Unlock()
+		http.Error(w, fmt.Sprintf("egress to %s blocked by kindship network policy", host), http.StatusForbidden)
+		return
+	}
+
+	if r.Method == http.MethodConnect {
+		p.tunnel(w, r)
+		return
+	}
+
+	r.RequestURI = ""
+	resp, err := http.DefaultTransport.RoundTrip(r)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadGateway)
+		return
+	}
+	defer resp.Body.Close()
+	for k, values := range resp.Header {
+		for _, v := range values {
+			w.Header().Add(k, v)
+		}
+	}
+	w.WriteHeader(resp.StatusCode)
+	_, _ = io.Copy(w, resp.Body)
+}
+
+// tunnel relays a CONNECT request (HTTPS) between client and upstream
+func (p *egressProxy) tunnel(w http.ResponseWriter, r *http.Request) {
+	upstream, err := net.DialTimeout("tcp", r.Host, 30*time.Second)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadGateway)
+		return
+	}
+	hijacker, ok := w.(http.Hijacker)
+	if !ok {
+		upstream.Close()
+		http.Error(w, "proxy does not support hijacking", http.StatusInternalServerError)
+		return
+	}
+	client, _, err := hijacker.Hijack()
+	if err != nil {
+		upstream.Close()
+		return
+	}
+	_, _ = client.Write([]byte("HTTP/1.1 200 Connection Established\r\n\r\n"))
+
+	go func() {
+		defer upstream.Close()
+		defer client.Close()
+		_, _ = io.Copy(upstream, client)
+	}()
+	go func() {
+		defer upstream.Close()
+		defer client.Close()
+		_, _ = io.Copy(client, upstream)
+	}()
+}
@@ -0,0 +1,61 @@
+package executor
+
+import (
+	"context"
+
+	"github.com/kindship-ai/kindship-cli/internal/boundaries"
+)
+
+// DefaultWorkDir is the directory executions run in inside agent containers
+// when Options.WorkDir is not set
+const DefaultWorkDir = "/workspace"
+
+// Options are the agent-wide execution settings of one invocation, built
+// from command flags and the repo config. Entity boundaries override them
+// per entity.
+type Options struct {
+	// WorkDir is the directory executions run in (DefaultWorkDir if empty)
+	WorkDir string
+	// Shell is the shell for BASH and TEST executions; nil means the
+	// platform shell
+	Shell *boundaries.ShellPolicy
+	// LLM holds the LLM permission settings
+	LLM *boundaries.LLMPolicy
+	// RepoContext controls the repository context added to LLM prompts
+	RepoContext RepoContextOptions
+	// Follow streams executions' stdout and stderr to the terminal as they
+	// are produced ('kindship run --follow'), in addition to capturing them
+	// for the API
+	Follow bool
+}
+
+// DefaultOptions returns the settings used when a command sets none
+func DefaultOptions() Options {
+	return Options{
+		WorkDir:     DefaultWorkDir,
+		RepoContext: RepoContextOptions{Enabled: true, Files: DefaultRepoContextFiles},
+	}
+}
+
+// Dir returns the directory executions run in
+func (o Options) Dir() string {
+	if o.WorkDir == "" {
+		return DefaultWorkDir
+	}
+	return o.WorkDir
+}
+
+type optionsKey struct{}
+
+// WithOptions returns a context whose executions use opts
+func WithOptions(ctx context.Context, opts Options) context.Context {
+	return context.WithValue(ctx, optionsKey{}, opts)
+}
+
+// optionsFrom returns the context's options, or DefaultOptions if none
+func optionsFrom(ctx context.Context) Options {
+	if opts, ok := ctx.Value(optionsKey{}).(Options); ok {
+		return opts
+	}
+	return DefaultOptions()
+}
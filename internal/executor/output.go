@@ -0,0 +1,46 @@
+package executor
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"os"
+)
+
+// readOutputFile returns the contents of a structured output file, or nil if
+// the script did not write to it. A file larger than maxOutputBytes is an
+// error: a truncated file would not parse as JSON.
+func readOutputFile(path string) ([]byte, error) {
+	if path == "" {
+		return nil, nil
+	}
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, nil
+	}
+	defer f.Close()
+	data, err := io.ReadAll(io.LimitReader(f, maxOutputBytes+1))
+	if err != nil || len(bytes.TrimSpace(data)) == 0 {
+		return nil, nil
+	}
+	if len(data) > maxOutputBytes {
+		return nil, fmt.Errorf("output too large: OUTPUT_FILE exceeds %d bytes", maxOutputBytes)
+	}
+	return data, nil
+}
+
+// attachOutputFile sets result's OutputFile from the file at path. An
+// oversized file fails an otherwise successful execution.
+func attachOutputFile(result *ExecutionResult, path string) *ExecutionResult {
+	data, err := readOutputFile(path)
+	if err != nil {
+		if result.Success {
+			result.Success = false
+			result.ExitCode = 1
+			result.Error = err
+		}
+		return result
+	}
+	result.OutputFile = data
+	return result
+}
@@ -0,0 +1,243 @@
+package executor
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/exec"
+	"regexp"
+	"strings"
+
+	"github.com/kindship-ai/kindship-cli/internal/api"
+	"github.com/kindship-ai/kindship-cli/internal/boundaries"
+)
+
+// Executor plugins handle execution modes the CLI does not implement itself.
+// An entity with execution_mode "JULIA_NOTEBOOK" is dispatched to the
+// executable "kindship-executor-julia-notebook" found on PATH.
+//
+// The plugin is started in the execution work directory (or the entity's
+// boundaries.workdir under it) with the same environment as BASH
+// executions (including INPUT_* variables) and is subject to the entity's
+// network boundaries and DefaultExecTimeout. It receives a single
+// PluginRequest as JSON on stdin and must write a single PluginResponse as
+// JSON to stdout before exiting. Anything written to stderr is kept as
+// diagnostic output.
+//
+//	stdin:  {"protocol_version": 1, "entity": {...}, "inputs": {...}, "work_dir": "/workspace"}
+//	stdout: {"success": true, "exit_code": 0, "stdout": "...", "stderr": "...",
+//	         "error": "", "structured_output": {...}, "cost_usd": 0.12}
+//
+// A plugin that exits non-zero without a valid response fails the execution
+// with its exit code.
+const pluginPrefix = "kindship-executor-"
+
+// PluginProtocolVersion is sent to plugins so they can reject requests they
+// do not understand
+const PluginProtocolVersion = 1
+
+// PluginRequest is written to a plugin's stdin
+type PluginRequest struct {
+	ProtocolVersion int                    `json:"protocol_version"`
+	Entity          *api.PlanningEntity    `json:"entity"`
+	Inputs          map[string]interface{} `json:"inputs"`
+	WorkDir         string                 `json:"work_dir"`
+}
+
+// PluginResponse is read from a plugin's stdout
+type PluginResponse struct {
+	Success          bool                   `json:"success"`
+	ExitCode         int                    `json:"exit_code"`
+	Stdout           string                 `json:"stdout"`
+	Stderr           string                 `json:"stderr"`
+	Error            string                 `json:"error,omitempty"`
+	StructuredOutput map[string]interface{} `json:"structured_output,omitempty"`
+	CostUSD          float64                `json:"cost_usd,omitempty"`
+}
+
+var pluginModePattern = regexp.MustCompile(`^[A-Za-z0-9_-]+$`)
+
+// PluginBinary returns the executable name that handles mode, or "" if mode
+// cannot name a plugin
+func PluginBinary(mode api.ExecutionMode) string {
+	if !pluginModePattern.MatchString(string(mode)) {
+		return ""
+	}
+	return pluginPrefix + strings.ToLower(strings.ReplaceAll(string(mode), "_", "-"))
+}
+
+// ExecutePlugin runs an entity through the plugin registered for its mode
+func ExecutePlugin(entity *api.PlanningEntity, inputs map[string]interface{}) *ExecutionResult {
+	return ExecutePluginWithContext(context.Background(), entity, inputs)
+}
+
+// ExecutePluginWithContext runs an entity through its mode's plugin with
+// context for cancellation/timeout.
+func ExecutePluginWithContext(ctx context.Context, entity *api.PlanningEntity, inputs map[string]interface{}) *ExecutionResult {
+	binary := PluginBinary(entity.ExecutionMode)
+	if binary == "" {
+		return &ExecutionResult{
+			Success:  false,
+			ExitCode: 1,
+			Error:    fmt.Errorf("unknown execution mode: %s", entity.ExecutionMode),
+		}
+	}
+	path, err := exec.LookPath(binary)
+	if err != nil {
+		return &ExecutionResult{
+			Success:  false,
+			ExitCode: 1,
+			Error:    fmt.Errorf("unknown execution mode %s: no %s plugin found in PATH", entity.ExecutionMode, binary),
+		}
+	}
+
+	policy, err := boundaries.Parse(entity.Boundaries)
+	if err != nil {
+		return &ExecutionResult{
+			Success:  false,
+			ExitCode: 1,
+			Error:    err,
+		}
+	}
+	workDir, err := policy.ResolveWorkDir(optionsFrom(ctx).Dir())
+	if err != nil {
+		return &ExecutionResult{
+			Success:  false,
+			ExitCode: 1,
+			Error:    err,
+		}
+	}
+
+	files, err := newRunFiles()
+	if err != nil {
+		return &ExecutionResult{
+			Success:  false,
+			ExitCode: 1,
+			Error:    fmt.Errorf("failed to create run files: %w", err),
+		}
+	}
+	defer files.Close()
+
+	request, err := json.Marshal(PluginRequest{
+		ProtocolVersion: PluginProtocolVersion,
+		Entity:          entity,
+		Inputs:          inputs,
+		WorkDir:         workDir,
+	})
+	if err != nil {
+		return &ExecutionResult{
+			Success:  false,
+			ExitCode: 1,
+			Error:    fmt.Errorf("failed to encode plugin request: %w", err),
+		}
+	}
+
+	execCtx, cancel := context.WithTimeout(ctx, DefaultExecTimeout)
+	defer cancel()
+
+	argv, env, network, err := prepareNetwork(policy.Network, []string{path}, withSecretEnv(ctx, policy.Env.Append(buildEnvWithInputs(files, inputs))))
+	if err != nil {
+		return &ExecutionResult{
+			Success:  false,
+			ExitCode: 1,
+			Error:    err,
+		}
+	}
+	defer network.Close()
+
+	cmd := exec.CommandContext(execCtx, argv[0], argv[1:]...)
+	cmd.Dir = workDir
+	cmd.Env = env
+	cmd.Stdin = bytes.NewReader(request)
+	setProcessGroup(cmd)
+
+	var stdout, stderr bytes.Buffer
+	stdoutW := &limitedWriter{buf: &stdout, limit: maxOutputBytes}
+	stderrW := &limitedWriter{buf: &stderr, limit: maxOutputBytes}
+	cmd.Stdout = stdoutW
+	// stdout is the JSON response; only the plugin's diagnostics are followed
+	cmd.Stderr = followed(ctx, stderrW, os.Stderr)
+
+	err = runProcessGroup(execCtx, cmd)
+	usage := processUsage(cmd, stdoutW, stderrW)
+	if err != nil && execCtx.Err() == context.DeadlineExceeded {
+		return &ExecutionResult{
+			Success:  false,
+			Stderr:   network.annotate(stderr.String()),
+			ExitCode: 124,
+			Error:    fmt.Errorf("execution timed out after %v", DefaultExecTimeout),
+			TimedOut: true,
+			Usage:    usage,
+		}
+	}
+	if err != nil && ctx.Err() == context.Canceled {
+		return &ExecutionResult{
+			Success:   false,
+			Stderr:    network.annotate(stderr.String()),
+			ExitCode:  130,
+			Error:     fmt.Errorf("execution cancelled"),
+			Cancelled: true,
+			Usage:     usage,
+		}
+	}
+	exitCode := 0
+	if err != nil {
+		if exitError, ok := err.(*exec.ExitError); ok {
+			exitCode = exitError.ExitCode()
+		} else {
+			exitCode = 1
+		}
+	}
+
+	var resp PluginResponse
+	if decodeErr := json.Unmarshal(bytes.TrimSpace(stdout.Bytes()), &resp); decodeErr != nil {
+		if err == nil {
+			err = fmt.Errorf("invalid response from %s: %w", binary, decodeErr)
+			exitCode = 1
+		}
+		return &ExecutionResult{
+			Success:  false,
+			Stdout:   stdout.String(),
+			Stderr:   network.annotate(stderr.String()),
+			ExitCode: exitCode,
+			Error:    err,
+			Usage:    usage,
+		}
+	}
+
+	// The plugin's own diagnostics follow the stderr it reports
+	combinedStderr := resp.Stderr
+	if extra := strings.TrimSpace(stderr.String()); extra != "" {
+		if combinedStderr != "" {
+			combinedStderr += "\n"
+		}
+		combinedStderr += extra
+	}
+
+	result := &ExecutionResult{
+		Success:  resp.Success && exitCode == 0,
+		Stdout:   resp.Stdout,
+		Stderr:   network.annotate(combinedStderr),
+		ExitCode: resp.ExitCode,
+		Error:    err,
+		CostUSD:  resp.CostUSD,
+		Usage:    usage,
+	}
+	if exitCode != 0 && result.ExitCode == 0 {
+		result.ExitCode = exitCode
+	}
+	if !resp.Success && result.ExitCode == 0 {
+		result.ExitCode = 1
+	}
+	if resp.Error != "" && result.Error == nil {
+		result.Error = fmt.Errorf("%s", resp.Error)
+	}
+	if resp.StructuredOutput != nil {
+		if data, marshalErr := json.Marshal(resp.StructuredOutput); marshalErr == nil {
+			result.OutputFile = data
+		}
+	}
+	return result
+}
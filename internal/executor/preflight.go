@@ -0,0 +1,84 @@
+package executor
+
+import (
+	"fmt"
+	"os/exec"
+	"strings"
+
+	"github.com/kindship-ai/kindship-cli/internal/api"
+	"github.com/kindship-ai/kindship-cli/internal/boundaries"
+)
+
+// RequiredRuntimes returns the executables that must be on PATH to run an
+// entity in the given execution mode under its boundaries policy (nil for
+// the agent defaults in opts). LLM modes shell out through 'kindship auth
+// <backend>', so kindship and the LLM backends of boundaries.llm are listed
+// (any one backend suffices); BASH and TEST need the boundaries.shell
+// interpreter; modes the CLI does not implement need their executor plugin
+// unless an executor is registered for them.
+func RequiredRuntimes(mode api.ExecutionMode, policy *boundaries.Policy, opts Options) []string {
+	var llm *boundaries.LLMPolicy
+	var shell *boundaries.ShellPolicy
+	if policy != nil {
+		llm, shell = policy.LLM, policy.Shell
+	}
+	switch mode {
+	case api.ExecutionModeLLMReasoning, api.ExecutionModeHybrid:
+		return append([]string{"kindship"}, opts.LLM.Merge(llm).BackendChain()...)
+	case api.ExecutionModeBash, api.ExecutionModeTest:
+		if shell := opts.Shell.Merge(shell); shell.Interpreter != "" {
+			return []string{shell.Interpreter}
+		}
+		return []string{ShellRuntime()}
+	case api.ExecutionModePython, api.ExecutionModePythonSandbox:
+		return []string{PythonRuntime}
+	case api.ExecutionModeAskUser, api.ExecutionModeOrchestrate:
+		return nil
+	default:
+		// Executors registered in-process bring their own runtime; any
+		// other mode is handled by an executor plugin
+		if _, ok := Lookup(mode); ok {
+			return nil
+		}
+		if binary := PluginBinary(mode); binary != "" {
+			return []string{binary}
+		}
+		return nil
+	}
+}
+
+// Preflight verifies that every runtime required by mode, policy and opts
+// is available. The returned error names all missing executables so the
+// failure reason is actionable without digging through stderr.
+func Preflight(mode api.ExecutionMode, policy *boundaries.Policy, opts Options) error {
+	runtimes := RequiredRuntimes(mode, policy, opts)
+	var alternatives []string
+	if mode == api.ExecutionModeLLMReasoning || mode == api.ExecutionModeHybrid {
+		// LLM backends are alternatives: one installed backend is enough
+		runtimes, alternatives = runtimes[:1], runtimes[1:]
+	}
+
+	var missing []string
+	for _, name := range runtimes {
+		if _, err := exec.LookPath(name); err != nil {
+			missing = append(missing, name)
+		}
+	}
+	if len(alternatives) > 0 && !anyOnPath(alternatives) {
+		missing = append(missing, strings.Join(alternatives, " or "))
+	}
+	if len(missing) > 0 {
+		return fmt.Errorf("required runtime not found in PATH for %s execution: %s", mode, strings.Join(missing, ", "))
+	}
+	return nil
+}
+
+// anyOnPath reports whether at least one of names is an executable on PATH
+func anyOnPath(names []string) bool {
+	for _, name := range names {
+		if _, err := exec.LookPath(name); err == nil {
+			return true
+		}
+	}
+	return false
+}
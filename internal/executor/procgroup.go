@@ -0,0 +1,79 @@
+package executor
+
+import (
+	"context"
+	"errors"
+	"os/exec"
+	"sync"
+	"time"
+)
+
+// processGroupWaitDelay is the grace period a cancelled command gets between
+// SIGTERM and SIGKILL. It also bounds how long Wait keeps reading output
+// after the command exits while leftover processes hold its pipes open.
+const processGroupWaitDelay = 10 * time.Second
+
+// runProcessGroup runs a command prepared with setProcessGroup. On Unix,
+// processes the command left behind (e.g. background servers started with
+// &) or that ignored SIGTERM are killed once it exits rather than keeping
+// the execution open until the timeout. While it runs, the group can be
+// force-killed through the kill switch of ctx (see WithKillSwitch).
+func runProcessGroup(ctx context.Context, cmd *exec.Cmd) error {
+	if err := cmd.Start(); err != nil {
+		return err
+	}
+	release := holdProcessGroup(ctx, cmd)
+	err := cmd.Wait()
+	release()
+	killLeftovers(cmd)
+	// The command itself succeeded; only its leftovers held the pipes open
+	if errors.Is(err, exec.ErrWaitDelay) {
+		return nil
+	}
+	return err
+}
+
+// killSwitch tracks the process groups started under one context
+type killSwitch struct {
+	mu     sync.Mutex
+	cmds   map[*exec.Cmd]struct{}
+	killed bool
+}
+
+type killSwitchKey struct{}
+
+// WithKillSwitch returns a context whose executions can be force-killed:
+// kill sends SIGKILL to every process group started under the context
+// without the SIGTERM grace period, and to any started afterwards.
+func WithKillSwitch(ctx context.Context) (context.Context, func()) {
+	ks := &killSwitch{cmds: make(map[*exec.Cmd]struct{})}
+	kill := func() {
+		ks.mu.Lock()
+		defer ks.mu.Unlock()
+		ks.killed = true
+		for cmd := range ks.cmds {
+			killProcessGroup(cmd)
+		}
+	}
+	return context.WithValue(ctx, killSwitchKey{}, ks), kill
+}
+
+// holdProcessGroup registers a started cmd with the kill switch of ctx, if
+// any, and returns a function that unregisters it
+func holdProcessGroup(ctx context.Context, cmd *exec.Cmd) func() {
+	ks, ok := ctx.Value(killSwitchKey{}).(*killSwitch)
+	if !ok {
+		return func() {}
+	}
+	ks.mu.Lock()
+	defer ks.mu.Unlock()
+	if ks.killed {
+		killProcessGroup(cmd)
+	}
+	ks.cmds[cmd] = struct{}{}
+	return func() {
+		ks.mu.Lock()
+		defer ks.mu.Unlock()
+		delete(ks.cmds, cmd)
+	}
+}
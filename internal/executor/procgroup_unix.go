@@ -0,0 +1,43 @@
+//go:build !windows
+
+package executor
+
+import (
+	"os/exec"
+	"syscall"
+)
+
+// setProcessGroup starts cmd in its own process group and makes context
+// cancellation terminate the whole group, so children spawned by the script
+// (background jobs, subshells, test runners) do not outlive it. The group
+// gets SIGTERM and, after processGroupWaitDelay, SIGKILL.
+func setProcessGroup(cmd *exec.Cmd) {
+	cmd.SysProcAttr = &syscall.SysProcAttr{Setpgid: true}
+	cmd.Cancel = func() error {
+		err := syscall.Kill(-cmd.Process.Pid, syscall.SIGTERM)
+		if err == syscall.ESRCH {
+			return nil
+		}
+		return err
+	}
+	cmd.WaitDelay = processGroupWaitDelay
+}
+
+// killProcessGroup kills every process left in cmd's process group
+func killProcessGroup(cmd *exec.Cmd) error {
+	if cmd.Process == nil {
+		return nil
+	}
+	err := syscall.Kill(-cmd.Process.Pid, syscall.SIGKILL)
+	if err == syscall.ESRCH {
+		return nil
+	}
+	return err
+}
+
+// killLeftovers kills the processes cmd left in its process group after
+// it exited. The group ID cannot be reused while any of them is alive, so
+// signalling it after Wait never reaches an unrelated process.
+func killLeftovers(cmd *exec.Cmd) {
+	_ = killProcessGroup(cmd)
+}
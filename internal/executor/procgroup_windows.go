@@ -0,0 +1,36 @@
+//go:build windows
+
+package executor
+
+import (
+	"os/exec"
+	"strconv"
+)
+
+// setProcessGroup makes context cancellation kill cmd's whole process
+// tree, so children spawned by the script do not outlive it. There is no
+// SIGTERM to send first, so the tree is killed without a grace period.
+func setProcessGroup(cmd *exec.Cmd) {
+	cmd.Cancel = func() error {
+		return killProcessGroup(cmd)
+	}
+	cmd.WaitDelay = processGroupWaitDelay
+}
+
+// killProcessGroup kills cmd and its descendants. Windows has no process
+// groups to signal, so this relies on taskkill walking the tree, which is
+// only possible while cmd itself is still running.
+func killProcessGroup(cmd *exec.Cmd) error {
+	if cmd.Process == nil {
+		return nil
+	}
+	if err := exec.Command("taskkill", "/T", "/F", "/PID", strconv.Itoa(cmd.Process.Pid)).Run(); err != nil {
+		return cmd.Process.Kill()
+	}
+	return nil
+}
+
+// killLeftovers does nothing on Windows: once cmd has exited its tree can
+// no longer be walked, and its PID may already belong to another process.
+// The tree is only killed on cancellation, while cmd is still running.
+func killLeftovers(cmd *exec.Cmd) {}
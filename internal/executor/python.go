@@ -4,9 +4,11 @@ import (
 	"bytes"
 	"context"
 	"fmt"
+	"os"
 	"os/exec"
 
 	"github.com/kindship-ai/kindship-cli/internal/api"
+	"github.com/kindship-ai/kindship-cli/internal/boundaries"
 )
 
 // ExecutePython runs Python code from entity.Code
@@ -27,24 +29,103 @@ func ExecutePythonWithContext(ctx context.Context, entity *api.PlanningEntity, i
 	execCtx, cancel := context.WithTimeout(ctx, DefaultExecTimeout)
 	defer cancel()
 
-	cmd := exec.CommandContext(execCtx, "python3", "-c", *entity.Code)
-	cmd.Dir = "/workspace"
-	cmd.Env = buildEnvWithInputs(inputs)
+	policy, err := boundaries.Parse(entity.Boundaries)
+	if err != nil {
+		return &ExecutionResult{
+			Success:  false,
+			ExitCode: 1,
+			Error:    err,
+		}
+	}
+	files, err := newRunFiles()
+	if err != nil {
+		return &ExecutionResult{
+			Success:  false,
+			ExitCode: 1,
+			Error:    fmt.Errorf("failed to create run files: %w", err),
+		}
+	}
+	defer files.Close()
+	outputPath, err := files.outputFile()
+	if err != nil {
+		return &ExecutionResult{
+			Success:  false,
+			ExitCode: 1,
+			Error:    fmt.Errorf("failed to create output file: %w", err),
+		}
+	}
+
+	workDir, err := policy.ResolveWorkDir(optionsFrom(ctx).Dir())
+	if err != nil {
+		return &ExecutionResult{
+			Success:  false,
+			ExitCode: 1,
+			Error:    err,
+		}
+	}
+
+	env := append(withSecretEnv(ctx, policy.Env.Append(buildEnvWithInputs(files, inputs))), "OUTPUT_FILE="+outputPath)
+	argv, env, network, err := prepareNetwork(policy.Network, []string{PythonRuntime, "-c", *entity.Code}, env)
+	if err != nil {
+		return &ExecutionResult{
+			Success:  false,
+			ExitCode: 1,
+			Error:    err,
+		}
+	}
+	defer network.Close()
+
+	stdin, err := stdinInputs(policy, inputs)
+	if err != nil {
+		return &ExecutionResult{
+			Success:  false,
+			ExitCode: 1,
+			Error:    err,
+		}
+	}
+
+	if IsDryRun(ctx) {
+		return dryRunResult(entity.ExecutionMode, workDir, argv, env, stdin, pythonSyntaxArgs, *entity.Code)
+	}
+
+	cmd := exec.CommandContext(execCtx, argv[0], argv[1:]...)
+	cmd.Dir = workDir
+	cmd.Env = env
+	if stdin != nil {
+		cmd.Stdin = bytes.NewReader(stdin)
+	}
+	setProcessGroup(cmd)
 
 	var stdout, stderr bytes.Buffer
-	cmd.Stdout = &limitedWriter{buf: &stdout, limit: maxOutputBytes}
-	cmd.Stderr = &limitedWriter{buf: &stderr, limit: maxOutputBytes}
+	stdoutW := &limitedWriter{buf: &stdout, limit: maxOutputBytes}
+	stderrW := &limitedWriter{buf: &stderr, limit: maxOutputBytes}
+	cmd.Stdout = followed(ctx, stdoutW, os.Stdout)
+	cmd.Stderr = followed(ctx, stderrW, os.Stderr)
 
-	err := cmd.Run()
+	err = runProcessGroup(execCtx, cmd)
+	usage := processUsage(cmd, stdoutW, stderrW)
 	exitCode := 0
 	if err != nil {
 		if execCtx.Err() == context.DeadlineExceeded {
 			return &ExecutionResult{
 				Success:  false,
 				Stdout:   stdout.String(),
-				Stderr:   stderr.String(),
+				Stderr:   network.annotate(stderr.String()),
 				ExitCode: 124,
 				Error:    fmt.Errorf("execution timed out after %v", DefaultExecTimeout),
+				TimedOut: true,
+				Usage:    usage,
+			}
+		}
+		if ctx.Err() == context.Canceled {
+			return &ExecutionResult{
+				Success:   false,
+				Stdout:    stdout.String(),
+				Stderr:    network.annotate(stderr.String()),
+				ExitCode:  130,
+				Error:     fmt.Errorf("execution cancelled"),
+				Cancelled: true,
+				Usage:     usage,
 			}
 		}
 		if exitError, ok := err.(*exec.ExitError); ok {
@@ -54,11 +135,12 @@ func ExecutePythonWithContext(ctx context.Context, entity *api.PlanningEntity, i
 		}
 	}
 
-	return &ExecutionResult{
+	return attachOutputFile(&ExecutionResult{
 		Success:  exitCode == 0,
 		Stdout:   stdout.String(),
-		Stderr:   stderr.String(),
+		Stderr:   network.annotate(stderr.String()),
 		ExitCode: exitCode,
 		Error:    err,
-	}
+		Usage:    usage,
+	}, outputPath)
 }
@@ -0,0 +1,57 @@
+package executor
+
+import (
+	"context"
+	"sync"
+
+	"github.com/kindship-ai/kindship-cli/internal/api"
+)
+
+// Executor runs entities of one execution mode
+type Executor interface {
+	Execute(ctx context.Context, entity *api.PlanningEntity, inputs map[string]interface{}) *ExecutionResult
+}
+
+// ExecutorFunc adapts a function to the Executor interface
+type ExecutorFunc func(ctx context.Context, entity *api.PlanningEntity, inputs map[string]interface{}) *ExecutionResult
+
+// Execute calls f
+func (f ExecutorFunc) Execute(ctx context.Context, entity *api.PlanningEntity, inputs map[string]interface{}) *ExecutionResult {
+	return f(ctx, entity, inputs)
+}
+
+var (
+	registryMu sync.RWMutex
+	registry   = map[api.ExecutionMode]Executor{
+		api.ExecutionModeLLMReasoning: ExecutorFunc(ExecuteLLMWithContext),
+		api.ExecutionModeBash:         ExecutorFunc(ExecuteBashWithContext),
+		api.ExecutionModeTest:         ExecutorFunc(ExecuteTestsWithContext),
+		api.ExecutionModePython:       ExecutorFunc(ExecutePythonWithContext),
+		// Legacy mode — treat as PYTHON
+		api.ExecutionModePythonSandbox: ExecutorFunc(ExecutePythonWithContext),
+		// HYBRID uses LLM with entity context + code as reference
+		api.ExecutionModeHybrid: ExecutorFunc(ExecuteLLMWithContext),
+	}
+)
+
+// Register makes e the executor for mode, replacing any executor already
+// registered for it, built-in ones included. Registering nil removes the
+// mode, so its entities go to an executor plugin again.
+func Register(mode api.ExecutionMode, e Executor) {
+	registryMu.Lock()
+	defer registryMu.Unlock()
+	if e == nil {
+		delete(registry, mode)
+		return
+	}
+	registry[mode] = e
+}
+
+// Lookup returns the executor registered for mode. Modes without one are
+// handled by an executor plugin (see ExecutePluginWithContext).
+func Lookup(mode api.ExecutionMode) (Executor, bool) {
+	registryMu.RLock()
+	defer registryMu.RUnlock()
+	e, ok := registry[mode]
+	return e, ok
+}
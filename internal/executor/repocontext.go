@@ -0,0 +1,76 @@
+package executor
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/kindship-ai/kindship-cli/internal/config"
+)
+
+// DefaultRepoContextFiles are the convention files included in LLM prompts
+var DefaultRepoContextFiles = []string{"AGENTS.md", "CLAUDE.md"}
+
+// maxRepoContextFileBytes caps how much of each convention file is included
+const maxRepoContextFileBytes = 16 * 1024
+
+// maxRepoContextDirtyFiles caps the uncommitted-file list in the prompt
+const maxRepoContextDirtyFiles = 50
+
+// RepoContextOptions controls the repository context added to LLM prompts
+type RepoContextOptions struct {
+	Enabled bool
+	// Files are convention files, relative to the repository root
+	Files []string
+}
+
+// buildRepoContext describes the repository bound in dir: the contents of
+// its convention files, the current branch and uncommitted files. Returns
+// "" when disabled or when dir is not bound to an agent with 'kindship setup'.
+func buildRepoContext(dir string, opts RepoContextOptions) string {
+	if !opts.Enabled {
+		return ""
+	}
+	configDir, err := config.FindRepoConfigDir(dir)
+	if err != nil {
+		return ""
+	}
+	root := filepath.Dir(configDir)
+
+	var b strings.Builder
+	for _, name := range opts.Files {
+		data, err := os.ReadFile(filepath.Join(root, name))
+		if err != nil || len(strings.TrimSpace(string(data))) == 0 {
+			continue
+		}
+		content := string(data)
+		if len(content) > maxRepoContextFileBytes {
+			content = content[:maxRepoContextFileBytes] + "\n[... truncated]"
+		}
+		b.WriteString(fmt.Sprintf("### %s\n", name))
+		b.WriteString(strings.TrimSpace(content))
+		b.WriteString("\n\n")
+	}
+
+	if branch, err := git(dir, "rev-parse", "--abbrev-ref", "HEAD"); err == nil {
+		b.WriteString(fmt.Sprintf("Current branch: %s\n", strings.TrimSpace(branch)))
+	}
+	if status, err := git(dir, "status", "--porcelain"); err == nil {
+		lines := strings.Split(strings.TrimRight(status, "\n"), "\n")
+		if len(lines) == 1 && lines[0] == "" {
+			b.WriteString("Working tree: clean\n")
+		} else {
+			b.WriteString("Uncommitted files:\n")
+			for i, line := range lines {
+				if i == maxRepoContextDirtyFiles {
+					b.WriteString(fmt.Sprintf("- ... and %d more\n", len(lines)-i))
+					break
+				}
+				b.WriteString(fmt.Sprintf("- %s\n", strings.TrimSpace(line)))
+			}
+		}
+	}
+
+	return strings.TrimSpace(b.String())
+}
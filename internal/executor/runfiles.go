@@ -0,0 +1,121 @@
+package executor
+
+import (
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+)
+
+// runDirPattern names the private directory each execution gets under the
+// system temp dir for the files it is given: INPUT_<LABEL>_FILE inputs
+// and its OUTPUT_FILE. BASH's echo interprets \n escape sequences,
+// corrupting JSON; file-based access avoids this.
+const runDirPattern = "kindship-run-*"
+
+// LeftoverRunDirs returns the run directories of this user that were not
+// removed, e.g. because the CLI was killed mid-execution
+func LeftoverRunDirs() []string {
+	matches, _ := filepath.Glob(filepath.Join(os.TempDir(), runDirPattern))
+	var dirs []string
+	for _, dir := range matches {
+		if info, err := os.Lstat(dir); err == nil && info.IsDir() && ownedByUser(info) {
+			dirs = append(dirs, dir)
+		}
+	}
+	return dirs
+}
+
+// runFiles is the directory of one execution's input and output files.
+// It and its files are only accessible to the CLI's user, and Close
+// shreds them, so one run's inputs and outputs never outlive it or leak
+// to another run.
+type runFiles struct {
+	dir string
+}
+
+// newRunFiles creates the directory of an execution's files. The caller
+// must Close it. Each run gets a fresh directory with an unpredictable
+// name that only the CLI's user can access; there is no shared parent
+// another user could create first.
+func newRunFiles() (*runFiles, error) {
+	dir, err := os.MkdirTemp(os.TempDir(), runDirPattern)
+	if err != nil {
+		return nil, err
+	}
+	if err := os.Mkdir(filepath.Join(dir, "inputs"), 0700); err != nil {
+		os.RemoveAll(dir)
+		return nil, err
+	}
+	return &runFiles{dir: dir}, nil
+}
+
+// writeInput writes an input's JSON and returns its path
+func (r *runFiles) writeInput(label string, data []byte) (string, error) {
+	path := filepath.Join(r.dir, "inputs", label+".json")
+	if err := os.WriteFile(path, data, 0600); err != nil {
+		return "", err
+	}
+	return path, nil
+}
+
+// writeInputPage writes page n of the inputs that did not fit in an LLM
+// prompt and returns its path
+func (r *runFiles) writeInputPage(n int, data []byte) (string, error) {
+	path := filepath.Join(r.dir, fmt.Sprintf("input-page-%d.json", n))
+	if err := os.WriteFile(path, data, 0600); err != nil {
+		return "", err
+	}
+	return path, nil
+}
+
+// outputFile reserves the empty OUTPUT_FILE of the execution and returns
+// its path
+func (r *runFiles) outputFile() (string, error) {
+	path := filepath.Join(r.dir, "output.json")
+	if err := os.WriteFile(path, nil, 0600); err != nil {
+		return "", err
+	}
+	return path, nil
+}
+
+// Close overwrites the execution's files with zeros and removes them.
+// Symlinks the task left behind are removed, never followed.
+func (r *runFiles) Close() {
+	if r == nil {
+		return
+	}
+	_ = filepath.WalkDir(r.dir, func(path string, d fs.DirEntry, err error) error {
+		if err == nil && d.Type().IsRegular() {
+			shredFile(path)
+		}
+		return nil
+	})
+	os.RemoveAll(r.dir)
+}
+
+// shredFile overwrites a file's contents with zeros, so they do not stay
+// on disk once it is removed
+func shredFile(path string) {
+	f, err := os.OpenFile(path, os.O_WRONLY, 0)
+	if err != nil {
+		return
+	}
+	defer f.Close()
+	info, err := f.Stat()
+	if err != nil {
+		return
+	}
+	zeros := make([]byte, 32<<10)
+	for remaining := info.Size(); remaining > 0; {
+		n := int64(len(zeros))
+		if remaining < n {
+			n = remaining
+		}
+		if _, err := f.Write(zeros[:n]); err != nil {
+			return
+		}
+		remaining -= n
+	}
+	f.Sync()
+}
@@ -0,0 +1,14 @@
+//go:build !windows
+
+package executor
+
+import (
+	"os"
+	"syscall"
+)
+
+// ownedByUser reports whether the CLI's user owns the file
+func ownedByUser(info os.FileInfo) bool {
+	stat, ok := info.Sys().(*syscall.Stat_t)
+	return ok && int(stat.Uid) == os.Getuid()
+}
@@ -0,0 +1,95 @@
+package executor
+
+import (
+	"context"
+	"sort"
+	"strings"
+)
+
+// minRedactLength is the shortest secret value redacted from output;
+// shorter values would match too much unrelated text
+const minRedactLength = 4
+
+type secretsKey struct{}
+
+// WithSecrets returns a context whose BASH, TEST, PYTHON and plugin
+// executions get secrets added to their environment. Secrets only ever
+// reach the child process environment; they are never written to disk.
+func WithSecrets(ctx context.Context, secrets map[string]string) context.Context {
+	if len(secrets) == 0 {
+		return ctx
+	}
+	return context.WithValue(ctx, secretsKey{}, secrets)
+}
+
+// withSecretEnv appends the context's secrets to env as KEY=value
+func withSecretEnv(ctx context.Context, env []string) []string {
+	secrets, _ := ctx.Value(secretsKey{}).(map[string]string)
+	for key, value := range secrets {
+		env = append(env, key+"="+value)
+	}
+	return env
+}
+
+// RedactSecrets replaces secret values in everything of result that is
+// reported to the API (output, transcript, error and test failure
+// messages) with [REDACTED:<KEY>]
+func RedactSecrets(result *ExecutionResult, secrets map[string]string) {
+	if len(secrets) == 0 {
+		return
+	}
+	// Longest values first, so a secret containing another is redacted whole
+	keys := make([]string, 0, len(secrets))
+	for key, value := range secrets {
+		if len(value) >= minRedactLength {
+			keys = append(keys, key)
+		}
+	}
+	sort.Slice(keys, func(i, j int) bool { return len(secrets[keys[i]]) > len(secrets[keys[j]]) })
+	pairs := make([]string, 0, 2*len(keys))
+	for _, key := range keys {
+		pairs = append(pairs, secrets[key], "[REDACTED:"+key+"]")
+	}
+	replacer := strings.NewReplacer(pairs...)
+
+	result.Stdout = replacer.Replace(result.Stdout)
+	result.Stderr = replacer.Replace(result.Stderr)
+	if result.Transcript != nil {
+		result.Transcript = []byte(replacer.Replace(string(result.Transcript)))
+	}
+	if result.OutputFile != nil {
+		result.OutputFile = []byte(replacer.Replace(string(result.OutputFile)))
+	}
+	if result.LLM != nil {
+		result.LLM.Result = replacer.Replace(result.LLM.Result)
+	}
+	// Errors and test failures often echo command lines and env values
+	if result.Error != nil {
+		if msg := replacer.Replace(result.Error.Error()); msg != result.Error.Error() {
+			result.Error = &redactedError{msg: msg, err: result.Error}
+		}
+	}
+	if result.Tests != nil {
+		for i := range result.Tests.Failures {
+			f := &result.Tests.Failures[i]
+			f.Suite = replacer.Replace(f.Suite)
+			f.Name = replacer.Replace(f.Name)
+			f.Message = replacer.Replace(f.Message)
+		}
+	}
+}
+
+// redactedError is an error whose message had secrets redacted. It still
+// unwraps to the original so errors.Is and errors.As keep working.
+type redactedError struct {
+	msg string
+	err error
+}
+
+func (e *redactedError) Error() string {
+	return e.msg
+}
+
+func (e *redactedError) Unwrap() error {
+	return e.err
+}
@@ -0,0 +1,25 @@
+//go:build !windows
+
+package executor
+
+// PythonRuntime is the interpreter PYTHON mode executes code with
+const PythonRuntime = "python3"
+
+// maxEnvVarBytes is the longest NAME=value string exec accepts: Linux
+// rejects any single argument or environment string over 128 KiB
+// (MAX_ARG_STRLEN) with "argument list too long"
+const maxEnvVarBytes = 128*1024 - 1
+
+// maxInputEnvBytes caps all INPUT_* variables together, leaving room in
+// the ~2 MB ARG_MAX that argv and the whole environment share
+const maxInputEnvBytes = 1 << 20
+
+// ShellRuntime returns the shell BASH mode executes code with
+func ShellRuntime() string {
+	return "sh"
+}
+
+// shellCommand returns the argv that runs code in the shell
+func shellCommand(code string) []string {
+	return []string{"sh", "-c", code}
+}
@@ -0,0 +1,33 @@
+//go:build windows
+
+package executor
+
+import "os/exec"
+
+// PythonRuntime is the interpreter PYTHON mode executes code with; the
+// python.org and Store installers do not provide a python3 executable
+const PythonRuntime = "python"
+
+// maxEnvVarBytes is the longest NAME=value string Windows allows in an
+// environment block
+const maxEnvVarBytes = 32767
+
+// maxInputEnvBytes caps all INPUT_* variables together
+const maxInputEnvBytes = 1 << 20
+
+// ShellRuntime returns the shell BASH mode executes code with: PowerShell
+// when installed, otherwise cmd
+func ShellRuntime() string {
+	if _, err := exec.LookPath("powershell"); err == nil {
+		return "powershell"
+	}
+	return "cmd"
+}
+
+// shellCommand returns the argv that runs code in the shell
+func shellCommand(code string) []string {
+	if ShellRuntime() == "powershell" {
+		return []string{"powershell", "-NoProfile", "-NonInteractive", "-Command", code}
+	}
+	return []string{"cmd", "/C", code}
+}
@@ -0,0 +1,48 @@
+package executor
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"text/template"
+)
+
+// templateFuncs are available to entity code rendered with boundaries.template
+var templateFuncs = template.FuncMap{
+	// json renders a value as compact JSON
+	"json": func(v interface{}) (string, error) {
+		data, err := json.Marshal(v)
+		if err != nil {
+			return "", err
+		}
+		return string(data), nil
+	},
+	// shellquote renders a value as a single-quoted shell word
+	"shellquote": func(v interface{}) string {
+		s, ok := v.(string)
+		if !ok {
+			data, _ := json.Marshal(v)
+			s = string(data)
+		}
+		return "'" + strings.ReplaceAll(s, "'", `'\''`) + "'"
+	},
+}
+
+// RenderCode renders entity code as a Go text/template with the run inputs
+// as its data, e.g. {{.repo_url}} or {{index . "repo-url" | shellquote}}.
+// Referencing an input that was not provided is an error.
+func RenderCode(code string, inputs map[string]interface{}) (string, error) {
+	tmpl, err := template.New("code").Funcs(templateFuncs).Option("missingkey=error").Parse(code)
+	if err != nil {
+		return "", fmt.Errorf("invalid code template: %w", err)
+	}
+	if inputs == nil {
+		inputs = map[string]interface{}{}
+	}
+	var buf bytes.Buffer
+	if err := tmpl.Execute(&buf, inputs); err != nil {
+		return "", fmt.Errorf("failed to render code template: %w", err)
+	}
+	return buf.String(), nil
+}
@@ -0,0 +1,252 @@
+package executor
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/xml"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/kindship-ai/kindship-cli/internal/api"
+	"github.com/kindship-ai/kindship-cli/internal/boundaries"
+)
+
+// maxFailureMessage caps the length of a single test failure message
+const maxFailureMessage = 500
+
+// TestSummary is the aggregated result of the test reports produced by a
+// TEST execution
+type TestSummary struct {
+	Total    int           `json:"total"`
+	Passed   int           `json:"passed"`
+	Failed   int           `json:"failed"`
+	Skipped  int           `json:"skipped"`
+	Reports  []string      `json:"reports,omitempty"`
+	Failures []TestFailure `json:"failures,omitempty"`
+}
+
+// TestFailure identifies a single failed test
+type TestFailure struct {
+	Suite   string `json:"suite,omitempty"`
+	Name    string `json:"name"`
+	Message string `json:"message,omitempty"`
+}
+
+// ExecuteTests runs entity.Code as a test command (like BASH) and parses the
+// results it produced. JUnit XML and TAP reports are read from the globs in
+// boundaries.test_reports, relative to the workspace; without any configured
+// reports, TAP on stdout is used. Reports older than the run are ignored.
+// The execution fails if any test failed, whatever the command's exit code.
+func ExecuteTests(entity *api.PlanningEntity, inputs map[string]interface{}) *ExecutionResult {
+	return ExecuteTestsWithContext(context.Background(), entity, inputs)
+}
+
+// ExecuteTestsWithContext runs a test command with context for cancellation/timeout.
+func ExecuteTestsWithContext(ctx context.Context, entity *api.PlanningEntity, inputs map[string]interface{}) *ExecutionResult {
+	if entity.Code == nil || *entity.Code == "" {
+		return &ExecutionResult{
+			Success:  false,
+			ExitCode: 1,
+			Error:    fmt.Errorf("no test command provided for TEST execution"),
+		}
+	}
+
+	// Some filesystems only keep whole-second modification times
+	start := time.Now().Truncate(time.Second)
+	result := ExecuteBashWithContext(ctx, entity, inputs)
+	if result.TimedOut || result.Cancelled || IsDryRun(ctx) {
+		return result
+	}
+
+	var reports []string
+	if policy, err := boundaries.Parse(entity.Boundaries); err == nil {
+		reports = policy.TestReports
+	}
+	summary, err := collectTestResults(optionsFrom(ctx).Dir(), reports, result.Stdout, start)
+	if err != nil {
+		result.Stderr = strings.TrimRight(result.Stderr, "\n") + "\n[kindship] " + err.Error()
+	}
+	if summary == nil {
+		return result
+	}
+
+	result.Tests = summary
+	if summary.Failed > 0 {
+		result.Success = false
+		if result.ExitCode == 0 {
+			result.ExitCode = 1
+		}
+		if result.Error == nil {
+			result.Error = fmt.Errorf("%d of %d tests failed", summary.Failed, summary.Total)
+		}
+	}
+	return result
+}
+
+// collectTestResults parses every report matching patterns (relative ones
+// under workDir) that was written since start. It returns nil if no report
+// was found.
+func collectTestResults(workDir string, patterns []string, stdout string, start time.Time) (*TestSummary, error) {
+	summary := &TestSummary{}
+	found := false
+
+	var errs []string
+	for _, pattern := range patterns {
+		if !filepath.IsAbs(pattern) {
+			pattern = filepath.Join(workDir, pattern)
+		}
+		matches, err := filepath.Glob(pattern)
+		if err != nil {
+			errs = append(errs, fmt.Sprintf("invalid test report pattern %q: %v", pattern, err))
+			continue
+		}
+		for _, path := range matches {
+			info, err := os.Stat(path)
+			if err != nil || info.IsDir() || info.ModTime().Before(start) {
+				continue
+			}
+			data, err := os.ReadFile(path)
+			if err != nil {
+				errs = append(errs, fmt.Sprintf("failed to read test report %s: %v", path, err))
+				continue
+			}
+			if isTAP(path, data) {
+				parseTAP(string(data), summary)
+			} else if err := parseJUnit(data, summary); err != nil {
+				errs = append(errs, fmt.Sprintf("failed to parse test report %s: %v", path, err))
+				continue
+			}
+			summary.Reports = append(summary.Reports, path)
+			found = true
+		}
+	}
+
+	if len(patterns) == 0 && isTAP("", []byte(stdout)) {
+		parseTAP(stdout, summary)
+		summary.Reports = append(summary.Reports, "stdout")
+		found = true
+	}
+
+	var err error
+	if len(errs) > 0 {
+		err = fmt.Errorf("%s", strings.Join(errs, "; "))
+	}
+	if !found {
+		return nil, err
+	}
+	return summary, err
+}
+
+func (s *TestSummary) add(suite, name string, passed, skipped bool, message string) {
+	s.Total++
+	switch {
+	case skipped:
+		s.Skipped++
+	case passed:
+		s.Passed++
+	default:
+		s.Failed++
+		message = strings.TrimSpace(message)
+		if len(message) > maxFailureMessage {
+			message = message[:maxFailureMessage] + "..."
+		}
+		s.Failures = append(s.Failures, TestFailure{Suite: suite, Name: name, Message: message})
+	}
+}
+
+// JUnit XML, accepting either <testsuites> or a bare <testsuite> as root
+type junitSuite struct {
+	Name   string          `xml:"name,attr"`
+	Suites []junitSuite    `xml:"testsuite"`
+	Cases  []junitTestCase `xml:"testcase"`
+}
+
+type junitTestCase struct {
+	Name      string        `xml:"name,attr"`
+	ClassName string        `xml:"classname,attr"`
+	Failure   *junitMessage `xml:"failure"`
+	Error     *junitMessage `xml:"error"`
+	Skipped   *junitMessage `xml:"skipped"`
+}
+
+type junitMessage struct {
+	Message string `xml:"message,attr"`
+	Body    string `xml:",chardata"`
+}
+
+func (m *junitMessage) text() string {
+	if m.Message != "" {
+		return m.Message
+	}
+	return m.Body
+}
+
+func parseJUnit(data []byte, summary *TestSummary) error {
+	var root junitSuite
+	if err := xml.Unmarshal(data, &root); err != nil {
+		return err
+	}
+	var walk func(s junitSuite)
+	walk = func(s junitSuite) {
+		for _, tc := range s.Cases {
+			suite := tc.ClassName
+			if suite == "" {
+				suite = s.Name
+			}
+			switch {
+			case tc.Failure != nil:
+				summary.add(suite, tc.Name, false, false, tc.Failure.text())
+			case tc.Error != nil:
+				summary.add(suite, tc.Name, false, false, tc.Error.text())
+			case tc.Skipped != nil:
+				summary.add(suite, tc.Name, false, true, "")
+			default:
+				summary.add(suite, tc.Name, true, false, "")
+			}
+		}
+		for _, child := range s.Suites {
+			walk(child)
+		}
+	}
+	walk(root)
+	return nil
+}
+
+var (
+	tapPlanPattern = regexp.MustCompile(`^\d+\.\.\d+`)
+	tapLinePattern = regexp.MustCompile(`^(not )?ok\b(?:\s+\d+)?\s*(?:-\s*)?([^#]*?)\s*(?:#\s*(.*))?$`)
+)
+
+// isTAP reports whether a report is in TAP format, by extension or content
+func isTAP(path string, data []byte) bool {
+	if strings.EqualFold(filepath.Ext(path), ".tap") {
+		return true
+	}
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if strings.HasPrefix(line, "TAP version") || tapPlanPattern.MatchString(line) {
+			return true
+		}
+	}
+	return false
+}
+
+func parseTAP(text string, summary *TestSummary) {
+	scanner := bufio.NewScanner(strings.NewReader(text))
+	for scanner.Scan() {
+		m := tapLinePattern.FindStringSubmatch(strings.TrimSpace(scanner.Text()))
+		if m == nil {
+			continue
+		}
+		directive := strings.ToUpper(m[3])
+		// SKIP and TODO tests do not count as failures
+		skipped := strings.HasPrefix(directive, "SKIP") || strings.HasPrefix(directive, "TODO")
+		summary.add("", m[2], m[1] == "", skipped, "")
+	}
+}
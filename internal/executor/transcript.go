@@ -0,0 +1,128 @@
+package executor
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"os/exec"
+	"strings"
+	"sync"
+)
+
+// maxTranscriptBytes caps how much of a Claude conversation is kept for
+// upload as an artifact
+const maxTranscriptBytes = 16 << 20 // 16MB
+
+// TranscriptArtifactName is the artifact name LLM transcripts are uploaded as
+const TranscriptArtifactName = "transcript.jsonl"
+
+// TranscriptContentType is the content type of uploaded transcripts
+const TranscriptContentType = "application/x-ndjson"
+
+// claudeTranscriptArgs makes Claude stream the whole conversation (every
+// message and tool call, one JSON event per line) instead of only printing
+// the final answer. The closing event is the same result object that
+// --output-format json prints.
+var claudeTranscriptArgs = []string{"--output-format", "stream-json", "--verbose"}
+
+var (
+	claudeJSONOnce      sync.Once
+	claudeJSONSupported bool
+)
+
+// claudeSupportsJSONOutput reports whether the installed claude CLI accepts
+// --output-format. Older releases only print free text.
+func claudeSupportsJSONOutput() bool {
+	claudeJSONOnce.Do(func() {
+		path, err := exec.LookPath("claude")
+		if err != nil {
+			// Let 'kindship auth claude' report the missing binary
+			claudeJSONSupported = true
+			return
+		}
+		out, _ := exec.Command(path, "--help").CombinedOutput()
+		claudeJSONSupported = bytes.Contains(out, []byte("--output-format"))
+	})
+	return claudeJSONSupported
+}
+
+// LLMResult is the result object Claude reports at the end of a run
+type LLMResult struct {
+	Subtype   string  `json:"subtype,omitempty"`
+	Result    string  `json:"result"`
+	IsError   bool    `json:"is_error"`
+	NumTurns  int     `json:"num_turns"`
+	CostUSD   float64 `json:"total_cost_usd"`
+	Duration  int64   `json:"duration_ms,omitempty"`
+	SessionID string  `json:"session_id,omitempty"`
+}
+
+// parseLLMResult returns the result object closing a stream-json
+// transcript, or nil if the output is not a transcript
+func parseLLMResult(transcript []byte) *LLMResult {
+	var result *LLMResult
+	scanner := bufio.NewScanner(bytes.NewReader(transcript))
+	scanner.Buffer(make([]byte, 64*1024), maxTranscriptBytes)
+	for scanner.Scan() {
+		line := bytes.TrimSpace(scanner.Bytes())
+		if len(line) == 0 || line[0] != '{' {
+			continue
+		}
+		var event struct {
+			Type string `json:"type"`
+			LLMResult
+			// Releases before total_cost_usd reported cost_usd
+			LegacyCostUSD float64 `json:"cost_usd"`
+		}
+		if json.Unmarshal(line, &event) != nil || event.Type != "result" {
+			continue
+		}
+		r := event.LLMResult
+		if r.CostUSD == 0 {
+			r.CostUSD = event.LegacyCostUSD
+		}
+		result = &r
+	}
+	return result
+}
+
+// lastAssistantText returns the text of the last assistant message in a
+// stream-json transcript, for runs that ended without a result event
+// (crashed, timed out or cancelled). ok is false when the output holds no
+// stream-json events at all, i.e. it is plain text.
+func lastAssistantText(transcript []byte) (text string, ok bool) {
+	scanner := bufio.NewScanner(bytes.NewReader(transcript))
+	scanner.Buffer(make([]byte, 64*1024), maxTranscriptBytes)
+	for scanner.Scan() {
+		line := bytes.TrimSpace(scanner.Bytes())
+		if len(line) == 0 || line[0] != '{' {
+			continue
+		}
+		var event struct {
+			Type    string `json:"type"`
+			Message struct {
+				Content []struct {
+					Type string `json:"type"`
+					Text string `json:"text"`
+				} `json:"content"`
+			} `json:"message"`
+		}
+		if json.Unmarshal(line, &event) != nil || event.Type == "" {
+			continue
+		}
+		ok = true
+		if event.Type != "assistant" {
+			continue
+		}
+		var parts []string
+		for _, block := range event.Message.Content {
+			if block.Type == "text" && block.Text != "" {
+				parts = append(parts, block.Text)
+			}
+		}
+		if len(parts) > 0 {
+			text = strings.Join(parts, "\n")
+		}
+	}
+	return text, ok
+}
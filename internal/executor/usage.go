@@ -0,0 +1,49 @@
+package executor
+
+import (
+	"os/exec"
+	"time"
+)
+
+// ProcessUsage is the resource usage of an executed process, including the
+// children it waited for
+type ProcessUsage struct {
+	// MaxRSSBytes is the peak resident set size (0 where the platform does
+	// not report it)
+	MaxRSSBytes int64
+	UserCPU     time.Duration
+	SystemCPU   time.Duration
+	// StdoutBytes and StderrBytes count everything the process wrote,
+	// including output beyond the capture limit
+	StdoutBytes int64
+	StderrBytes int64
+}
+
+// Metrics returns the usage as execution output metrics
+func (u *ProcessUsage) Metrics() map[string]interface{} {
+	metrics := map[string]interface{}{
+		"user_cpu_ms":   u.UserCPU.Milliseconds(),
+		"system_cpu_ms": u.SystemCPU.Milliseconds(),
+		"stdout_bytes":  u.StdoutBytes,
+		"stderr_bytes":  u.StderrBytes,
+	}
+	if u.MaxRSSBytes > 0 {
+		metrics["max_rss_bytes"] = u.MaxRSSBytes
+	}
+	return metrics
+}
+
+// processUsage returns the usage of a command that has exited, or nil if
+// it never started
+func processUsage(cmd *exec.Cmd, stdout, stderr *limitedWriter) *ProcessUsage {
+	if cmd.ProcessState == nil {
+		return nil
+	}
+	return &ProcessUsage{
+		MaxRSSBytes: maxRSSBytes(cmd.ProcessState),
+		UserCPU:     cmd.ProcessState.UserTime(),
+		SystemCPU:   cmd.ProcessState.SystemTime(),
+		StdoutBytes: stdout.written,
+		StderrBytes: stderr.written,
+	}
+}
@@ -0,0 +1,22 @@
+//go:build !windows
+
+package executor
+
+import (
+	"os"
+	"runtime"
+	"syscall"
+)
+
+// maxRSSBytes returns the peak resident set size of an exited process.
+// ru_maxrss is in bytes on macOS and in kilobytes elsewhere.
+func maxRSSBytes(state *os.ProcessState) int64 {
+	rusage, ok := state.SysUsage().(*syscall.Rusage)
+	if !ok {
+		return 0
+	}
+	if runtime.GOOS == "darwin" {
+		return int64(rusage.Maxrss)
+	}
+	return int64(rusage.Maxrss) * 1024
+}
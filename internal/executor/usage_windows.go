@@ -0,0 +1,11 @@
+//go:build windows
+
+package executor
+
+import "os"
+
+// maxRSSBytes returns 0: Windows does not report the peak working set of
+// an exited process through os.ProcessState
+func maxRSSBytes(state *os.ProcessState) int64 {
+	return 0
+}
@@ -8,6 +8,8 @@ import (
 	"os"
 	"sync"
 	"time"
+
+	"github.com/kindship-ai/kindship-cli/internal/config"
 )
 
 // Logger sends structured logs to Axiom
@@ -20,47 +22,84 @@ type Logger struct {
 	agentID   string
 	command   string
 	component string
-	verbose   bool
+	// verbose echoes recorded entries to stderr
+	verbose bool
+	// level is the minimum severity recorded, on stderr and for Axiom
+	level Level
+
+	// environment names the deployment; see SetEnvironment
+	environment string
+
+	// fields are attached to every entry; set via WithFields
+	fields map[string]interface{}
+
+	// timings accumulates WithDuration entries when enabled (root only)
+	timings *timingRecorder
+
+	// parent is set on loggers derived via WithAgent or WithFields; entries
+	// are buffered and flushed through the parent so all derived loggers
+	// share one Axiom batch.
+	parent *Logger
 }
 
 // LogEntry is a structured log entry for Axiom
 type LogEntry struct {
-	Timestamp  time.Time `json:"_time"`
-	Level      string    `json:"level"`
-	Message    string    `json:"message"`
-	AgentID    string    `json:"agent_id,omitempty"`
-	Command    string    `json:"command,omitempty"`
-	DurationMs int64     `json:"duration_ms,omitempty"`
-	Error      string    `json:"error,omitempty"`
-	Component  string    `json:"component"`
-	Extra      map[string]interface{} `json:"extra,omitempty"`
+	Timestamp    time.Time              `json:"_time"`
+	Level        string                 `json:"level"`
+	Message      string                 `json:"message"`
+	AgentID      string                 `json:"agent_id,omitempty"`
+	Command      string                 `json:"command,omitempty"`
+	EntityID     string                 `json:"entity_id,omitempty"`
+	ExecutionID  string                 `json:"execution_id,omitempty"`
+	ProcessRunID string                 `json:"process_run_id,omitempty"`
+	DurationMs   int64                  `json:"duration_ms,omitempty"`
+	Error        string                 `json:"error,omitempty"`
+	Component    string                 `json:"component"`
+	Environment  string                 `json:"environment,omitempty"`
+	Extra        map[string]interface{} `json:"extra,omitempty"`
 }
 
 var (
 	globalLogger *Logger
 	once         sync.Once
+
+	// configuredEnvironment is set by SetEnvironment
+	configuredEnvironment string
 )
 
+// SetEnvironment tags entries of loggers created by Init with a deployment
+// name (e.g. staging). Must be called before Init.
+func SetEnvironment(name string) {
+	configuredEnvironment = name
+}
+
 // Init initializes the global logger
 func Init(agentID, command string, verbose bool) *Logger {
 	once.Do(func() {
+		level, echo := levelFor(verbose)
 		token := os.Getenv("AXIOM_TOKEN")
+		if config.LoadSelfHostedProfile() != nil {
+			// Axiom ingest is a hosted-only integration
+			token = ""
+		}
 		dataset := os.Getenv("AXIOM_DATASET")
 		if dataset == "" {
 			dataset = "kindship-logs"
 		}
 
 		globalLogger = &Logger{
-			token:     token,
-			dataset:   dataset,
+			token:   token,
+			dataset: dataset,
 			client: &http.Client{
 				Timeout: 5 * time.Second,
 			},
-			buffer:    make([]LogEntry, 0, 10),
-			agentID:   agentID,
-			command:   command,
-			component: "kindship-cli",
-			verbose:   verbose,
+			buffer:      make([]LogEntry, 0, 10),
+			agentID:     agentID,
+			command:     command,
+			component:   "kindship-cli",
+			verbose:     echo,
+			level:       level,
+			environment: configuredEnvironment,
 		}
 	})
 	return globalLogger
@@ -69,7 +108,7 @@ func Init(agentID, command string, verbose bool) *Logger {
 // Get returns the global logger
 func Get() *Logger {
 	if globalLogger == nil {
-		return &Logger{verbose: false}
+		return &Logger{verbose: false, level: LevelInfo}
 	}
 	return globalLogger
 }
@@ -82,35 +121,123 @@ func (l *Logger) SetComponent(component string) {
 	l.component = component
 }
 
+// WithAgent returns a logger that tags entries with the given agent ID while
+// sharing the receiver's buffer and Axiom configuration. Used by the agent
+// loop to keep per-agent context when polling for several agents.
+func (l *Logger) WithAgent(agentID string) *Logger {
+	child := l.child()
+	child.agentID = agentID
+	return child
+}
+
+// WithFields returns a logger that attaches fields to every entry, on top
+// of the receiver's own fields. entity_id, execution_id and process_run_id
+// become top-level columns so all entries of one run can be queried
+// together; other fields go into extra.
+func (l *Logger) WithFields(fields map[string]interface{}) *Logger {
+	child := l.child()
+	child.fields = make(map[string]interface{}, len(l.fields)+len(fields))
+	for k, v := range l.fields {
+		child.fields[k] = v
+	}
+	for k, v := range fields {
+		child.fields[k] = v
+	}
+	return child
+}
+
+// child returns a copy of l that buffers through the root logger
+func (l *Logger) child() *Logger {
+	root := l.root()
+	l.mu.Lock()
+	component := l.component
+	l.mu.Unlock()
+	return &Logger{
+		token:       root.token,
+		dataset:     root.dataset,
+		client:      root.client,
+		agentID:     l.agentID,
+		command:     l.command,
+		component:   component,
+		verbose:     l.verbose,
+		level:       l.level,
+		environment: l.environment,
+		fields:      l.fields,
+		parent:      root,
+	}
+}
+
 // IsEnabled returns true if Axiom logging is configured
 func (l *Logger) IsEnabled() bool {
 	return l.token != ""
 }
 
 // log adds an entry to the buffer
-func (l *Logger) log(level, message string, extra map[string]interface{}) {
+func (l *Logger) log(lv Level, message string, extra map[string]interface{}) {
+	if lv < l.level {
+		return
+	}
+	level := lv.String()
 	entry := LogEntry{
-		Timestamp: time.Now().UTC(),
-		Level:     level,
-		Message:   message,
-		AgentID:   l.agentID,
-		Command:   l.command,
-		Component: l.component,
-		Extra:     extra,
+		Timestamp:   time.Now().UTC(),
+		Level:       level,
+		Message:     message,
+		AgentID:     l.agentID,
+		Command:     l.command,
+		Component:   l.component,
+		Environment: l.environment,
+		Extra:       extra,
 	}
+	l.applyFields(&entry)
 
-	// Also print to stderr if verbose
+	// Also print to stderr if enabled
 	if l.verbose {
-		fmt.Fprintf(os.Stderr, "[kindship:%s] %s\n", level, message)
+		if l.parent != nil && l.agentID != "" && l.agentID != l.parent.agentID {
+			fmt.Fprintf(os.Stderr, "[kindship:%s] [%s] %s\n", level, l.agentID, message)
+		} else {
+			fmt.Fprintf(os.Stderr, "[kindship:%s] %s\n", level, message)
+		}
 	}
 
 	if !l.IsEnabled() {
 		return
 	}
 
-	l.mu.Lock()
-	l.buffer = append(l.buffer, entry)
-	l.mu.Unlock()
+	target := l
+	if l.parent != nil {
+		target = l.parent
+	}
+	target.mu.Lock()
+	target.buffer = append(target.buffer, entry)
+	target.mu.Unlock()
+}
+
+// applyFields copies the logger's fields into entry. Explicit extra values
+// win over fields of the same name; the caller's extra map is not modified.
+func (l *Logger) applyFields(entry *LogEntry) {
+	if len(l.fields) == 0 {
+		return
+	}
+	extra := make(map[string]interface{}, len(entry.Extra)+len(l.fields))
+	for k, v := range l.fields {
+		switch k {
+		case "entity_id":
+			entry.EntityID = fmt.Sprint(v)
+		case "execution_id":
+			entry.ExecutionID = fmt.Sprint(v)
+		case "process_run_id":
+			entry.ProcessRunID = fmt.Sprint(v)
+		default:
+			extra[k] = v
+		}
+	}
+	if len(extra) == 0 {
+		return
+	}
+	for k, v := range entry.Extra {
+		extra[k] = v
+	}
+	entry.Extra = extra
 }
 
 // Info logs an info message
@@ -119,7 +246,7 @@ func (l *Logger) Info(message string, extra ...map[string]interface{}) {
 	if len(extra) > 0 {
 		e = extra[0]
 	}
-	l.log("info", message, e)
+	l.log(LevelInfo, message, e)
 }
 
 // Error logs an error message
@@ -133,7 +260,7 @@ func (l *Logger) Error(message string, err error, extra ...map[string]interface{
 	if err != nil {
 		e["error"] = err.Error()
 	}
-	l.log("error", message, e)
+	l.log(LevelError, message, e)
 }
 
 // Warn logs a warning message
@@ -142,22 +269,20 @@ func (l *Logger) Warn(message string, extra ...map[string]interface{}) {
 	if len(extra) > 0 {
 		e = extra[0]
 	}
-	l.log("warn", message, e)
+	l.log(LevelWarn, message, e)
 }
 
-// Debug logs a debug message (only if verbose)
+// Debug logs a debug message (only at debug level)
 func (l *Logger) Debug(message string, extra ...map[string]interface{}) {
-	if !l.verbose {
-		return
-	}
 	var e map[string]interface{}
 	if len(extra) > 0 {
 		e = extra[0]
 	}
-	l.log("debug", message, e)
+	l.log(LevelDebug, message, e)
 }
 
-// WithDuration logs a message with duration
+// WithDuration logs a message with duration. The duration is also
+// accumulated under the message when timings are enabled.
 func (l *Logger) WithDuration(message string, duration time.Duration, extra ...map[string]interface{}) {
 	e := make(map[string]interface{})
 	if len(extra) > 0 {
@@ -166,11 +291,17 @@ func (l *Logger) WithDuration(message string, duration time.Duration, extra ...m
 		}
 	}
 	e["duration_ms"] = duration.Milliseconds()
-	l.log("info", message, e)
+	l.recordTiming(message, duration)
+	l.log(LevelInfo, message, e)
 }
 
-// Flush sends all buffered logs to Axiom
+// Flush sends all buffered logs to Axiom. A batch that cannot be sent is
+// saved to the pending directory and retried after the next successful
+// flush, so ingest outages don't drop entries.
 func (l *Logger) Flush() error {
+	if l.parent != nil {
+		return l.parent.Flush()
+	}
 	if !l.IsEnabled() {
 		return nil
 	}
@@ -189,6 +320,22 @@ func (l *Logger) Flush() error {
 		return fmt.Errorf("failed to marshal logs: %w", err)
 	}
 
+	if err := l.send(body); err != nil {
+		if saveErr := savePending(body); saveErr != nil {
+			return fmt.Errorf("%w (and failed to save batch for retry: %v)", err, saveErr)
+		}
+		return fmt.Errorf("%w (batch saved for retry)", err)
+	}
+
+	// Ingest is reachable again: catch up on batches from earlier failures
+	if _, err := l.SyncPending(); err != nil {
+		return fmt.Errorf("failed to send pending logs: %w", err)
+	}
+	return nil
+}
+
+// send posts a JSON array of entries to the Axiom ingest endpoint
+func (l *Logger) send(body []byte) error {
 	// Use EU edge endpoint for ingest (dataset is in eu-central-1)
 	url := fmt.Sprintf("https://eu-central-1.aws.edge.axiom.co/v1/ingest/%s", l.dataset)
 	req, err := http.NewRequest(http.MethodPost, url, bytes.NewReader(body))
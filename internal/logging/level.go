@@ -0,0 +1,73 @@
+package logging
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Level is the minimum severity a logger records
+type Level int
+
+const (
+	LevelDebug Level = iota
+	LevelInfo
+	LevelWarn
+	LevelError
+)
+
+// LevelUsage is the flag description for --log-level
+const LevelUsage = "Log level (debug, info, warn, error); defaults to KINDSHIP_LOG_LEVEL"
+
+// ParseLevel parses a level name as accepted by --log-level
+func ParseLevel(s string) (Level, error) {
+	switch strings.ToLower(strings.TrimSpace(s)) {
+	case "debug":
+		return LevelDebug, nil
+	case "info":
+		return LevelInfo, nil
+	case "warn", "warning":
+		return LevelWarn, nil
+	case "error":
+		return LevelError, nil
+	default:
+		return 0, fmt.Errorf("invalid log level %q (expected debug, info, warn or error)", s)
+	}
+}
+
+// String returns the level name used in log entries
+func (lv Level) String() string {
+	switch lv {
+	case LevelDebug:
+		return "debug"
+	case LevelWarn:
+		return "warn"
+	case LevelError:
+		return "error"
+	default:
+		return "info"
+	}
+}
+
+// configuredLevel is set by SetLevel; nil means the level follows --verbose
+var configuredLevel *Level
+
+// SetLevel sets the level of loggers created by Init. An explicit level
+// filters both stderr output and entries buffered for Axiom, and makes
+// entries at or above it visible on stderr without --verbose. Must be
+// called before Init.
+func SetLevel(level Level) {
+	configuredLevel = &level
+}
+
+// levelFor returns the effective level and whether entries are echoed to
+// stderr. Without an explicit level, --verbose means debug on stderr and
+// otherwise info is recorded silently, as before levels were configurable.
+func levelFor(verbose bool) (Level, bool) {
+	if configuredLevel != nil {
+		return *configuredLevel, true
+	}
+	if verbose {
+		return LevelDebug, true
+	}
+	return LevelInfo, false
+}
@@ -0,0 +1,122 @@
+package logging
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/kindship-ai/kindship-cli/internal/config"
+)
+
+const (
+	// pendingDir holds unsent log batches, relative to the state dir
+	pendingDir = "logs/pending"
+	// maxPendingBatches caps the pending directory; the oldest batches are
+	// dropped first so a long outage cannot fill the disk
+	maxPendingBatches = 200
+)
+
+// PendingDir returns the directory unsent log batches are saved to
+func PendingDir() (string, error) {
+	dir, err := config.GetStateDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, filepath.FromSlash(pendingDir)), nil
+}
+
+// savePending writes a batch that could not be sent to the pending directory
+func savePending(body []byte) error {
+	dir, err := PendingDir()
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(dir, config.ConfigDirMode); err != nil {
+		return fmt.Errorf("failed to create pending log directory: %w", err)
+	}
+
+	name := fmt.Sprintf("%d-%d.json", time.Now().UnixNano(), os.Getpid())
+	if err := os.WriteFile(filepath.Join(dir, name), body, config.ConfigFileMode); err != nil {
+		return fmt.Errorf("failed to save pending logs: %w", err)
+	}
+
+	// Pruning is best effort; the batch itself is saved
+	batches, err := pendingBatches(dir)
+	if err != nil {
+		return nil
+	}
+	for len(batches) > maxPendingBatches {
+		os.Remove(batches[0])
+		batches = batches[1:]
+	}
+	return nil
+}
+
+// pendingBatches lists saved batches, oldest first
+func pendingBatches(dir string) ([]string, error) {
+	entries, err := os.ReadDir(dir)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to list pending logs: %w", err)
+	}
+	var paths []string
+	for _, e := range entries {
+		if !e.IsDir() && strings.HasSuffix(e.Name(), ".json") {
+			paths = append(paths, filepath.Join(dir, e.Name()))
+		}
+	}
+	// Names start with a nanosecond timestamp
+	sort.Strings(paths)
+	return paths, nil
+}
+
+// PendingCount returns how many unsent batches are waiting on disk
+func PendingCount() (int, error) {
+	dir, err := PendingDir()
+	if err != nil {
+		return 0, err
+	}
+	batches, err := pendingBatches(dir)
+	return len(batches), err
+}
+
+// SyncPending sends batches saved by failed flushes, oldest first, deleting
+// each once sent. Stops at the first failure, leaving the rest for later.
+// Returns the number of batches sent.
+func (l *Logger) SyncPending() (int, error) {
+	if l.parent != nil {
+		return l.parent.SyncPending()
+	}
+	if !l.IsEnabled() {
+		return 0, nil
+	}
+	dir, err := PendingDir()
+	if err != nil {
+		return 0, err
+	}
+	batches, err := pendingBatches(dir)
+	if err != nil {
+		return 0, err
+	}
+
+	sent := 0
+	for _, path := range batches {
+		body, err := os.ReadFile(path)
+		if err != nil {
+			return sent, fmt.Errorf("failed to read pending logs: %w", err)
+		}
+		if err := l.send(body); err != nil {
+			return sent, err
+		}
+		if err := os.Remove(path); err != nil {
+			return sent, fmt.Errorf("failed to remove sent logs: %w", err)
+		}
+		sent++
+	}
+	return sent, nil
+}
@@ -0,0 +1,68 @@
+package logging
+
+import (
+	"time"
+)
+
+// Timing is the accumulated duration of every WithDuration entry with the
+// same message
+type Timing struct {
+	Name  string        `json:"name"`
+	Count int           `json:"count"`
+	Total time.Duration `json:"total"`
+}
+
+// timingRecorder accumulates WithDuration entries in first-seen order
+type timingRecorder struct {
+	timings []Timing
+	index   map[string]int
+}
+
+// EnableTimings makes the logger (and every logger derived from it)
+// accumulate WithDuration entries, for --timings summaries
+func (l *Logger) EnableTimings() {
+	root := l.root()
+	root.mu.Lock()
+	defer root.mu.Unlock()
+	if root.timings == nil {
+		root.timings = &timingRecorder{index: map[string]int{}}
+	}
+}
+
+// Timings returns the accumulated durations in the order they were first
+// recorded, or nil if timings are not enabled
+func (l *Logger) Timings() []Timing {
+	root := l.root()
+	root.mu.Lock()
+	defer root.mu.Unlock()
+	if root.timings == nil {
+		return nil
+	}
+	return append([]Timing(nil), root.timings.timings...)
+}
+
+// recordTiming adds d to the timing named name, if timings are enabled
+func (l *Logger) recordTiming(name string, d time.Duration) {
+	root := l.root()
+	root.mu.Lock()
+	defer root.mu.Unlock()
+	if root.timings == nil {
+		return
+	}
+	i, ok := root.timings.index[name]
+	if !ok {
+		i = len(root.timings.timings)
+		root.timings.index[name] = i
+		root.timings.timings = append(root.timings.timings, Timing{Name: name})
+	}
+	root.timings.timings[i].Count++
+	root.timings.timings[i].Total += d
+}
+
+// root returns the logger that owns the shared buffer
+func (l *Logger) root() *Logger {
+	if l.parent != nil {
+		return l.parent
+	}
+	return l
+}
@@ -0,0 +1,268 @@
+// Package mockapi records Kindship API traffic to a fixture file and replays
+// it, so the agent loop and run command can be exercised hermetically.
+package mockapi
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+)
+
+// redacted replaces secret values in recorded responses
+const redacted = "REDACTED"
+
+// Interaction is a single recorded request/response pair
+type Interaction struct {
+	Method string `json:"method"`
+	Path   string `json:"path"`
+	// Query, when set, must match the request's query string (in any order)
+	Query   string          `json:"query,omitempty"`
+	Request json.RawMessage `json:"request,omitempty"`
+	Status  int             `json:"status"`
+	Body    json.RawMessage `json:"body,omitempty"`
+	// Text holds a non-JSON response body
+	Text string `json:"text,omitempty"`
+}
+
+// Fixtures is the on-disk fixture file format
+type Fixtures struct {
+	Interactions []Interaction `json:"interactions"`
+}
+
+// Load reads a fixture file
+func Load(path string) (*Fixtures, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read fixtures: %w", err)
+	}
+	var f Fixtures
+	if err := json.Unmarshal(data, &f); err != nil {
+		return nil, fmt.Errorf("failed to parse fixtures: %w", err)
+	}
+	return &f, nil
+}
+
+// Save writes a fixture file atomically
+func (f *Fixtures) Save(path string) error {
+	data, err := json.MarshalIndent(f, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal fixtures: %w", err)
+	}
+	tmp, err := os.CreateTemp(filepath.Dir(path), ".fixtures-*.json")
+	if err != nil {
+		return fmt.Errorf("failed to write fixtures: %w", err)
+	}
+	if _, err := tmp.Write(append(data, '\n')); err != nil {
+		tmp.Close()
+		os.Remove(tmp.Name())
+		return fmt.Errorf("failed to write fixtures: %w", err)
+	}
+	tmp.Close()
+	if err := os.Rename(tmp.Name(), path); err != nil {
+		os.Remove(tmp.Name())
+		return fmt.Errorf("failed to write fixtures: %w", err)
+	}
+	return nil
+}
+
+func (i *Interaction) matches(r *http.Request) bool {
+	if i.Method != r.Method || i.Path != r.URL.Path {
+		return false
+	}
+	if i.Query == "" {
+		return true
+	}
+	want, err := url.ParseQuery(i.Query)
+	if err != nil {
+		return false
+	}
+	return want.Encode() == r.URL.Query().Encode()
+}
+
+func (i *Interaction) write(w http.ResponseWriter) {
+	if i.Text != "" {
+		w.Header().Set("Content-Type", "text/plain; charset=utf-8")
+		w.WriteHeader(i.Status)
+		io.WriteString(w, i.Text)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(i.Status)
+	w.Write(i.Body)
+}
+
+// Replayer serves recorded interactions. Interactions with the same method,
+// path and query are served in the order they were recorded, and the last
+// one repeats once they are used up, so a sequence like "task, task, no
+// task" for plan/next replays deterministically.
+type Replayer struct {
+	mu       sync.Mutex
+	fixtures *Fixtures
+	served   map[int]bool
+	// Unmatched is called for requests without a fixture
+	Unmatched func(r *http.Request)
+}
+
+// NewReplayer creates a handler serving the given fixtures
+func NewReplayer(f *Fixtures) *Replayer {
+	return &Replayer{fixtures: f, served: map[int]bool{}}
+}
+
+func (p *Replayer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	// Prefer fixtures that pin the query string over ones that don't
+	match := -1
+	last := -1
+	for pass := 0; pass < 2 && match == -1; pass++ {
+		for i := range p.fixtures.Interactions {
+			ix := &p.fixtures.Interactions[i]
+			if (pass == 0) == (ix.Query == "") || !ix.matches(r) {
+				continue
+			}
+			last = i
+			if !p.served[i] {
+				match = i
+				break
+			}
+		}
+		if last != -1 && match == -1 {
+			match = last
+		}
+	}
+
+	if match == -1 {
+		if p.Unmatched != nil {
+			p.Unmatched(r)
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusNotFound)
+		json.NewEncoder(w).Encode(map[string]string{
+			"error": fmt.Sprintf("no fixture for %s %s", r.Method, r.URL.RequestURI()),
+		})
+		return
+	}
+
+	p.served[match] = true
+	p.fixtures.Interactions[match].write(w)
+}
+
+// Recorder proxies requests to an upstream API and appends every exchange
+// to a fixture file. Secret values returned by the credentials endpoint are
+// redacted before they are written.
+type Recorder struct {
+	mu       sync.Mutex
+	upstream *url.URL
+	path     string
+	fixtures *Fixtures
+	client   *http.Client
+}
+
+// NewRecorder creates a recording proxy for upstream that writes to path,
+// appending to any interactions already recorded there
+func NewRecorder(upstream, path string) (*Recorder, error) {
+	u, err := url.Parse(upstream)
+	if err != nil || u.Scheme == "" || u.Host == "" {
+		return nil, fmt.Errorf("invalid upstream URL: %s", upstream)
+	}
+	fixtures := &Fixtures{}
+	if _, err := os.Stat(path); err == nil {
+		if fixtures, err = Load(path); err != nil {
+			return nil, err
+		}
+	}
+	return &Recorder{
+		upstream: u,
+		path:     path,
+		fixtures: fixtures,
+		client:   &http.Client{Timeout: 5 * time.Minute},
+	}, nil
+}
+
+func (rec *Recorder) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	reqBody, err := io.ReadAll(r.Body)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	target := *rec.upstream
+	target.Path = strings.TrimSuffix(rec.upstream.Path, "/") + r.URL.Path
+	target.RawQuery = r.URL.RawQuery
+	out, err := http.NewRequest(r.Method, target.String(), bytes.NewReader(reqBody))
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadGateway)
+		return
+	}
+	out.Header = r.Header.Clone()
+
+	resp, err := rec.client.Do(out)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadGateway)
+		return
+	}
+	defer resp.Body.Close()
+	respBody, err := io.ReadAll(resp.Body)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadGateway)
+		return
+	}
+
+	for k, v := range resp.Header {
+		w.Header()[k] = v
+	}
+	w.WriteHeader(resp.StatusCode)
+	w.Write(respBody)
+
+	ix := Interaction{
+		Method: r.Method,
+		Path:   r.URL.Path,
+		Query:  r.URL.RawQuery,
+		Status: resp.StatusCode,
+	}
+	if json.Valid(reqBody) {
+		ix.Request = reqBody
+	}
+	if json.Valid(respBody) {
+		ix.Body = redact(respBody)
+	} else {
+		ix.Text = string(respBody)
+	}
+
+	rec.mu.Lock()
+	defer rec.mu.Unlock()
+	rec.fixtures.Interactions = append(rec.fixtures.Interactions, ix)
+	if err := rec.fixtures.Save(rec.path); err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: %v\n", err)
+	}
+}
+
+// redact blanks the values of an "env" object, which carries injected
+// credentials in secrets responses
+func redact(body []byte) json.RawMessage {
+	var obj map[string]interface{}
+	if json.Unmarshal(body, &obj) != nil {
+		return body
+	}
+	env, ok := obj["env"].(map[string]interface{})
+	if !ok {
+		return body
+	}
+	for k := range env {
+		env[k] = redacted
+	}
+	data, err := json.Marshal(obj)
+	if err != nil {
+		return body
+	}
+	return data
+}
@@ -0,0 +1,188 @@
+// Package output renders command results as aligned tables, JSON or YAML.
+package output
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"unicode/utf8"
+)
+
+// Format selects how a command renders its result
+type Format string
+
+const (
+	FormatTable Format = "table"
+	FormatJSON  Format = "json"
+	FormatYAML  Format = "yaml"
+)
+
+// FormatUsage is the flag description shared by every --format flag
+const FormatUsage = "Output format (table, json, yaml)"
+
+// ParseFormat validates a --format value. "text" is accepted as an alias
+// for "table" for compatibility with earlier releases.
+func ParseFormat(s string) (Format, error) {
+	switch f := Format(strings.ToLower(s)); f {
+	case FormatTable, FormatJSON, FormatYAML:
+		return f, nil
+	case "text", "":
+		return FormatTable, nil
+	default:
+		return "", fmt.Errorf("unsupported output format %q (expected table, json or yaml)", s)
+	}
+}
+
+// Render writes v to w as JSON or YAML, or calls table for table output
+func Render(w io.Writer, format Format, v interface{}, table func() error) error {
+	switch format {
+	case FormatJSON:
+		return JSON(w, v)
+	case FormatYAML:
+		return YAML(w, v)
+	default:
+		return table()
+	}
+}
+
+// JSON writes v as indented JSON
+func JSON(w io.Writer, v interface{}) error {
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(v)
+}
+
+// Table renders rows with aligned columns. Cells may contain color codes;
+// alignment is based on their visible width.
+type Table struct {
+	// Indent is written before every line
+	Indent  string
+	headers []string
+	rows    [][]string
+}
+
+// NewTable creates a table. With no headers, only rows are printed, which
+// suits key/value listings.
+func NewTable(headers ...string) *Table {
+	return &Table{headers: headers}
+}
+
+// Row appends a row of cells
+func (t *Table) Row(cells ...string) {
+	t.rows = append(t.rows, cells)
+}
+
+// Render writes the table to w
+func (t *Table) Render(w io.Writer) error {
+	var widths []int
+	measure := func(cells []string) {
+		for i, c := range cells {
+			if i >= len(widths) {
+				widths = append(widths, 0)
+			}
+			if n := visibleWidth(c); n > widths[i] {
+				widths[i] = n
+			}
+		}
+	}
+	measure(t.headers)
+	for _, r := range t.rows {
+		measure(r)
+	}
+
+	line := func(cells []string, style func(string) string) error {
+		var b strings.Builder
+		b.WriteString(t.Indent)
+		for i, c := range cells {
+			if i == len(cells)-1 {
+				b.WriteString(style(c))
+				break
+			}
+			b.WriteString(style(c))
+			b.WriteString(strings.Repeat(" ", widths[i]-visibleWidth(c)+2))
+		}
+		b.WriteString("\n")
+		_, err := io.WriteString(w, b.String())
+		return err
+	}
+
+	if len(t.headers) > 0 {
+		upper := make([]string, len(t.headers))
+		for i, h := range t.headers {
+			upper[i] = strings.ToUpper(h)
+		}
+		if err := line(upper, Bold); err != nil {
+			return err
+		}
+	}
+	for _, r := range t.rows {
+		if err := line(r, func(s string) string { return s }); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// visibleWidth returns the printed width of s, ignoring ANSI escapes
+func visibleWidth(s string) int {
+	n := 0
+	for i := 0; i < len(s); {
+		if s[i] == '\x1b' {
+			if j := strings.IndexByte(s[i:], 'm'); j != -1 {
+				i += j + 1
+				continue
+			}
+		}
+		_, size := utf8.DecodeRuneInString(s[i:])
+		i += size
+		n++
+	}
+	return n
+}
+
+// colorEnabled is decided once: color only for terminals, and never when
+// NO_COLOR is set (https://no-color.org)
+var colorEnabled = detectColor()
+
+func detectColor() bool {
+	if os.Getenv("NO_COLOR") != "" || os.Getenv("TERM") == "dumb" {
+		return false
+	}
+	info, err := os.Stdout.Stat()
+	return err == nil && info.Mode()&os.ModeCharDevice != 0
+}
+
+// SetColor overrides terminal color detection
+func SetColor(enabled bool) {
+	colorEnabled = enabled
+}
+
+func paint(code, s string) string {
+	if !colorEnabled || s == "" {
+		return s
+	}
+	return "\x1b[" + code + "m" + s + "\x1b[0m"
+}
+
+// Bold renders s in bold
+func Bold(s string) string { return paint("1", s) }
+
+// Green renders s in green
+func Green(s string) string { return paint("32", s) }
+
+// Red renders s in red
+func Red(s string) string { return paint("31", s) }
+
+// Yellow renders s in yellow
+func Yellow(s string) string { return paint("33", s) }
+
+// Dim renders s faint
+func Dim(s string) string { return paint("2", s) }
+
+// OK prefixes s with a green check mark
+func OK(s string) string { return Green("✓") + " " + s }
+
+// Fail prefixes s with a red cross
+func Fail(s string) string { return Red("✗") + " " + s }
@@ -0,0 +1,32 @@
+//go:build windows
+
+package sysinfo
+
+import (
+	"syscall"
+	"unsafe"
+)
+
+// FreeDiskBytes returns the number of bytes available to the current user
+// on the volume containing path.
+func FreeDiskBytes(path string) (uint64, error) {
+	kernel32 := syscall.NewLazyDLL("kernel32.dll")
+	getDiskFreeSpaceEx := kernel32.NewProc("GetDiskFreeSpaceExW")
+
+	pathPtr, err := syscall.UTF16PtrFromString(path)
+	if err != nil {
+		return 0, err
+	}
+
+	var freeBytesAvailable uint64
+	r, _, callErr := getDiskFreeSpaceEx.Call(
+		uintptr(unsafe.Pointer(pathPtr)),
+		uintptr(unsafe.Pointer(&freeBytesAvailable)),
+		0,
+		0,
+	)
+	if r == 0 {
+		return 0, callErr
+	}
+	return freeBytesAvailable, nil
+}
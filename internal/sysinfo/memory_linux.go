@@ -0,0 +1,105 @@
+//go:build linux
+
+package sysinfo
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+)
+
+// AvailableMemoryBytes returns the memory available for new work: the
+// kernel's MemAvailable estimate, further limited by the cgroup v2 memory
+// limit when running in a constrained container.
+func AvailableMemoryBytes() (uint64, error) {
+	available, err := memAvailable()
+	if err != nil {
+		return 0, err
+	}
+	if headroom, ok := cgroupMemoryHeadroom(); ok && headroom < available {
+		available = headroom
+	}
+	return available, nil
+}
+
+// memAvailable reads MemAvailable from /proc/meminfo
+func memAvailable() (uint64, error) {
+	f, err := os.Open("/proc/meminfo")
+	if err != nil {
+		return 0, err
+	}
+	defer f.Close()
+
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) >= 2 && fields[0] == "MemAvailable:" {
+			kb, err := strconv.ParseUint(fields[1], 10, 64)
+			if err != nil {
+				return 0, fmt.Errorf("invalid MemAvailable: %w", err)
+			}
+			return kb * 1024, nil
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return 0, err
+	}
+	return 0, fmt.Errorf("MemAvailable not found in /proc/meminfo")
+}
+
+// cgroupMemoryHeadroom returns memory.max minus the working set of the
+// process's cgroup v2, if a limit is set. Like the kubelet, the working
+// set is memory.current less inactive_file: memory.current includes page
+// cache the kernel reclaims under pressure, so IO-heavy containers would
+// otherwise look starved.
+func cgroupMemoryHeadroom() (uint64, bool) {
+	read := func(name string) (uint64, bool) {
+		data, err := os.ReadFile("/sys/fs/cgroup/" + name)
+		if err != nil {
+			return 0, false
+		}
+		value, err := strconv.ParseUint(strings.TrimSpace(string(data)), 10, 64)
+		return value, err == nil
+	}
+	limit, ok := read("memory.max") // "max" when unlimited
+	if !ok {
+		return 0, false
+	}
+	current, ok := read("memory.current")
+	if !ok {
+		return 0, false
+	}
+	workingSet := current
+	if inactive, ok := cgroupMemoryStat("inactive_file"); ok {
+		if inactive < workingSet {
+			workingSet -= inactive
+		} else {
+			workingSet = 0
+		}
+	}
+	if workingSet >= limit {
+		return 0, true
+	}
+	return limit - workingSet, true
+}
+
+// cgroupMemoryStat returns a counter from the cgroup v2 memory.stat
+func cgroupMemoryStat(key string) (uint64, bool) {
+	f, err := os.Open("/sys/fs/cgroup/memory.stat")
+	if err != nil {
+		return 0, false
+	}
+	defer f.Close()
+
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) == 2 && fields[0] == key {
+			value, err := strconv.ParseUint(fields[1], 10, 64)
+			return value, err == nil
+		}
+	}
+	return 0, false
+}
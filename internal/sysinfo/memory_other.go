@@ -0,0 +1,13 @@
+//go:build !linux && !windows
+
+package sysinfo
+
+import (
+	"errors"
+	"runtime"
+)
+
+// AvailableMemoryBytes is not implemented on this platform
+func AvailableMemoryBytes() (uint64, error) {
+	return 0, errors.New("available memory is not reported on " + runtime.GOOS)
+}
@@ -0,0 +1,35 @@
+//go:build windows
+
+package sysinfo
+
+import (
+	"syscall"
+	"unsafe"
+)
+
+// memoryStatusEx mirrors the Win32 MEMORYSTATUSEX structure
+type memoryStatusEx struct {
+	Length               uint32
+	MemoryLoad           uint32
+	TotalPhys            uint64
+	AvailPhys            uint64
+	TotalPageFile        uint64
+	AvailPageFile        uint64
+	TotalVirtual         uint64
+	AvailVirtual         uint64
+	AvailExtendedVirtual uint64
+}
+
+// AvailableMemoryBytes returns the physical memory currently available
+func AvailableMemoryBytes() (uint64, error) {
+	kernel32 := syscall.NewLazyDLL("kernel32.dll")
+	globalMemoryStatusEx := kernel32.NewProc("GlobalMemoryStatusEx")
+
+	status := memoryStatusEx{}
+	status.Length = uint32(unsafe.Sizeof(status))
+	r, _, callErr := globalMemoryStatusEx.Call(uintptr(unsafe.Pointer(&status)))
+	if r == 0 {
+		return 0, callErr
+	}
+	return status.AvailPhys, nil
+}
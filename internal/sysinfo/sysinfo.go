@@ -0,0 +1,26 @@
+package sysinfo
+
+import (
+	"os/exec"
+	"runtime"
+)
+
+// KnownRuntimes are the executables probed when reporting agent capabilities
+var KnownRuntimes = []string{"sh", "bash", "python3", "node", "claude", "git"}
+
+// AvailableRuntimes returns the subset of KnownRuntimes found in PATH,
+// mapped to their resolved executable paths.
+func AvailableRuntimes() map[string]string {
+	found := make(map[string]string)
+	for _, name := range KnownRuntimes {
+		if path, err := exec.LookPath(name); err == nil {
+			found[name] = path
+		}
+	}
+	return found
+}
+
+// Platform returns the GOOS/GOARCH pair for the running binary
+func Platform() string {
+	return runtime.GOOS + "/" + runtime.GOARCH
+}
@@ -0,0 +1,72 @@
+package testharness
+
+import (
+	"bytes"
+	"io"
+	"os"
+	"sync"
+	"testing"
+)
+
+// CommandResult is the outcome of a command run with RunCommand
+type CommandResult struct {
+	Stdout string
+	Stderr string
+	Err    error
+}
+
+// runMu serialises RunCommand calls, which swap process-wide state
+var runMu sync.Mutex
+
+// RunCommand runs the CLI in-process with the given arguments and captures
+// what it writes to stdout and stderr. execute is the CLI entry point,
+// normally cmd.Execute; it is passed in so tests inside the cmd package can
+// use the harness without an import cycle.
+//
+// Commands read flags into package variables that persist between runs,
+// so tests should pass every flag they rely on.
+func RunCommand(tb testing.TB, execute func() error, args ...string) CommandResult {
+	tb.Helper()
+	runMu.Lock()
+	defer runMu.Unlock()
+
+	oldArgs, oldStdout, oldStderr := os.Args, os.Stdout, os.Stderr
+	defer func() {
+		os.Args, os.Stdout, os.Stderr = oldArgs, oldStdout, oldStderr
+	}()
+
+	stdout, readStdout := capture(tb)
+	stderr, readStderr := capture(tb)
+	os.Args = append([]string{"kindship"}, args...)
+	os.Stdout, os.Stderr = stdout, stderr
+
+	err := execute()
+	stdout.Close()
+	stderr.Close()
+	return CommandResult{Stdout: readStdout(), Stderr: readStderr(), Err: err}
+}
+
+// Flags returns the connection flags that point a command at the fake API
+func (f *FakeAPI) Flags(agentID string) []string {
+	return []string{"--api-url", f.URL, "--service-key", f.ServiceKey, "--agent-id", agentID}
+}
+
+// capture returns a pipe writer and a function that returns everything
+// written to it once the writer is closed
+func capture(tb testing.TB) (*os.File, func() string) {
+	r, w, err := os.Pipe()
+	if err != nil {
+		tb.Fatalf("failed to create pipe: %v", err)
+	}
+	var buf bytes.Buffer
+	done := make(chan struct{})
+	go func() {
+		io.Copy(&buf, r)
+		r.Close()
+		close(done)
+	}()
+	return w, func() string {
+		<-done
+		return buf.String()
+	}
+}
@@ -0,0 +1,672 @@
+// Package testharness provides an in-memory fake of the Kindship API and
+// helpers to run CLI commands against it, for end-to-end tests of the run,
+// agent loop and process execution paths.
+package testharness
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/kindship-ai/kindship-cli/internal/api"
+	"github.com/kindship-ai/kindship-cli/internal/checksum"
+)
+
+// Entity statuses tracked by the fake API
+const (
+	StatusPending    = "PENDING"
+	StatusInProgress = "IN_PROGRESS"
+	StatusCompleted  = "COMPLETED"
+	StatusFailed     = "FAILED"
+)
+
+// DefaultServiceKey is the service key the fake API accepts unless
+// ServiceKey is changed
+const DefaultServiceKey = "test-service-key"
+
+// Entity is a planning entity held by the fake API
+type Entity struct {
+	api.PlanningEntity
+
+	// Parent is the ID of the ORCHESTRATE entity whose children this entity
+	// belongs to; top-level entities are served to agents by plan/next
+	Parent string
+
+	// Inputs are passed to the entity in addition to the structured
+	// outputs of its labeled dependencies
+	Inputs map[string]interface{}
+}
+
+// Execution is an execution attempt recorded by the fake API
+type Execution struct {
+	ID            string
+	EntityID      string
+	AgentID       string
+	Mode          api.ExecutionMode
+	AttemptNumber int
+	Status        api.ExecutionAttemptStatus
+	StartedAt     time.Time
+
+	// Complete is the request that completed the execution, if any
+	Complete *api.ExecutionCompleteRequest
+
+	// Artifacts maps uploaded artifact names to their contents
+	Artifacts map[string][]byte
+}
+
+// FakeAPI is an httptest server implementing the endpoints the CLI uses to
+// fetch, claim, run and complete work. It is safe for concurrent use.
+type FakeAPI struct {
+	*httptest.Server
+
+	// ServiceKey is the key every request must present
+	ServiceKey string
+
+	// Features are advertised on capability negotiation (all optional
+	// features by default)
+	Features []string
+
+	// ArtifactChunkSize is the part size of chunked artifact uploads
+	ArtifactChunkSize int64
+
+	mu          sync.Mutex
+	entities    map[string]*Entity
+	order       []string
+	secrets     map[string]string
+	executions  []*Execution
+	heartbeats  []api.HeartbeatRequest
+	events      []api.AgentEventRequest
+	escalations []api.EscalationRequest
+	preflights  map[string][]api.PreflightReport
+	workspace   map[string][]byte
+	uploads     map[string]*artifactUpload
+}
+
+// artifactUpload is a chunked artifact upload in progress
+type artifactUpload struct {
+	executionID string
+	name        string
+	parts       map[int][]byte
+}
+
+// NewFakeAPI starts a fake API that is closed when the test finishes
+func NewFakeAPI(tb testing.TB) *FakeAPI {
+	f := &FakeAPI{
+		ServiceKey: DefaultServiceKey,
+		Features: []string{api.FeatureHeartbeats, api.FeatureAgentEvents, api.FeatureArtifacts,
+			api.FeatureEscalation, api.FeatureArtifactChunks, api.FeaturePreflight},
+		ArtifactChunkSize: 1 << 20,
+		entities:          map[string]*Entity{},
+		secrets:           map[string]string{},
+		workspace:         map[string][]byte{},
+		uploads:           map[string]*artifactUpload{},
+		preflights:        map[string][]api.PreflightReport{},
+	}
+	f.Server = httptest.NewServer(http.HandlerFunc(f.serveHTTP))
+	tb.Cleanup(f.Close)
+	return f
+}
+
+// AddEntity adds an entity in PENDING status unless it sets another.
+// Entities are served by plan/next in the order they were added.
+func (f *FakeAPI) AddEntity(e Entity) *Entity {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if e.ID == "" {
+		e.ID = fmt.Sprintf("entity-%d", len(f.order)+1)
+	}
+	if e.Type == "" {
+		e.Type = "TASK"
+	}
+	if e.Status == "" {
+		e.Status = StatusPending
+	}
+	f.entities[e.ID] = &e
+	f.order = append(f.order, e.ID)
+	return &e
+}
+
+// SetSecret sets a secret returned to every agent
+func (f *FakeAPI) SetSecret(name, value string) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.secrets[name] = value
+}
+
+// EntityStatus returns the current status of an entity
+func (f *FakeAPI) EntityStatus(id string) string {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if e, ok := f.entities[id]; ok {
+		return e.Status
+	}
+	return ""
+}
+
+// SetEntityStatus changes the status of an entity, as a remote agent
+// working on it would
+func (f *FakeAPI) SetEntityStatus(id, status string) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if e, ok := f.entities[id]; ok {
+		e.Status = status
+	}
+}
+
+// Executions returns a copy of the recorded executions in start order
+func (f *FakeAPI) Executions() []Execution {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	out := make([]Execution, len(f.executions))
+	for i, e := range f.executions {
+		out[i] = *e
+	}
+	return out
+}
+
+// ExecutionsFor returns the recorded executions of one entity
+func (f *FakeAPI) ExecutionsFor(entityID string) []Execution {
+	var out []Execution
+	for _, e := range f.Executions() {
+		if e.EntityID == entityID {
+			out = append(out, e)
+		}
+	}
+	return out
+}
+
+// Heartbeats returns the heartbeats received so far
+func (f *FakeAPI) Heartbeats() []api.HeartbeatRequest {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return append([]api.HeartbeatRequest(nil), f.heartbeats...)
+}
+
+// Events returns the agent lifecycle events received so far
+func (f *FakeAPI) Events() []api.AgentEventRequest {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return append([]api.AgentEventRequest(nil), f.events...)
+}
+
+// Escalations returns the ASK_USER escalations received so far
+func (f *FakeAPI) Escalations() []api.EscalationRequest {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return append([]api.EscalationRequest(nil), f.escalations...)
+}
+
+// Preflights returns the pre-flight failures reported for an entity
+func (f *FakeAPI) Preflights(entityID string) []api.PreflightReport {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return append([]api.PreflightReport(nil), f.preflights[entityID]...)
+}
+
+// CancelExecution marks a running execution as cancelled, as the UI does
+func (f *FakeAPI) CancelExecution(id string) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if exec := f.execution(id); exec != nil {
+		exec.Status = api.ExecutionAttemptStatusCancelled
+	}
+}
+
+func (f *FakeAPI) serveHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Header.Get("X-Kindship-Service-Key") != f.ServiceKey {
+		writeJSON(w, http.StatusUnauthorized, map[string]string{"error": "invalid service key"})
+		return
+	}
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	parts := strings.Split(strings.Trim(r.URL.Path, "/"), "/")
+	switch {
+	case match(parts, "api", "agent-containers", "*", "secrets") && r.Method == http.MethodGet:
+		writeJSON(w, http.StatusOK, api.SecretsResponse{Env: f.secrets})
+	case match(parts, "api", "planning", "entity", "*", "execute"):
+		f.handleExecute(w, parts[3])
+	case match(parts, "api", "planning", "entity", "*", "preflight") && r.Method == http.MethodPost:
+		var report api.PreflightReport
+		if !readJSON(w, r, &report) {
+			return
+		}
+		f.preflights[parts[3]] = append(f.preflights[parts[3]], report)
+		writeJSON(w, http.StatusOK, api.PreflightReportResponse{Success: true, ID: fmt.Sprintf("preflight-%d", len(f.preflights[parts[3]]))})
+	case match(parts, "api", "planning", "execution", "start") && r.Method == http.MethodPost:
+		f.handleStart(w, r)
+	case match(parts, "api", "planning", "execution", "*", "complete") && r.Method == http.MethodPost:
+		f.handleComplete(w, r, parts[3])
+	case match(parts, "api", "planning", "execution", "*", "artifacts") && r.Method == http.MethodPost:
+		f.handleArtifact(w, r, parts[3])
+	case match(parts, "api", "planning", "execution", "*", "artifacts", "uploads") && r.Method == http.MethodPost:
+		f.handleUploadStart(w, r, parts[3])
+	case match(parts, "api", "planning", "execution", "*", "artifacts", "uploads", "*", "parts", "*") && r.Method == http.MethodPut:
+		f.handleUploadPart(w, r, parts[6], parts[8])
+	case match(parts, "api", "planning", "execution", "*", "artifacts", "uploads", "*", "complete") && r.Method == http.MethodPost:
+		f.handleUploadComplete(w, r, parts[6])
+	case match(parts, "api", "planning", "execution", "*", "escalate") && r.Method == http.MethodPost:
+		var req api.EscalationRequest
+		if !readJSON(w, r, &req) {
+			return
+		}
+		f.escalations = append(f.escalations, req)
+		writeJSON(w, http.StatusOK, api.EscalationResponse{Success: true, Priority: "high"})
+	case match(parts, "api", "planning", "execution", "*") && r.Method == http.MethodGet:
+		exec := f.execution(parts[3])
+		if exec == nil {
+			writeJSON(w, http.StatusNotFound, map[string]string{"error": "execution not found"})
+			return
+		}
+		writeJSON(w, http.StatusOK, api.ExecutionStatusResponse{ExecutionID: exec.ID, Status: exec.Status})
+	case match(parts, "api", "cli", "plan", "next") && r.Method == http.MethodGet:
+		f.handleNext(w, r)
+	case match(parts, "api", "cli", "entity", "*", "attempts") && r.Method == http.MethodGet:
+		f.handleAttempts(w, parts[3], r.URL.Query().Get("include") == "outputs")
+	case match(parts, "api", "cli", "agent", "queue") && r.Method == http.MethodGet:
+		f.handleQueue(w, r)
+	case match(parts, "api", "cli", "agent", "recover-runs"):
+		writeJSON(w, http.StatusOK, api.RecoverRunsResponse{ResumedRuns: []api.ResumedRun{}})
+	case match(parts, "api", "cli", "agent", "*", "workspace") && r.Method == http.MethodPut:
+		data, err := io.ReadAll(r.Body)
+		if err != nil {
+			writeJSON(w, http.StatusBadRequest, map[string]string{"error": err.Error()})
+			return
+		}
+		f.workspace[parts[3]+"/"+r.URL.Query().Get("path")] = data
+		writeJSON(w, http.StatusOK, map[string]bool{"success": true})
+	case match(parts, "api", "cli", "agent", "*", "workspace") && r.Method == http.MethodGet:
+		data, ok := f.workspace[parts[3]+"/"+r.URL.Query().Get("path")]
+		if !ok {
+			writeJSON(w, http.StatusNotFound, map[string]string{"error": "no archive stored"})
+			return
+		}
+		w.Header().Set("Content-Type", "application/gzip")
+		w.Write(data)
+	case match(parts, "api", "cli", "capabilities") && r.Method == http.MethodPost:
+		writeJSON(w, http.StatusOK, api.Capabilities{APIVersion: "fake", Features: f.Features})
+	case match(parts, "api", "cli", "agent", "heartbeat") && r.Method == http.MethodPost:
+		var req api.HeartbeatRequest
+		if !readJSON(w, r, &req) {
+			return
+		}
+		f.heartbeats = append(f.heartbeats, req)
+		writeJSON(w, http.StatusOK, api.HeartbeatResponse{Success: true})
+	case match(parts, "api", "cli", "agent", "events") && r.Method == http.MethodPost:
+		var req api.AgentEventRequest
+		if !readJSON(w, r, &req) {
+			return
+		}
+		f.events = append(f.events, req)
+		writeJSON(w, http.StatusOK, api.AgentEventResponse{Success: true})
+	default:
+		writeJSON(w, http.StatusNotFound, map[string]string{"error": "not found: " + r.Method + " " + r.URL.Path})
+	}
+}
+
+func (f *FakeAPI) handleExecute(w http.ResponseWriter, id string) {
+	e, ok := f.entities[id]
+	if !ok {
+		writeJSON(w, http.StatusNotFound, map[string]string{"error": "entity not found"})
+		return
+	}
+	pending := f.pendingDependencies(e)
+	writeJSON(w, http.StatusOK, api.EntityExecuteResponse{
+		Entity:             e.PlanningEntity,
+		DependenciesStatus: api.DependencyStatus{AllMet: len(pending) == 0, Pending: pending},
+		Inputs:             f.inputs(e),
+	})
+}
+
+func (f *FakeAPI) handleStart(w http.ResponseWriter, r *http.Request) {
+	var req api.ExecutionStartRequest
+	if !readJSON(w, r, &req) {
+		return
+	}
+	e, ok := f.entities[req.EntityID]
+	if !ok {
+		writeJSON(w, http.StatusNotFound, map[string]string{"error": "entity not found"})
+		return
+	}
+
+	attempt := 1
+	for _, exec := range f.executions {
+		if exec.EntityID == e.ID && exec.Mode == req.ExecutionMode {
+			attempt++
+		}
+	}
+	exec := &Execution{
+		ID:            fmt.Sprintf("execution-%d", len(f.executions)+1),
+		EntityID:      e.ID,
+		AgentID:       req.AgentID,
+		Mode:          req.ExecutionMode,
+		AttemptNumber: attempt,
+		Status:        api.ExecutionAttemptStatusRunning,
+		StartedAt:     time.Now(),
+		Artifacts:     map[string][]byte{},
+	}
+	f.executions = append(f.executions, exec)
+	e.Status = StatusInProgress
+
+	writeJSON(w, http.StatusOK, api.ExecutionStartResponse{
+		ExecutionID:   exec.ID,
+		AttemptNumber: exec.AttemptNumber,
+		Inputs:        f.inputs(e),
+	})
+}
+
+func (f *FakeAPI) handleComplete(w http.ResponseWriter, r *http.Request, id string) {
+	var req api.ExecutionCompleteRequest
+	if !readJSON(w, r, &req) {
+		return
+	}
+	exec := f.execution(id)
+	if exec == nil {
+		writeJSON(w, http.StatusNotFound, map[string]string{"error": "execution not found"})
+		return
+	}
+	exec.Status = req.Status
+	exec.Complete = &req
+
+	e := f.entities[exec.EntityID]
+	switch req.Status {
+	case api.ExecutionAttemptStatusSuccess:
+		e.Status = StatusCompleted
+	case api.ExecutionAttemptStatusFailed:
+		e.Status = StatusFailed
+	default:
+		e.Status = StatusPending
+	}
+	writeJSON(w, http.StatusOK, api.ExecutionCompleteResponse{Success: true})
+}
+
+func (f *FakeAPI) handleArtifact(w http.ResponseWriter, r *http.Request, id string) {
+	exec := f.execution(id)
+	if exec == nil {
+		writeJSON(w, http.StatusNotFound, map[string]string{"error": "execution not found"})
+		return
+	}
+	data, err := io.ReadAll(r.Body)
+	if err != nil {
+		writeJSON(w, http.StatusBadRequest, map[string]string{"error": err.Error()})
+		return
+	}
+	if !checksumMatches(w, r, data) {
+		return
+	}
+	name := r.URL.Query().Get("name")
+	exec.Artifacts[name] = data
+	writeJSON(w, http.StatusOK, api.ArtifactUploadResponse{
+		ID:   fmt.Sprintf("%s-artifact-%d", id, len(exec.Artifacts)),
+		Name: name,
+	})
+}
+
+func (f *FakeAPI) handleUploadStart(w http.ResponseWriter, r *http.Request, id string) {
+	if f.execution(id) == nil {
+		writeJSON(w, http.StatusNotFound, map[string]string{"error": "execution not found"})
+		return
+	}
+	var req api.ArtifactUploadStartRequest
+	if !readJSON(w, r, &req) {
+		return
+	}
+	uploadID := fmt.Sprintf("upload-%d", len(f.uploads)+1)
+	f.uploads[uploadID] = &artifactUpload{executionID: id, name: req.Name, parts: map[int][]byte{}}
+	writeJSON(w, http.StatusOK, api.ArtifactUploadSession{UploadID: uploadID, ChunkSize: f.ArtifactChunkSize})
+}
+
+func (f *FakeAPI) handleUploadPart(w http.ResponseWriter, r *http.Request, uploadID, part string) {
+	upload, ok := f.uploads[uploadID]
+	if !ok {
+		writeJSON(w, http.StatusNotFound, map[string]string{"error": "upload not found"})
+		return
+	}
+	n, err := strconv.Atoi(part)
+	if err != nil || n < 1 {
+		writeJSON(w, http.StatusBadRequest, map[string]string{"error": "invalid part number"})
+		return
+	}
+	data, err := io.ReadAll(r.Body)
+	if err != nil {
+		writeJSON(w, http.StatusBadRequest, map[string]string{"error": err.Error()})
+		return
+	}
+	if !checksumMatches(w, r, data) {
+		return
+	}
+	upload.parts[n] = data
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (f *FakeAPI) handleUploadComplete(w http.ResponseWriter, r *http.Request, uploadID string) {
+	upload, ok := f.uploads[uploadID]
+	if !ok {
+		writeJSON(w, http.StatusNotFound, map[string]string{"error": "upload not found"})
+		return
+	}
+	var req api.ArtifactUploadCompleteRequest
+	if !readJSON(w, r, &req) {
+		return
+	}
+	var data []byte
+	for n := 1; n <= req.Parts; n++ {
+		part, ok := upload.parts[n]
+		if !ok {
+			writeJSON(w, http.StatusBadRequest, map[string]string{"error": fmt.Sprintf("part %d missing", n)})
+			return
+		}
+		data = append(data, part...)
+	}
+	if checksum.SHA256(data) != req.SHA256 {
+		writeJSON(w, http.StatusBadRequest, map[string]string{"error": "checksum mismatch"})
+		return
+	}
+	delete(f.uploads, uploadID)
+	exec := f.execution(upload.executionID)
+	exec.Artifacts[upload.name] = data
+	writeJSON(w, http.StatusOK, api.ArtifactUploadResponse{
+		ID:   fmt.Sprintf("%s-artifact-%d", exec.ID, len(exec.Artifacts)),
+		Name: upload.name,
+	})
+}
+
+// checksumMatches rejects a body that does not match its
+// X-Kindship-Content-SHA256 header, when one is sent
+func checksumMatches(w http.ResponseWriter, r *http.Request, data []byte) bool {
+	want := r.Header.Get("X-Kindship-Content-SHA256")
+	if want != "" && checksum.SHA256(data) != want {
+		writeJSON(w, http.StatusBadRequest, map[string]string{"error": "checksum mismatch"})
+		return false
+	}
+	return true
+}
+
+// handleNext serves the first runnable PENDING entity: a child of
+// entity_uuid in orchestrate mode, otherwise a top-level entity
+func (f *FakeAPI) handleNext(w http.ResponseWriter, r *http.Request) {
+	parent := ""
+	if r.URL.Query().Get("mode") == "orchestrate" {
+		parent = r.URL.Query().Get("entity_uuid")
+	}
+
+	resp := api.PlanNextResponse{}
+	for _, id := range f.order {
+		e := f.entities[id]
+		if e.Parent != parent || e.Status == StatusCompleted || e.Status == StatusFailed {
+			continue
+		}
+		if resp.Task == nil && e.Status == StatusPending && len(f.pendingDependencies(e)) == 0 {
+			resp.Task = taskInfo(e)
+			continue
+		}
+		resp.PendingCount++
+	}
+	writeJSON(w, http.StatusOK, resp)
+}
+
+// handleAttempts lists the execution attempts of an entity
+func (f *FakeAPI) handleAttempts(w http.ResponseWriter, id string, withOutputs bool) {
+	attempts := []api.ExecutionAttempt{}
+	for _, exec := range f.executions {
+		if exec.EntityID != id {
+			continue
+		}
+		attempt := api.ExecutionAttempt{
+			ID:            exec.ID,
+			EntityID:      exec.EntityID,
+			AttemptNumber: exec.AttemptNumber,
+			Status:        exec.Status,
+			ExecutionMode: exec.Mode,
+			AgentID:       exec.AgentID,
+			StartedAt:     exec.StartedAt,
+		}
+		if exec.Complete != nil {
+			attempt.FailureReason = exec.Complete.FailureReason
+			if withOutputs {
+				attempt.Outputs = exec.Complete.Outputs
+			}
+		}
+		attempts = append(attempts, attempt)
+	}
+	writeJSON(w, http.StatusOK, api.ListAttemptsResponse{Attempts: attempts})
+}
+
+// handleQueue lists the PENDING entities that plan/next would serve, in
+// order, and those blocked on unmet dependencies
+func (f *FakeAPI) handleQueue(w http.ResponseWriter, r *http.Request) {
+	resp := map[string]interface{}{"agent_id": r.URL.Query().Get("agent_id")}
+	runnable := []map[string]interface{}{}
+	blocked := []map[string]interface{}{}
+	for _, id := range f.order {
+		e := f.entities[id]
+		if e.Status != StatusPending {
+			continue
+		}
+		task := map[string]interface{}{
+			"id":             e.ID,
+			"title":          e.Title,
+			"execution_mode": e.ExecutionMode,
+			"status":         e.Status,
+		}
+		if parent, ok := f.entities[e.Parent]; ok {
+			task["parent_title"] = parent.Title
+		}
+		pending := f.pendingDependencies(e)
+		if len(pending) == 0 {
+			runnable = append(runnable, task)
+			continue
+		}
+		var waiting []map[string]interface{}
+		for _, dep := range pending {
+			d := map[string]interface{}{"label": dep.Label, "entity_id": dep.EntityID}
+			if de, ok := f.entities[dep.EntityID]; ok {
+				d["title"], d["status"] = de.Title, de.Status
+			}
+			waiting = append(waiting, d)
+		}
+		task["waiting_on"] = waiting
+		blocked = append(blocked, task)
+	}
+	resp["runnable"], resp["blocked"] = runnable, blocked
+	writeJSON(w, http.StatusOK, resp)
+}
+
+// pendingDependencies returns the dependencies of e not yet completed
+func (f *FakeAPI) pendingDependencies(e *Entity) []api.PendingDependency {
+	var pending []api.PendingDependency
+	for _, id := range e.Dependencies {
+		if dep, ok := f.entities[id]; !ok || dep.Status != StatusCompleted {
+			pending = append(pending, api.PendingDependency{EntityID: id})
+		}
+	}
+	for label, id := range e.DependenciesLabeled {
+		if dep, ok := f.entities[id]; !ok || dep.Status != StatusCompleted {
+			pending = append(pending, api.PendingDependency{Label: label, EntityID: id})
+		}
+	}
+	return pending
+}
+
+// inputs returns the entity's own inputs plus the structured output of
+// the last successful execution of each labeled dependency
+func (f *FakeAPI) inputs(e *Entity) map[string]interface{} {
+	inputs := map[string]interface{}{}
+	for k, v := range e.Inputs {
+		inputs[k] = v
+	}
+	for label, id := range e.DependenciesLabeled {
+		for _, exec := range f.executions {
+			if exec.EntityID == id && exec.Status == api.ExecutionAttemptStatusSuccess && exec.Complete.Outputs != nil {
+				inputs[label] = exec.Complete.Outputs.Structured
+			}
+		}
+	}
+	return inputs
+}
+
+func (f *FakeAPI) execution(id string) *Execution {
+	for _, exec := range f.executions {
+		if exec.ID == id {
+			return exec
+		}
+	}
+	return nil
+}
+
+// taskInfo converts an entity to its plan/next representation
+func taskInfo(e *Entity) *api.TaskInfo {
+	task := &api.TaskInfo{
+		ID:                  e.ID,
+		Title:               e.Title,
+		Description:         e.Description,
+		InputSchema:         e.InputSchema,
+		OutputSchema:        e.OutputSchema,
+		ExecutionMode:       string(e.ExecutionMode),
+		Code:                e.Code,
+		Boundaries:          e.Boundaries,
+		Dependencies:        e.Dependencies,
+		DependenciesLabeled: e.DependenciesLabeled,
+		SequenceOrder:       e.SequenceOrder,
+	}
+	if data, err := json.Marshal(e.SuccessCriteria); err == nil {
+		json.Unmarshal(data, &task.SuccessCriteria)
+	}
+	return task
+}
+
+// match reports whether path parts equal pattern, where "*" matches any
+// single part
+func match(parts []string, pattern ...string) bool {
+	if len(parts) != len(pattern) {
+		return false
+	}
+	for i, p := range pattern {
+		if p != "*" && p != parts[i] {
+			return false
+		}
+	}
+	return true
+}
+
+func readJSON(w http.ResponseWriter, r *http.Request, v interface{}) bool {
+	if err := json.NewDecoder(r.Body).Decode(v); err != nil {
+		writeJSON(w, http.StatusBadRequest, map[string]string{"error": "invalid JSON: " + err.Error()})
+		return false
+	}
+	return true
+}
+
+func writeJSON(w http.ResponseWriter, status int, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(v)
+}
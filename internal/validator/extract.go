@@ -0,0 +1,142 @@
+package validator
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+)
+
+// Extraction strategies accepted in boundaries.output_extraction
+const (
+	ExtractFirst      = "first"
+	ExtractLast       = "last"
+	ExtractLargest    = "largest"
+	ExtractFencedOnly = "fenced-only"
+)
+
+// ExtractionStrategies lists the valid extraction strategies
+var ExtractionStrategies = []string{ExtractFirst, ExtractLast, ExtractLargest, ExtractFencedOnly}
+
+// jsonCandidate is a JSON value found in output with its source text
+type jsonCandidate struct {
+	value interface{}
+	size  int
+}
+
+// ExtractJSONFromOutput extracts structured output from stdout with the
+// default "first" strategy
+func ExtractJSONFromOutput(stdout string) (map[string]interface{}, error) {
+	return ExtractJSONFromOutputWith(stdout, ExtractFirst)
+}
+
+// ExtractJSONFromOutputWith extracts structured output from stdout.
+// JSON in markdown code fences is preferred (```json fences over untagged
+// ones), then output that is entirely JSON, then raw JSON objects found
+// among other text, and raw arrays only when there are no objects, as
+// bracketed log output often parses as one. When several values are found
+// the strategy picks the first, the last or the largest; "fenced-only"
+// only accepts the first fenced block. Non-object values are wrapped with
+// WrapStructured.
+func ExtractJSONFromOutputWith(stdout, strategy string) (map[string]interface{}, error) {
+	stdout = strings.TrimSpace(stdout)
+
+	candidates := fencedCandidates(stdout, true)
+	if len(candidates) == 0 {
+		candidates = fencedCandidates(stdout, false)
+	}
+	if strategy == ExtractFencedOnly {
+		if len(candidates) == 0 {
+			return nil, fmt.Errorf("no fenced JSON block found in output")
+		}
+		return WrapStructured(candidates[0].value), nil
+	}
+
+	if len(candidates) == 0 {
+		// Output that is a single JSON value, including a scalar
+		var whole interface{}
+		if err := json.Unmarshal([]byte(stdout), &whole); err == nil && whole != nil {
+			return WrapStructured(whole), nil
+		}
+		candidates = rawCandidates(stdout)
+	}
+	if len(candidates) == 0 {
+		return nil, fmt.Errorf("no JSON object or array found in output")
+	}
+
+	picked := candidates[0]
+	switch strategy {
+	case ExtractLast:
+		picked = candidates[len(candidates)-1]
+	case ExtractLargest:
+		for _, c := range candidates[1:] {
+			if c.size > picked.size {
+				picked = c
+			}
+		}
+	}
+	return WrapStructured(picked.value), nil
+}
+
+// fencedCandidates returns the JSON values of markdown code fences in
+// output. With tagged set only ```json fences are considered.
+func fencedCandidates(output string, tagged bool) []jsonCandidate {
+	var candidates []jsonCandidate
+	rest := output
+	for {
+		start := strings.Index(rest, "```")
+		if start == -1 {
+			break
+		}
+		body := rest[start+3:]
+		end := strings.Index(body, "```")
+		if end == -1 {
+			break
+		}
+		rest = body[end+3:]
+		body = body[:end]
+
+		// Split off the language identifier
+		lang := ""
+		if newline := strings.Index(body, "\n"); newline != -1 {
+			lang, body = strings.TrimSpace(body[:newline]), body[newline+1:]
+		}
+		if tagged && lang != "json" {
+			continue
+		}
+
+		var value interface{}
+		text := strings.TrimSpace(body)
+		if err := json.Unmarshal([]byte(text), &value); err == nil && value != nil {
+			candidates = append(candidates, jsonCandidate{value: value, size: len(text)})
+		}
+	}
+	return candidates
+}
+
+// rawCandidates returns the top-level JSON objects found in output, e.g.
+// among log lines, or its top-level arrays if it has no objects. Bracketed
+// text that is not JSON, such as "[INFO]", is skipped.
+func rawCandidates(output string) []jsonCandidate {
+	var objects, arrays []jsonCandidate
+	for i := 0; i < len(output); i++ {
+		if output[i] != '{' && output[i] != '[' {
+			continue
+		}
+		dec := json.NewDecoder(strings.NewReader(output[i:]))
+		var value interface{}
+		if err := dec.Decode(&value); err != nil {
+			continue
+		}
+		size := int(dec.InputOffset())
+		if output[i] == '{' {
+			objects = append(objects, jsonCandidate{value: value, size: size})
+		} else {
+			arrays = append(arrays, jsonCandidate{value: value, size: size})
+		}
+		i += size - 1
+	}
+	if len(objects) > 0 {
+		return objects
+	}
+	return arrays
+}
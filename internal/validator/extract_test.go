@@ -0,0 +1,41 @@
+package validator
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestExtractJSONFromOutputRaw(t *testing.T) {
+	tests := []struct {
+		name   string
+		stdout string
+		want   map[string]interface{}
+	}{
+		{
+			name:   "object after bracketed array in logs",
+			stdout: "[1, 2, 3] items scanned\nresult: {\"count\": 3}",
+			want:   map[string]interface{}{"count": float64(3)},
+		},
+		{
+			name:   "object after log level tags",
+			stdout: "[INFO] starting\n[\"a\"] queued\n{\"ok\": true}\n[INFO] done",
+			want:   map[string]interface{}{"ok": true},
+		},
+		{
+			name:   "array when there is no object",
+			stdout: "[INFO] listing\n[\"a\", \"b\"]",
+			want:   map[string]interface{}{StructuredValueKey: []interface{}{"a", "b"}},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ExtractJSONFromOutput(tt.stdout)
+			if err != nil {
+				t.Fatalf("ExtractJSONFromOutput() error = %v", err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("ExtractJSONFromOutput() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,164 @@
+package validator
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// pathStep is one step of a jq-style path: a key, an index or an
+// iteration over every element
+type pathStep struct {
+	key     string
+	index   int
+	isKey   bool
+	iterate bool
+}
+
+// ParsePath checks the syntax of a jq-style path without evaluating it
+func ParsePath(expr string) error {
+	_, err := parsePath(expr)
+	return err
+}
+
+// ExtractPath evaluates a jq-style path against a structured output and
+// returns the values it selects. Supported steps are .key, ["key"], [N]
+// (negative counts from the end) and [] (every element of an array or
+// object), e.g. .items[].name or .["odd key"][-1]; "." selects the whole
+// value. As in jq, a missing key or index yields null. Outputs wrapped
+// for transport are unwrapped along the way.
+func ExtractPath(value interface{}, expr string) ([]interface{}, error) {
+	steps, err := parsePath(expr)
+	if err != nil {
+		return nil, err
+	}
+
+	values := []interface{}{unwrap(value)}
+	for _, step := range steps {
+		next := []interface{}{}
+		for _, v := range values {
+			selected, err := step.apply(v)
+			if err != nil {
+				return nil, fmt.Errorf("%s: %w", expr, err)
+			}
+			for _, s := range selected {
+				next = append(next, unwrap(s))
+			}
+		}
+		values = next
+	}
+	return values, nil
+}
+
+func parsePath(expr string) ([]pathStep, error) {
+	rest := strings.TrimSpace(expr)
+	if rest == "" || rest == "." {
+		return nil, nil
+	}
+	if rest[0] != '.' && rest[0] != '[' {
+		return nil, fmt.Errorf("%s: paths start with . or [", expr)
+	}
+
+	var steps []pathStep
+	for rest != "" {
+		switch rest[0] {
+		case '.':
+			rest = rest[1:]
+			if rest == "" || rest[0] == '[' {
+				// ".[0]" is the same as "[0]"
+				continue
+			}
+			end := strings.IndexAny(rest, ".[")
+			if end < 0 {
+				end = len(rest)
+			}
+			if end == 0 {
+				return nil, fmt.Errorf("%s: empty key", expr)
+			}
+			steps = append(steps, pathStep{key: rest[:end], isKey: true})
+			rest = rest[end:]
+		case '[':
+			end := strings.IndexByte(rest, ']')
+			if end < 0 {
+				return nil, fmt.Errorf("%s: unterminated [", expr)
+			}
+			inner := strings.TrimSpace(rest[1:end])
+			rest = rest[end+1:]
+			if inner == "" {
+				steps = append(steps, pathStep{iterate: true})
+				continue
+			}
+			if key, err := strconv.Unquote(inner); err == nil {
+				steps = append(steps, pathStep{key: key, isKey: true})
+				continue
+			}
+			index, err := strconv.Atoi(inner)
+			if err != nil {
+				return nil, fmt.Errorf("%s: [%s] must be an index, a quoted key or empty", expr, inner)
+			}
+			steps = append(steps, pathStep{index: index})
+		default:
+			return nil, fmt.Errorf("%s: unexpected %q", expr, rest[0])
+		}
+	}
+	return steps, nil
+}
+
+// apply returns the values step selects from value
+func (s pathStep) apply(value interface{}) ([]interface{}, error) {
+	switch v := value.(type) {
+	case nil:
+		if s.iterate {
+			return nil, fmt.Errorf("cannot iterate over null")
+		}
+		return []interface{}{nil}, nil
+	case map[string]interface{}:
+		switch {
+		case s.iterate:
+			values := make([]interface{}, 0, len(v))
+			for _, key := range sortedKeys(v) {
+				values = append(values, v[key])
+			}
+			return values, nil
+		case s.isKey:
+			return []interface{}{v[s.key]}, nil
+		default:
+			return nil, fmt.Errorf("cannot index an object with [%d]", s.index)
+		}
+	case []interface{}:
+		switch {
+		case s.iterate:
+			return v, nil
+		case s.isKey:
+			return nil, fmt.Errorf("cannot read key %q of an array", s.key)
+		}
+		index := s.index
+		if index < 0 {
+			index += len(v)
+		}
+		if index < 0 || index >= len(v) {
+			return []interface{}{nil}, nil
+		}
+		return []interface{}{v[index]}, nil
+	default:
+		return nil, fmt.Errorf("cannot descend into %T", value)
+	}
+}
+
+// unwrap unwraps an object wrapped for transport
+func unwrap(value interface{}) interface{} {
+	if m, ok := value.(map[string]interface{}); ok {
+		return UnwrapStructured(m)
+	}
+	return value
+}
+
+func sortedKeys(m map[string]interface{}) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
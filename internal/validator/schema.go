@@ -3,6 +3,7 @@ package validator
 import (
 	"encoding/json"
 	"fmt"
+	"sort"
 	"strings"
 
 	"github.com/xeipuuv/gojsonschema"
@@ -33,14 +34,16 @@ func ValidateInputs(inputs map[string]interface{}, schema map[string]interface{}
 	return nil
 }
 
-// ValidateOutputs validates outputs against output_schema
+// ValidateOutputs validates outputs against output_schema. Wrapped
+// non-object outputs are validated as their original value, so a schema
+// may declare an array or scalar root.
 func ValidateOutputs(outputs map[string]interface{}, schema map[string]interface{}) error {
 	if schema == nil || len(schema) == 0 {
 		return nil // No schema = no validation
 	}
 
 	schemaLoader := gojsonschema.NewGoLoader(schema)
-	dataLoader := gojsonschema.NewGoLoader(outputs)
+	dataLoader := gojsonschema.NewGoLoader(UnwrapStructured(outputs))
 
 	result, err := gojsonschema.Validate(schemaLoader, dataLoader)
 	if err != nil {
@@ -58,24 +61,27 @@ func ValidateOutputs(outputs map[string]interface{}, schema map[string]interface
 	return nil
 }
 
-// GetInputLabels returns the list of labels from an inputs map
+// GetInputLabels returns the labels of an inputs map, sorted so logs and
+// prompts list them in the same order on every attempt
 func GetInputLabels(inputs map[string]interface{}) []string {
 	labels := make([]string, 0, len(inputs))
 	for label := range inputs {
 		labels = append(labels, label)
 	}
+	sort.Strings(labels)
 	return labels
 }
 
-// FormatInputsForDisplay formats inputs for logging/display
+// FormatInputsForDisplay formats inputs for logging/display, one per line
+// in label order
 func FormatInputsForDisplay(inputs map[string]interface{}) string {
 	if len(inputs) == 0 {
 		return "No inputs"
 	}
 
 	parts := make([]string, 0, len(inputs))
-	for label, value := range inputs {
-		jsonBytes, err := json.Marshal(value)
+	for _, label := range GetInputLabels(inputs) {
+		jsonBytes, err := json.Marshal(inputs[label])
 		if err != nil {
 			parts = append(parts, fmt.Sprintf("%s: [error marshaling]", label))
 			continue
@@ -89,68 +95,3 @@ func FormatInputsForDisplay(inputs map[string]interface{}) string {
 	}
 	return strings.Join(parts, "\n  ")
 }
-
-// ExtractJSONFromOutput attempts to extract a JSON object from stdout
-// It looks for JSON blocks in markdown code fences or raw JSON objects
-func ExtractJSONFromOutput(stdout string) (map[string]interface{}, error) {
-	stdout = strings.TrimSpace(stdout)
-
-	// Try to find JSON in markdown code fence
-	jsonStart := strings.Index(stdout, "```json")
-	if jsonStart != -1 {
-		jsonStart += 7 // Skip past ```json
-		jsonEnd := strings.Index(stdout[jsonStart:], "```")
-		if jsonEnd != -1 {
-			stdout = strings.TrimSpace(stdout[jsonStart : jsonStart+jsonEnd])
-		}
-	} else {
-		// Try generic code fence
-		codeStart := strings.Index(stdout, "```")
-		if codeStart != -1 {
-			codeStart += 3
-			// Skip language identifier if present
-			newline := strings.Index(stdout[codeStart:], "\n")
-			if newline != -1 {
-				codeStart += newline + 1
-			}
-			codeEnd := strings.Index(stdout[codeStart:], "```")
-			if codeEnd != -1 {
-				stdout = strings.TrimSpace(stdout[codeStart : codeStart+codeEnd])
-			}
-		}
-	}
-
-	// Try to find a JSON object (starts with { ends with })
-	braceStart := strings.Index(stdout, "{")
-	if braceStart == -1 {
-		return nil, fmt.Errorf("no JSON object found in output")
-	}
-
-	// Find matching closing brace
-	braceCount := 0
-	braceEnd := -1
-	for i := braceStart; i < len(stdout); i++ {
-		if stdout[i] == '{' {
-			braceCount++
-		} else if stdout[i] == '}' {
-			braceCount--
-			if braceCount == 0 {
-				braceEnd = i + 1
-				break
-			}
-		}
-	}
-
-	if braceEnd == -1 {
-		return nil, fmt.Errorf("no matching closing brace found")
-	}
-
-	jsonStr := stdout[braceStart:braceEnd]
-
-	var result map[string]interface{}
-	if err := json.Unmarshal([]byte(jsonStr), &result); err != nil {
-		return nil, fmt.Errorf("failed to parse JSON: %w", err)
-	}
-
-	return result, nil
-}
@@ -0,0 +1,30 @@
+package validator
+
+// StructuredValueKey is the key under which a structured output that is
+// not a JSON object (an array, string, number or boolean) is wrapped for
+// transport, since ExecutionOutputs.Structured is always an object:
+//
+//	[1, 2, 3]  →  {"$value": [1, 2, 3]}
+const StructuredValueKey = "$value"
+
+// WrapStructured returns a structured output as an object, wrapping
+// non-object values under StructuredValueKey. Returns nil for null.
+func WrapStructured(value interface{}) map[string]interface{} {
+	switch v := value.(type) {
+	case nil:
+		return nil
+	case map[string]interface{}:
+		return v
+	default:
+		return map[string]interface{}{StructuredValueKey: v}
+	}
+}
+
+// UnwrapStructured reverses WrapStructured, returning the original value
+// of a wrapped output and any other object unchanged
+func UnwrapStructured(structured map[string]interface{}) interface{} {
+	if value, ok := structured[StructuredValueKey]; ok && len(structured) == 1 {
+		return value
+	}
+	return structured
+}
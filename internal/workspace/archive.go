@@ -0,0 +1,225 @@
+// Package workspace packs agent workspace directories into archives and
+// keeps them in the platform's artifact store or an S3-compatible bucket,
+// so state survives container restarts.
+package workspace
+
+import (
+	"archive/tar"
+	"compress/gzip"
+	"fmt"
+	"io"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+)
+
+// Pack writes dir as a gzipped tar to w and returns the number of files
+// in it. Paths in the archive are relative to dir. Regular files,
+// directories and symlinks are kept; other file types are skipped.
+// Symlinks follow the rule Unpack enforces: absolute links to a path
+// inside dir are stored relative, and links leaving dir are an error, so
+// every archive Pack writes can be unpacked.
+func Pack(dir string, w io.Writer) (int, error) {
+	abs, err := filepath.Abs(dir)
+	if err != nil {
+		return 0, err
+	}
+	gz := gzip.NewWriter(w)
+	tw := tar.NewWriter(gz)
+
+	files := 0
+	err = filepath.Walk(abs, func(p string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		rel, err := filepath.Rel(abs, p)
+		if err != nil || rel == "." {
+			return err
+		}
+
+		link := ""
+		switch mode := info.Mode(); {
+		case mode.IsRegular(), mode.IsDir():
+		case mode&os.ModeSymlink != 0:
+			if link, err = packLink(abs, p, rel); err != nil {
+				return err
+			}
+		default:
+			return nil
+		}
+
+		hdr, err := tar.FileInfoHeader(info, link)
+		if err != nil {
+			return err
+		}
+		hdr.Name = filepath.ToSlash(rel)
+		if info.IsDir() {
+			hdr.Name += "/"
+		}
+		// Owners differ between containers; keep the archive portable
+		hdr.Uid, hdr.Gid, hdr.Uname, hdr.Gname = 0, 0, "", ""
+		if err := tw.WriteHeader(hdr); err != nil {
+			return err
+		}
+		if !info.Mode().IsRegular() {
+			return nil
+		}
+
+		f, err := os.Open(p)
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+		if _, err := io.Copy(tw, f); err != nil {
+			return err
+		}
+		files++
+		return nil
+	})
+	if err != nil {
+		return files, fmt.Errorf("failed to archive %s: %w", dir, err)
+	}
+	if err := tw.Close(); err != nil {
+		return files, err
+	}
+	return files, gz.Close()
+}
+
+// packLink returns the target to store for the symlink at p (rel within
+// dir): absolute targets inside dir are made relative to the link
+func packLink(dir, p, rel string) (string, error) {
+	link, err := os.Readlink(p)
+	if err != nil {
+		return "", err
+	}
+	if filepath.IsAbs(link) {
+		if inside, err := filepath.Rel(dir, link); err == nil && inside != ".." && !strings.HasPrefix(inside, ".."+string(filepath.Separator)) {
+			if link, err = filepath.Rel(filepath.Dir(p), link); err != nil {
+				return "", err
+			}
+		}
+	}
+	link = filepath.ToSlash(link)
+	if escapes(rel, link) {
+		return "", fmt.Errorf("symlink %s points outside the directory (%s); only links within it can be archived", filepath.ToSlash(rel), link)
+	}
+	return link, nil
+}
+
+// Unpack extracts a gzipped tar written by Pack into dest, which must not
+// exist yet, and returns the number of files extracted. Entries that would
+// land outside dest, symlinks pointing outside it and entries below a
+// symlink are rejected.
+func Unpack(r io.Reader, dest string) (int, error) {
+	gz, err := gzip.NewReader(r)
+	if err != nil {
+		return 0, fmt.Errorf("invalid archive: %w", err)
+	}
+	defer gz.Close()
+
+	if err := os.Mkdir(dest, 0755); err != nil {
+		return 0, err
+	}
+
+	tr := tar.NewReader(gz)
+	files := 0
+	for {
+		hdr, err := tr.Next()
+		if err == io.EOF {
+			return files, nil
+		}
+		if err != nil {
+			return files, fmt.Errorf("invalid archive: %w", err)
+		}
+
+		name, err := entryPath(hdr.Name)
+		if err != nil {
+			return files, err
+		}
+		if err := checkParents(dest, name); err != nil {
+			return files, err
+		}
+		target := filepath.Join(dest, name)
+		if err := os.MkdirAll(filepath.Dir(target), 0755); err != nil {
+			return files, err
+		}
+
+		switch hdr.Typeflag {
+		case tar.TypeDir:
+			if err := os.MkdirAll(target, hdr.FileInfo().Mode().Perm()|0700); err != nil {
+				return files, err
+			}
+		case tar.TypeReg:
+			if err := writeFile(target, tr, hdr.FileInfo().Mode().Perm()); err != nil {
+				return files, err
+			}
+			files++
+		case tar.TypeSymlink:
+			if escapes(name, hdr.Linkname) {
+				return files, fmt.Errorf("invalid archive: symlink %s points outside the directory", hdr.Name)
+			}
+			if err := os.Symlink(hdr.Linkname, target); err != nil {
+				return files, err
+			}
+		default:
+			return files, fmt.Errorf("invalid archive: unsupported entry %s", hdr.Name)
+		}
+	}
+}
+
+// entryPath returns an archive entry's path relative to the extraction
+// directory, rejecting absolute paths and ".." components
+func entryPath(name string) (string, error) {
+	clean := path.Clean(strings.TrimSuffix(name, "/"))
+	if path.IsAbs(clean) || clean == "." || clean == ".." || strings.HasPrefix(clean, "../") {
+		return "", fmt.Errorf("invalid archive: entry %s is outside the directory", name)
+	}
+	return filepath.FromSlash(clean), nil
+}
+
+// checkParents rejects an entry whose parent directories include a
+// symlink extracted earlier: writing through it could leave dest. Pack
+// never stores entries below a symlink.
+func checkParents(dest, name string) error {
+	dir := dest
+	for _, part := range strings.Split(filepath.ToSlash(filepath.Dir(name)), "/") {
+		if part == "." {
+			continue
+		}
+		dir = filepath.Join(dir, part)
+		info, err := os.Lstat(dir)
+		if os.IsNotExist(err) {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		if info.Mode()&os.ModeSymlink != 0 {
+			return fmt.Errorf("invalid archive: entry %s is below a symlink", name)
+		}
+	}
+	return nil
+}
+
+// escapes reports whether a symlink at name (relative to the extraction
+// directory) pointing to link resolves outside that directory
+func escapes(name, link string) bool {
+	if path.IsAbs(link) || filepath.IsAbs(link) {
+		return true
+	}
+	resolved := path.Join(path.Dir(filepath.ToSlash(name)), link)
+	return resolved == ".." || strings.HasPrefix(resolved, "../")
+}
+
+func writeFile(target string, r io.Reader, perm os.FileMode) error {
+	f, err := os.OpenFile(target, os.O_WRONLY|os.O_CREATE|os.O_EXCL, perm|0600)
+	if err != nil {
+		return err
+	}
+	if _, err := io.Copy(f, r); err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
@@ -0,0 +1,129 @@
+package workspace
+
+import (
+	"fmt"
+	"io/fs"
+	"path"
+	"path/filepath"
+	"sort"
+	"time"
+
+	"github.com/kindship-ai/kindship-cli/internal/api"
+	"github.com/kindship-ai/kindship-cli/internal/checksum"
+)
+
+// MaxManifestFiles caps the files a manifest records, so a task in a huge
+// workspace does not spend its time hashing
+const MaxManifestFiles = 50000
+
+// MaxDiffFiles caps the changed files listed in a diff; the counts always
+// cover every change
+const MaxDiffFiles = 200
+
+// FileEntry is a regular file recorded in a Manifest
+type FileEntry struct {
+	Size    int64
+	ModTime time.Time
+	SHA256  string
+}
+
+// Manifest maps the regular files of a directory, by slash-separated path
+// relative to it, to their size and hash
+type Manifest map[string]FileEntry
+
+// Snapshot records the regular files under dir. .git and paths matching
+// an exclude pattern (matched against the relative path and the base
+// name) are skipped, directories included. Files whose size and
+// modification time are unchanged since prev keep their hash from it
+// instead of being read again; prev may be nil.
+func Snapshot(dir string, exclude []string, prev Manifest) (Manifest, error) {
+	manifest := Manifest{}
+	err := filepath.WalkDir(dir, func(p string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		rel, err := filepath.Rel(dir, p)
+		if err != nil || rel == "." {
+			return err
+		}
+		rel = filepath.ToSlash(rel)
+		if excluded(rel, exclude) {
+			if d.IsDir() {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if !d.Type().IsRegular() {
+			return nil
+		}
+		if len(manifest) == MaxManifestFiles {
+			return fmt.Errorf("workspace has more than %d files; exclude generated directories", MaxManifestFiles)
+		}
+
+		info, err := d.Info()
+		if err != nil {
+			return err
+		}
+		entry := FileEntry{Size: info.Size(), ModTime: info.ModTime()}
+		if old, ok := prev[rel]; ok && old.Size == entry.Size && old.ModTime.Equal(entry.ModTime) {
+			entry.SHA256 = old.SHA256
+		} else if entry.SHA256, err = checksum.SHA256File(p); err != nil {
+			return err
+		}
+		manifest[rel] = entry
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return manifest, nil
+}
+
+// Diff summarizes the files added, modified and deleted between two
+// manifests. Files are listed in path order, up to MaxDiffFiles.
+func Diff(before, after Manifest) *api.WorkspaceDiff {
+	diff := &api.WorkspaceDiff{Files: []api.FileChange{}}
+	var changes []api.FileChange
+	for p, a := range after {
+		b, ok := before[p]
+		switch {
+		case !ok:
+			diff.Added++
+			changes = append(changes, api.FileChange{Path: p, Change: api.FileAdded, SizeAfter: a.Size, SHA256After: a.SHA256})
+		case b.SHA256 != a.SHA256:
+			diff.Modified++
+			changes = append(changes, api.FileChange{Path: p, Change: api.FileModified,
+				SizeBefore: b.Size, SizeAfter: a.Size, SHA256Before: b.SHA256, SHA256After: a.SHA256})
+		}
+	}
+	for p, b := range before {
+		if _, ok := after[p]; !ok {
+			diff.Deleted++
+			changes = append(changes, api.FileChange{Path: p, Change: api.FileDeleted, SizeBefore: b.Size, SHA256Before: b.SHA256})
+		}
+	}
+	sort.Slice(changes, func(i, j int) bool { return changes[i].Path < changes[j].Path })
+	if len(changes) > MaxDiffFiles {
+		changes = changes[:MaxDiffFiles]
+		diff.Truncated = true
+	}
+	diff.Files = append(diff.Files, changes...)
+	return diff
+}
+
+// excluded reports whether rel is .git or matches an exclude pattern
+func excluded(rel string, exclude []string) bool {
+	base := path.Base(rel)
+	if base == ".git" {
+		return true
+	}
+	for _, pattern := range exclude {
+		if ok, _ := path.Match(pattern, rel); ok {
+			return true
+		}
+		if ok, _ := path.Match(pattern, base); ok {
+			return true
+		}
+	}
+	return false
+}
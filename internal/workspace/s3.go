@@ -0,0 +1,231 @@
+package workspace
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"path"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/kindship-ai/kindship-cli/internal/api"
+	"github.com/kindship-ai/kindship-cli/internal/checksum"
+)
+
+// S3 settings, read from the environment or the agent's secrets
+const (
+	S3BucketVar       = "KINDSHIP_WORKSPACE_S3_BUCKET"
+	S3EndpointVar     = "KINDSHIP_WORKSPACE_S3_ENDPOINT"
+	S3RegionVar       = "KINDSHIP_WORKSPACE_S3_REGION"
+	s3AccessKeyVar    = "AWS_ACCESS_KEY_ID"
+	s3SecretKeyVar    = "AWS_SECRET_ACCESS_KEY"
+	s3SessionTokenVar = "AWS_SESSION_TOKEN"
+	s3FallbackRegion  = "AWS_REGION"
+)
+
+// s3Timeout bounds one archive transfer
+const s3Timeout = 10 * time.Minute
+
+// S3Store keeps archives in an S3-compatible bucket, addressed path-style
+// (endpoint/bucket/key) so MinIO and other S3 implementations work too.
+// Requests are signed with AWS Signature Version 4.
+type S3Store struct {
+	Endpoint     string
+	Bucket       string
+	Prefix       string
+	Region       string
+	AccessKey    string
+	SecretKey    string
+	SessionToken string
+	AgentID      string
+
+	httpClient *http.Client
+}
+
+// S3StoreFromSettings builds an S3Store from the S3 settings returned by
+// lookup, or returns nil if no bucket is configured.
+// KINDSHIP_WORKSPACE_S3_BUCKET is "bucket" or "bucket/prefix"; the
+// endpoint defaults to AWS S3 in the region (KINDSHIP_WORKSPACE_S3_REGION,
+// else AWS_REGION, else us-east-1).
+func S3StoreFromSettings(agentID string, lookup func(string) string) (*S3Store, error) {
+	bucket := strings.Trim(strings.TrimPrefix(lookup(S3BucketVar), "s3://"), "/")
+	if bucket == "" {
+		return nil, nil
+	}
+	s := &S3Store{
+		Endpoint:     lookup(S3EndpointVar),
+		Region:       lookup(S3RegionVar),
+		AccessKey:    lookup(s3AccessKeyVar),
+		SecretKey:    lookup(s3SecretKeyVar),
+		SessionToken: lookup(s3SessionTokenVar),
+		AgentID:      agentID,
+		httpClient:   api.NewHTTPClient(s3Timeout),
+	}
+	s.Bucket, s.Prefix, _ = strings.Cut(bucket, "/")
+	if s.Region == "" {
+		s.Region = lookup(s3FallbackRegion)
+	}
+	if s.Region == "" {
+		s.Region = "us-east-1"
+	}
+	if s.Endpoint == "" {
+		s.Endpoint = fmt.Sprintf("https://s3.%s.amazonaws.com", s.Region)
+	}
+	s.Endpoint = strings.TrimRight(s.Endpoint, "/")
+	if s.AccessKey == "" || s.SecretKey == "" {
+		return nil, fmt.Errorf("%s is set but %s or %s is missing", S3BucketVar, s3AccessKeyVar, s3SecretKeyVar)
+	}
+	return s, nil
+}
+
+// Put uploads archive as the archive of dir. It is read twice: once to
+// hash it for the request signature, then to send it.
+func (s *S3Store) Put(dir string, archive io.ReadSeeker, size int64) error {
+	hash, err := checksum.SHA256Reader(archive)
+	if err != nil {
+		return fmt.Errorf("failed to hash archive: %w", err)
+	}
+	if _, err := archive.Seek(0, io.SeekStart); err != nil {
+		return err
+	}
+	resp, err := s.do(http.MethodPut, dir, archive, size, hash)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return s.statusError(resp)
+	}
+	return nil
+}
+
+// Get downloads the archive of dir
+func (s *S3Store) Get(dir string) (io.ReadCloser, error) {
+	resp, err := s.do(http.MethodGet, dir, nil, 0, checksum.SHA256(nil))
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode == http.StatusNotFound {
+		resp.Body.Close()
+		return nil, ErrNotFound
+	}
+	if resp.StatusCode != http.StatusOK {
+		defer resp.Body.Close()
+		return nil, s.statusError(resp)
+	}
+	return resp.Body, nil
+}
+
+// Describe names the bucket and prefix
+func (s *S3Store) Describe() string {
+	return "s3://" + path.Join(s.Bucket, s.Prefix)
+}
+
+// do sends a signed request for the object of dir with the size bytes of
+// body, whose SHA-256 is payloadHash
+func (s *S3Store) do(method, dir string, body io.Reader, size int64, payloadHash string) (*http.Response, error) {
+	key := path.Join(s.Prefix, archiveKey(s.AgentID, dir))
+	u, err := url.Parse(s.Endpoint)
+	if err != nil {
+		return nil, fmt.Errorf("invalid %s: %w", S3EndpointVar, err)
+	}
+	u.Path = strings.TrimRight(u.Path, "/") + "/" + s.Bucket + "/" + key
+	// The signature covers the path encoded as SigV4 does it
+	u.RawPath = uriEncodePath(u.Path)
+
+	req, err := http.NewRequest(method, u.String(), body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+	if body != nil {
+		req.ContentLength = size
+		req.Header.Set("Content-Type", "application/gzip")
+	}
+	if s.SessionToken != "" {
+		req.Header.Set("X-Amz-Security-Token", s.SessionToken)
+	}
+	s.sign(req, payloadHash, time.Now().UTC())
+
+	resp, err := s.httpClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("request failed: %w", err)
+	}
+	return resp, nil
+}
+
+// sign adds AWS Signature Version 4 headers covering the host and every
+// header already set on req, for a payload with the SHA-256 payloadHash
+func (s *S3Store) sign(req *http.Request, payloadHash string, now time.Time) {
+	amzDate := now.Format("20060102T150405Z")
+	date := now.Format("20060102")
+	req.Header.Set("X-Amz-Date", amzDate)
+	req.Header.Set("X-Amz-Content-Sha256", payloadHash)
+
+	headers := map[string]string{"host": req.URL.Host}
+	for name, values := range req.Header {
+		headers[strings.ToLower(name)] = strings.TrimSpace(strings.Join(values, ","))
+	}
+	names := make([]string, 0, len(headers))
+	for name := range headers {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	var canonicalHeaders strings.Builder
+	for _, name := range names {
+		canonicalHeaders.WriteString(name + ":" + headers[name] + "\n")
+	}
+	signedHeaders := strings.Join(names, ";")
+
+	canonicalRequest := strings.Join([]string{
+		req.Method,
+		req.URL.EscapedPath(),
+		req.URL.RawQuery,
+		canonicalHeaders.String(),
+		signedHeaders,
+		payloadHash,
+	}, "\n")
+	scope := date + "/" + s.Region + "/s3/aws4_request"
+	stringToSign := "AWS4-HMAC-SHA256\n" + amzDate + "\n" + scope + "\n" + checksum.SHA256([]byte(canonicalRequest))
+
+	signingKey := hmacSHA256([]byte("AWS4"+s.SecretKey), date)
+	signingKey = hmacSHA256(signingKey, s.Region)
+	signingKey = hmacSHA256(signingKey, "s3")
+	signingKey = hmacSHA256(signingKey, "aws4_request")
+	signature := hex.EncodeToString(hmacSHA256(signingKey, stringToSign))
+
+	req.Header.Set("Authorization", fmt.Sprintf("AWS4-HMAC-SHA256 Credential=%s/%s, SignedHeaders=%s, Signature=%s",
+		s.AccessKey, scope, signedHeaders, signature))
+}
+
+// statusError turns an S3 error response into an error with its code
+func (s *S3Store) statusError(resp *http.Response) error {
+	body, _ := io.ReadAll(io.LimitReader(resp.Body, 4096))
+	return &api.StatusError{StatusCode: resp.StatusCode, Message: strings.TrimSpace(string(body))}
+}
+
+// uriEncodePath percent-encodes a path as SigV4 requires: everything but
+// unreserved characters and '/'
+func uriEncodePath(s string) string {
+	var b strings.Builder
+	for _, c := range []byte(s) {
+		switch {
+		case 'A' <= c && c <= 'Z', 'a' <= c && c <= 'z', '0' <= c && c <= '9',
+			c == '-', c == '_', c == '.', c == '~', c == '/':
+			b.WriteByte(c)
+		default:
+			fmt.Fprintf(&b, "%%%02X", c)
+		}
+	}
+	return b.String()
+}
+
+func hmacSHA256(key []byte, data string) []byte {
+	mac := hmac.New(sha256.New, key)
+	mac.Write([]byte(data))
+	return mac.Sum(nil)
+}
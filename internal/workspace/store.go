@@ -0,0 +1,60 @@
+package workspace
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"path"
+
+	"github.com/kindship-ai/kindship-cli/internal/api"
+)
+
+// ErrNotFound is returned by Store.Get when no archive was stored for a
+// directory
+var ErrNotFound = errors.New("not found")
+
+// Store keeps one agent's workspace archives, one per directory. dir is
+// slash-separated and relative to the workspace.
+// Archives are streamed: Put reads the size bytes of archive, rewinding it
+// if it has to read it twice, and Get returns a stream the caller must
+// close.
+type Store interface {
+	Put(dir string, archive io.ReadSeeker, size int64) error
+	// Get returns ErrNotFound when no archive was stored for dir
+	Get(dir string) (io.ReadCloser, error)
+	// Describe names the store in messages, e.g. "s3://bucket/prefix"
+	Describe() string
+}
+
+// archiveKey is the object key of an agent's archive of dir
+func archiveKey(agentID, dir string) string {
+	return path.Join("agents", agentID, "workspace", dir+".tar.gz")
+}
+
+// APIStore keeps archives in the platform's artifact store
+type APIStore struct {
+	Client     *api.Client
+	AgentID    string
+	ServiceKey string
+}
+
+// Put uploads archive as the archive of dir
+func (s *APIStore) Put(dir string, archive io.ReadSeeker, size int64) error {
+	return s.Client.UploadWorkspaceArchive(s.AgentID, dir, archive, size, s.ServiceKey)
+}
+
+// Get downloads the archive of dir
+func (s *APIStore) Get(dir string) (io.ReadCloser, error) {
+	archive, err := s.Client.DownloadWorkspaceArchive(s.AgentID, dir, s.ServiceKey)
+	var statusErr *api.StatusError
+	if errors.As(err, &statusErr) && statusErr.StatusCode == http.StatusNotFound {
+		return nil, ErrNotFound
+	}
+	return archive, err
+}
+
+// Describe names the platform's artifact store
+func (s *APIStore) Describe() string {
+	return fmt.Sprintf("the platform artifact store (%s)", s.Client.BaseURL())
+}